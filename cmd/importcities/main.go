@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"go-mongodb-api/config"
+	"go-mongodb-api/helpers"
+	"go-mongodb-api/middleware"
+	"go-mongodb-api/models"
+	"go-mongodb-api/repositories"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// importcities loads the offline geocoding dataset from a CSV file with the header
+// name,country,latitude,longitude[,population]. Countries are matched by name against the countries
+// collection; rows of unknown countries are skipped. Importing again updates the cities in place.
+func main() {
+	file := flag.String("file", "", "CSV file of cities to import")
+	flag.Parse()
+	if *file == "" {
+		log.Fatal("Usage: importcities -file cities.csv")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", *file, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	// Initialize configuration
+	if _, err := config.Init(); err != nil {
+		log.Fatalf("Failed to initialize config: %v", err)
+	}
+
+	// Initialize MongoDB connection
+	_, err = config.InitMongo()
+	if err != nil {
+		log.Fatalf("Failed to initialize MongoDB: %v", err)
+	}
+	defer func() {
+		if err := config.DisconnectMongo(); err != nil {
+			log.Printf("error disconnecting MongoDB: %v", err)
+		}
+	}()
+
+	db, err := config.GetDatabase("job_board")
+	if err != nil {
+		log.Fatalf("Failed to get database: %v", err)
+	}
+
+	// The country repository is only used for lookups, which never delete
+	countryRepo := repositories.NewCountryRepository(db, nil)
+	cityRepo := repositories.NewCityRepository(db)
+
+	reader := csv.NewReader(f)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		log.Fatalf("Failed to read header: %v", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"name", "country", "latitude", "longitude"} {
+		if _, ok := columns[name]; !ok {
+			log.Fatalf("Missing %q column", name)
+		}
+	}
+
+	ctx := context.Background()
+	countryIDs := map[string]bson.ObjectID{}
+	unknownCountries := map[string]int{}
+	var created, updated, invalid int
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			log.Fatalf("Failed to read line %d: %v", line, err)
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		countryName := field("country")
+		countryID, known := countryIDs[strings.ToLower(countryName)]
+		if !known {
+			country, err := countryRepo.GetByName(ctx, countryName)
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				unknownCountries[countryName]++
+				continue
+			}
+			if err != nil {
+				log.Fatalf("Failed to look up country %q: %v", countryName, err)
+			}
+			countryID = country.ID
+			countryIDs[strings.ToLower(countryName)] = countryID
+		}
+
+		city, err := parseCity(field("name"), countryID, field("latitude"), field("longitude"), field("population"))
+		if err != nil {
+			log.Printf("line %d: %v", line, err)
+			invalid++
+			continue
+		}
+
+		inserted, err := cityRepo.Upsert(ctx, city)
+		if err != nil {
+			log.Fatalf("Failed to import line %d: %v", line, err)
+		}
+		if inserted {
+			created++
+		} else {
+			updated++
+		}
+	}
+
+	fmt.Printf("Imported cities: %d created, %d updated, %d invalid\n", created, updated, invalid)
+	for _, name := range slices.Sorted(maps.Keys(unknownCountries)) {
+		fmt.Printf("  skipped %d city(ies) of unknown country %q\n", unknownCountries[name], name)
+	}
+}
+
+// parseCity builds a city from the fields of a CSV row
+func parseCity(name string, countryID bson.ObjectID, latitude, longitude, population string) (*models.City, error) {
+	lat, err := strconv.ParseFloat(latitude, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latitude %q", latitude)
+	}
+	lng, err := strconv.ParseFloat(longitude, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid longitude %q", longitude)
+	}
+	location := models.NewGeoPoint(lat, lng)
+	if !location.Valid() {
+		return nil, fmt.Errorf("coordinates %s,%s out of range", latitude, longitude)
+	}
+
+	city := &models.City{
+		Name:      name,
+		CountryID: countryID,
+		Location:  *location,
+	}
+	city.SetUpdated(middleware.SystemActor, time.Now())
+	if population != "" {
+		if city.Population, err = strconv.Atoi(population); err != nil {
+			return nil, fmt.Errorf("invalid population %q", population)
+		}
+	}
+	if validationErrors := helpers.ValidateStruct(city); len(validationErrors) > 0 {
+		return nil, fmt.Errorf("invalid %s: %s", validationErrors[0].Field, validationErrors[0].Message)
+	}
+	return city, nil
+}
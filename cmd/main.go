@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"go-mongodb-api/config"
 	"go-mongodb-api/handlers"
+	"go-mongodb-api/interfaces"
 	authMW "go-mongodb-api/middleware"
+	"go-mongodb-api/models"
+	"go-mongodb-api/notifiers"
 	"go-mongodb-api/repositories"
 	"go-mongodb-api/services"
 	"log"
@@ -53,36 +56,89 @@ func main() {
 		log.Fatalf("Failed to ensure indexes: %v", err)
 	}
 
+	// Initialize delete policies
+	deleteRelations, err := repositories.DeleteRelations(cfg.DeletePolicies)
+	if err != nil {
+		log.Fatalf("Invalid DELETE_POLICIES configuration: %v", err)
+	}
+	deleter := repositories.NewDeleter(db, deleteRelations)
+
 	// Initialize repositories
-	userRepo := repositories.NewUserRepository(db)
-	jobRepo := repositories.NewJobRepository(db)
-	skillRepo := repositories.NewSkillRepository(db)
-	applicationRepo := repositories.NewApplicationRepository(db)
-	jobCategoryRepo := repositories.NewJobCategoryRepository(db)
-	candidateSkillRepo := repositories.NewCandidateSkillRepository(db)
-	jobSkillRepo := repositories.NewJobSkillRepository(db)
-	articleRepo := repositories.NewArticleRepository(db)
-	countryRepo := repositories.NewCountryRepository(db)
-	educationLevelRepo := repositories.NewEducationLevelRepository(db)
-	jobTypeRepo := repositories.NewJobTypeRepository(db)
-	knowledgeLevelRepo := repositories.NewKnowledgeLevelRepository(db)
-	locationAvailabilityRepo := repositories.NewLocationAvailabilityRepository(db)
+	userRepo := repositories.NewUserRepository(db, deleter)
+	jobRepo := repositories.NewJobRepository(db, deleter)
+	skillRepo := repositories.NewSkillRepository(db, deleter)
+	applicationRepo := repositories.NewApplicationRepository(db, deleter)
+	jobCategoryRepo := repositories.NewJobCategoryRepository(db, deleter)
+	candidateSkillRepo := repositories.NewCandidateSkillRepository(db, deleter)
+	jobSkillRepo := repositories.NewJobSkillRepository(db, deleter)
+	articleRepo := repositories.NewArticleRepository(db, deleter)
+	countryRepo := repositories.NewCountryRepository(db, deleter)
+	educationLevelRepo := repositories.NewEducationLevelRepository(db, deleter)
+	jobTypeRepo := repositories.NewJobTypeRepository(db, deleter)
+	knowledgeLevelRepo := repositories.NewKnowledgeLevelRepository(db, deleter)
+	locationAvailabilityRepo := repositories.NewLocationAvailabilityRepository(db, deleter)
+	sessionRepo := repositories.NewSessionRepository(db)
+	userTokenRepo := repositories.NewUserTokenRepository(db)
+	loginAttemptRepo := repositories.NewLoginAttemptRepository(db)
+	apiKeyRepo := repositories.NewAPIKeyRepository(db)
+	exchangeRateRepo := repositories.NewExchangeRateRepository(db)
+	pipelineTemplateRepo := repositories.NewPipelineTemplateRepository(db)
+	cityRepo := repositories.NewCityRepository(db)
+
+	// Initialize access token signing keys
+	keySet, err := loadKeySet(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load JWT signing keys: %v", err)
+	}
+
+	// Initialize notifier
+	var notifier interfaces.Notifier = notifiers.NewLogNotifier()
+	if cfg.Notifier == "file" {
+		notifier = notifiers.NewFileNotifier(cfg.NotifierFile)
+	}
 
 	// Initialize services
 	userService := services.NewUserService(userRepo)
-	jobService := services.NewJobService(jobRepo, userRepo, jobCategoryRepo)
+	geocoder := services.NewGeocoder(cityRepo, countryRepo)
+	jobService := services.NewJobService(jobRepo, userRepo, jobCategoryRepo,
+		services.WithSalaryBands(cfg.JobSalaryBands),
+		services.WithSalaryNormalization(exchangeRateRepo, cfg.SalaryBaseCurrency),
+		services.WithGeocoder(geocoder),
+		services.WithReferenceData(jobTypeRepo, countryRepo, educationLevelRepo, locationAvailabilityRepo),
+		services.WithPipelineTemplates(pipelineTemplateRepo),
+	)
 	skillService := services.NewSkillService(skillRepo)
-	applicationService := services.NewApplicationService(applicationRepo, jobRepo, userRepo)
+	applicationService := services.NewApplicationService(applicationRepo, jobRepo, userRepo,
+		services.WithReapplyCooldown(cfg.ReapplyCooldown),
+		services.WithRejectionReasons(cfg.RejectionReasons),
+	)
 	jobCategoryService := services.NewJobCategoryService(jobCategoryRepo)
 	candidateSkillService := services.NewCandidateSkillService(candidateSkillRepo, userRepo, skillRepo)
 	jobSkillService := services.NewJobSkillService(jobSkillRepo, jobRepo, skillRepo)
-	authService := services.NewAuthService(userService, cfg.JWTSecret)
+	authService := services.NewAuthService(userService, sessionRepo, userTokenRepo, loginAttemptRepo, notifier, services.AuthConfig{
+		Keys:               keySet,
+		JWTSecret:          cfg.JWTSecret,
+		AccessTokenTTL:     cfg.AccessTokenTTL,
+		RefreshTokenTTL:    cfg.RefreshTokenTTL,
+		PasswordResetTTL:   cfg.PasswordResetTTL,
+		VerificationTTL:    cfg.VerificationTTL,
+		InvitationTTL:      cfg.InvitationTTL,
+		AppBaseURL:         cfg.AppBaseURL,
+		MaxLoginAttempts:   cfg.MaxLoginAttempts,
+		MaxIPLoginAttempts: cfg.MaxIPLoginAttempts,
+		LoginLockoutBase:   cfg.LoginLockoutBase,
+		LoginLockoutMax:    cfg.LoginLockoutMax,
+		MFAIssuer:          cfg.MFAIssuer,
+	})
 	articleService := services.NewArticleService(articleRepo)
 	countryService := services.NewCountryService(countryRepo)
 	educationLevelService := services.NewEducationLevelService(educationLevelRepo)
 	jobTypeService := services.NewJobTypeService(jobTypeRepo)
 	knowledgeLevelService := services.NewKnowledgeLevelService(knowledgeLevelRepo)
 	locationAvailabilityService := services.NewLocationAvailabilityService(locationAvailabilityRepo)
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo)
+	exchangeRateService := services.NewExchangeRateService(exchangeRateRepo, jobRepo, cfg.SalaryBaseCurrency)
+	pipelineTemplateService := services.NewPipelineTemplateService(pipelineTemplateRepo, userRepo)
 
 	// Initialize handlers
 	userHandler := handlers.NewUserHandler(userService)
@@ -93,12 +149,16 @@ func main() {
 	candidateSkillHandler := handlers.NewCandidateSkillHandler(candidateSkillService)
 	jobSkillHandler := handlers.NewJobSkillHandler(jobSkillService)
 	authHandler := handlers.NewAuthHandler(authService)
+	jwksHandler := handlers.NewJWKSHandler(keySet)
 	articleHandler := handlers.NewArticleHandler(articleService)
 	countryHandler := handlers.NewCountryHandler(countryService)
 	educationLevelHandler := handlers.NewEducationLevelHandler(educationLevelService)
 	jobTypeHandler := handlers.NewJobTypeHandler(jobTypeService)
 	knowledgeLevelHandler := handlers.NewKnowledgeLevelHandler(knowledgeLevelService)
 	locationAvailabilityHandler := handlers.NewLocationAvailabilityHandler(locationAvailabilityService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	exchangeRateHandler := handlers.NewExchangeRateHandler(exchangeRateService)
+	pipelineTemplateHandler := handlers.NewPipelineTemplateHandler(pipelineTemplateService)
 
 	// Validate port
 	port, err := strconv.Atoi(cfg.Port)
@@ -109,6 +169,10 @@ func main() {
 	// Setup router with middleware
 	r := chi.NewRouter()
 	r.Use(middleware.Recoverer)
+	if cfg.TrustProxyHeaders {
+		// Login lockout counts failures per client IP, so take it from the proxy headers
+		r.Use(middleware.RealIP)
+	}
 
 	// ── Public routes ────────────────────────────────────────────────────────
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -120,107 +184,199 @@ func main() {
 	})
 
 	// Auth endpoints
+	r.Get("/.well-known/jwks.json", jwksHandler.GetJWKS)
 	r.Post("/auth/login", authHandler.Login)
+	r.Post("/auth/login/mfa", authHandler.VerifyMFA)
 	r.Post("/auth/register", authHandler.Register)
+	r.Post("/auth/refresh", authHandler.Refresh)
+	r.Post("/auth/forgot-password", authHandler.ForgotPassword)
+	r.Post("/auth/reset-password", authHandler.ResetPassword)
+	r.Post("/auth/verify", authHandler.VerifyEmail)
+	r.Post("/auth/verify/resend", authHandler.ResendVerification)
+	r.Post("/auth/invitations/accept", authHandler.AcceptInvitation)
 
-	// Public read-only
-	r.Get("/jobs", jobHandler.GetAllJobs)
-	r.Get("/jobs/{id}", jobHandler.GetJobByID)
-	r.Get("/users/{userId}/jobs", jobHandler.GetJobsByUser)
-	r.Get("/skills", skillHandler.GetAllSkills)
-	r.Get("/skills/{id}", skillHandler.GetSkillByID)
-	r.Get("/jobcategories", jobCategoryHandler.GetAllJobCategories)
-	r.Get("/jobcategories/{id}", jobCategoryHandler.GetJobCategoryByID)
-	r.Get("/jobs/{jobId}/skills", jobSkillHandler.GetJobSkillsByJobID)
-	r.Get("/articles", articleHandler.GetAllArticles)
-	r.Get("/articles/{id}", articleHandler.GetArticleByID)
-	r.Get("/countries", countryHandler.GetAllCountries)
-	r.Get("/countries/{id}", countryHandler.GetCountryByID)
-	r.Get("/educationlevels", educationLevelHandler.GetAllEducationLevels)
-	r.Get("/educationlevels/{id}", educationLevelHandler.GetEducationLevelByID)
-	r.Get("/jobtypes", jobTypeHandler.GetAllJobTypes)
-	r.Get("/jobtypes/{id}", jobTypeHandler.GetJobTypeByID)
-	r.Get("/knowledgelevels", knowledgeLevelHandler.GetAllKnowledgeLevels)
-	r.Get("/knowledgelevels/{id}", knowledgeLevelHandler.GetKnowledgeLevelByID)
-	r.Get("/locationavailabilities", locationAvailabilityHandler.GetAllLocationAvailabilities)
-	r.Get("/locationavailabilities/{id}", locationAvailabilityHandler.GetLocationAvailabilityByID)
-
-	// Public user listing (supports ?role=candidate etc.)
-	r.Get("/users", userHandler.GetAllUsers)
-	r.Get("/users/{id}", userHandler.GetUserByID)
+	authOptions := []authMW.AuthOption{
+		authMW.WithKeySet(keySet),
+		authMW.WithSessionChecker(authService),
+		authMW.WithAPIKeys(apiKeyService, apiKeyScopes()),
+	}
+	authenticate := authMW.Authenticate(cfg.JWTSecret, authOptions...)
+	requireMFA := authMW.RequireMFA(cfg.RequireMFARoles...)
+	// identify authenticates the callers of a public route who send a valid token or API key, for the
+	// parts of the response only some callers may see; everyone else is served anonymously
+	identify := authMW.Authenticate(cfg.JWTSecret, append(authOptions, authMW.WithAnonymousFallback())...)
+
+	r.Group(func(r chi.Router) {
+		// listing soft-deleted records with ?include_deleted=true is reserved for admins
+		r.Use(authMW.WhenQuery("include_deleted", authenticate, requireMFA, authMW.RequireRoles("admin")))
+
+		// Public read-only. Jobs are listed for callers who send a token too, so recruiters and admins
+		// also see the drafts and archived jobs they may.
+		r.With(identify).Get("/jobs", jobHandler.GetAllJobs)
+		r.With(identify).Get("/jobs/{id}", jobHandler.GetJobByID)
+		r.With(identify).Get("/users/{userId}/jobs", jobHandler.GetJobsByUser)
+		r.Get("/skills", skillHandler.GetAllSkills)
+		r.Get("/skills/{id}", skillHandler.GetSkillByID)
+		r.Get("/jobcategories", jobCategoryHandler.GetAllJobCategories)
+		r.Get("/jobcategories/{id}", jobCategoryHandler.GetJobCategoryByID)
+		r.Get("/jobs/{jobId}/skills", jobSkillHandler.GetJobSkillsByJobID)
+		r.Get("/articles", articleHandler.GetAllArticles)
+		r.Get("/articles/{id}", articleHandler.GetArticleByID)
+		r.Get("/countries", countryHandler.GetAllCountries)
+		r.Get("/countries/{id}", countryHandler.GetCountryByID)
+		r.Get("/educationlevels", educationLevelHandler.GetAllEducationLevels)
+		r.Get("/educationlevels/{id}", educationLevelHandler.GetEducationLevelByID)
+		r.Get("/jobtypes", jobTypeHandler.GetAllJobTypes)
+		r.Get("/jobtypes/{id}", jobTypeHandler.GetJobTypeByID)
+		r.Get("/knowledgelevels", knowledgeLevelHandler.GetAllKnowledgeLevels)
+		r.Get("/knowledgelevels/{id}", knowledgeLevelHandler.GetKnowledgeLevelByID)
+		r.Get("/locationavailabilities", locationAvailabilityHandler.GetAllLocationAvailabilities)
+		r.Get("/locationavailabilities/{id}", locationAvailabilityHandler.GetLocationAvailabilityByID)
+
+		// Public user listing (supports ?role=candidate etc.)
+		r.With(identify).Get("/users", userHandler.GetAllUsers)
+		r.With(identify).Get("/users/{id}", userHandler.GetUserByID)
+	})
 
 	// ── Authenticated routes ─────────────────────────────────────────────────
+	requireVerified := authMW.RequireVerified(cfg.RequireVerifiedRoles...)
 	r.Group(func(r chi.Router) {
-		r.Use(authMW.Authenticate(cfg.JWTSecret))
+		r.Use(authenticate)
 
-		// admin only
-		r.Group(func(r chi.Router) {
-			r.Use(authMW.RequireRoles("admin"))
-			r.Post("/users", userHandler.CreateUser)
-			r.Put("/users/{id}", userHandler.UpdateUser)
-			r.Delete("/users/{id}", userHandler.DeleteUser)
-			r.Post("/skills", skillHandler.CreateSkill)
-			r.Put("/skills/{id}", skillHandler.UpdateSkill)
-			r.Delete("/skills/{id}", skillHandler.DeleteSkill)
-			r.Post("/jobcategories", jobCategoryHandler.CreateJobCategory)
-			r.Put("/jobcategories/{id}", jobCategoryHandler.UpdateJobCategory)
-			r.Delete("/jobcategories/{id}", jobCategoryHandler.DeleteJobCategory)
-			r.Post("/articles", articleHandler.CreateArticle)
-			r.Put("/articles/{id}", articleHandler.UpdateArticle)
-			r.Delete("/articles/{id}", articleHandler.DeleteArticle)
-			r.Post("/countries", countryHandler.CreateCountry)
-			r.Put("/countries/{id}", countryHandler.UpdateCountry)
-			r.Delete("/countries/{id}", countryHandler.DeleteCountry)
-			r.Post("/educationlevels", educationLevelHandler.CreateEducationLevel)
-			r.Put("/educationlevels/{id}", educationLevelHandler.UpdateEducationLevel)
-			r.Delete("/educationlevels/{id}", educationLevelHandler.DeleteEducationLevel)
-			r.Post("/jobtypes", jobTypeHandler.CreateJobType)
-			r.Put("/jobtypes/{id}", jobTypeHandler.UpdateJobType)
-			r.Delete("/jobtypes/{id}", jobTypeHandler.DeleteJobType)
-			r.Post("/knowledgelevels", knowledgeLevelHandler.CreateKnowledgeLevel)
-			r.Put("/knowledgelevels/{id}", knowledgeLevelHandler.UpdateKnowledgeLevel)
-			r.Delete("/knowledgelevels/{id}", knowledgeLevelHandler.DeleteKnowledgeLevel)
-			r.Post("/locationavailabilities", locationAvailabilityHandler.CreateLocationAvailability)
-			r.Put("/locationavailabilities/{id}", locationAvailabilityHandler.UpdateLocationAvailability)
-			r.Delete("/locationavailabilities/{id}", locationAvailabilityHandler.DeleteLocationAvailability)
-			r.Get("/candidateskills", candidateSkillHandler.GetAllCandidateSkills)
-			r.Get("/jobskills", jobSkillHandler.GetAllJobSkills)
-			r.Get("/applications", applicationHandler.GetAllApplications)
-		})
+		r.Post("/auth/logout", authHandler.Logout)
 
-		// admin + recruiter
+		// two-factor enrollment stays reachable for sessions that have not passed MFA yet
 		r.Group(func(r chi.Router) {
 			r.Use(authMW.RequireRoles("admin", "recruiter"))
-			r.Post("/jobs", jobHandler.CreateJob)
-			r.Delete("/jobs/{id}", jobHandler.DeleteJob)
-			r.Post("/jobskills", jobSkillHandler.CreateJobSkill)
-			r.Get("/jobskills/{id}", jobSkillHandler.GetJobSkillByID)
-			r.Put("/jobskills/{id}", jobSkillHandler.UpdateJobSkillProficiencyLevel)
-			r.Delete("/jobskills/{id}", jobSkillHandler.DeleteJobSkill)
-			r.Get("/jobs/{jobId}/applications", applicationHandler.GetApplicationsByJobID)
-			r.Put("/applications/{id}", applicationHandler.UpdateApplicationStatus)
+			r.Post("/auth/mfa/enroll", authHandler.EnrollMFA)
+			r.Post("/auth/mfa/confirm", authHandler.ConfirmMFA)
+			r.Post("/auth/mfa/disable", authHandler.DisableMFA)
 		})
 
-		// admin + candidate
 		r.Group(func(r chi.Router) {
-			r.Use(authMW.RequireRoles("admin", "candidate"))
-			r.Post("/applications", applicationHandler.CreateApplication)
-			r.Post("/candidateskills", candidateSkillHandler.CreateCandidateSkill)
-			r.Put("/candidateskills/{id}", candidateSkillHandler.UpdateCandidateSkillProficiencyLevel)
-			r.Delete("/candidateskills/{id}", candidateSkillHandler.DeleteCandidateSkill)
-			r.Get("/users/{userId}/applications", applicationHandler.GetApplicationsByUserID)
-			r.Delete("/applications/{id}", applicationHandler.DeleteApplication)
-			r.Get("/candidateskills/{id}", candidateSkillHandler.GetCandidateSkillByID)
-		})
+			// roles listed in REQUIRE_MFA_ROLES need a session that passed two-factor authentication
+			r.Use(requireMFA)
 
-		// admin + candidate + recruiter
-		r.Group(func(r chi.Router) {
-			r.Use(authMW.RequireRoles("admin", "candidate", "recruiter"))
-			r.Get("/applications/{id}", applicationHandler.GetApplicationByID)
-			r.Get("/users/{userId}/skills", candidateSkillHandler.GetCandidateSkillsByUserID)
+			// admin only
+			r.Group(func(r chi.Router) {
+				r.Use(authMW.RequireRoles("admin"))
+				r.Post("/auth/invitations", authHandler.Invite)
+				r.Post("/users", userHandler.CreateUser)
+				r.Put("/users/{id}", userHandler.UpdateUser)
+				r.Delete("/users/{id}", userHandler.DeleteUser)
+				r.Post("/users/{id}/unlock", authHandler.UnlockAccount)
+				r.Post("/skills", skillHandler.CreateSkill)
+				r.Put("/skills/{id}", skillHandler.UpdateSkill)
+				r.Delete("/skills/{id}", skillHandler.DeleteSkill)
+				r.Post("/jobcategories", jobCategoryHandler.CreateJobCategory)
+				r.Put("/jobcategories/{id}", jobCategoryHandler.UpdateJobCategory)
+				r.Delete("/jobcategories/{id}", jobCategoryHandler.DeleteJobCategory)
+				r.Post("/articles", articleHandler.CreateArticle)
+				r.Put("/articles/{id}", articleHandler.UpdateArticle)
+				r.Delete("/articles/{id}", articleHandler.DeleteArticle)
+				r.Post("/countries", countryHandler.CreateCountry)
+				r.Put("/countries/{id}", countryHandler.UpdateCountry)
+				r.Delete("/countries/{id}", countryHandler.DeleteCountry)
+				r.Post("/educationlevels", educationLevelHandler.CreateEducationLevel)
+				r.Put("/educationlevels/{id}", educationLevelHandler.UpdateEducationLevel)
+				r.Delete("/educationlevels/{id}", educationLevelHandler.DeleteEducationLevel)
+				r.Post("/jobtypes", jobTypeHandler.CreateJobType)
+				r.Put("/jobtypes/{id}", jobTypeHandler.UpdateJobType)
+				r.Delete("/jobtypes/{id}", jobTypeHandler.DeleteJobType)
+				r.Post("/knowledgelevels", knowledgeLevelHandler.CreateKnowledgeLevel)
+				r.Put("/knowledgelevels/{id}", knowledgeLevelHandler.UpdateKnowledgeLevel)
+				r.Delete("/knowledgelevels/{id}", knowledgeLevelHandler.DeleteKnowledgeLevel)
+				r.Post("/locationavailabilities", locationAvailabilityHandler.CreateLocationAvailability)
+				r.Put("/locationavailabilities/{id}", locationAvailabilityHandler.UpdateLocationAvailability)
+				r.Delete("/locationavailabilities/{id}", locationAvailabilityHandler.DeleteLocationAvailability)
+				r.Get("/candidateskills", candidateSkillHandler.GetAllCandidateSkills)
+				r.Get("/jobskills", jobSkillHandler.GetAllJobSkills)
+				r.Get("/applications", applicationHandler.GetAllApplications)
+				r.Get("/apikeys", apiKeyHandler.GetAllAPIKeys)
+				r.Post("/apikeys", apiKeyHandler.CreateAPIKey)
+				r.Delete("/apikeys/{id}", apiKeyHandler.RevokeAPIKey)
+
+				r.Get("/exchangerates", exchangeRateHandler.GetExchangeRates)
+				r.Put("/exchangerates/{currency}", exchangeRateHandler.SetExchangeRate)
+				r.Delete("/exchangerates/{currency}", exchangeRateHandler.DeleteExchangeRate)
+
+				// soft-deleted records
+				r.Post("/users/{id}/restore", userHandler.RestoreUser)
+				r.Post("/jobs/{id}/restore", jobHandler.RestoreJob)
+				r.Post("/applications/{id}/restore", applicationHandler.RestoreApplication)
+				r.Post("/skills/{id}/restore", skillHandler.RestoreSkill)
+				r.Post("/jobcategories/{id}/restore", jobCategoryHandler.RestoreJobCategory)
+				r.Post("/articles/{id}/restore", articleHandler.RestoreArticle)
+				r.Post("/countries/{id}/restore", countryHandler.RestoreCountry)
+				r.Post("/educationlevels/{id}/restore", educationLevelHandler.RestoreEducationLevel)
+				r.Post("/jobtypes/{id}/restore", jobTypeHandler.RestoreJobType)
+				r.Post("/knowledgelevels/{id}/restore", knowledgeLevelHandler.RestoreKnowledgeLevel)
+				r.Post("/locationavailabilities/{id}/restore", locationAvailabilityHandler.RestoreLocationAvailability)
+				r.Post("/candidateskills/{id}/restore", candidateSkillHandler.RestoreCandidateSkill)
+				r.Post("/jobskills/{id}/restore", jobSkillHandler.RestoreJobSkill)
+			})
+
+			// admin + recruiter
+			r.Group(func(r chi.Router) {
+				r.Use(authMW.RequireRoles("admin", "recruiter"))
+				r.With(requireVerified).Post("/jobs", jobHandler.CreateJob)
+				r.Put("/jobs/{id}", jobHandler.UpdateJob)
+				r.Patch("/jobs/{id}", jobHandler.PatchJob)
+				r.Delete("/jobs/{id}", jobHandler.DeleteJob)
+				r.Post("/jobs/{id}/publish", jobHandler.PublishJob)
+				r.Post("/jobs/{id}/close", jobHandler.CloseJob)
+				r.Post("/jobs/{id}/reopen", jobHandler.ReopenJob)
+				r.Post("/jobs/{id}/archive", jobHandler.ArchiveJob)
+				r.Put("/jobs/{id}/stages", jobHandler.SetJobStages)
+				r.Post("/jobskills", jobSkillHandler.CreateJobSkill)
+				r.Get("/jobskills/{id}", jobSkillHandler.GetJobSkillByID)
+				r.Put("/jobskills/{id}", jobSkillHandler.UpdateJobSkillProficiencyLevel)
+				r.Delete("/jobskills/{id}", jobSkillHandler.DeleteJobSkill)
+				r.Get("/jobs/{jobId}/applications", applicationHandler.GetApplicationsByJobID)
+				r.Post("/jobs/{jobId}/applications/bulk", applicationHandler.BulkUpdateApplications)
+				r.Get("/rejectionreasons", applicationHandler.GetRejectionReasons)
+				r.Put("/applications/{id}/stage", applicationHandler.MoveApplicationStage)
+				r.Get("/pipelinetemplates", pipelineTemplateHandler.GetPipelineTemplates)
+				r.Post("/pipelinetemplates", pipelineTemplateHandler.CreatePipelineTemplate)
+				r.Get("/pipelinetemplates/{id}", pipelineTemplateHandler.GetPipelineTemplateByID)
+				r.Put("/pipelinetemplates/{id}", pipelineTemplateHandler.UpdatePipelineTemplate)
+				r.Delete("/pipelinetemplates/{id}", pipelineTemplateHandler.DeletePipelineTemplate)
+			})
+
+			// admin + candidate
+			r.Group(func(r chi.Router) {
+				r.Use(authMW.RequireRoles("admin", "candidate"))
+				r.With(requireVerified).Post("/applications", applicationHandler.CreateApplication)
+				r.Post("/candidateskills", candidateSkillHandler.CreateCandidateSkill)
+				r.Put("/candidateskills/{id}", candidateSkillHandler.UpdateCandidateSkillProficiencyLevel)
+				r.Delete("/candidateskills/{id}", candidateSkillHandler.DeleteCandidateSkill)
+				r.Get("/users/{userId}/applications", applicationHandler.GetApplicationsByUserID)
+				r.Delete("/applications/{id}", applicationHandler.DeleteApplication)
+				r.Get("/candidateskills/{id}", candidateSkillHandler.GetCandidateSkillByID)
+			})
+
+			// candidate
+			r.Group(func(r chi.Router) {
+				r.Use(authMW.RequireRoles("candidate"))
+				r.Post("/applications/{id}/withdraw", applicationHandler.WithdrawApplication)
+			})
+
+			// admin + candidate + recruiter
+			r.Group(func(r chi.Router) {
+				r.Use(authMW.RequireRoles("admin", "candidate", "recruiter"))
+				r.Get("/applications/{id}", applicationHandler.GetApplicationByID)
+				r.Get("/applications/{id}/history", applicationHandler.GetApplicationHistory)
+				r.Put("/applications/{id}", applicationHandler.UpdateApplicationStatus)
+				r.Get("/users/{userId}/skills", candidateSkillHandler.GetCandidateSkillsByUserID)
+			})
 		})
 	})
 
+	// Close jobs whose closes_at deadline has passed
+	sweepCtx, stopSweeper := context.WithCancel(context.Background())
+	defer stopSweeper()
+	go jobService.RunExpirySweeper(sweepCtx, cfg.JobSweepInterval)
+
 	// Start server
 	fmt.Printf("Starting API server on port %s\n", cfg.Port)
 
@@ -256,3 +412,80 @@ func main() {
 		}
 	}
 }
+
+// loadKeySet reads the configured signing keys. Without keys, tokens are signed with JWT_SECRET (HS256).
+func loadKeySet(cfg *config.Config) (*authMW.KeySet, error) {
+	if len(cfg.JWTKeyFiles) == 0 {
+		return authMW.NewHMACKeySet(cfg.JWTSecret), nil
+	}
+
+	keys := make([]authMW.SigningKey, 0, len(cfg.JWTKeyFiles))
+	for _, file := range cfg.JWTKeyFiles {
+		pemBytes, err := os.ReadFile(file.Path)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %q: %w", file.ID, err)
+		}
+		key, err := authMW.ParseSigningKey(file.ID, pemBytes)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return authMW.NewKeySet(cfg.JWTSigningKeyID, keys, cfg.JWTSecret)
+}
+
+// apiKeyScopes lists the routes API keys may call and the scope each one needs. API keys act as admins
+// within their scopes; authenticated routes missing here reject API keys, and public routes serve them
+// anonymously.
+func apiKeyScopes() authMW.RouteScopes {
+	return authMW.RouteScopes{
+		// public, with drafts and archived jobs
+		"GET /jobs":                models.ScopeJobsRead,
+		"GET /jobs/{id}":           models.ScopeJobsRead,
+		"GET /users/{userId}/jobs": models.ScopeJobsRead,
+
+		// admin only
+		"GET /candidateskills": models.ScopeCandidatesRead,
+		"GET /jobskills":       models.ScopeJobsRead,
+		"GET /applications":    models.ScopeApplicationsRead,
+
+		// admin + recruiter
+		"POST /jobs":                           models.ScopeJobsWrite,
+		"PUT /jobs/{id}":                       models.ScopeJobsWrite,
+		"PATCH /jobs/{id}":                     models.ScopeJobsWrite,
+		"DELETE /jobs/{id}":                    models.ScopeJobsWrite,
+		"POST /jobs/{id}/publish":              models.ScopeJobsWrite,
+		"POST /jobs/{id}/close":                models.ScopeJobsWrite,
+		"POST /jobs/{id}/reopen":               models.ScopeJobsWrite,
+		"POST /jobs/{id}/archive":              models.ScopeJobsWrite,
+		"PUT /jobs/{id}/stages":                models.ScopeJobsWrite,
+		"POST /jobskills":                      models.ScopeJobsWrite,
+		"GET /jobskills/{id}":                  models.ScopeJobsRead,
+		"PUT /jobskills/{id}":                  models.ScopeJobsWrite,
+		"DELETE /jobskills/{id}":               models.ScopeJobsWrite,
+		"GET /jobs/{jobId}/applications":       models.ScopeApplicationsRead,
+		"POST /jobs/{jobId}/applications/bulk": models.ScopeApplicationsWrite,
+		"GET /rejectionreasons":                models.ScopeApplicationsRead,
+		"PUT /applications/{id}/stage":         models.ScopeApplicationsWrite,
+		"GET /pipelinetemplates":               models.ScopeJobsRead,
+		"POST /pipelinetemplates":              models.ScopeJobsWrite,
+		"GET /pipelinetemplates/{id}":          models.ScopeJobsRead,
+		"PUT /pipelinetemplates/{id}":          models.ScopeJobsWrite,
+		"DELETE /pipelinetemplates/{id}":       models.ScopeJobsWrite,
+
+		// admin + candidate
+		"POST /applications":               models.ScopeApplicationsWrite,
+		"POST /candidateskills":            models.ScopeCandidatesWrite,
+		"PUT /candidateskills/{id}":        models.ScopeCandidatesWrite,
+		"DELETE /candidateskills/{id}":     models.ScopeCandidatesWrite,
+		"GET /users/{userId}/applications": models.ScopeApplicationsRead,
+		"DELETE /applications/{id}":        models.ScopeApplicationsWrite,
+		"GET /candidateskills/{id}":        models.ScopeCandidatesRead,
+
+		// admin + candidate + recruiter
+		"GET /applications/{id}":         models.ScopeApplicationsRead,
+		"GET /applications/{id}/history": models.ScopeApplicationsRead,
+		"PUT /applications/{id}":         models.ScopeApplicationsWrite,
+		"GET /users/{userId}/skills":     models.ScopeCandidatesRead,
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"go-mongodb-api/config"
+	"log"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// migrate moves jobs from the free-text job_type field to job_type_id, a reference to the job type
+// whose title matches it (ignoring case). Jobs whose job_type matches no job type are left as they are
+// and reported; create the missing job types and run it again. Use -dry-run to only report.
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report the changes without writing them")
+	flag.Parse()
+
+	// Initialize configuration
+	if _, err := config.Init(); err != nil {
+		log.Fatalf("Failed to initialize config: %v", err)
+	}
+
+	// Initialize MongoDB connection
+	_, err := config.InitMongo()
+	if err != nil {
+		log.Fatalf("Failed to initialize MongoDB: %v", err)
+	}
+	defer func() {
+		if err := config.DisconnectMongo(); err != nil {
+			log.Printf("error disconnecting MongoDB: %v", err)
+		}
+	}()
+
+	db, err := config.GetDatabase("job_board")
+	if err != nil {
+		log.Fatalf("Failed to get database: %v", err)
+	}
+
+	ctx := context.Background()
+	jobs := db.Collection("jobs")
+	jobTypes := db.Collection("jobtypes")
+
+	pending := bson.M{"job_type": bson.M{"$exists": true}, "job_type_id": bson.M{"$exists": false}}
+	var values []string
+	if err := jobs.Distinct(ctx, "job_type", pending).Decode(&values); err != nil {
+		log.Fatalf("Failed to read job types of jobs: %v", err)
+	}
+
+	byTitle := options.FindOne().SetCollation(&options.Collation{Locale: "en", Strength: 2})
+	unmatched := 0
+	for _, value := range values {
+		filter := bson.M{"job_type": value, "job_type_id": bson.M{"$exists": false}}
+		var jobType struct {
+			ID bson.ObjectID `bson:"_id"`
+		}
+		err := jobTypes.FindOne(ctx, bson.M{"title": value, "deleted_time": bson.M{"$exists": false}}, byTitle).Decode(&jobType)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			count, err := jobs.CountDocuments(ctx, filter)
+			if err != nil {
+				log.Fatalf("Failed to count jobs of type %q: %v", value, err)
+			}
+			fmt.Printf("  no job type titled %q (%d job(s))\n", value, count)
+			unmatched++
+			continue
+		}
+		if err != nil {
+			log.Fatalf("Failed to look up job type %q: %v", value, err)
+		}
+
+		if *dryRun {
+			count, err := jobs.CountDocuments(ctx, filter)
+			if err != nil {
+				log.Fatalf("Failed to count jobs of type %q: %v", value, err)
+			}
+			fmt.Printf("  %q → %s (%d job(s))\n", value, jobType.ID.Hex(), count)
+			continue
+		}
+		result, err := jobs.UpdateMany(ctx, filter, bson.M{
+			"$set":   bson.M{"job_type_id": jobType.ID},
+			"$unset": bson.M{"job_type": ""},
+		})
+		if err != nil {
+			log.Fatalf("Failed to migrate jobs of type %q: %v", value, err)
+		}
+		fmt.Printf("  %q → %s (%d job(s))\n", value, jobType.ID.Hex(), result.ModifiedCount)
+	}
+
+	if unmatched > 0 {
+		fmt.Printf("%d job type(s) could not be matched\n", unmatched)
+	}
+}
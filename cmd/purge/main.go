@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"go-mongodb-api/config"
+	"go-mongodb-api/repositories"
+	"log"
+	"sort"
+	"time"
+)
+
+// purge permanently removes records that were soft deleted longer ago than the retention window
+// (SOFT_DELETE_RETENTION_DAYS, or -days). Run it from cron or a scheduled job.
+func main() {
+	days := flag.Int("days", 0, "retention window in days (default: SOFT_DELETE_RETENTION_DAYS)")
+	flag.Parse()
+
+	// Initialize configuration
+	cfg, err := config.Init()
+	if err != nil {
+		log.Fatalf("Failed to initialize config: %v", err)
+	}
+
+	retention := cfg.SoftDeleteRetention
+	if *days > 0 {
+		retention = time.Duration(*days) * 24 * time.Hour
+	}
+
+	// Initialize MongoDB connection
+	_, err = config.InitMongo()
+	if err != nil {
+		log.Fatalf("Failed to initialize MongoDB: %v", err)
+	}
+	defer func() {
+		if err := config.DisconnectMongo(); err != nil {
+			log.Printf("error disconnecting MongoDB: %v", err)
+		}
+	}()
+
+	db, err := config.GetDatabase("job_board")
+	if err != nil {
+		log.Fatalf("Failed to get database: %v", err)
+	}
+
+	deleteRelations, err := repositories.DeleteRelations(cfg.DeletePolicies)
+	if err != nil {
+		log.Fatalf("Invalid DELETE_POLICIES configuration: %v", err)
+	}
+	deleter := repositories.NewDeleter(db, deleteRelations)
+
+	cutoff := time.Now().Add(-retention)
+	purged, err := deleter.Purge(context.Background(), cutoff)
+	if err != nil {
+		log.Fatalf("Purge failed: %v", err)
+	}
+
+	collections := make([]string, 0, len(purged))
+	for collection := range purged {
+		collections = append(collections, collection)
+	}
+	sort.Strings(collections)
+
+	fmt.Printf("Purged records deleted before %s\n", cutoff.Format(time.RFC3339))
+	for _, collection := range collections {
+		fmt.Printf("  %s: %d\n", collection, purged[collection])
+	}
+}
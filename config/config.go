@@ -1,6 +1,7 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
@@ -11,12 +12,43 @@ import (
 	"github.com/joho/godotenv"
 )
 
+// JWTKeyFile is a PEM encoded private key used to sign access tokens, identified by its kid
+type JWTKeyFile struct {
+	ID   string
+	Path string
+}
+
 // Config holds all application configuration
 type Config struct {
-	MongoURI  string
-	Port      string
-	Timeout   time.Duration
-	JWTSecret string
+	MongoURI             string
+	Port                 string
+	Timeout              time.Duration
+	JWTSecret            string
+	JWTKeyFiles          []JWTKeyFile
+	JWTSigningKeyID      string
+	AccessTokenTTL       time.Duration
+	RefreshTokenTTL      time.Duration
+	PasswordResetTTL     time.Duration
+	VerificationTTL      time.Duration
+	InvitationTTL        time.Duration
+	MaxLoginAttempts     int
+	MaxIPLoginAttempts   int
+	LoginLockoutBase     time.Duration
+	LoginLockoutMax      time.Duration
+	TrustProxyHeaders    bool
+	RequireMFARoles      []string
+	MFAIssuer            string
+	RequireVerifiedRoles []string
+	AppBaseURL           string
+	Notifier             string
+	NotifierFile         string
+	JobSweepInterval     time.Duration
+	DeletePolicies       map[string]string
+	SoftDeleteRetention  time.Duration
+	JobSalaryBands       []int
+	SalaryBaseCurrency   string
+	ReapplyCooldown      time.Duration
+	RejectionReasons     map[string]string
 }
 
 var appConfig *Config
@@ -38,6 +70,129 @@ func isValidPort(port string) error {
 	return nil
 }
 
+// durationFromEnv reads a positive integer from the environment and multiplies it by unit,
+// falling back to def when the variable is unset or invalid
+func durationFromEnv(key string, def, unit time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		log.Printf("warning: invalid %s value '%s', using default %v", key, value, def)
+		return def
+	}
+	return time.Duration(n) * unit
+}
+
+// intFromEnv reads a positive integer from the environment, falling back to def when the
+// variable is unset or invalid
+func intFromEnv(key string, def int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		log.Printf("warning: invalid %s value '%s', using default %d", key, value, def)
+		return def
+	}
+	return n
+}
+
+// parseKeyFiles parses a comma separated list of kid=path entries
+func parseKeyFiles(value string) ([]JWTKeyFile, error) {
+	var files []JWTKeyFile
+	for _, item := range splitList(value) {
+		id, path, ok := strings.Cut(item, "=")
+		id, path = strings.TrimSpace(id), strings.TrimSpace(path)
+		if !ok || id == "" || path == "" {
+			return nil, fmt.Errorf("invalid entry '%s': expected kid=path", item)
+		}
+		files = append(files, JWTKeyFile{ID: id, Path: path})
+	}
+	return files, nil
+}
+
+// parseDeletePolicies parses a comma separated list of relation=policy entries
+func parseDeletePolicies(value string) (map[string]string, error) {
+	policies := map[string]string{}
+	for _, item := range splitList(value) {
+		relation, policy, ok := strings.Cut(item, "=")
+		relation, policy = strings.TrimSpace(relation), strings.TrimSpace(policy)
+		if !ok || relation == "" || policy == "" {
+			return nil, fmt.Errorf("invalid entry '%s': expected relation=policy", item)
+		}
+		policies[relation] = policy
+	}
+	return policies, nil
+}
+
+// parseSalaryBands parses a comma separated list of ascending, non-negative salary band lower bounds
+func parseSalaryBands(value string) ([]int, error) {
+	var bands []int
+	for _, item := range splitList(value) {
+		n, err := strconv.Atoi(item)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid entry '%s': expected a non-negative integer", item)
+		}
+		if len(bands) > 0 && n <= bands[len(bands)-1] {
+			return nil, fmt.Errorf("invalid entry '%s': bands must be in ascending order", item)
+		}
+		bands = append(bands, n)
+	}
+	return bands, nil
+}
+
+// loadRejectionReasons reads a JSON object of rejection reason texts by key from path. An empty path
+// returns no reasons.
+func loadRejectionReasons(path string) (map[string]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var reasons map[string]string
+	if err := json.Unmarshal(data, &reasons); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(reasons) == 0 {
+		return nil, fmt.Errorf("%s: no reasons", path)
+	}
+	for key, text := range reasons {
+		if strings.TrimSpace(key) == "" || strings.TrimSpace(text) == "" {
+			return nil, fmt.Errorf("%s: reason '%s' needs a key and a text", path, key)
+		}
+	}
+	return reasons, nil
+}
+
+// isCurrencyCode reports whether value has the shape of an ISO 4217 code: three upper-case letters
+func isCurrencyCode(value string) bool {
+	if len(value) != 3 {
+		return false
+	}
+	for _, c := range value {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// splitList splits a comma separated value into trimmed, non-empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // Init loads configuration from environment variables
 func Init() (*Config, error) {
 	// Load .env file
@@ -75,20 +230,150 @@ func Init() (*Config, error) {
 		return nil, fmt.Errorf("invalid MONGO_URI: must start with 'mongodb://' or 'mongodb+srv://'")
 	}
 
-	// Load and validate JWT secret
+	// Load asymmetric signing keys. The active key defaults to the last one listed.
+	jwtKeyFiles, err := parseKeyFiles(os.Getenv("JWT_KEYS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_KEYS configuration: %w", err)
+	}
+	jwtSigningKeyID := os.Getenv("JWT_SIGNING_KEY_ID")
+
+	// Load and validate JWT secret. It is only required when no signing keys are configured;
+	// alongside keys it keeps HS256 tokens valid while migrating.
 	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		return nil, fmt.Errorf("JWT_SECRET not found in environment variables")
+	if jwtSecret == "" && len(jwtKeyFiles) == 0 {
+		return nil, fmt.Errorf("JWT_SECRET or JWT_KEYS must be set")
 	}
-	if len(jwtSecret) < 32 {
+	if jwtSecret != "" && len(jwtSecret) < 32 {
 		return nil, fmt.Errorf("JWT_SECRET must be at least 32 characters long")
 	}
 
+	// Load token lifetimes
+	accessTokenTTL := durationFromEnv("ACCESS_TOKEN_TTL_MINUTES", 15*time.Minute, time.Minute)
+	refreshTokenTTL := durationFromEnv("REFRESH_TOKEN_TTL_HOURS", 7*24*time.Hour, time.Hour)
+	passwordResetTTL := durationFromEnv("PASSWORD_RESET_TTL_MINUTES", time.Hour, time.Minute)
+	verificationTTL := durationFromEnv("EMAIL_VERIFICATION_TTL_HOURS", 48*time.Hour, time.Hour)
+	invitationTTL := durationFromEnv("INVITATION_TTL_HOURS", 72*time.Hour, time.Hour)
+
+	// Load login lockout policy
+	maxLoginAttempts := intFromEnv("LOGIN_MAX_ATTEMPTS", 5)
+	maxIPLoginAttempts := intFromEnv("LOGIN_IP_MAX_ATTEMPTS", 20)
+	loginLockoutBase := durationFromEnv("LOGIN_LOCKOUT_MINUTES", time.Minute, time.Minute)
+	loginLockoutMax := durationFromEnv("LOGIN_LOCKOUT_MAX_MINUTES", time.Hour, time.Minute)
+
+	// Only trust X-Forwarded-For / X-Real-IP when running behind a proxy that sets them
+	trustProxyHeaders := os.Getenv("TRUST_PROXY_HEADERS") == "true"
+
+	// Load roles that must verify their email before posting jobs or applications.
+	// An explicitly empty value disables the policy.
+	requireVerifiedRoles := []string{"candidate", "recruiter"}
+	if value, ok := os.LookupEnv("REQUIRE_VERIFIED_ROLES"); ok {
+		requireVerifiedRoles = splitList(value)
+	}
+
+	// Load roles whose sessions must pass two-factor authentication (for example "admin").
+	// Unset or empty disables the policy.
+	requireMFARoles := splitList(os.Getenv("REQUIRE_MFA_ROLES"))
+
+	// Load issuer name shown in authenticator apps
+	mfaIssuer := os.Getenv("MFA_ISSUER")
+	if mfaIssuer == "" {
+		mfaIssuer = "Jobs API"
+	}
+
+	// Load base URL used in links sent to users
+	appBaseURL := os.Getenv("APP_BASE_URL")
+	if appBaseURL == "" {
+		appBaseURL = "http://localhost:" + port
+	}
+
+	// Load and validate notifier
+	notifier := os.Getenv("NOTIFIER")
+	if notifier == "" {
+		notifier = "log"
+	}
+	if notifier != "log" && notifier != "file" {
+		return nil, fmt.Errorf("invalid NOTIFIER '%s': must be 'log' or 'file'", notifier)
+	}
+	notifierFile := os.Getenv("NOTIFIER_FILE")
+	if notifierFile == "" {
+		notifierFile = "outbox.log"
+	}
+
+	// Load how often active jobs past their closes_at deadline are closed
+	jobSweepInterval := durationFromEnv("JOB_SWEEP_INTERVAL_MINUTES", 5*time.Minute, time.Minute)
+
+	// Load delete policy overrides, e.g. "jobs.applications=restrict". Relations and policies are
+	// checked when the repositories are set up.
+	deletePolicies, err := parseDeletePolicies(os.Getenv("DELETE_POLICIES"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DELETE_POLICIES configuration: %w", err)
+	}
+
+	// Load how long soft-deleted records are kept before the purge command removes them
+	softDeleteRetention := durationFromEnv("SOFT_DELETE_RETENTION_DAYS", 30*24*time.Hour, 24*time.Hour)
+
+	// Load how long candidates wait to reapply to a job after withdrawing or being rejected; 0 lets
+	// them reapply right away
+	reapplyCooldown := 30 * 24 * time.Hour
+	if value := os.Getenv("REAPPLY_COOLDOWN_DAYS"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 0 {
+			return nil, fmt.Errorf("invalid REAPPLY_COOLDOWN_DAYS '%s': expected a number of days, 0 or more", value)
+		}
+		reapplyCooldown = time.Duration(days) * 24 * time.Hour
+	}
+
+	// Load the reasons applications can be rejected with in bulk; unset keeps the service defaults
+	rejectionReasons, err := loadRejectionReasons(os.Getenv("REJECTION_REASONS_FILE"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid REJECTION_REASONS_FILE: %w", err)
+	}
+
+	// Load the currency job salaries are normalized to
+	salaryBaseCurrency := strings.ToUpper(os.Getenv("SALARY_BASE_CURRENCY"))
+	if salaryBaseCurrency == "" {
+		salaryBaseCurrency = "USD"
+	}
+	if !isCurrencyCode(salaryBaseCurrency) {
+		return nil, fmt.Errorf("invalid SALARY_BASE_CURRENCY '%s': expected a three-letter ISO 4217 code", salaryBaseCurrency)
+	}
+
+	// Load the lower bounds of the salary facet bands; empty keeps the service defaults
+	jobSalaryBands, err := parseSalaryBands(os.Getenv("JOB_SALARY_BANDS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_SALARY_BANDS configuration: %w", err)
+	}
+
 	appConfig = &Config{
-		MongoURI:  mongoURI,
-		Port:      port,
-		Timeout:   timeout,
-		JWTSecret: jwtSecret,
+		MongoURI:             mongoURI,
+		Port:                 port,
+		Timeout:              timeout,
+		JWTSecret:            jwtSecret,
+		JWTKeyFiles:          jwtKeyFiles,
+		JWTSigningKeyID:      jwtSigningKeyID,
+		AccessTokenTTL:       accessTokenTTL,
+		RefreshTokenTTL:      refreshTokenTTL,
+		PasswordResetTTL:     passwordResetTTL,
+		VerificationTTL:      verificationTTL,
+		InvitationTTL:        invitationTTL,
+		MaxLoginAttempts:     maxLoginAttempts,
+		MaxIPLoginAttempts:   maxIPLoginAttempts,
+		LoginLockoutBase:     loginLockoutBase,
+		LoginLockoutMax:      loginLockoutMax,
+		TrustProxyHeaders:    trustProxyHeaders,
+		RequireMFARoles:      requireMFARoles,
+		MFAIssuer:            mfaIssuer,
+		RequireVerifiedRoles: requireVerifiedRoles,
+		AppBaseURL:           appBaseURL,
+		Notifier:             notifier,
+		NotifierFile:         notifierFile,
+		JobSweepInterval:     jobSweepInterval,
+		DeletePolicies:       deletePolicies,
+		SoftDeleteRetention:  softDeleteRetention,
+		JobSalaryBands:       jobSalaryBands,
+		SalaryBaseCurrency:   salaryBaseCurrency,
+		ReapplyCooldown:      reapplyCooldown,
+		RejectionReasons:     rejectionReasons,
 	}
 
 	log.Printf("configuration loaded: port=%s, timeout=%v", port, timeout)
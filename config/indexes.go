@@ -2,16 +2,34 @@ package config
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"go-mongodb-api/models"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
+// caseInsensitive is the collation of the name indexes used to match names ignoring case
+var caseInsensitive = &options.Collation{Locale: "en", Strength: 2}
+
+// replaced lists, by collection, indexes of earlier versions that EnsureIndexes drops. One
+// application per job and candidate (job_user_unique) became one per attempt, so that candidates can
+// reapply, and the other unique indexes of soft-deleted collections now include deleted_time.
+var replaced = map[string][]string{
+	"users":           {"email_unique"},
+	"applications":    {"job_user_unique"},
+	"skills":          {"name_unique"},
+	"jobcategories":   {"name_unique"},
+	"candidateskills": {"user_skill_unique"},
+	"jobskills":       {"job_skill_unique"},
+}
+
 // EnsureIndexes creates all required indexes across every collection.
 // It is idempotent: running it multiple times does not return an error for
-// indexes that already exist.
+// indexes that already exist. The unique indexes of soft-deleted collections
+// include deleted_time, so only live documents (where it is missing) clash.
 func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
 	specs := []struct {
 		collection string
@@ -21,8 +39,8 @@ func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
 			collection: "users",
 			models: []mongo.IndexModel{
 				{
-					Keys:    bson.D{{Key: "email", Value: 1}},
-					Options: options.Index().SetUnique(true).SetName("email_unique"),
+					Keys:    bson.D{{Key: "email", Value: 1}, {Key: "deleted_time", Value: 1}},
+					Options: options.Index().SetUnique(true).SetName("email_live_unique"),
 				},
 				{
 					Keys:    bson.D{{Key: "role", Value: 1}},
@@ -41,10 +59,51 @@ func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
 					Keys:    bson.D{{Key: "category_id", Value: 1}},
 					Options: options.Index().SetName("category_id"),
 				},
+				{
+					Keys:    bson.D{{Key: "job_type_id", Value: 1}},
+					Options: options.Index().SetName("job_type_id"),
+				},
+				{
+					Keys:    bson.D{{Key: "country_id", Value: 1}},
+					Options: options.Index().SetName("country_id"),
+				},
+				{
+					Keys:    bson.D{{Key: "education_level_id", Value: 1}},
+					Options: options.Index().SetName("education_level_id"),
+				},
+				{
+					Keys:    bson.D{{Key: "location_availability_id", Value: 1}},
+					Options: options.Index().SetName("location_availability_id"),
+				},
 				{
 					Keys:    bson.D{{Key: "status", Value: 1}},
 					Options: options.Index().SetName("status"),
 				},
+				{
+					Keys:    bson.D{{Key: "status", Value: 1}, {Key: "closes_at", Value: 1}},
+					Options: options.Index().SetName("status_closes_at"),
+				},
+				{
+					Keys:    bson.D{{Key: "salary_currency", Value: 1}},
+					Options: options.Index().SetName("salary_currency"),
+				},
+				{
+					// Full-text search over GET /jobs?q=, title matches rank highest
+					Keys: bson.D{
+						{Key: "title", Value: "text"},
+						{Key: "description", Value: "text"},
+						{Key: "location", Value: "text"},
+					},
+					Options: options.Index().
+						SetWeights(bson.D{{Key: "title", Value: 10}, {Key: "location", Value: 5}, {Key: "description", Value: 1}}).
+						SetDefaultLanguage("english").
+						SetName("jobs_text"),
+				},
+				{
+					// Distance searches over GET /jobs?lat=&lng=
+					Keys:    bson.D{{Key: "coordinates", Value: "2dsphere"}},
+					Options: options.Index().SetName("coordinates_2dsphere"),
+				},
 				{
 					Keys:    bson.D{{Key: "created_time", Value: -1}},
 					Options: options.Index().SetName("created_time_desc"),
@@ -70,8 +129,9 @@ func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
 					Keys: bson.D{
 						{Key: "job_id", Value: 1},
 						{Key: "user_id", Value: 1},
+						{Key: "attempt", Value: -1},
 					},
-					Options: options.Index().SetUnique(true).SetName("job_user_unique"),
+					Options: options.Index().SetUnique(true).SetName("job_user_attempt_unique"),
 				},
 			},
 		},
@@ -79,8 +139,8 @@ func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
 			collection: "skills",
 			models: []mongo.IndexModel{
 				{
-					Keys:    bson.D{{Key: "name", Value: 1}},
-					Options: options.Index().SetUnique(true).SetName("name_unique"),
+					Keys:    bson.D{{Key: "name", Value: 1}, {Key: "deleted_time", Value: 1}},
+					Options: options.Index().SetUnique(true).SetName("name_live_unique"),
 				},
 			},
 		},
@@ -88,8 +148,8 @@ func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
 			collection: "jobcategories",
 			models: []mongo.IndexModel{
 				{
-					Keys:    bson.D{{Key: "name", Value: 1}},
-					Options: options.Index().SetUnique(true).SetName("name_unique"),
+					Keys:    bson.D{{Key: "name", Value: 1}, {Key: "deleted_time", Value: 1}},
+					Options: options.Index().SetUnique(true).SetName("name_live_unique"),
 				},
 			},
 		},
@@ -100,12 +160,17 @@ func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
 					Keys:    bson.D{{Key: "user_id", Value: 1}},
 					Options: options.Index().SetName("user_id"),
 				},
+				{
+					Keys:    bson.D{{Key: "skill_id", Value: 1}},
+					Options: options.Index().SetName("skill_id"),
+				},
 				{
 					Keys: bson.D{
 						{Key: "user_id", Value: 1},
 						{Key: "skill_id", Value: 1},
+						{Key: "deleted_time", Value: 1},
 					},
-					Options: options.Index().SetUnique(true).SetName("user_skill_unique"),
+					Options: options.Index().SetUnique(true).SetName("user_skill_live_unique"),
 				},
 			},
 		},
@@ -116,12 +181,138 @@ func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
 					Keys:    bson.D{{Key: "job_id", Value: 1}},
 					Options: options.Index().SetName("job_id"),
 				},
+				{
+					Keys:    bson.D{{Key: "skill_id", Value: 1}},
+					Options: options.Index().SetName("skill_id"),
+				},
 				{
 					Keys: bson.D{
 						{Key: "job_id", Value: 1},
 						{Key: "skill_id", Value: 1},
+						{Key: "deleted_time", Value: 1},
 					},
-					Options: options.Index().SetUnique(true).SetName("job_skill_unique"),
+					Options: options.Index().SetUnique(true).SetName("job_skill_live_unique"),
+				},
+			},
+		},
+		{
+			collection: "sessions",
+			models: []mongo.IndexModel{
+				{
+					Keys:    bson.D{{Key: "token_hash", Value: 1}},
+					Options: options.Index().SetUnique(true).SetName("token_hash_unique"),
+				},
+				{
+					Keys:    bson.D{{Key: "previous_hashes", Value: 1}},
+					Options: options.Index().SetName("previous_hashes"),
+				},
+				{
+					Keys:    bson.D{{Key: "user_id", Value: 1}},
+					Options: options.Index().SetName("user_id"),
+				},
+				{
+					// Expired sessions are removed by MongoDB's TTL monitor
+					Keys:    bson.D{{Key: "expires_time", Value: 1}},
+					Options: options.Index().SetExpireAfterSeconds(0).SetName("expires_time_ttl"),
+				},
+			},
+		},
+		{
+			collection: "usertokens",
+			models: []mongo.IndexModel{
+				{
+					Keys:    bson.D{{Key: "token_hash", Value: 1}},
+					Options: options.Index().SetUnique(true).SetName("token_hash_unique"),
+				},
+				{
+					Keys: bson.D{
+						{Key: "user_id", Value: 1},
+						{Key: "purpose", Value: 1},
+					},
+					Options: options.Index().SetName("user_purpose"),
+				},
+				{
+					Keys: bson.D{
+						{Key: "email", Value: 1},
+						{Key: "purpose", Value: 1},
+					},
+					Options: options.Index().SetName("email_purpose"),
+				},
+				{
+					// Expired tokens are removed by MongoDB's TTL monitor
+					Keys:    bson.D{{Key: "expires_time", Value: 1}},
+					Options: options.Index().SetExpireAfterSeconds(0).SetName("expires_time_ttl"),
+				},
+			},
+		},
+		{
+			collection: "loginattempts",
+			models: []mongo.IndexModel{
+				{
+					Keys: bson.D{
+						{Key: "scope", Value: 1},
+						{Key: "value", Value: 1},
+					},
+					Options: options.Index().SetUnique(true).SetName("scope_value_unique"),
+				},
+				{
+					// Counters are removed by MongoDB's TTL monitor once the attempt window has passed
+					Keys:    bson.D{{Key: "expires_time", Value: 1}},
+					Options: options.Index().SetExpireAfterSeconds(0).SetName("expires_time_ttl"),
+				},
+			},
+		},
+		{
+			// Geocoding matches city and country names ignoring case, through the same collation
+			collection: "cities",
+			models: []mongo.IndexModel{
+				{
+					Keys:    bson.D{{Key: "name", Value: 1}, {Key: "country_id", Value: 1}},
+					Options: options.Index().SetUnique(true).SetCollation(caseInsensitive).SetName("name_country_unique"),
+				},
+				{
+					Keys:    bson.D{{Key: "country_id", Value: 1}},
+					Options: options.Index().SetName("country_id"),
+				},
+			},
+		},
+		{
+			collection: "countries",
+			models: []mongo.IndexModel{
+				{
+					Keys:    bson.D{{Key: "name", Value: 1}},
+					Options: options.Index().SetCollation(caseInsensitive).SetName("name_ci"),
+				},
+			},
+		},
+		{
+			collection: "exchangerates",
+			models: []mongo.IndexModel{
+				{
+					Keys:    bson.D{{Key: "currency", Value: 1}},
+					Options: options.Index().SetUnique(true).SetName("currency_unique"),
+				},
+			},
+		},
+		{
+			collection: "pipelinetemplates",
+			models: []mongo.IndexModel{
+				{
+					Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "name", Value: 1}},
+					Options: options.Index().SetName("user_name"),
+				},
+				{
+					Keys:    bson.D{{Key: "company_name", Value: 1}, {Key: "name", Value: 1}},
+					Options: options.Index().SetName("company_name"),
+				},
+			},
+		},
+		{
+			collection: "apikeys",
+			models: []mongo.IndexModel{
+				{
+					Keys:    bson.D{{Key: "key_hash", Value: 1}},
+					Options: options.Index().SetUnique(true).SetName("key_hash_unique"),
 				},
 			},
 		},
@@ -129,10 +320,37 @@ func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
 
 	for _, spec := range specs {
 		col := db.Collection(spec.collection)
+		for _, name := range replaced[spec.collection] {
+			if err := dropIndexIfExists(ctx, col, name); err != nil {
+				return fmt.Errorf("indexes for %q: %w", spec.collection, err)
+			}
+		}
 		if _, err := col.Indexes().CreateMany(ctx, spec.models); err != nil {
 			return fmt.Errorf("indexes for %q: %w", spec.collection, err)
 		}
 	}
 
+	// The purge command finds soft-deleted documents through a sparse index on deleted_time
+	for _, collection := range models.SoftDeleteCollections {
+		index := mongo.IndexModel{
+			Keys:    bson.D{{Key: "deleted_time", Value: 1}},
+			Options: options.Index().SetSparse(true).SetName("deleted_time"),
+		}
+		if _, err := db.Collection(collection).Indexes().CreateOne(ctx, index); err != nil {
+			return fmt.Errorf("indexes for %q: %w", collection, err)
+		}
+	}
+
 	return nil
 }
+
+// dropIndexIfExists drops the named index of a collection, doing nothing when either does not exist
+func dropIndexIfExists(ctx context.Context, col *mongo.Collection, name string) error {
+	err := col.Indexes().DropOne(ctx, name)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && (cmdErr.Code == 26 || cmdErr.Code == 27) {
+		// NamespaceNotFound, IndexNotFound
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-mongodb-api/helpers"
+	"go-mongodb-api/interfaces"
+	"go-mongodb-api/models"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type APIKeyHandler struct {
+	service interfaces.APIKeyService
+}
+
+func NewAPIKeyHandler(service interfaces.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: service}
+}
+
+type createAPIKeyRequest struct {
+	Name        string     `json:"name"`
+	Scopes      []string   `json:"scopes"`
+	ExpiresTime *time.Time `json:"expires_time"`
+}
+
+// GetAllAPIKeys handles GET /apikeys request
+func (h *APIKeyHandler) GetAllAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.service.GetAllAPIKeys(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to retrieve API keys", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(keys); err != nil {
+		log.Printf("error encoding response: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// CreateAPIKey handles POST /apikeys request. The response is the only time the plaintext key is shown.
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req createAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	key := models.APIKey{
+		Name:        req.Name,
+		Scopes:      req.Scopes,
+		ExpiresTime: req.ExpiresTime,
+	}
+
+	validationErrors := helpers.ValidateStruct(key)
+	if key.ExpiresTime != nil && !key.ExpiresTime.After(time.Now()) {
+		validationErrors = append(validationErrors, helpers.ValidationError{
+			Field:   "expires_time",
+			Message: "must be in the future",
+		})
+	}
+	if len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
+		return
+	}
+
+	created, err := h.service.CreateAPIKey(r.Context(), &key)
+	if err != nil {
+		http.Error(w, "Failed to create API key", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(created); err != nil {
+		log.Printf("error encoding response: %v", err)
+		return
+	}
+}
+
+// RevokeAPIKey handles DELETE /apikeys/{id} request
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	keyID := chi.URLParam(r, "id")
+
+	if err := h.service.RevokeAPIKey(r.Context(), keyID); err != nil {
+		http.Error(w, "API key not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,164 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-mongodb-api/handlers"
+	"go-mongodb-api/mocks"
+	"go-mongodb-api/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestAPIKeyHandler_GetAllAPIKeys_Success(t *testing.T) {
+	mockSvc := new(mocks.MockAPIKeyService)
+	h := handlers.NewAPIKeyHandler(mockSvc)
+
+	keys := []models.APIKey{{ID: bson.NewObjectID(), Name: "ATS sync", Prefix: "jak_abcdefgh", KeyHash: "hash"}}
+	mockSvc.On("GetAllAPIKeys", mock.Anything).Return(keys, nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/apikeys", nil)
+	w := httptest.NewRecorder()
+
+	h.GetAllAPIKeys(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "hash")
+	mockSvc.AssertExpectations(t)
+}
+
+func TestAPIKeyHandler_GetAllAPIKeys_Error(t *testing.T) {
+	mockSvc := new(mocks.MockAPIKeyService)
+	h := handlers.NewAPIKeyHandler(mockSvc)
+
+	mockSvc.On("GetAllAPIKeys", mock.Anything).Return([]models.APIKey(nil), errors.New("db error"))
+
+	r := httptest.NewRequest(http.MethodGet, "/apikeys", nil)
+	w := httptest.NewRecorder()
+
+	h.GetAllAPIKeys(w, r)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestAPIKeyHandler_CreateAPIKey_Success(t *testing.T) {
+	mockSvc := new(mocks.MockAPIKeyService)
+	h := handlers.NewAPIKeyHandler(mockSvc)
+
+	created := &models.CreatedAPIKey{
+		APIKey: models.APIKey{ID: bson.NewObjectID(), Name: "ATS sync", Prefix: "jak_abcdefgh", Scopes: []string{models.ScopeJobsRead}},
+		Key:    "jak_abcdefghsecret",
+	}
+	mockSvc.On("CreateAPIKey", mock.Anything, mock.MatchedBy(func(k *models.APIKey) bool {
+		return k.Name == "ATS sync" && len(k.Scopes) == 1 && k.Scopes[0] == models.ScopeJobsRead
+	})).Return(created, nil)
+
+	body := []byte(`{"name":"ATS sync","scopes":["jobs:read"]}`)
+	r := httptest.NewRequest(http.MethodPost, "/apikeys", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	h.CreateAPIKey(w, r)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "jak_abcdefghsecret", response["key"])
+	assert.Equal(t, "jak_abcdefgh", response["prefix"])
+	mockSvc.AssertExpectations(t)
+}
+
+func TestAPIKeyHandler_CreateAPIKey_UnknownScope(t *testing.T) {
+	mockSvc := new(mocks.MockAPIKeyService)
+	h := handlers.NewAPIKeyHandler(mockSvc)
+
+	body := []byte(`{"name":"ATS sync","scopes":["users:write"]}`)
+	r := httptest.NewRequest(http.MethodPost, "/apikeys", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	h.CreateAPIKey(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "CreateAPIKey", mock.Anything, mock.Anything)
+}
+
+func TestAPIKeyHandler_CreateAPIKey_NoScopes(t *testing.T) {
+	mockSvc := new(mocks.MockAPIKeyService)
+	h := handlers.NewAPIKeyHandler(mockSvc)
+
+	body := []byte(`{"name":"ATS sync","scopes":[]}`)
+	r := httptest.NewRequest(http.MethodPost, "/apikeys", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	h.CreateAPIKey(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAPIKeyHandler_CreateAPIKey_ExpiryInPast(t *testing.T) {
+	mockSvc := new(mocks.MockAPIKeyService)
+	h := handlers.NewAPIKeyHandler(mockSvc)
+
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	body := []byte(`{"name":"ATS sync","scopes":["jobs:read"],"expires_time":"` + past + `"}`)
+	r := httptest.NewRequest(http.MethodPost, "/apikeys", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	h.CreateAPIKey(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "expires_time")
+}
+
+func TestAPIKeyHandler_CreateAPIKey_InvalidBody(t *testing.T) {
+	mockSvc := new(mocks.MockAPIKeyService)
+	h := handlers.NewAPIKeyHandler(mockSvc)
+
+	r := httptest.NewRequest(http.MethodPost, "/apikeys", bytes.NewReader([]byte("not-json")))
+	w := httptest.NewRecorder()
+
+	h.CreateAPIKey(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAPIKeyHandler_RevokeAPIKey_Success(t *testing.T) {
+	mockSvc := new(mocks.MockAPIKeyService)
+	h := handlers.NewAPIKeyHandler(mockSvc)
+
+	id := bson.NewObjectID()
+	mockSvc.On("RevokeAPIKey", mock.Anything, id.Hex()).Return(nil)
+
+	r := httptest.NewRequest(http.MethodDelete, "/apikeys/"+id.Hex(), nil)
+	r = addChiURLParam(r, "id", id.Hex())
+	w := httptest.NewRecorder()
+
+	h.RevokeAPIKey(w, r)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestAPIKeyHandler_RevokeAPIKey_NotFound(t *testing.T) {
+	mockSvc := new(mocks.MockAPIKeyService)
+	h := handlers.NewAPIKeyHandler(mockSvc)
+
+	id := bson.NewObjectID()
+	mockSvc.On("RevokeAPIKey", mock.Anything, id.Hex()).Return(mongo.ErrNoDocuments)
+
+	r := httptest.NewRequest(http.MethodDelete, "/apikeys/"+id.Hex(), nil)
+	r = addChiURLParam(r, "id", id.Hex())
+	w := httptest.NewRecorder()
+
+	h.RevokeAPIKey(w, r)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
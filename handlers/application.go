@@ -2,13 +2,15 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"go-mongodb-api/helpers"
 	"go-mongodb-api/interfaces"
 	"go-mongodb-api/models"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
-	"time"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -17,6 +19,17 @@ type ApplicationHandler struct {
 	service interfaces.ApplicationService
 }
 
+// stagedApplicationsResponse is the body of a job's application listing with stage counts
+type stagedApplicationsResponse struct {
+	Data        []models.Application `json:"data"`
+	StageCounts []models.StageCount  `json:"stage_counts"`
+}
+
+type updateApplicationStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=applied under_review rejected accepted withdrawn"`
+	Note   string `json:"note" validate:"max=2000"`
+}
+
 // NewApplicationHandler creates a new application handler
 func NewApplicationHandler(service interfaces.ApplicationService) *ApplicationHandler {
 	return &ApplicationHandler{service: service}
@@ -50,6 +63,15 @@ func (h *ApplicationHandler) GetAllApplications(w http.ResponseWriter, r *http.R
 		"job_id":  r.URL.Query().Get("job_id"),
 		"user_id": r.URL.Query().Get("user_id"),
 	}
+	if includeDeleted(r) {
+		filters[models.FilterIncludeDeleted] = "true"
+	}
+
+	expand, message := parseExpand(r, models.ApplicationExpansions)
+	if message != "" {
+		http.Error(w, message, http.StatusBadRequest)
+		return
+	}
 
 	sort := r.URL.Query().Get("sort")
 	order := r.URL.Query().Get("order")
@@ -60,6 +82,9 @@ func (h *ApplicationHandler) GetAllApplications(w http.ResponseWriter, r *http.R
 		http.Error(w, "Failed to retrieve applications", http.StatusInternalServerError)
 		return
 	}
+	if !h.expandApplications(w, r, applications, expand) {
+		return
+	}
 
 	// Build paginated response
 	pagination := helpers.NewPagination(page, limit)
@@ -83,11 +108,22 @@ func (h *ApplicationHandler) GetApplicationByID(w http.ResponseWriter, r *http.R
 	ctx := r.Context()
 	applicationID := chi.URLParam(r, "id")
 
+	expand, message := parseExpand(r, models.ApplicationExpansions)
+	if message != "" {
+		http.Error(w, message, http.StatusBadRequest)
+		return
+	}
+
 	application, err := h.service.GetApplicationByID(ctx, applicationID)
 	if err != nil {
-		http.Error(w, "Application not found", http.StatusNotFound)
+		writeServiceError(w, err, "Application not found", http.StatusNotFound)
 		return
 	}
+	applications := []models.Application{*application}
+	if !h.expandApplications(w, r, applications, expand) {
+		return
+	}
+	application = &applications[0]
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(application); err != nil {
@@ -102,14 +138,34 @@ func (h *ApplicationHandler) GetApplicationsByJobID(w http.ResponseWriter, r *ht
 	ctx := r.Context()
 	jobID := chi.URLParam(r, "jobId")
 
+	expand, message := parseExpand(r, models.ApplicationExpansions)
+	if message != "" {
+		http.Error(w, message, http.StatusBadRequest)
+		return
+	}
+
 	applications, err := h.service.GetApplicationsByJobID(ctx, jobID)
 	if err != nil {
-		http.Error(w, "Failed to retrieve applications", http.StatusInternalServerError)
+		writeServiceError(w, err, "Failed to retrieve applications", http.StatusInternalServerError)
+		return
+	}
+	if !h.expandApplications(w, r, applications, expand) {
 		return
 	}
+	var response interface{} = applications
+
+	// Kanban boards also get the number of applications in each pipeline stage
+	if r.URL.Query().Get(models.FilterStageCounts) == "true" {
+		stageCounts, err := h.service.GetApplicationStageCounts(ctx, jobID)
+		if err != nil {
+			writeServiceError(w, err, "Failed to count applications by stage", http.StatusInternalServerError)
+			return
+		}
+		response = stagedApplicationsResponse{Data: applications, StageCounts: stageCounts}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(applications); err != nil {
+	if err := json.NewEncoder(w).Encode(response); err != nil {
 		log.Printf("error encoding response: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
@@ -121,9 +177,18 @@ func (h *ApplicationHandler) GetApplicationsByUserID(w http.ResponseWriter, r *h
 	ctx := r.Context()
 	userID := chi.URLParam(r, "userId")
 
+	expand, message := parseExpand(r, models.ApplicationExpansions)
+	if message != "" {
+		http.Error(w, message, http.StatusBadRequest)
+		return
+	}
+
 	applications, err := h.service.GetApplicationsByUserID(ctx, userID)
 	if err != nil {
-		http.Error(w, "Failed to retrieve applications", http.StatusInternalServerError)
+		writeServiceError(w, err, "Failed to retrieve applications", http.StatusInternalServerError)
+		return
+	}
+	if !h.expandApplications(w, r, applications, expand) {
 		return
 	}
 
@@ -135,6 +200,19 @@ func (h *ApplicationHandler) GetApplicationsByUserID(w http.ResponseWriter, r *h
 	}
 }
 
+// expandApplications embeds the requested references into applications. It writes the error
+// response and returns false when they cannot be resolved.
+func (h *ApplicationHandler) expandApplications(w http.ResponseWriter, r *http.Request, applications []models.Application, expand []string) bool {
+	if len(expand) == 0 {
+		return true
+	}
+	if err := h.service.ExpandApplications(r.Context(), applications, expand); err != nil {
+		http.Error(w, "Failed to expand applications", http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
 // CreateApplication handles POST /applications request
 func (h *ApplicationHandler) CreateApplication(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -146,6 +224,17 @@ func (h *ApplicationHandler) CreateApplication(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	application.UserID = ownerFromClaims(ctx, application.UserID)
+
+	// Applications always start as applied; later statuses go through their transitions
+	application.Status = models.ApplicationStatusApplied
+	application.RecruiterNote = ""
+	application.RejectionReason = ""
+	application.WithdrawalReason = ""
+	application.ClosedTime = nil
+	application.Tags = nil
+	application.CoverLetter = strings.TrimSpace(application.CoverLetter)
+
 	// Validate request body
 	validationErrors := helpers.ValidateStruct(application)
 	if len(validationErrors) > 0 {
@@ -157,14 +246,9 @@ func (h *ApplicationHandler) CreateApplication(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	application.AppliedTime = time.Now()
-	application.UpdatedTime = time.Now()
-	application.CreatedBy = "system"
-	application.UpdatedBy = "system"
-
 	err = h.service.CreateApplication(ctx, &application)
 	if err != nil {
-		http.Error(w, "Failed to create application", http.StatusInternalServerError)
+		writeServiceError(w, err, "Failed to create application", http.StatusInternalServerError)
 		return
 	}
 
@@ -181,23 +265,182 @@ func (h *ApplicationHandler) UpdateApplicationStatus(w http.ResponseWriter, r *h
 	ctx := r.Context()
 	applicationID := chi.URLParam(r, "id")
 
-	var request struct {
-		Status string `json:"status"`
-	}
+	var request updateApplicationStatusRequest
 	err := json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	if validationErrors := helpers.ValidateStruct(request); len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
+		return
+	}
+
+	application, err := h.service.UpdateApplicationStatus(ctx, applicationID, request.Status, request.Note)
+	if err != nil {
+		writeServiceError(w, err, "Application not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(application); err != nil {
+		log.Printf("error encoding response: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// withdrawApplicationRequest is the optional body of POST /applications/{id}/withdraw
+type withdrawApplicationRequest struct {
+	Reason string `json:"reason" validate:"max=2000"`
+}
+
+// WithdrawApplication handles POST /applications/{id}/withdraw request. An empty body withdraws without
+// a reason.
+func (h *ApplicationHandler) WithdrawApplication(w http.ResponseWriter, r *http.Request) {
+	var request withdrawApplicationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if validationErrors := helpers.ValidateStruct(request); len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
+		return
+	}
+
+	application, err := h.service.WithdrawApplication(r.Context(), chi.URLParam(r, "id"), strings.TrimSpace(request.Reason))
+	if err != nil {
+		writeServiceError(w, err, "Application not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(application); err != nil {
+		log.Printf("error encoding response: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// moveApplicationStageRequest is the body of PUT /applications/{id}/stage
+type moveApplicationStageRequest struct {
+	Stage string `json:"stage" validate:"required,max=50"`
+	Note  string `json:"note" validate:"max=2000"`
+}
+
+// MoveApplicationStage handles PUT /applications/{id}/stage request, moving an application to
+// another stage of the job's pipeline
+func (h *ApplicationHandler) MoveApplicationStage(w http.ResponseWriter, r *http.Request) {
+	var request moveApplicationStageRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if validationErrors := helpers.ValidateStruct(request); len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
+		return
+	}
+
+	application, err := h.service.MoveApplicationStage(r.Context(), chi.URLParam(r, "id"), request.Stage, request.Note)
+	if err != nil {
+		writeServiceError(w, err, "Application not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(application); err != nil {
+		log.Printf("error encoding response: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// bulkApplicationsResponse is the body of a bulk action's response: the result for each application
+// and how many were updated, unchanged and failed
+type bulkApplicationsResponse struct {
+	Results   []models.ApplicationBulkResult `json:"results"`
+	Updated   int                            `json:"updated"`
+	Unchanged int                            `json:"unchanged"`
+	Failed    int                            `json:"failed"`
+}
+
+// BulkUpdateApplications handles POST /jobs/{jobId}/applications/bulk request, moving, rejecting or
+// tagging many applications to a job at once
+func (h *ApplicationHandler) BulkUpdateApplications(w http.ResponseWriter, r *http.Request) {
+	var action models.ApplicationBulkAction
+	if err := json.NewDecoder(r.Body).Decode(&action); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	for i, tag := range action.Tags {
+		action.Tags[i] = strings.ToLower(strings.TrimSpace(tag))
+	}
+	validationErrors := helpers.ValidateStruct(action)
+	if action.Action == models.ApplicationActionTag && len(action.Tags) == 0 {
+		validationErrors = append(validationErrors, helpers.ValidationError{
+			Field:   "tags",
+			Message: "tags are required to tag applications",
+		})
+	}
+	if len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
+		return
+	}
+
+	results, err := h.service.BulkUpdateApplications(r.Context(), chi.URLParam(r, "jobId"), action)
+	if err != nil {
+		writeServiceError(w, err, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	response := bulkApplicationsResponse{Results: results}
+	for _, result := range results {
+		switch result.Result {
+		case models.BulkResultUpdated:
+			response.Updated++
+		case models.BulkResultUnchanged:
+			response.Unchanged++
+		default:
+			response.Failed++
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("error encoding response: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
 
-	err = h.service.UpdateApplicationStatus(ctx, applicationID, request.Status)
+// GetApplicationHistory handles GET /applications/{id}/history request
+func (h *ApplicationHandler) GetApplicationHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	applicationID := chi.URLParam(r, "id")
+
+	history, err := h.service.GetApplicationHistory(ctx, applicationID)
 	if err != nil {
-		http.Error(w, "Failed to update application", http.StatusInternalServerError)
+		writeServiceError(w, err, "Application not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(history); err != nil {
+		log.Printf("error encoding response: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
+}
 
+// GetRejectionReasons handles GET /rejectionreasons request
+func (h *ApplicationHandler) GetRejectionReasons(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(h.service.GetRejectionReasons(r.Context())); err != nil {
+		log.Printf("error encoding response: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
 }
 
 // DeleteApplication handles DELETE /applications/{id} request
@@ -207,7 +450,21 @@ func (h *ApplicationHandler) DeleteApplication(w http.ResponseWriter, r *http.Re
 
 	err := h.service.DeleteApplication(ctx, applicationID)
 	if err != nil {
-		http.Error(w, "Application not found", http.StatusNotFound)
+		writeServiceError(w, err, "Application not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreApplication handles POST /applications/{id}/restore request
+func (h *ApplicationHandler) RestoreApplication(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	applicationID := chi.URLParam(r, "id")
+
+	err := h.service.RestoreApplication(ctx, applicationID)
+	if err != nil {
+		writeServiceError(w, err, "Deleted application not found", http.StatusNotFound)
 		return
 	}
 
@@ -3,17 +3,23 @@ package handlers_test
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"go-mongodb-api/handlers"
+	"go-mongodb-api/helpers"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/mocks"
 	"go-mongodb-api/models"
+	"go-mongodb-api/services"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 func TestApplicationHandler_GetAllApplications_Success(t *testing.T) {
@@ -64,6 +70,33 @@ func TestApplicationHandler_GetApplicationByID_Success(t *testing.T) {
 	mockSvc.AssertExpectations(t)
 }
 
+func TestApplicationHandler_GetApplicationByID_Expand(t *testing.T) {
+	mockSvc := new(mocks.MockApplicationService)
+	h := handlers.NewApplicationHandler(mockSvc)
+
+	id := bson.NewObjectID()
+	app := &models.Application{ID: id, Status: "applied"}
+	mockSvc.On("GetApplicationByID", mock.Anything, id.Hex()).Return(app, nil)
+	mockSvc.On("ExpandApplications", mock.Anything, []models.Application{*app}, []string{models.ExpandJob, models.ExpandCandidate}).
+		Run(func(args mock.Arguments) {
+			expanded := args.Get(1).([]models.Application)
+			expanded[0].Job = &models.Job{Title: "Go Developer"}
+			expanded[0].Candidate = &models.UserResponse{Email: "candidate@example.com"}
+		}).
+		Return(nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/applications/"+id.Hex()+"?expand=job,candidate", nil)
+	r = addChiURLParam(r, "id", id.Hex())
+	w := httptest.NewRecorder()
+
+	h.GetApplicationByID(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"title":"Go Developer"`)
+	assert.Contains(t, w.Body.String(), `"email":"candidate@example.com"`)
+	mockSvc.AssertExpectations(t)
+}
+
 func TestApplicationHandler_GetApplicationByID_NotFound(t *testing.T) {
 	mockSvc := new(mocks.MockApplicationService)
 	h := handlers.NewApplicationHandler(mockSvc)
@@ -97,6 +130,42 @@ func TestApplicationHandler_GetApplicationsByJobID(t *testing.T) {
 	mockSvc.AssertExpectations(t)
 }
 
+func TestApplicationHandler_GetApplicationsByJobID_StageCounts(t *testing.T) {
+	mockSvc := new(mocks.MockApplicationService)
+	h := handlers.NewApplicationHandler(mockSvc)
+
+	jobID := bson.NewObjectID()
+	apps := []models.Application{{Status: "applied", Stage: "screen"}}
+	mockSvc.On("GetApplicationsByJobID", mock.Anything, jobID.Hex()).Return(apps, nil)
+	mockSvc.On("GetApplicationStageCounts", mock.Anything, jobID.Hex()).Return([]models.StageCount{{Key: "screen", Name: "Screening", Count: 1}}, nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/jobs/"+jobID.Hex()+"/applications?stage_counts=true", nil)
+	r = addChiURLParam(r, "jobId", jobID.Hex())
+	w := httptest.NewRecorder()
+
+	h.GetApplicationsByJobID(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"data":[`)
+	assert.Contains(t, w.Body.String(), `"stage_counts":[{"key":"screen","name":"Screening","count":1}]`)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestApplicationHandler_GetApplicationsByJobID_InvalidExpand(t *testing.T) {
+	mockSvc := new(mocks.MockApplicationService)
+	h := handlers.NewApplicationHandler(mockSvc)
+
+	jobID := bson.NewObjectID()
+	r := httptest.NewRequest(http.MethodGet, "/jobs/"+jobID.Hex()+"/applications?expand=recruiter", nil)
+	r = addChiURLParam(r, "jobId", jobID.Hex())
+	w := httptest.NewRecorder()
+
+	h.GetApplicationsByJobID(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "GetApplicationsByJobID", mock.Anything, mock.Anything)
+}
+
 func TestApplicationHandler_GetApplicationsByUserID(t *testing.T) {
 	mockSvc := new(mocks.MockApplicationService)
 	h := handlers.NewApplicationHandler(mockSvc)
@@ -134,6 +203,66 @@ func TestApplicationHandler_CreateApplication_Success(t *testing.T) {
 	mockSvc.AssertExpectations(t)
 }
 
+func TestApplicationHandler_CreateApplication_StartsApplied(t *testing.T) {
+	mockSvc := new(mocks.MockApplicationService)
+	h := handlers.NewApplicationHandler(mockSvc)
+
+	mockSvc.On("CreateApplication", mock.Anything, mock.MatchedBy(func(a *models.Application) bool {
+		return a.Status == models.ApplicationStatusApplied && a.RecruiterNote == ""
+	})).Return(nil)
+
+	body := `{"job_id":"` + bson.NewObjectID().Hex() + `","user_id":"` + bson.NewObjectID().Hex() + `","status":"accepted","recruiter_note":"Hire me"}`
+	r := httptest.NewRequest(http.MethodPost, "/applications", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.CreateApplication(w, r)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"applied"`)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestApplicationHandler_CreateApplication_UsesCallerFromToken(t *testing.T) {
+	mockSvc := new(mocks.MockApplicationService)
+	h := handlers.NewApplicationHandler(mockSvc)
+
+	callerID := bson.NewObjectID()
+	mockSvc.On("CreateApplication", mock.Anything, mock.MatchedBy(func(a *models.Application) bool {
+		return a.UserID == callerID
+	})).Return(nil)
+
+	body := `{"job_id":"` + bson.NewObjectID().Hex() + `","user_id":"` + bson.NewObjectID().Hex() + `","status":"applied"}`
+	r := httptest.NewRequest(http.MethodPost, "/applications", bytes.NewBufferString(body))
+	r = withClaims(r, &middleware.Claims{UserID: callerID.Hex(), Role: "candidate"})
+	w := httptest.NewRecorder()
+
+	h.CreateApplication(w, r)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestApplicationHandler_CreateApplication_AdminKeepsBodyUser(t *testing.T) {
+	mockSvc := new(mocks.MockApplicationService)
+	h := handlers.NewApplicationHandler(mockSvc)
+
+	adminID := bson.NewObjectID()
+	userID := bson.NewObjectID()
+	mockSvc.On("CreateApplication", mock.Anything, mock.MatchedBy(func(a *models.Application) bool {
+		return a.UserID == userID
+	})).Return(nil)
+
+	body := `{"job_id":"` + bson.NewObjectID().Hex() + `","user_id":"` + userID.Hex() + `","status":"applied"}`
+	r := httptest.NewRequest(http.MethodPost, "/applications", bytes.NewBufferString(body))
+	r = withClaims(r, &middleware.Claims{UserID: adminID.Hex(), Role: "admin"})
+	w := httptest.NewRecorder()
+
+	h.CreateApplication(w, r)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
 func TestApplicationHandler_CreateApplication_InvalidBody(t *testing.T) {
 	mockSvc := new(mocks.MockApplicationService)
 	h := handlers.NewApplicationHandler(mockSvc)
@@ -164,13 +293,83 @@ func TestApplicationHandler_CreateApplication_ServiceError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
+func TestApplicationHandler_CreateApplication_Reapply(t *testing.T) {
+	until := time.Date(2026, 11, 16, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		err     error
+		message string
+	}{
+		{"already applied", services.ErrAlreadyApplied, "You have already applied to this job"},
+		{"cooldown", &services.ReapplyCooldownError{Until: until}, "You can reapply to this job from 2026-11-16T09:30:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockApplicationService)
+			h := handlers.NewApplicationHandler(mockSvc)
+
+			mockSvc.On("CreateApplication", mock.Anything, mock.AnythingOfType("*models.Application")).Return(tt.err)
+
+			body := `{"job_id":"` + bson.NewObjectID().Hex() + `","user_id":"` + bson.NewObjectID().Hex() + `","status":"applied"}`
+			r := httptest.NewRequest(http.MethodPost, "/applications", bytes.NewBufferString(body))
+			w := httptest.NewRecorder()
+
+			h.CreateApplication(w, r)
+
+			assert.Equal(t, http.StatusConflict, w.Code)
+			assert.Contains(t, w.Body.String(), tt.message)
+		})
+	}
+}
+
+func TestApplicationHandler_CreateApplication_CoverLetterAndAnswers(t *testing.T) {
+	mockSvc := new(mocks.MockApplicationService)
+	h := handlers.NewApplicationHandler(mockSvc)
+
+	mockSvc.On("CreateApplication", mock.Anything, mock.MatchedBy(func(a *models.Application) bool {
+		return a.CoverLetter == "I have built APIs in Go for five years." && len(a.Answers) == 2 &&
+			*a.Answers[0].Yes && *a.Answers[1].Number == 5
+	})).Return(nil)
+
+	body := `{"job_id":"` + bson.NewObjectID().Hex() + `","user_id":"` + bson.NewObjectID().Hex() + `",
+		"cover_letter":"  I have built APIs in Go for five years.\n",
+		"answers":[{"key":"relocate","yes":true},{"key":"years_go","number":5}]}`
+	r := httptest.NewRequest(http.MethodPost, "/applications", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.CreateApplication(w, r)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestApplicationHandler_CreateApplication_InvalidAnswers(t *testing.T) {
+	mockSvc := new(mocks.MockApplicationService)
+	h := handlers.NewApplicationHandler(mockSvc)
+
+	mockSvc.On("CreateApplication", mock.Anything, mock.AnythingOfType("*models.Application")).Return(&services.ScreeningAnswersError{
+		Errors: []helpers.ValidationError{{Field: "answers", Message: "relocate: an answer is required"}},
+	})
+
+	body := `{"job_id":"` + bson.NewObjectID().Hex() + `","user_id":"` + bson.NewObjectID().Hex() + `"}`
+	r := httptest.NewRequest(http.MethodPost, "/applications", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.CreateApplication(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "relocate: an answer is required")
+}
+
 func TestApplicationHandler_UpdateApplicationStatus_Success(t *testing.T) {
 	mockSvc := new(mocks.MockApplicationService)
 	h := handlers.NewApplicationHandler(mockSvc)
 
-	mockSvc.On("UpdateApplicationStatus", mock.Anything, "app-id", "accepted").Return(nil)
+	updated := &models.Application{Status: "accepted", RecruiterNote: "Strong interview"}
+	mockSvc.On("UpdateApplicationStatus", mock.Anything, "app-id", "accepted", "Strong interview").Return(updated, nil)
 
-	body := `{"status":"accepted"}`
+	body := `{"status":"accepted","note":"Strong interview"}`
 	r := httptest.NewRequest(http.MethodPut, "/applications/app-id", bytes.NewBufferString(body))
 	r = addChiURLParam(r, "id", "app-id")
 	w := httptest.NewRecorder()
@@ -178,9 +377,93 @@ func TestApplicationHandler_UpdateApplicationStatus_Success(t *testing.T) {
 	h.UpdateApplicationStatus(w, r)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"accepted"`)
 	mockSvc.AssertExpectations(t)
 }
 
+func TestApplicationHandler_UpdateApplicationStatus_InvalidStatus(t *testing.T) {
+	mockSvc := new(mocks.MockApplicationService)
+	h := handlers.NewApplicationHandler(mockSvc)
+
+	for _, body := range []string{`{"status":"hired"}`, `{}`} {
+		r := httptest.NewRequest(http.MethodPut, "/applications/app-id", bytes.NewBufferString(body))
+		r = addChiURLParam(r, "id", "app-id")
+		w := httptest.NewRecorder()
+
+		h.UpdateApplicationStatus(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	mockSvc.AssertNotCalled(t, "UpdateApplicationStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApplicationHandler_UpdateApplicationStatus_NotFound(t *testing.T) {
+	mockSvc := new(mocks.MockApplicationService)
+	h := handlers.NewApplicationHandler(mockSvc)
+
+	mockSvc.On("UpdateApplicationStatus", mock.Anything, "app-id", "under_review", "").Return(nil, mongo.ErrNoDocuments)
+
+	r := httptest.NewRequest(http.MethodPut, "/applications/app-id", bytes.NewBufferString(`{"status":"under_review"}`))
+	r = addChiURLParam(r, "id", "app-id")
+	w := httptest.NewRecorder()
+
+	h.UpdateApplicationStatus(w, r)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestApplicationHandler_UpdateApplicationStatus_InvalidTransition(t *testing.T) {
+	mockSvc := new(mocks.MockApplicationService)
+	h := handlers.NewApplicationHandler(mockSvc)
+
+	mockSvc.On("UpdateApplicationStatus", mock.Anything, "app-id", "accepted", "").
+		Return(nil, fmt.Errorf("%w: applied to accepted", services.ErrInvalidApplicationTransition))
+
+	r := httptest.NewRequest(http.MethodPut, "/applications/app-id", bytes.NewBufferString(`{"status":"accepted"}`))
+	r = addChiURLParam(r, "id", "app-id")
+	w := httptest.NewRecorder()
+
+	h.UpdateApplicationStatus(w, r)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestApplicationHandler_GetApplicationHistory(t *testing.T) {
+	mockSvc := new(mocks.MockApplicationService)
+	h := handlers.NewApplicationHandler(mockSvc)
+
+	history := []models.ApplicationStatusChange{
+		{Status: "applied", ChangedBy: "candidate-id"},
+		{Status: "under_review", FromStatus: "applied", Note: "Shortlisted", ChangedBy: "recruiter-id"},
+	}
+	mockSvc.On("GetApplicationHistory", mock.Anything, "app-id").Return(history, nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/applications/app-id/history", nil)
+	r = addChiURLParam(r, "id", "app-id")
+	w := httptest.NewRecorder()
+
+	h.GetApplicationHistory(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"from_status":"applied"`)
+	assert.Contains(t, w.Body.String(), `"note":"Shortlisted"`)
+}
+
+func TestApplicationHandler_GetApplicationHistory_Forbidden(t *testing.T) {
+	mockSvc := new(mocks.MockApplicationService)
+	h := handlers.NewApplicationHandler(mockSvc)
+
+	mockSvc.On("GetApplicationHistory", mock.Anything, "app-id").Return(nil, services.ErrForbidden)
+
+	r := httptest.NewRequest(http.MethodGet, "/applications/app-id/history", nil)
+	r = addChiURLParam(r, "id", "app-id")
+	w := httptest.NewRecorder()
+
+	h.GetApplicationHistory(w, r)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
 func TestApplicationHandler_UpdateApplicationStatus_InvalidBody(t *testing.T) {
 	mockSvc := new(mocks.MockApplicationService)
 	h := handlers.NewApplicationHandler(mockSvc)
@@ -224,3 +507,272 @@ func TestApplicationHandler_DeleteApplication_NotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
+
+func TestApplicationHandler_GetApplicationsByUserID_Forbidden(t *testing.T) {
+	mockSvc := new(mocks.MockApplicationService)
+	h := handlers.NewApplicationHandler(mockSvc)
+
+	mockSvc.On("GetApplicationsByUserID", mock.Anything, "other-user").Return([]models.Application(nil), services.ErrForbidden)
+
+	r := httptest.NewRequest(http.MethodGet, "/users/other-user/applications", nil)
+	r = addChiURLParam(r, "userId", "other-user")
+	w := httptest.NewRecorder()
+
+	h.GetApplicationsByUserID(w, r)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestApplicationHandler_DeleteApplication_Forbidden(t *testing.T) {
+	mockSvc := new(mocks.MockApplicationService)
+	h := handlers.NewApplicationHandler(mockSvc)
+
+	mockSvc.On("DeleteApplication", mock.Anything, "app-id").Return(services.ErrForbidden)
+
+	r := httptest.NewRequest(http.MethodDelete, "/applications/app-id", nil)
+	r = addChiURLParam(r, "id", "app-id")
+	w := httptest.NewRecorder()
+
+	h.DeleteApplication(w, r)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestApplicationHandler_WithdrawApplication(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		reason string
+	}{
+		{"with reason", `{"reason":" Accepted another offer "}`, "Accepted another offer"},
+		{"empty body", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockApplicationService)
+			h := handlers.NewApplicationHandler(mockSvc)
+
+			mockSvc.On("WithdrawApplication", mock.Anything, "app-id", tt.reason).
+				Return(&models.Application{Status: models.ApplicationStatusWithdrawn, WithdrawalReason: tt.reason}, nil)
+
+			r := httptest.NewRequest(http.MethodPost, "/applications/app-id/withdraw", bytes.NewBufferString(tt.body))
+			r = addChiURLParam(r, "id", "app-id")
+			w := httptest.NewRecorder()
+
+			h.WithdrawApplication(w, r)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Body.String(), `"status":"withdrawn"`)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestApplicationHandler_WithdrawApplication_InvalidBody(t *testing.T) {
+	mockSvc := new(mocks.MockApplicationService)
+	h := handlers.NewApplicationHandler(mockSvc)
+
+	r := httptest.NewRequest(http.MethodPost, "/applications/app-id/withdraw", bytes.NewBufferString("bad-json"))
+	r = addChiURLParam(r, "id", "app-id")
+	w := httptest.NewRecorder()
+
+	h.WithdrawApplication(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "WithdrawApplication", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApplicationHandler_WithdrawApplication_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"not the candidate", services.ErrForbidden, http.StatusForbidden},
+		{"already closed", fmt.Errorf("%w: rejected to withdrawn", services.ErrInvalidApplicationTransition), http.StatusConflict},
+		{"application not found", mongo.ErrNoDocuments, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockApplicationService)
+			h := handlers.NewApplicationHandler(mockSvc)
+
+			mockSvc.On("WithdrawApplication", mock.Anything, "app-id", "").Return(nil, tt.err)
+
+			r := httptest.NewRequest(http.MethodPost, "/applications/app-id/withdraw", nil)
+			r = addChiURLParam(r, "id", "app-id")
+			w := httptest.NewRecorder()
+
+			h.WithdrawApplication(w, r)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestApplicationHandler_MoveApplicationStage(t *testing.T) {
+	mockSvc := new(mocks.MockApplicationService)
+	h := handlers.NewApplicationHandler(mockSvc)
+
+	mockSvc.On("MoveApplicationStage", mock.Anything, "app-id", "interview", "Strong CV").
+		Return(&models.Application{Status: models.ApplicationStatusUnderReview, Stage: "interview"}, nil)
+
+	r := httptest.NewRequest(http.MethodPut, "/applications/app-id/stage", bytes.NewBufferString(`{"stage":"interview","note":"Strong CV"}`))
+	r = addChiURLParam(r, "id", "app-id")
+	w := httptest.NewRecorder()
+
+	h.MoveApplicationStage(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"stage":"interview"`)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestApplicationHandler_MoveApplicationStage_MissingStage(t *testing.T) {
+	mockSvc := new(mocks.MockApplicationService)
+	h := handlers.NewApplicationHandler(mockSvc)
+
+	r := httptest.NewRequest(http.MethodPut, "/applications/app-id/stage", bytes.NewBufferString(`{"note":"Strong CV"}`))
+	r = addChiURLParam(r, "id", "app-id")
+	w := httptest.NewRecorder()
+
+	h.MoveApplicationStage(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "MoveApplicationStage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApplicationHandler_MoveApplicationStage_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"stage not found", services.ErrStageNotFound, http.StatusBadRequest},
+		{"invalid transition", fmt.Errorf("%w: applied to accepted", services.ErrInvalidApplicationTransition), http.StatusConflict},
+		{"not job owner", services.ErrForbidden, http.StatusForbidden},
+		{"application not found", mongo.ErrNoDocuments, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockApplicationService)
+			h := handlers.NewApplicationHandler(mockSvc)
+
+			mockSvc.On("MoveApplicationStage", mock.Anything, "app-id", "hired", "").Return(nil, tt.err)
+
+			r := httptest.NewRequest(http.MethodPut, "/applications/app-id/stage", bytes.NewBufferString(`{"stage":"hired"}`))
+			r = addChiURLParam(r, "id", "app-id")
+			w := httptest.NewRecorder()
+
+			h.MoveApplicationStage(w, r)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestApplicationHandler_BulkUpdateApplications(t *testing.T) {
+	mockSvc := new(mocks.MockApplicationService)
+	h := handlers.NewApplicationHandler(mockSvc)
+
+	jobID := bson.NewObjectID().Hex()
+	mockSvc.On("BulkUpdateApplications", mock.Anything, jobID, mock.MatchedBy(func(a models.ApplicationBulkAction) bool {
+		return a.Action == models.ApplicationActionTag && len(a.ApplicationIDs) == 3 && a.Tags[0] == "senior"
+	})).Return([]models.ApplicationBulkResult{
+		{ID: "a", Result: models.BulkResultUpdated},
+		{ID: "b", Result: models.BulkResultUnchanged},
+		{ID: "c", Result: models.BulkResultFailed, Error: "application not found"},
+	}, nil)
+
+	body := `{"action":"tag","application_ids":["a","b","c"],"tags":[" Senior "]}`
+	r := httptest.NewRequest(http.MethodPost, "/jobs/"+jobID+"/applications/bulk", bytes.NewBufferString(body))
+	r = addChiURLParam(r, "jobId", jobID)
+	w := httptest.NewRecorder()
+
+	h.BulkUpdateApplications(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"updated":1,"unchanged":1,"failed":1`)
+	assert.Contains(t, w.Body.String(), `{"id":"c","result":"failed","error":"application not found"}`)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestApplicationHandler_GetRejectionReasons(t *testing.T) {
+	mockSvc := new(mocks.MockApplicationService)
+	h := handlers.NewApplicationHandler(mockSvc)
+
+	mockSvc.On("GetRejectionReasons", mock.Anything).Return([]models.RejectionReason{
+		{Key: "position_filled", Text: "The position has now been filled."},
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/rejectionreasons", nil)
+	w := httptest.NewRecorder()
+
+	h.GetRejectionReasons(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"key":"position_filled","text":"The position has now been filled."}]`, w.Body.String())
+	mockSvc.AssertExpectations(t)
+}
+
+func TestApplicationHandler_BulkUpdateApplications_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"unknown action", `{"action":"archive","application_ids":["a"]}`},
+		{"no applications", `{"action":"tag","application_ids":[],"tags":["senior"]}`},
+		{"move without stage", `{"action":"move","application_ids":["a"]}`},
+		{"reject without reason", `{"action":"reject","application_ids":["a"]}`},
+		{"tag without tags", `{"action":"tag","application_ids":["a"]}`},
+		{"blank tag", `{"action":"tag","application_ids":["a"],"tags":["  "]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockApplicationService)
+			h := handlers.NewApplicationHandler(mockSvc)
+
+			r := httptest.NewRequest(http.MethodPost, "/jobs/job-id/applications/bulk", bytes.NewBufferString(tt.body))
+			r = addChiURLParam(r, "jobId", "job-id")
+			w := httptest.NewRecorder()
+
+			h.BulkUpdateApplications(w, r)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockSvc.AssertNotCalled(t, "BulkUpdateApplications", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestApplicationHandler_BulkUpdateApplications_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"unknown reason", services.ErrRejectionReasonNotFound, http.StatusBadRequest},
+		{"not job owner", services.ErrForbidden, http.StatusForbidden},
+		{"job not found", mongo.ErrNoDocuments, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockApplicationService)
+			h := handlers.NewApplicationHandler(mockSvc)
+
+			mockSvc.On("BulkUpdateApplications", mock.Anything, "job-id", mock.Anything).Return(nil, tt.err)
+
+			r := httptest.NewRequest(http.MethodPost, "/jobs/job-id/applications/bulk", bytes.NewBufferString(`{"action":"reject","application_ids":["a"],"reason":"location"}`))
+			r = addChiURLParam(r, "jobId", "job-id")
+			w := httptest.NewRecorder()
+
+			h.BulkUpdateApplications(w, r)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"log"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -43,6 +42,9 @@ func (h *ArticleHandler) GetAllArticles(w http.ResponseWriter, r *http.Request)
 	filters := map[string]string{
 		"name": r.URL.Query().Get("name"),
 	}
+	if includeDeleted(r) {
+		filters[models.FilterIncludeDeleted] = "true"
+	}
 
 	sort := r.URL.Query().Get("sort")
 	order := r.URL.Query().Get("order")
@@ -107,11 +109,6 @@ func (h *ArticleHandler) CreateArticle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	article.CreatedTime = time.Now()
-	article.UpdatedTime = time.Now()
-	article.CreatedBy = "system"
-	article.UpdatedBy = "system"
-
 	err = h.service.CreateArticle(ctx, &article)
 	if err != nil {
 		http.Error(w, "Failed to create article", http.StatusInternalServerError)
@@ -136,9 +133,6 @@ func (h *ArticleHandler) UpdateArticle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	article.UpdatedTime = time.Now()
-	article.UpdatedBy = "system"
-
 	updated, err := h.service.UpdateArticle(ctx, articleID, &article)
 	if err != nil {
 		http.Error(w, "Article not found", http.StatusNotFound)
@@ -165,3 +159,16 @@ func (h *ArticleHandler) DeleteArticle(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (h *ArticleHandler) RestoreArticle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	articleID := chi.URLParam(r, "id")
+
+	err := h.service.RestoreArticle(ctx, articleID)
+	if err != nil {
+		writeServiceError(w, err, "Deleted article not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
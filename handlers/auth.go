@@ -2,11 +2,21 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"go-mongodb-api/helpers"
 	"go-mongodb-api/interfaces"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/models"
+	"go-mongodb-api/services"
 	"log"
+	"math"
+	"net"
 	"net/http"
-	"time"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 type AuthHandler struct {
@@ -22,8 +32,58 @@ type loginRequest struct {
 	Password string `json:"password"`
 }
 
-type loginResponse struct {
-	Token string `json:"token"`
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type logoutRequest struct {
+	All bool `json:"all"`
+}
+
+type emailRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type verifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type resetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8"`
+}
+
+// registerRequest is the public sign-up payload. Admin accounts can only be created through invitations.
+type registerRequest struct {
+	FirstName     string `json:"first_name" validate:"required,min=2,max=100"`
+	LastName      string `json:"last_name" validate:"required,min=2,max=100"`
+	Email         string `json:"email" validate:"required,email"`
+	Password      string `json:"password" validate:"required,min=8"`
+	Phone         string `json:"phone"`
+	Role          string `json:"role" validate:"required,oneof=candidate recruiter"`
+	CompanyName   string `json:"company_name" validate:"required_if=Role recruiter"`
+	TermsAccepted bool   `json:"terms_accepted" validate:"required_if=Role recruiter"`
+}
+
+type mfaLoginRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type mfaCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type inviteRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type acceptInvitationRequest struct {
+	Token     string `json:"token" validate:"required"`
+	FirstName string `json:"first_name" validate:"required,min=2,max=100"`
+	LastName  string `json:"last_name" validate:"required,min=2,max=100"`
+	Password  string `json:"password" validate:"required,min=8"`
+	Phone     string `json:"phone"`
 }
 
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
@@ -37,35 +97,207 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := h.service.Login(r.Context(), req.Email, req.Password)
+	tokens, err := h.service.Login(r.Context(), req.Email, req.Password, clientIP(r))
+	if writeLoginLocked(w, err) {
+		return
+	}
 	if err != nil {
 		http.Error(w, "invalid credentials", http.StatusUnauthorized)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(loginResponse{Token: token}); err != nil {
+	if err := json.NewEncoder(w).Encode(tokens); err != nil {
 		log.Printf("error encoding login response: %v", err)
 	}
 }
 
+// VerifyMFA handles POST /auth/login/mfa, the second login step for accounts with two-factor authentication
+func (h *AuthHandler) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+	var req mfaLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if validationErrors := helpers.ValidateStruct(req); len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
+		return
+	}
+
+	tokens, err := h.service.VerifyMFALogin(r.Context(), req.MFAToken, req.Code, clientIP(r))
+	if writeLoginLocked(w, err) {
+		return
+	}
+	if err != nil {
+		http.Error(w, "invalid two-factor code", http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(tokens); err != nil {
+		log.Printf("error encoding mfa login response: %v", err)
+	}
+}
+
+// EnrollMFA handles POST /auth/mfa/enroll, returning a new TOTP secret for the caller's authenticator app
+func (h *AuthHandler) EnrollMFA(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r.Context())
+	if !ok {
+		http.Error(w, "unauthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	enrollment, err := h.service.EnrollMFA(r.Context(), claims.UserID)
+	if err != nil {
+		writeServiceError(w, err, "failed to start two-factor enrollment", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(enrollment); err != nil {
+		log.Printf("error encoding mfa enrollment response: %v", err)
+	}
+}
+
+// ConfirmMFA handles POST /auth/mfa/confirm, enabling two-factor authentication and returning recovery codes
+func (h *AuthHandler) ConfirmMFA(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r.Context())
+	if !ok {
+		http.Error(w, "unauthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	var req mfaCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if validationErrors := helpers.ValidateStruct(req); len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
+		return
+	}
+
+	codes, err := h.service.ConfirmMFA(r.Context(), claims.UserID, req.Code)
+	if err != nil {
+		writeServiceError(w, err, "failed to enable two-factor authentication", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string][]string{"recovery_codes": codes}); err != nil {
+		log.Printf("error encoding mfa confirm response: %v", err)
+	}
+}
+
+// DisableMFA handles POST /auth/mfa/disable. A current TOTP or recovery code is required.
+func (h *AuthHandler) DisableMFA(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r.Context())
+	if !ok {
+		http.Error(w, "unauthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	var req mfaCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if validationErrors := helpers.ValidateStruct(req); len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
+		return
+	}
+
+	if err := h.service.DisableMFA(r.Context(), claims.UserID, req.Code); err != nil {
+		writeServiceError(w, err, "failed to disable two-factor authentication", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Refresh handles POST /auth/refresh, exchanging a refresh token for a new token pair
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req refreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.RefreshToken == "" {
+		http.Error(w, "refresh_token is required", http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := h.service.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		http.Error(w, "invalid refresh token", http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(tokens); err != nil {
+		log.Printf("error encoding refresh response: %v", err)
+	}
+}
+
+// Logout handles POST /auth/logout, revoking the caller's session or, with {"all": true}, every session of the caller
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r.Context())
+	if !ok {
+		http.Error(w, "unauthenticated", http.StatusUnauthorized)
+		return
+	}
+
+	var req logoutRequest
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var err error
+	if req.All {
+		err = h.service.LogoutAll(r.Context(), claims.UserID)
+	} else {
+		err = h.service.Logout(r.Context(), claims.SessionID)
+	}
+	if err != nil {
+		http.Error(w, "logout failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Register handles POST /auth/register for candidates and recruiters
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
-	var user models.User
-	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+	var req registerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	if user.Email == "" || user.Password == "" || user.Role == "" {
-		http.Error(w, "email, password and role are required", http.StatusBadRequest)
+	if validationErrors := helpers.ValidateStruct(req); len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
 		return
 	}
 
-	user.CreatedTime = time.Now()
-	user.UpdatedTime = time.Now()
+	user := models.User{
+		FirstName:     req.FirstName,
+		LastName:      req.LastName,
+		Email:         req.Email,
+		Password:      req.Password,
+		Phone:         req.Phone,
+		Role:          req.Role,
+		CompanyName:   req.CompanyName,
+		TermsAccepted: req.TermsAccepted,
+	}
 
 	if err := h.service.Register(r.Context(), &user); err != nil {
-		http.Error(w, "registration failed", http.StatusInternalServerError)
+		writeServiceError(w, err, "registration failed", http.StatusInternalServerError)
 		return
 	}
 
@@ -75,3 +307,196 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		log.Printf("error encoding register response: %v", err)
 	}
 }
+
+// Invite handles POST /auth/invitations. Only admins can invite, and invitations create admin accounts.
+func (h *AuthHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	var req inviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if validationErrors := helpers.ValidateStruct(req); len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
+		return
+	}
+
+	if err := h.service.Invite(r.Context(), req.Email); err != nil {
+		writeServiceError(w, err, "invitation failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// AcceptInvitation handles POST /auth/invitations/accept and creates the invited account
+func (h *AuthHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	var req acceptInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if validationErrors := helpers.ValidateStruct(req); len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
+		return
+	}
+
+	user := models.User{
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Password:  req.Password,
+		Phone:     req.Phone,
+	}
+
+	if err := h.service.AcceptInvitation(r.Context(), req.Token, &user); err != nil {
+		writeServiceError(w, err, "failed to accept invitation", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(user.ToResponse()); err != nil {
+		log.Printf("error encoding accept invitation response: %v", err)
+	}
+}
+
+// UnlockAccount handles POST /users/{id}/unlock, clearing the user's failed login counter and lockout
+func (h *AuthHandler) UnlockAccount(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "id")
+	if _, err := bson.ObjectIDFromHex(userID); err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.service.UnlockAccount(r.Context(), userID); err != nil {
+		message, status := "Failed to unlock account", http.StatusInternalServerError
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			message, status = "User not found", http.StatusNotFound
+		}
+		writeServiceError(w, err, message, status)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ForgotPassword handles POST /auth/forgot-password. The response is the same whether or not the email exists.
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req emailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if validationErrors := helpers.ValidateStruct(req); len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
+		return
+	}
+
+	if err := h.service.ForgotPassword(r.Context(), req.Email); err != nil {
+		log.Printf("error processing forgot password request: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(map[string]string{"message": "if the account exists, a reset link has been sent"}); err != nil {
+		log.Printf("error encoding forgot password response: %v", err)
+	}
+}
+
+// ResetPassword handles POST /auth/reset-password
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req resetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if validationErrors := helpers.ValidateStruct(req); len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
+		return
+	}
+
+	if err := h.service.ResetPassword(r.Context(), req.Token, req.Password); err != nil {
+		if errors.Is(err, services.ErrInvalidResetToken) {
+			http.Error(w, "invalid or expired reset token", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "password reset failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// VerifyEmail handles POST /auth/verify
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req verifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if validationErrors := helpers.ValidateStruct(req); len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
+		return
+	}
+
+	if err := h.service.VerifyEmail(r.Context(), req.Token); err != nil {
+		if errors.Is(err, services.ErrInvalidVerifyToken) {
+			http.Error(w, "invalid or expired verification token", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "email verification failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ResendVerification handles POST /auth/verify/resend. The response is the same whether or not the email exists.
+func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	var req emailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if validationErrors := helpers.ValidateStruct(req); len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
+		return
+	}
+
+	if err := h.service.ResendVerification(r.Context(), req.Email); err != nil {
+		log.Printf("error resending verification: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(map[string]string{"message": "if the account exists and is unverified, a verification link has been sent"}); err != nil {
+		log.Printf("error encoding resend verification response: %v", err)
+	}
+}
+
+// writeLoginLocked writes a 429 response with a Retry-After header when err is a login lockout
+// and reports whether it did
+func writeLoginLocked(w http.ResponseWriter, err error) bool {
+	var lockedErr *services.LoginLockedError
+	if !errors.As(err, &lockedErr) {
+		return false
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+	http.Error(w, "too many failed login attempts", http.StatusTooManyRequests)
+	return true
+}
+
+// clientIP returns the host part of the request's remote address. Behind a trusted proxy the
+// RealIP middleware has already replaced it with the forwarded client address.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
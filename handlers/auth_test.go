@@ -2,24 +2,32 @@ package handlers_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"go-mongodb-api/handlers"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/mocks"
+	"go-mongodb-api/models"
+	"go-mongodb-api/services"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 func TestAuthHandler_Login_Success(t *testing.T) {
 	mockSvc := new(mocks.MockAuthService)
 	h := handlers.NewAuthHandler(mockSvc)
 
-	mockSvc.On("Login", mock.Anything, "alice@example.com", "password123").Return("jwt-token", nil)
+	tokens := &models.AuthTokens{AccessToken: "jwt-token", RefreshToken: "refresh-token", ExpiresIn: 900}
+	mockSvc.On("Login", mock.Anything, "alice@example.com", "password123", "192.0.2.1").Return(tokens, nil)
 
 	body := `{"email":"alice@example.com","password":"password123"}`
 	r := httptest.NewRequest(http.MethodPost, "/auth/login", bytes.NewBufferString(body))
@@ -29,9 +37,10 @@ func TestAuthHandler_Login_Success(t *testing.T) {
 	h.Login(w, r)
 
 	assert.Equal(t, http.StatusOK, w.Code)
-	var resp map[string]string
+	var resp map[string]interface{}
 	json.NewDecoder(w.Body).Decode(&resp)
 	assert.Equal(t, "jwt-token", resp["token"])
+	assert.Equal(t, "refresh-token", resp["refresh_token"])
 	mockSvc.AssertExpectations(t)
 }
 
@@ -39,7 +48,7 @@ func TestAuthHandler_Login_InvalidCredentials(t *testing.T) {
 	mockSvc := new(mocks.MockAuthService)
 	h := handlers.NewAuthHandler(mockSvc)
 
-	mockSvc.On("Login", mock.Anything, "alice@example.com", "wrong").Return("", errors.New("invalid credentials"))
+	mockSvc.On("Login", mock.Anything, "alice@example.com", "wrong", mock.Anything).Return(nil, errors.New("invalid credentials"))
 
 	body := `{"email":"alice@example.com","password":"wrong"}`
 	r := httptest.NewRequest(http.MethodPost, "/auth/login", bytes.NewBufferString(body))
@@ -51,6 +60,24 @@ func TestAuthHandler_Login_InvalidCredentials(t *testing.T) {
 	mockSvc.AssertExpectations(t)
 }
 
+func TestAuthHandler_Login_Locked(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	lockedErr := &services.LoginLockedError{RetryAfter: 90*time.Second + 200*time.Millisecond}
+	mockSvc.On("Login", mock.Anything, "alice@example.com", "password123", "192.0.2.1").Return(nil, lockedErr)
+
+	body := `{"email":"alice@example.com","password":"password123"}`
+	r := httptest.NewRequest(http.MethodPost, "/auth/login", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.Login(w, r)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "91", w.Header().Get("Retry-After"))
+	mockSvc.AssertExpectations(t)
+}
+
 func TestAuthHandler_Login_MissingBody(t *testing.T) {
 	mockSvc := new(mocks.MockAuthService)
 	h := handlers.NewAuthHandler(mockSvc)
@@ -147,3 +174,615 @@ func TestAuthHandler_Register_ServiceError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 	mockSvc.AssertExpectations(t)
 }
+
+func withClaims(r *http.Request, claims *middleware.Claims) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), middleware.ClaimsKey, claims))
+}
+
+func TestAuthHandler_Refresh_Success(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	tokens := &models.AuthTokens{AccessToken: "new-access", RefreshToken: "new-refresh", ExpiresIn: 900}
+	mockSvc.On("Refresh", mock.Anything, "old-refresh").Return(tokens, nil)
+
+	body := `{"refresh_token":"old-refresh"}`
+	r := httptest.NewRequest(http.MethodPost, "/auth/refresh", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.Refresh(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Equal(t, "new-refresh", resp["refresh_token"])
+	mockSvc.AssertExpectations(t)
+}
+
+func TestAuthHandler_Refresh_InvalidToken(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("Refresh", mock.Anything, "reused").Return(nil, errors.New("refresh token reuse detected"))
+
+	body := `{"refresh_token":"reused"}`
+	r := httptest.NewRequest(http.MethodPost, "/auth/refresh", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.Refresh(w, r)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestAuthHandler_Refresh_MissingToken(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	r := httptest.NewRequest(http.MethodPost, "/auth/refresh", bytes.NewBufferString(`{}`))
+	w := httptest.NewRecorder()
+
+	h.Refresh(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAuthHandler_Logout_CurrentSession(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("Logout", mock.Anything, "session-1").Return(nil)
+
+	r := httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+	r = withClaims(r, &middleware.Claims{UserID: "user-1", SessionID: "session-1"})
+	w := httptest.NewRecorder()
+
+	h.Logout(w, r)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestAuthHandler_Logout_AllSessions(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("LogoutAll", mock.Anything, "user-1").Return(nil)
+
+	r := httptest.NewRequest(http.MethodPost, "/auth/logout", bytes.NewBufferString(`{"all":true}`))
+	r = withClaims(r, &middleware.Claims{UserID: "user-1", SessionID: "session-1"})
+	w := httptest.NewRecorder()
+
+	h.Logout(w, r)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestAuthHandler_Logout_Unauthenticated(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	r := httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+	w := httptest.NewRecorder()
+
+	h.Logout(w, r)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthHandler_ForgotPassword_Accepted(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("ForgotPassword", mock.Anything, "alice@example.com").Return(nil)
+
+	body := `{"email":"alice@example.com"}`
+	r := httptest.NewRequest(http.MethodPost, "/auth/forgot-password", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.ForgotPassword(w, r)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestAuthHandler_ForgotPassword_ServiceErrorStillAccepted(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("ForgotPassword", mock.Anything, "alice@example.com").Return(errors.New("smtp down"))
+
+	body := `{"email":"alice@example.com"}`
+	r := httptest.NewRequest(http.MethodPost, "/auth/forgot-password", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.ForgotPassword(w, r)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+}
+
+func TestAuthHandler_ForgotPassword_InvalidEmail(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	body := `{"email":"not-an-email"}`
+	r := httptest.NewRequest(http.MethodPost, "/auth/forgot-password", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.ForgotPassword(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "ForgotPassword", mock.Anything, mock.Anything)
+}
+
+func TestAuthHandler_ResetPassword_Success(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("ResetPassword", mock.Anything, "reset-token", "newpassword123").Return(nil)
+
+	body := `{"token":"reset-token","password":"newpassword123"}`
+	r := httptest.NewRequest(http.MethodPost, "/auth/reset-password", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.ResetPassword(w, r)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestAuthHandler_ResetPassword_InvalidToken(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("ResetPassword", mock.Anything, "used-token", "newpassword123").Return(services.ErrInvalidResetToken)
+
+	body := `{"token":"used-token","password":"newpassword123"}`
+	r := httptest.NewRequest(http.MethodPost, "/auth/reset-password", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.ResetPassword(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestAuthHandler_ResetPassword_ShortPassword(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	body := `{"token":"reset-token","password":"short"}`
+	r := httptest.NewRequest(http.MethodPost, "/auth/reset-password", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.ResetPassword(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "ResetPassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthHandler_VerifyEmail_Success(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("VerifyEmail", mock.Anything, "verify-token").Return(nil)
+
+	r := httptest.NewRequest(http.MethodPost, "/auth/verify", bytes.NewBufferString(`{"token":"verify-token"}`))
+	w := httptest.NewRecorder()
+
+	h.VerifyEmail(w, r)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestAuthHandler_VerifyEmail_InvalidToken(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("VerifyEmail", mock.Anything, "expired").Return(services.ErrInvalidVerifyToken)
+
+	r := httptest.NewRequest(http.MethodPost, "/auth/verify", bytes.NewBufferString(`{"token":"expired"}`))
+	w := httptest.NewRecorder()
+
+	h.VerifyEmail(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAuthHandler_VerifyEmail_MissingToken(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	r := httptest.NewRequest(http.MethodPost, "/auth/verify", bytes.NewBufferString(`{}`))
+	w := httptest.NewRecorder()
+
+	h.VerifyEmail(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "VerifyEmail", mock.Anything, mock.Anything)
+}
+
+func TestAuthHandler_ResendVerification_Accepted(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("ResendVerification", mock.Anything, "alice@example.com").Return(nil)
+
+	r := httptest.NewRequest(http.MethodPost, "/auth/verify/resend", bytes.NewBufferString(`{"email":"alice@example.com"}`))
+	w := httptest.NewRecorder()
+
+	h.ResendVerification(w, r)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestAuthHandler_Register_AdminRoleRejected(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	body := `{"first_name":"Mallory","last_name":"Smith","email":"mallory@example.com","password":"password123","role":"admin"}`
+	r := httptest.NewRequest(http.MethodPost, "/auth/register", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.Register(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"Role"`)
+	mockSvc.AssertNotCalled(t, "Register", mock.Anything, mock.Anything)
+}
+
+func TestAuthHandler_Register_RecruiterRequiresCompanyAndTerms(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	body := `{"first_name":"Bob","last_name":"Jones","email":"bob@acme.com","password":"password123","role":"recruiter"}`
+	r := httptest.NewRequest(http.MethodPost, "/auth/register", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.Register(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"CompanyName"`)
+	assert.Contains(t, w.Body.String(), `"field":"TermsAccepted"`)
+	mockSvc.AssertNotCalled(t, "Register", mock.Anything, mock.Anything)
+}
+
+func TestAuthHandler_Register_Recruiter(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("Register", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+		return u.Role == "recruiter" && u.CompanyName == "Acme" && u.TermsAccepted
+	})).Return(nil)
+
+	body := `{"first_name":"Bob","last_name":"Jones","email":"bob@acme.com","password":"password123","role":"recruiter","company_name":"Acme","terms_accepted":true}`
+	r := httptest.NewRequest(http.MethodPost, "/auth/register", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.Register(w, r)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestAuthHandler_Register_DuplicateEmail(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("Register", mock.Anything, mock.AnythingOfType("*models.User")).Return(services.ErrEmailTaken)
+
+	body := `{"first_name":"Alice","last_name":"Smith","email":"alice@example.com","password":"password123","role":"candidate"}`
+	r := httptest.NewRequest(http.MethodPost, "/auth/register", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.Register(w, r)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestAuthHandler_Invite_Accepted(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("Invite", mock.Anything, "new.admin@example.com").Return(nil)
+
+	r := httptest.NewRequest(http.MethodPost, "/auth/invitations", bytes.NewBufferString(`{"email":"new.admin@example.com"}`))
+	w := httptest.NewRecorder()
+
+	h.Invite(w, r)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestAuthHandler_Invite_EmailTaken(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("Invite", mock.Anything, "alice@example.com").Return(services.ErrEmailTaken)
+
+	r := httptest.NewRequest(http.MethodPost, "/auth/invitations", bytes.NewBufferString(`{"email":"alice@example.com"}`))
+	w := httptest.NewRecorder()
+
+	h.Invite(w, r)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestAuthHandler_AcceptInvitation_Success(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("AcceptInvitation", mock.Anything, "invite-token", mock.AnythingOfType("*models.User")).Return(nil)
+
+	body := `{"token":"invite-token","first_name":"Nina","last_name":"Admin","password":"password123"}`
+	r := httptest.NewRequest(http.MethodPost, "/auth/invitations/accept", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.AcceptInvitation(w, r)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestAuthHandler_AcceptInvitation_InvalidToken(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("AcceptInvitation", mock.Anything, "bad", mock.AnythingOfType("*models.User")).Return(services.ErrInvalidInvitation)
+
+	body := `{"token":"bad","first_name":"Nina","last_name":"Admin","password":"password123"}`
+	r := httptest.NewRequest(http.MethodPost, "/auth/invitations/accept", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.AcceptInvitation(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAuthHandler_UnlockAccount_Success(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	userID := bson.NewObjectID().Hex()
+	mockSvc.On("UnlockAccount", mock.Anything, userID).Return(nil)
+
+	r := httptest.NewRequest(http.MethodPost, "/users/"+userID+"/unlock", nil)
+	r = addChiURLParam(r, "id", userID)
+	w := httptest.NewRecorder()
+
+	h.UnlockAccount(w, r)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestAuthHandler_UnlockAccount_NotFound(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	userID := bson.NewObjectID().Hex()
+	mockSvc.On("UnlockAccount", mock.Anything, userID).Return(mongo.ErrNoDocuments)
+
+	r := httptest.NewRequest(http.MethodPost, "/users/"+userID+"/unlock", nil)
+	r = addChiURLParam(r, "id", userID)
+	w := httptest.NewRecorder()
+
+	h.UnlockAccount(w, r)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAuthHandler_UnlockAccount_InvalidID(t *testing.T) {
+	for _, id := range []string{"not-an-id", "zzzzzzzzzzzzzzzzzzzzzzzz"} {
+		t.Run(id, func(t *testing.T) {
+			mockSvc := new(mocks.MockAuthService)
+			h := handlers.NewAuthHandler(mockSvc)
+
+			r := httptest.NewRequest(http.MethodPost, "/users/"+id+"/unlock", nil)
+			r = addChiURLParam(r, "id", id)
+			w := httptest.NewRecorder()
+
+			h.UnlockAccount(w, r)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			mockSvc.AssertNotCalled(t, "UnlockAccount", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAuthHandler_UnlockAccount_ServiceError(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	userID := bson.NewObjectID().Hex()
+	mockSvc.On("UnlockAccount", mock.Anything, userID).Return(errors.New("connection reset"))
+
+	r := httptest.NewRequest(http.MethodPost, "/users/"+userID+"/unlock", nil)
+	r = addChiURLParam(r, "id", userID)
+	w := httptest.NewRecorder()
+
+	h.UnlockAccount(w, r)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestAuthHandler_Login_MFARequired(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	challenge := &models.AuthTokens{MFARequired: true, MFAToken: "challenge", ExpiresIn: 300}
+	mockSvc.On("Login", mock.Anything, "admin@example.com", "password123", mock.Anything).Return(challenge, nil)
+
+	body := `{"email":"admin@example.com","password":"password123"}`
+	r := httptest.NewRequest(http.MethodPost, "/auth/login", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.Login(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Equal(t, true, resp["mfa_required"])
+	assert.Equal(t, "challenge", resp["mfa_token"])
+	assert.NotContains(t, resp, "token")
+}
+
+func TestAuthHandler_VerifyMFA_Success(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	tokens := &models.AuthTokens{AccessToken: "jwt-token", RefreshToken: "refresh-token", ExpiresIn: 900}
+	mockSvc.On("VerifyMFALogin", mock.Anything, "challenge", "123456", "192.0.2.1").Return(tokens, nil)
+
+	body := `{"mfa_token":"challenge","code":"123456"}`
+	r := httptest.NewRequest(http.MethodPost, "/auth/login/mfa", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.VerifyMFA(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Equal(t, "jwt-token", resp["token"])
+	mockSvc.AssertExpectations(t)
+}
+
+func TestAuthHandler_VerifyMFA_InvalidCode(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("VerifyMFALogin", mock.Anything, "challenge", "000000", mock.Anything).Return(nil, services.ErrInvalidMFACode)
+
+	body := `{"mfa_token":"challenge","code":"000000"}`
+	r := httptest.NewRequest(http.MethodPost, "/auth/login/mfa", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.VerifyMFA(w, r)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthHandler_VerifyMFA_Locked(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("VerifyMFALogin", mock.Anything, "challenge", "000000", mock.Anything).Return(nil, &services.LoginLockedError{RetryAfter: time.Minute})
+
+	body := `{"mfa_token":"challenge","code":"000000"}`
+	r := httptest.NewRequest(http.MethodPost, "/auth/login/mfa", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.VerifyMFA(w, r)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+}
+
+func TestAuthHandler_VerifyMFA_MissingCode(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	body := `{"mfa_token":"challenge"}`
+	r := httptest.NewRequest(http.MethodPost, "/auth/login/mfa", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.VerifyMFA(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "VerifyMFALogin", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthHandler_EnrollMFA_Success(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	enrollment := &models.MFAEnrollment{Secret: "SECRET", URI: "otpauth://totp/Jobs%20API:admin@example.com?secret=SECRET"}
+	mockSvc.On("EnrollMFA", mock.Anything, "user-1").Return(enrollment, nil)
+
+	r := withClaims(httptest.NewRequest(http.MethodPost, "/auth/mfa/enroll", nil), &middleware.Claims{UserID: "user-1", Role: "admin"})
+	w := httptest.NewRecorder()
+
+	h.EnrollMFA(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Equal(t, "SECRET", resp["secret"])
+	assert.Equal(t, enrollment.URI, resp["otpauth_uri"])
+}
+
+func TestAuthHandler_EnrollMFA_AlreadyEnabled(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("EnrollMFA", mock.Anything, "user-1").Return(nil, services.ErrMFAAlreadyEnabled)
+
+	r := withClaims(httptest.NewRequest(http.MethodPost, "/auth/mfa/enroll", nil), &middleware.Claims{UserID: "user-1", Role: "admin"})
+	w := httptest.NewRecorder()
+
+	h.EnrollMFA(w, r)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestAuthHandler_ConfirmMFA_Success(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("ConfirmMFA", mock.Anything, "user-1", "123456").Return([]string{"0a1b2-c3d4e"}, nil)
+
+	r := withClaims(httptest.NewRequest(http.MethodPost, "/auth/mfa/confirm", bytes.NewBufferString(`{"code":"123456"}`)), &middleware.Claims{UserID: "user-1", Role: "admin"})
+	w := httptest.NewRecorder()
+
+	h.ConfirmMFA(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string][]string
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Equal(t, []string{"0a1b2-c3d4e"}, resp["recovery_codes"])
+}
+
+func TestAuthHandler_ConfirmMFA_InvalidCode(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("ConfirmMFA", mock.Anything, "user-1", "000000").Return([]string(nil), services.ErrInvalidMFACode)
+
+	r := withClaims(httptest.NewRequest(http.MethodPost, "/auth/mfa/confirm", bytes.NewBufferString(`{"code":"000000"}`)), &middleware.Claims{UserID: "user-1", Role: "admin"})
+	w := httptest.NewRecorder()
+
+	h.ConfirmMFA(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAuthHandler_DisableMFA_Success(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	mockSvc.On("DisableMFA", mock.Anything, "user-1", "123456").Return(nil)
+
+	r := withClaims(httptest.NewRequest(http.MethodPost, "/auth/mfa/disable", bytes.NewBufferString(`{"code":"123456"}`)), &middleware.Claims{UserID: "user-1", Role: "recruiter"})
+	w := httptest.NewRecorder()
+
+	h.DisableMFA(w, r)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestAuthHandler_DisableMFA_Unauthenticated(t *testing.T) {
+	mockSvc := new(mocks.MockAuthService)
+	h := handlers.NewAuthHandler(mockSvc)
+
+	r := httptest.NewRequest(http.MethodPost, "/auth/mfa/disable", bytes.NewBufferString(`{"code":"123456"}`))
+	w := httptest.NewRecorder()
+
+	h.DisableMFA(w, r)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
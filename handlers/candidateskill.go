@@ -8,7 +8,6 @@ import (
 	"log"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -50,6 +49,9 @@ func (h *CandidateSkillHandler) GetAllCandidateSkills(w http.ResponseWriter, r *
 		"skill_id":          r.URL.Query().Get("skill_id"),
 		"proficiency_level": r.URL.Query().Get("proficiency_level"),
 	}
+	if includeDeleted(r) {
+		filters[models.FilterIncludeDeleted] = "true"
+	}
 
 	sort := r.URL.Query().Get("sort")
 	order := r.URL.Query().Get("order")
@@ -85,7 +87,7 @@ func (h *CandidateSkillHandler) GetCandidateSkillByID(w http.ResponseWriter, r *
 
 	candidateSkill, err := h.service.GetCandidateSkillByID(ctx, candidateSkillID)
 	if err != nil {
-		http.Error(w, "Candidate skill not found", http.StatusNotFound)
+		writeServiceError(w, err, "Candidate skill not found", http.StatusNotFound)
 		return
 	}
 
@@ -104,7 +106,7 @@ func (h *CandidateSkillHandler) GetCandidateSkillsByUserID(w http.ResponseWriter
 
 	candidateSkills, err := h.service.GetCandidateSkillsByUserID(ctx, userID)
 	if err != nil {
-		http.Error(w, "Failed to retrieve candidate skills", http.StatusInternalServerError)
+		writeServiceError(w, err, "Failed to retrieve candidate skills", http.StatusInternalServerError)
 		return
 	}
 
@@ -138,14 +140,9 @@ func (h *CandidateSkillHandler) CreateCandidateSkill(w http.ResponseWriter, r *h
 		return
 	}
 
-	candidateSkill.CreatedTime = time.Now()
-	candidateSkill.UpdatedTime = time.Now()
-	candidateSkill.CreatedBy = "system"
-	candidateSkill.UpdatedBy = "system"
-
 	err = h.service.CreateCandidateSkill(ctx, &candidateSkill)
 	if err != nil {
-		http.Error(w, "Failed to create candidate skill", http.StatusInternalServerError)
+		writeServiceError(w, err, "Failed to create candidate skill", http.StatusInternalServerError)
 		return
 	}
 
@@ -173,7 +170,7 @@ func (h *CandidateSkillHandler) UpdateCandidateSkillProficiencyLevel(w http.Resp
 
 	err = h.service.UpdateCandidateSkillProficiencyLevel(ctx, candidateSkillID, request.ProficiencyLevel)
 	if err != nil {
-		http.Error(w, "Failed to update candidate skill", http.StatusInternalServerError)
+		writeServiceError(w, err, "Failed to update candidate skill", http.StatusInternalServerError)
 		return
 	}
 
@@ -188,7 +185,21 @@ func (h *CandidateSkillHandler) DeleteCandidateSkill(w http.ResponseWriter, r *h
 
 	err := h.service.DeleteCandidateSkill(ctx, candidateSkillID)
 	if err != nil {
-		http.Error(w, "Candidate skill not found", http.StatusNotFound)
+		writeServiceError(w, err, "Candidate skill not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreCandidateSkill handles POST /candidateskills/{id}/restore request
+func (h *CandidateSkillHandler) RestoreCandidateSkill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	candidateSkillID := chi.URLParam(r, "id")
+
+	err := h.service.RestoreCandidateSkill(ctx, candidateSkillID)
+	if err != nil {
+		writeServiceError(w, err, "Deleted candidate skill not found", http.StatusNotFound)
 		return
 	}
 
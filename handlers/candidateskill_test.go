@@ -10,6 +10,7 @@ import (
 	"go-mongodb-api/handlers"
 	"go-mongodb-api/mocks"
 	"go-mongodb-api/models"
+	"go-mongodb-api/services"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -188,3 +189,18 @@ func TestCandidateSkillHandler_DeleteCandidateSkill_NotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
+
+func TestCandidateSkillHandler_DeleteCandidateSkill_Forbidden(t *testing.T) {
+	mockSvc := new(mocks.MockCandidateSkillService)
+	h := handlers.NewCandidateSkillHandler(mockSvc)
+
+	mockSvc.On("DeleteCandidateSkill", mock.Anything, "cs-id").Return(services.ErrForbidden)
+
+	r := httptest.NewRequest(http.MethodDelete, "/candidateskills/cs-id", nil)
+	r = addChiURLParam(r, "id", "cs-id")
+	w := httptest.NewRecorder()
+
+	h.DeleteCandidateSkill(w, r)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
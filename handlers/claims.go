@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"context"
+	"go-mongodb-api/middleware"
+	"go-mongodb-api/models"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ownerFromClaims returns the user a new resource should belong to. Non-admin callers always
+// act for themselves, so their ID is taken from the token; admins may act for the user in the body.
+func ownerFromClaims(ctx context.Context, requested bson.ObjectID) bson.ObjectID {
+	claims, ok := middleware.GetClaims(ctx)
+	if !ok || claims.Role == models.RoleAdmin {
+		return requested
+	}
+
+	userID, err := bson.ObjectIDFromHex(claims.UserID)
+	if err != nil {
+		return requested
+	}
+	return userID
+}
+
+// includeDeleted reports whether a listing asked for soft-deleted records with
+// ?include_deleted=true. Only admins get them; the flag is ignored for anyone else.
+func includeDeleted(r *http.Request) bool {
+	if r.URL.Query().Get(models.FilterIncludeDeleted) != "true" {
+		return false
+	}
+	claims, ok := middleware.GetClaims(r.Context())
+	return ok && claims.Role == models.RoleAdmin
+}
+
+// userResponse returns the response for user. Whether the account has two-factor authentication
+// is only included for the user themselves and for admins.
+func userResponse(ctx context.Context, user *models.User) models.UserResponse {
+	response := user.ToResponse()
+	claims, ok := middleware.GetClaims(ctx)
+	if ok && (claims.Role == models.RoleAdmin || claims.UserID == user.ID.Hex()) {
+		mfaEnabled := user.MFAEnabled
+		response.MFAEnabled = &mfaEnabled
+	}
+	return response
+}
@@ -8,7 +8,6 @@ import (
 	"log"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -43,6 +42,9 @@ func (h *CountryHandler) GetAllCountries(w http.ResponseWriter, r *http.Request)
 	filters := map[string]string{
 		"name": r.URL.Query().Get("name"),
 	}
+	if includeDeleted(r) {
+		filters[models.FilterIncludeDeleted] = "true"
+	}
 
 	sort := r.URL.Query().Get("sort")
 	order := r.URL.Query().Get("order")
@@ -107,11 +109,6 @@ func (h *CountryHandler) CreateCountry(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	country.CreatedTime = time.Now()
-	country.UpdatedTime = time.Now()
-	country.CreatedBy = "system"
-	country.UpdatedBy = "system"
-
 	err = h.service.CreateCountry(ctx, &country)
 	if err != nil {
 		http.Error(w, "Failed to create country", http.StatusInternalServerError)
@@ -136,9 +133,6 @@ func (h *CountryHandler) UpdateCountry(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	country.UpdatedTime = time.Now()
-	country.UpdatedBy = "system"
-
 	updated, err := h.service.UpdateCountry(ctx, countryID, &country)
 	if err != nil {
 		http.Error(w, "Country not found", http.StatusNotFound)
@@ -159,7 +153,20 @@ func (h *CountryHandler) DeleteCountry(w http.ResponseWriter, r *http.Request) {
 
 	err := h.service.DeleteCountry(ctx, countryID)
 	if err != nil {
-		http.Error(w, "Country not found", http.StatusNotFound)
+		writeServiceError(w, err, "Country not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CountryHandler) RestoreCountry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	countryID := chi.URLParam(r, "id")
+
+	err := h.service.RestoreCountry(ctx, countryID)
+	if err != nil {
+		writeServiceError(w, err, "Deleted country not found", http.StatusNotFound)
 		return
 	}
 
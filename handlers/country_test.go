@@ -200,3 +200,19 @@ func TestCountryHandler_DeleteCountry_Success(t *testing.T) {
 	assert.Equal(t, http.StatusNoContent, w.Code)
 	mockSvc.AssertExpectations(t)
 }
+
+func TestCountryHandler_DeleteCountry_HasDependents(t *testing.T) {
+	mockSvc := new(mocks.MockCountryService)
+	h := handlers.NewCountryHandler(mockSvc)
+
+	mockSvc.On("DeleteCountry", mock.Anything, "country-id").Return(&models.DependentsError{Dependents: map[string]int64{"jobs": 2, "cities": 40}})
+
+	r := httptest.NewRequest(http.MethodDelete, "/countries/country-id", nil)
+	r = addChiURLParam(r, "id", "country-id")
+	w := httptest.NewRecorder()
+
+	h.DeleteCountry(w, r)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"cities":40`)
+}
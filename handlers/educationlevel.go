@@ -8,7 +8,6 @@ import (
 	"log"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -43,6 +42,9 @@ func (h *EducationLevelHandler) GetAllEducationLevels(w http.ResponseWriter, r *
 	filters := map[string]string{
 		"title": r.URL.Query().Get("title"),
 	}
+	if includeDeleted(r) {
+		filters[models.FilterIncludeDeleted] = "true"
+	}
 
 	sort := r.URL.Query().Get("sort")
 	order := r.URL.Query().Get("order")
@@ -107,11 +109,6 @@ func (h *EducationLevelHandler) CreateEducationLevel(w http.ResponseWriter, r *h
 		return
 	}
 
-	educationLevel.CreatedTime = time.Now()
-	educationLevel.UpdatedTime = time.Now()
-	educationLevel.CreatedBy = "system"
-	educationLevel.UpdatedBy = "system"
-
 	err = h.service.CreateEducationLevel(ctx, &educationLevel)
 	if err != nil {
 		http.Error(w, "Failed to create education level", http.StatusInternalServerError)
@@ -136,9 +133,6 @@ func (h *EducationLevelHandler) UpdateEducationLevel(w http.ResponseWriter, r *h
 		return
 	}
 
-	educationLevel.UpdatedTime = time.Now()
-	educationLevel.UpdatedBy = "system"
-
 	updated, err := h.service.UpdateEducationLevel(ctx, educationLevelID, &educationLevel)
 	if err != nil {
 		http.Error(w, "Education level not found", http.StatusNotFound)
@@ -159,7 +153,20 @@ func (h *EducationLevelHandler) DeleteEducationLevel(w http.ResponseWriter, r *h
 
 	err := h.service.DeleteEducationLevel(ctx, educationLevelID)
 	if err != nil {
-		http.Error(w, "Education level not found", http.StatusNotFound)
+		writeServiceError(w, err, "Education level not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *EducationLevelHandler) RestoreEducationLevel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	educationLevelID := chi.URLParam(r, "id")
+
+	err := h.service.RestoreEducationLevel(ctx, educationLevelID)
+	if err != nil {
+		writeServiceError(w, err, "Deleted education level not found", http.StatusNotFound)
 		return
 	}
 
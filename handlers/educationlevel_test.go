@@ -200,3 +200,19 @@ func TestEducationLevelHandler_DeleteEducationLevel_Success(t *testing.T) {
 	assert.Equal(t, http.StatusNoContent, w.Code)
 	mockSvc.AssertExpectations(t)
 }
+
+func TestEducationLevelHandler_DeleteEducationLevel_HasDependents(t *testing.T) {
+	mockSvc := new(mocks.MockEducationLevelService)
+	h := handlers.NewEducationLevelHandler(mockSvc)
+
+	mockSvc.On("DeleteEducationLevel", mock.Anything, "level-id").Return(&models.DependentsError{Dependents: map[string]int64{"jobs": 3}})
+
+	r := httptest.NewRequest(http.MethodDelete, "/educationlevels/level-id", nil)
+	r = addChiURLParam(r, "id", "level-id")
+	w := httptest.NewRecorder()
+
+	h.DeleteEducationLevel(w, r)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"jobs":3`)
+}
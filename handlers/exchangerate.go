@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-mongodb-api/helpers"
+	"go-mongodb-api/interfaces"
+	"go-mongodb-api/models"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ExchangeRateHandler struct {
+	service interfaces.ExchangeRateService
+}
+
+func NewExchangeRateHandler(service interfaces.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{service: service}
+}
+
+type setExchangeRateRequest struct {
+	Rate float64 `json:"rate"`
+}
+
+// GetExchangeRates handles GET /exchangerates request
+func (h *ExchangeRateHandler) GetExchangeRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.service.GetExchangeRates(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to retrieve exchange rates", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rates); err != nil {
+		log.Printf("error encoding response: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// SetExchangeRate handles PUT /exchangerates/{currency} request, creating or replacing the rate
+func (h *ExchangeRateHandler) SetExchangeRate(w http.ResponseWriter, r *http.Request) {
+	var req setExchangeRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	rate := models.ExchangeRate{
+		Currency: strings.ToUpper(chi.URLParam(r, "currency")),
+		Rate:     req.Rate,
+	}
+	if validationErrors := helpers.ValidateStruct(rate); len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
+		return
+	}
+
+	stored, err := h.service.SetExchangeRate(r.Context(), rate.Currency, rate.Rate)
+	if err != nil {
+		writeServiceError(w, err, "Failed to set exchange rate", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(stored); err != nil {
+		log.Printf("error encoding response: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteExchangeRate handles DELETE /exchangerates/{currency} request
+func (h *ExchangeRateHandler) DeleteExchangeRate(w http.ResponseWriter, r *http.Request) {
+	currency := strings.ToUpper(chi.URLParam(r, "currency"))
+
+	if err := h.service.DeleteExchangeRate(r.Context(), currency); err != nil {
+		writeServiceError(w, err, "Exchange rate not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,140 @@
+package handlers_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-mongodb-api/handlers"
+	"go-mongodb-api/mocks"
+	"go-mongodb-api/models"
+	"go-mongodb-api/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestExchangeRateHandler_GetExchangeRates_Success(t *testing.T) {
+	mockSvc := new(mocks.MockExchangeRateService)
+	h := handlers.NewExchangeRateHandler(mockSvc)
+
+	rates := &models.ExchangeRates{BaseCurrency: "USD", Rates: []models.ExchangeRate{{Currency: "EUR", Rate: 0.92}}}
+	mockSvc.On("GetExchangeRates", mock.Anything).Return(rates, nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/exchangerates", nil)
+	w := httptest.NewRecorder()
+
+	h.GetExchangeRates(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"base_currency":"USD"`)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestExchangeRateHandler_GetExchangeRates_Error(t *testing.T) {
+	mockSvc := new(mocks.MockExchangeRateService)
+	h := handlers.NewExchangeRateHandler(mockSvc)
+
+	mockSvc.On("GetExchangeRates", mock.Anything).Return(nil, errors.New("db error"))
+
+	r := httptest.NewRequest(http.MethodGet, "/exchangerates", nil)
+	w := httptest.NewRecorder()
+
+	h.GetExchangeRates(w, r)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestExchangeRateHandler_SetExchangeRate_Success(t *testing.T) {
+	mockSvc := new(mocks.MockExchangeRateService)
+	h := handlers.NewExchangeRateHandler(mockSvc)
+
+	mockSvc.On("SetExchangeRate", mock.Anything, "EUR", 0.92).Return(&models.ExchangeRate{Currency: "EUR", Rate: 0.92}, nil)
+
+	r := httptest.NewRequest(http.MethodPut, "/exchangerates/eur", bytes.NewBufferString(`{"rate":0.92}`))
+	r = addChiURLParam(r, "currency", "eur")
+	w := httptest.NewRecorder()
+
+	h.SetExchangeRate(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestExchangeRateHandler_SetExchangeRate_InvalidRate(t *testing.T) {
+	mockSvc := new(mocks.MockExchangeRateService)
+	h := handlers.NewExchangeRateHandler(mockSvc)
+
+	r := httptest.NewRequest(http.MethodPut, "/exchangerates/EUR", bytes.NewBufferString(`{"rate":0}`))
+	r = addChiURLParam(r, "currency", "EUR")
+	w := httptest.NewRecorder()
+
+	h.SetExchangeRate(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Rate")
+	mockSvc.AssertNotCalled(t, "SetExchangeRate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExchangeRateHandler_SetExchangeRate_UnknownCurrency(t *testing.T) {
+	mockSvc := new(mocks.MockExchangeRateService)
+	h := handlers.NewExchangeRateHandler(mockSvc)
+
+	r := httptest.NewRequest(http.MethodPut, "/exchangerates/ABC", bytes.NewBufferString(`{"rate":1.5}`))
+	r = addChiURLParam(r, "currency", "ABC")
+	w := httptest.NewRecorder()
+
+	h.SetExchangeRate(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Currency")
+}
+
+func TestExchangeRateHandler_SetExchangeRate_BaseCurrency(t *testing.T) {
+	mockSvc := new(mocks.MockExchangeRateService)
+	h := handlers.NewExchangeRateHandler(mockSvc)
+
+	mockSvc.On("SetExchangeRate", mock.Anything, "USD", 1.5).Return(nil, services.ErrBaseCurrencyRate)
+
+	r := httptest.NewRequest(http.MethodPut, "/exchangerates/USD", bytes.NewBufferString(`{"rate":1.5}`))
+	r = addChiURLParam(r, "currency", "USD")
+	w := httptest.NewRecorder()
+
+	h.SetExchangeRate(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestExchangeRateHandler_DeleteExchangeRate_Success(t *testing.T) {
+	mockSvc := new(mocks.MockExchangeRateService)
+	h := handlers.NewExchangeRateHandler(mockSvc)
+
+	mockSvc.On("DeleteExchangeRate", mock.Anything, "EUR").Return(nil)
+
+	r := httptest.NewRequest(http.MethodDelete, "/exchangerates/eur", nil)
+	r = addChiURLParam(r, "currency", "eur")
+	w := httptest.NewRecorder()
+
+	h.DeleteExchangeRate(w, r)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestExchangeRateHandler_DeleteExchangeRate_NotFound(t *testing.T) {
+	mockSvc := new(mocks.MockExchangeRateService)
+	h := handlers.NewExchangeRateHandler(mockSvc)
+
+	mockSvc.On("DeleteExchangeRate", mock.Anything, "EUR").Return(mongo.ErrNoDocuments)
+
+	r := httptest.NewRequest(http.MethodDelete, "/exchangerates/EUR", nil)
+	r = addChiURLParam(r, "currency", "EUR")
+	w := httptest.NewRecorder()
+
+	h.DeleteExchangeRate(w, r)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"go-mongodb-api/models"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// parseExpand reads the comma-separated ?expand= list of references to embed. It returns the
+// expansions without duplicates and, when one is not in allowed, the message for a 400 response.
+func parseExpand(r *http.Request, allowed []string) ([]string, string) {
+	value := r.URL.Query().Get(models.FilterExpand)
+	if value == "" {
+		return nil, ""
+	}
+
+	var expand []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if !slices.Contains(allowed, field) {
+			return nil, "Invalid expand: " + field + " (allowed: " + strings.Join(allowed, ", ") + ")"
+		}
+		if !slices.Contains(expand, field) {
+			expand = append(expand, field)
+		}
+	}
+	return expand, ""
+}
@@ -2,15 +2,22 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"go-mongodb-api/helpers"
 	"go-mongodb-api/interfaces"
 	"go-mongodb-api/models"
+	"io"
 	"log"
+	"maps"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 type JobHandler struct {
@@ -49,9 +56,60 @@ func (h *JobHandler) GetAllJobs(w http.ResponseWriter, r *http.Request) {
 		"title":       r.URL.Query().Get("title"),
 		"description": r.URL.Query().Get("description"),
 		"location":    r.URL.Query().Get("location"),
-		"job_type":    r.URL.Query().Get("job_type"),
 		"status":      r.URL.Query().Get("status"),
 	}
+	if includeDeleted(r) {
+		filters[models.FilterIncludeDeleted] = "true"
+	}
+	if q := r.URL.Query().Get(models.FilterSearch); q != "" {
+		search, err := helpers.ParseSearchQuery(q)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		filters[models.FilterSearch] = search.String()
+	}
+	if message := parseLocationSearch(r.URL.Query(), filters); message != "" {
+		http.Error(w, message, http.StatusBadRequest)
+		return
+	}
+	for _, param := range []string{models.FilterSalaryMin, models.FilterSalaryMax} {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			continue
+		}
+		if amount, err := strconv.Atoi(value); err != nil || amount < 0 {
+			http.Error(w, "Invalid "+param, http.StatusBadRequest)
+			return
+		}
+		filters[param] = value
+	}
+	if currency := r.URL.Query().Get("salary_currency"); currency != "" {
+		filters["salary_currency"] = strings.ToUpper(currency)
+	}
+	if period := r.URL.Query().Get("salary_period"); period != "" {
+		filters["salary_period"] = period
+	}
+	if r.URL.Query().Get(models.FilterNormalizeSalary) == "true" {
+		filters[models.FilterNormalizeSalary] = "true"
+	}
+	for _, param := range models.JobReferenceFilters {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			continue
+		}
+		if _, err := bson.ObjectIDFromHex(value); err != nil {
+			http.Error(w, "Invalid "+param, http.StatusBadRequest)
+			return
+		}
+		filters[param] = value
+	}
+
+	expand, message := parseExpand(r, models.JobExpansions)
+	if message != "" {
+		http.Error(w, message, http.StatusBadRequest)
+		return
+	}
 
 	// Parse sort parameters
 	sort := r.URL.Query().Get("sort")
@@ -60,7 +118,10 @@ func (h *JobHandler) GetAllJobs(w http.ResponseWriter, r *http.Request) {
 	// Get jobs with pagination, filters, and sorting
 	jobs, total, err := h.service.GetAllJobs(ctx, page, limit, filters, sort, order)
 	if err != nil {
-		http.Error(w, "Failed to retrieve jobs", http.StatusInternalServerError)
+		writeServiceError(w, err, "Failed to retrieve jobs", http.StatusInternalServerError)
+		return
+	}
+	if !h.expandJobs(w, r, jobs, expand) {
 		return
 	}
 
@@ -68,10 +129,24 @@ func (h *JobHandler) GetAllJobs(w http.ResponseWriter, r *http.Request) {
 	pagination := helpers.NewPagination(page, limit)
 	pagination.SetTotal(total)
 
-	response := helpers.PaginatedResponse{
+	result := helpers.PaginatedResponse{
 		Data:       jobs,
 		Pagination: pagination,
 	}
+	var response interface{} = result
+
+	// Facet mode adds counts computed over the same filters as the page
+	if r.URL.Query().Get(models.FilterFacets) == "true" {
+		facets, err := h.service.GetJobFacets(ctx, filters)
+		if err != nil {
+			http.Error(w, "Failed to retrieve job facets", http.StatusInternalServerError)
+			return
+		}
+		response = facetedJobsResponse{
+			PaginatedResponse: result,
+			Facets:            facets,
+		}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -81,16 +156,80 @@ func (h *JobHandler) GetAllJobs(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// maxRadiusKm is the largest search radius, half the Earth's circumference
+const maxRadiusKm = 20038
+
+// parseLocationSearch copies the location search parameters (lat and lng, or near, with an optional
+// radius_km) into filters. It returns the reason they are rejected, or an empty string.
+func parseLocationSearch(query url.Values, filters map[string]string) string {
+	lat := query.Get(models.FilterLatitude)
+	lng := query.Get(models.FilterLongitude)
+	near := query.Get(models.FilterNear)
+	radius := query.Get(models.FilterRadiusKm)
+
+	switch {
+	case lat == "" && lng == "" && near == "":
+		if radius != "" {
+			return "radius_km requires lat and lng, or near"
+		}
+		return ""
+	case near != "" && (lat != "" || lng != ""):
+		return "Use either near or lat and lng"
+	case near == "" && (lat == "" || lng == ""):
+		return "lat and lng must be given together"
+	case filters[models.FilterSearch] != "":
+		// $geoNear cannot run a text search
+		return "Full-text search cannot be combined with a location search"
+	}
+
+	if near != "" {
+		filters[models.FilterNear] = near
+	} else {
+		if value, err := strconv.ParseFloat(lat, 64); err != nil || value < -90 || value > 90 {
+			return "Invalid lat"
+		}
+		if value, err := strconv.ParseFloat(lng, 64); err != nil || value < -180 || value > 180 {
+			return "Invalid lng"
+		}
+		filters[models.FilterLatitude] = lat
+		filters[models.FilterLongitude] = lng
+	}
+	if radius != "" {
+		if value, err := strconv.ParseFloat(radius, 64); err != nil || value <= 0 || value > maxRadiusKm {
+			return "Invalid radius_km"
+		}
+		filters[models.FilterRadiusKm] = radius
+	}
+	return ""
+}
+
+// facetedJobsResponse is a page of jobs with the facet counts for the whole result
+type facetedJobsResponse struct {
+	helpers.PaginatedResponse
+	Facets *models.JobFacets `json:"facets"`
+}
+
 // GetJobByID handles GET /jobs/:id request
 func (h *JobHandler) GetJobByID(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	jobID := chi.URLParam(r, "id")
 
+	expand, message := parseExpand(r, models.JobExpansions)
+	if message != "" {
+		http.Error(w, message, http.StatusBadRequest)
+		return
+	}
+
 	job, err := h.service.GetJobByID(ctx, jobID)
 	if err != nil {
 		http.Error(w, "Job not found", http.StatusNotFound)
 		return
 	}
+	jobs := []models.Job{*job}
+	if !h.expandJobs(w, r, jobs, expand) {
+		return
+	}
+	job = &jobs[0]
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(job); err != nil {
@@ -105,11 +244,20 @@ func (h *JobHandler) GetJobsByUser(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	userID := chi.URLParam(r, "userId")
 
+	expand, message := parseExpand(r, models.JobExpansions)
+	if message != "" {
+		http.Error(w, message, http.StatusBadRequest)
+		return
+	}
+
 	jobs, err := h.service.GetJobsByUser(ctx, userID)
 	if err != nil {
 		http.Error(w, "Failed to retrieve jobs", http.StatusInternalServerError)
 		return
 	}
+	if !h.expandJobs(w, r, jobs, expand) {
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(jobs); err != nil {
@@ -119,6 +267,19 @@ func (h *JobHandler) GetJobsByUser(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// expandJobs embeds the requested references into jobs. It writes the error response and returns
+// false when they cannot be resolved.
+func (h *JobHandler) expandJobs(w http.ResponseWriter, r *http.Request, jobs []models.Job, expand []string) bool {
+	if len(expand) == 0 {
+		return true
+	}
+	if err := h.service.ExpandJobs(r.Context(), jobs, expand); err != nil {
+		http.Error(w, "Failed to expand jobs", http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
 // CreateJob handles POST /jobs request
 func (h *JobHandler) CreateJob(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -130,43 +291,345 @@ func (h *JobHandler) CreateJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	job.UserID = ownerFromClaims(ctx, job.UserID)
+	if job.Status == "" {
+		job.Status = models.JobStatusDraft
+	}
+	normalizeSalaryFields(&job)
+
 	// Validate request body
-	validationErrors := helpers.ValidateStruct(job)
+	validationErrors := validateJob(job)
+	if len(validationErrors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(helpers.ErrorResponse{Errors: validationErrors}); err != nil {
+			log.Printf("error encoding validation error response: %v", err)
+		}
+		return
+	}
+
+	err = h.service.CreateJob(ctx, &job)
+	if err != nil {
+		writeServiceError(w, err, "Failed to create job", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(job); err != nil {
+		log.Printf("error encoding response: %v", err)
+		return
+	}
+}
+
+// UpdateJob handles PUT /jobs/{id} request, replacing every editable field of the job
+func (h *JobHandler) UpdateJob(w http.ResponseWriter, r *http.Request) {
+	h.updateJob(w, r, false)
+}
+
+// PatchJob handles PATCH /jobs/{id} request. The body is a JSON Merge Patch (RFC 7396)
+// of the editable fields; fields it leaves out keep their current value.
+func (h *JobHandler) PatchJob(w http.ResponseWriter, r *http.Request) {
+	h.updateJob(w, r, true)
+}
+
+// jobUpdatableFields are the JSON fields of a job that PUT and PATCH may change. Status changes
+// go through the lifecycle actions (publish, close, reopen, archive) instead, and pipeline stages
+// through PUT /jobs/{id}/stages.
+var jobUpdatableFields = map[string]struct{}{
+	"title":                    {},
+	"description":              {},
+	"category_id":              {},
+	"location":                 {},
+	"job_type_id":              {},
+	"country_id":               {},
+	"education_level_id":       {},
+	"location_availability_id": {},
+	"salary_min":               {},
+	"salary_max":               {},
+	"salary_currency":          {},
+	"salary_period":            {},
+	"closes_at":                {},
+	"coordinates":              {},
+	"screening_questions":      {},
+}
+
+func (h *JobHandler) updateJob(w http.ResponseWriter, r *http.Request, merge bool) {
+	ctx := r.Context()
+	jobID := chi.URLParam(r, "id")
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil || fields == nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var validationErrors []helpers.ValidationError
+	for _, field := range slices.Sorted(maps.Keys(fields)) {
+		if _, ok := jobUpdatableFields[field]; !ok {
+			validationErrors = append(validationErrors, helpers.ValidationError{
+				Field:   field,
+				Message: "This field cannot be updated",
+			})
+		}
+	}
+	if len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
+		return
+	}
+
+	existing, err := h.service.GetJobByID(ctx, jobID)
+	if err != nil {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
 
-	// Validate cross-field constraint: salary_max must be >= salary_min
+	if merge {
+		current, err := json.Marshal(existing)
+		if err != nil {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if body, err = helpers.MergePatch(current, body); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var changes models.Job
+	if err := json.Unmarshal(body, &changes); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	job := *existing
+	job.Title = changes.Title
+	job.Description = changes.Description
+	job.CategoryID = changes.CategoryID
+	job.Location = changes.Location
+	job.JobTypeID = changes.JobTypeID
+	job.CountryID = changes.CountryID
+	job.EducationLevelID = changes.EducationLevelID
+	job.LocationAvailabilityID = changes.LocationAvailabilityID
+	job.SalaryMin = changes.SalaryMin
+	job.SalaryMax = changes.SalaryMax
+	job.SalaryCurrency = changes.SalaryCurrency
+	job.SalaryPeriod = changes.SalaryPeriod
+	job.ClosesAt = changes.ClosesAt
+	job.Coordinates = changes.Coordinates
+	job.ScreeningQuestions = changes.ScreeningQuestions
+	if _, given := fields["coordinates"]; !given && job.Location != existing.Location {
+		// the coordinates of the old location no longer apply; they are geocoded again
+		job.Coordinates = nil
+	}
+	normalizeSalaryFields(&job)
+
+	if validationErrors := validateJob(job); len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
+		return
+	}
+
+	updated, err := h.service.UpdateJob(ctx, jobID, &job)
+	if err != nil {
+		writeServiceError(w, err, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(updated); err != nil {
+		log.Printf("error encoding response: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// normalizeSalaryFields upper-cases the salary currency and defaults the pay period to yearly
+func normalizeSalaryFields(job *models.Job) {
+	job.SalaryCurrency = strings.ToUpper(job.SalaryCurrency)
+	if job.SalaryPeriod == "" {
+		job.SalaryPeriod = models.SalaryPeriodYearly
+	}
+}
+
+// validateJob checks the struct rules of a job, its pipeline stages and screening questions, that
+// salary_max is not below salary_min and that coordinates, when given, are a valid point
+func validateJob(job models.Job) []helpers.ValidationError {
+	validationErrors := append(helpers.ValidateStruct(job), validateStages(job.Stages)...)
+	validationErrors = append(validationErrors, validateScreeningQuestions(job.ScreeningQuestions)...)
+	if job.Coordinates != nil && !job.Coordinates.Valid() {
+		validationErrors = append(validationErrors, helpers.ValidationError{
+			Field:   "coordinates",
+			Message: "must be a GeoJSON Point of [longitude, latitude]",
+		})
+	}
 	if job.SalaryMax < job.SalaryMin {
 		validationErrors = append(validationErrors, helpers.ValidationError{
 			Field:   "salary_max",
 			Message: "must be greater than or equal to salary_min",
 		})
 	}
+	return validationErrors
+}
 
-	if len(validationErrors) > 0 {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		if err := json.NewEncoder(w).Encode(helpers.ErrorResponse{Errors: validationErrors}); err != nil {
-			log.Printf("error encoding validation error response: %v", err)
+// validateScreeningQuestions checks that screening question keys are unique, that only multiple
+// choice questions have options (at least two), and that knockout rules fit their question: an
+// expected answer for yes/no, accepted options for multiple choice, a range for number questions and
+// none for text
+func validateScreeningQuestions(questions []models.ScreeningQuestion) []helpers.ValidationError {
+	var validationErrors []helpers.ValidationError
+	invalid := func(key, message string) {
+		validationErrors = append(validationErrors, helpers.ValidationError{
+			Field:   "screening_questions",
+			Message: key + ": " + message,
+		})
+	}
+
+	seen := make(map[string]bool, len(questions))
+	for _, question := range questions {
+		if seen[question.Key] {
+			invalid(question.Key, "duplicate question key")
+		}
+		seen[question.Key] = true
+
+		if question.Type == models.QuestionTypeMultipleChoice {
+			if len(question.Options) < 2 {
+				invalid(question.Key, "multiple choice questions need at least 2 options")
+			}
+		} else if len(question.Options) > 0 {
+			invalid(question.Key, "only multiple choice questions have options")
 		}
+
+		knockout := question.Knockout
+		if knockout == nil {
+			continue
+		}
+		switch question.Type {
+		case models.QuestionTypeYesNo:
+			if knockout.Expected == nil || len(knockout.Accepted) > 0 || knockout.Min != nil || knockout.Max != nil {
+				invalid(question.Key, "the knockout rule of a yes/no question needs only expected")
+			}
+		case models.QuestionTypeMultipleChoice:
+			if knockout.Expected != nil || len(knockout.Accepted) == 0 || knockout.Min != nil || knockout.Max != nil {
+				invalid(question.Key, "the knockout rule of a multiple choice question needs only accepted")
+			}
+			for _, option := range knockout.Accepted {
+				if !slices.Contains(question.Options, option) {
+					invalid(question.Key, "accepted option "+option+" is not one of the options")
+				}
+			}
+		case models.QuestionTypeNumber:
+			if knockout.Expected != nil || len(knockout.Accepted) > 0 || (knockout.Min == nil && knockout.Max == nil) {
+				invalid(question.Key, "the knockout rule of a number question needs only min, max or both")
+			} else if knockout.Min != nil && knockout.Max != nil && *knockout.Max < *knockout.Min {
+				invalid(question.Key, "the knockout max must be greater than or equal to min")
+			}
+		default:
+			invalid(question.Key, "text questions cannot have a knockout rule")
+		}
+	}
+	return validationErrors
+}
+
+// jobDeadlineRequest is the optional body of the publish and reopen actions
+type jobDeadlineRequest struct {
+	ClosesAt *time.Time `json:"closes_at"`
+}
+
+// PublishJob handles POST /jobs/{id}/publish request, moving a draft job to active
+func (h *JobHandler) PublishJob(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeJobDeadline(w, r)
+	if !ok {
 		return
 	}
+	job, err := h.service.PublishJob(r.Context(), chi.URLParam(r, "id"), req.ClosesAt)
+	writeJobTransition(w, job, err)
+}
 
-	job.CreatedTime = time.Now()
-	job.UpdatedTime = time.Now()
-	job.CreatedBy = "system"
-	job.UpdatedBy = "system"
+// CloseJob handles POST /jobs/{id}/close request, stopping an active job from taking applications
+func (h *JobHandler) CloseJob(w http.ResponseWriter, r *http.Request) {
+	job, err := h.service.CloseJob(r.Context(), chi.URLParam(r, "id"))
+	writeJobTransition(w, job, err)
+}
 
-	err = h.service.CreateJob(ctx, &job)
+// ReopenJob handles POST /jobs/{id}/reopen request, moving a closed job back to active
+func (h *JobHandler) ReopenJob(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeJobDeadline(w, r)
+	if !ok {
+		return
+	}
+	job, err := h.service.ReopenJob(r.Context(), chi.URLParam(r, "id"), req.ClosesAt)
+	writeJobTransition(w, job, err)
+}
+
+// ArchiveJob handles POST /jobs/{id}/archive request
+func (h *JobHandler) ArchiveJob(w http.ResponseWriter, r *http.Request) {
+	job, err := h.service.ArchiveJob(r.Context(), chi.URLParam(r, "id"))
+	writeJobTransition(w, job, err)
+}
+
+// decodeJobDeadline reads the optional closes_at body of a lifecycle action. An empty body is allowed.
+func decodeJobDeadline(w http.ResponseWriter, r *http.Request) (jobDeadlineRequest, bool) {
+	var req jobDeadlineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return req, false
+	}
+	return req, true
+}
+
+// writeJobTransition writes the job returned by an action on a job, or the error that stopped it
+func writeJobTransition(w http.ResponseWriter, job *models.Job, err error) {
 	if err != nil {
-		http.Error(w, "Failed to create job", http.StatusInternalServerError)
+		writeServiceError(w, err, "Job not found", http.StatusNotFound)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(job); err != nil {
 		log.Printf("error encoding response: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// jobStagesRequest is the body of PUT /jobs/{id}/stages: the stages themselves, or the pipeline
+// template to copy them from
+type jobStagesRequest struct {
+	Stages     []models.PipelineStage `json:"stages" validate:"max=20,dive"`
+	TemplateID string                 `json:"template_id"`
+}
+
+// SetJobStages handles PUT /jobs/{id}/stages request, replacing the job's pipeline stages
+func (h *JobHandler) SetJobStages(w http.ResponseWriter, r *http.Request) {
+	var req jobStagesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.TemplateID != "" {
+		if len(req.Stages) > 0 {
+			http.Error(w, "Give either stages or template_id", http.StatusBadRequest)
+			return
+		}
+		if _, err := bson.ObjectIDFromHex(req.TemplateID); err != nil {
+			http.Error(w, "Invalid template_id", http.StatusBadRequest)
+			return
+		}
+	}
+	validationErrors := append(helpers.ValidateStruct(req), validateStages(req.Stages)...)
+	if len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
 		return
 	}
+
+	job, err := h.service.SetJobStages(r.Context(), chi.URLParam(r, "id"), req.Stages, req.TemplateID)
+	writeJobTransition(w, job, err)
 }
 
 // DeleteJob handles DELETE /jobs/{id} request
@@ -176,7 +639,21 @@ func (h *JobHandler) DeleteJob(w http.ResponseWriter, r *http.Request) {
 
 	err := h.service.DeleteJob(ctx, jobID)
 	if err != nil {
-		http.Error(w, "Job not found", http.StatusNotFound)
+		writeServiceError(w, err, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreJob handles POST /jobs/{id}/restore request
+func (h *JobHandler) RestoreJob(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	jobID := chi.URLParam(r, "id")
+
+	err := h.service.RestoreJob(ctx, jobID)
+	if err != nil {
+		writeServiceError(w, err, "Deleted job not found", http.StatusNotFound)
 		return
 	}
 
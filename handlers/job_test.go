@@ -3,17 +3,23 @@ package handlers_test
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"go-mongodb-api/handlers"
 	"go-mongodb-api/mocks"
 	"go-mongodb-api/models"
+	"go-mongodb-api/services"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 func TestJobHandler_GetAllJobs_Success(t *testing.T) {
@@ -46,6 +52,336 @@ func TestJobHandler_GetAllJobs_Error(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
+func TestJobHandler_GetAllJobs_Search(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	jobs := []models.Job{{ID: bson.NewObjectID(), Title: "Go Developer", Score: 2.5}}
+	mockSvc.On("GetAllJobs", mock.Anything, 1, 10, mock.MatchedBy(func(f map[string]string) bool {
+		return f[models.FilterSearch] == `"remote first" golang -php -"on site"`
+	}), "relevance", "").Return(jobs, int64(1), nil)
+
+	q := url.QueryEscape(`golang "remote first" -php -"on   site"`)
+	r := httptest.NewRequest(http.MethodGet, "/jobs?q="+q+"&sort=relevance", nil)
+	w := httptest.NewRecorder()
+
+	h.GetAllJobs(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"score":2.5`)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestJobHandler_GetAllJobs_SearchUnterminatedPhrase(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("GetAllJobs", mock.Anything, 1, 10, mock.MatchedBy(func(f map[string]string) bool {
+		return f[models.FilterSearch] == `"senior engineer"`
+	}), "", "").Return([]models.Job{}, int64(0), nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/jobs?q="+url.QueryEscape(`"senior engineer\`), nil)
+	w := httptest.NewRecorder()
+
+	h.GetAllJobs(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestJobHandler_GetAllJobs_SearchOnlyExclusions(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	r := httptest.NewRequest(http.MethodGet, "/jobs?q="+url.QueryEscape(`-php -"on site"`), nil)
+	w := httptest.NewRecorder()
+
+	h.GetAllJobs(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "GetAllJobs", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestJobHandler_GetAllJobs_SearchTooLong(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	r := httptest.NewRequest(http.MethodGet, "/jobs?q="+strings.Repeat("a", 300), nil)
+	w := httptest.NewRecorder()
+
+	h.GetAllJobs(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestJobHandler_GetAllJobs_Facets(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	categoryID := bson.NewObjectID()
+	upper := 50000
+	facets := &models.JobFacets{
+		Categories:  []models.FacetCount{{Value: categoryID.Hex(), Count: 3}},
+		JobTypes:    []models.FacetCount{{Value: "665f1c2e8b3a4d0012345678", Count: 3}},
+		SalaryBands: []models.SalaryBandCount{{Min: 30000, Max: &upper, Count: 2}},
+	}
+	sameFilters := mock.MatchedBy(func(f map[string]string) bool {
+		return f["category_id"] == categoryID.Hex() && f["location"] == "Berlin"
+	})
+	mockSvc.On("GetAllJobs", mock.Anything, 1, 10, sameFilters, "", "").Return([]models.Job{}, int64(3), nil)
+	mockSvc.On("GetJobFacets", mock.Anything, sameFilters).Return(facets, nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/jobs?facets=true&location=Berlin&category_id="+categoryID.Hex(), nil)
+	w := httptest.NewRecorder()
+
+	h.GetAllJobs(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `"pagination"`)
+	assert.Contains(t, body, `"job_types":[{"value":"665f1c2e8b3a4d0012345678","count":3}]`)
+	assert.Contains(t, body, `"salary_bands":[{"min":30000,"max":50000,"count":2}]`)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestJobHandler_GetAllJobs_FacetsError(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("GetAllJobs", mock.Anything, 1, 10, mock.Anything, "", "").Return([]models.Job{}, int64(0), nil)
+	mockSvc.On("GetJobFacets", mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+
+	r := httptest.NewRequest(http.MethodGet, "/jobs?facets=true", nil)
+	w := httptest.NewRecorder()
+
+	h.GetAllJobs(w, r)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestJobHandler_GetAllJobs_WithoutFacets(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("GetAllJobs", mock.Anything, 1, 10, mock.Anything, "", "").Return([]models.Job{}, int64(0), nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/jobs", nil)
+	w := httptest.NewRecorder()
+
+	h.GetAllJobs(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), `"facets"`)
+	mockSvc.AssertNotCalled(t, "GetJobFacets", mock.Anything, mock.Anything)
+}
+
+func TestJobHandler_GetAllJobs_InvalidCategoryID(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	r := httptest.NewRequest(http.MethodGet, "/jobs?category_id=not-an-id", nil)
+	w := httptest.NewRecorder()
+
+	h.GetAllJobs(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestJobHandler_GetAllJobs_ReferenceFilters(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	jobTypeID, countryID, availabilityID := bson.NewObjectID().Hex(), bson.NewObjectID().Hex(), bson.NewObjectID().Hex()
+	mockSvc.On("GetAllJobs", mock.Anything, 1, 10, mock.MatchedBy(func(f map[string]string) bool {
+		return f["job_type_id"] == jobTypeID && f["country_id"] == countryID && f["location_availability_id"] == availabilityID
+	}), "", "").Return([]models.Job{}, int64(0), nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/jobs?job_type_id="+jobTypeID+"&country_id="+countryID+"&location_availability_id="+availabilityID, nil)
+	w := httptest.NewRecorder()
+
+	h.GetAllJobs(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestJobHandler_GetAllJobs_InvalidReferenceFilter(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	r := httptest.NewRequest(http.MethodGet, "/jobs?education_level_id=masters", nil)
+	w := httptest.NewRecorder()
+
+	h.GetAllJobs(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "education_level_id")
+}
+
+func TestJobHandler_GetAllJobs_SalaryFilters(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("GetAllJobs", mock.Anything, 1, 10, mock.MatchedBy(func(f map[string]string) bool {
+		return f[models.FilterSalaryMin] == "50000" && f[models.FilterSalaryMax] == "90000" &&
+			f["salary_currency"] == "EUR" && f["salary_period"] == "yearly" && f[models.FilterNormalizeSalary] == "true"
+	}), "", "").Return([]models.Job{}, int64(0), nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/jobs?salary_min=50000&salary_max=90000&salary_currency=eur&salary_period=yearly&normalize_salary=true", nil)
+	w := httptest.NewRecorder()
+
+	h.GetAllJobs(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestJobHandler_GetAllJobs_InvalidSalaryFilter(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	r := httptest.NewRequest(http.MethodGet, "/jobs?salary_min=lots", nil)
+	w := httptest.NewRecorder()
+
+	h.GetAllJobs(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "salary_min")
+}
+
+func TestJobHandler_GetAllJobs_LocationSearch(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	distance := 12.5
+	jobs := []models.Job{{Title: "Go Developer", DistanceKm: &distance}}
+	mockSvc.On("GetAllJobs", mock.Anything, 1, 10, mock.MatchedBy(func(f map[string]string) bool {
+		return f[models.FilterLatitude] == "41.9981" && f[models.FilterLongitude] == "21.4254" && f[models.FilterRadiusKm] == "30"
+	}), "", "").Return(jobs, int64(1), nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/jobs?lat=41.9981&lng=21.4254&radius_km=30", nil)
+	w := httptest.NewRecorder()
+
+	h.GetAllJobs(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"distance_km":12.5`)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestJobHandler_GetAllJobs_NearPlace(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("GetAllJobs", mock.Anything, 1, 10, mock.MatchedBy(func(f map[string]string) bool {
+		return f[models.FilterNear] == "Skopje" && f[models.FilterLatitude] == ""
+	}), "", "").Return([]models.Job{}, int64(0), nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/jobs?near=Skopje", nil)
+	w := httptest.NewRecorder()
+
+	h.GetAllJobs(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestJobHandler_GetAllJobs_NearUnknownPlace(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("GetAllJobs", mock.Anything, 1, 10, mock.Anything, "", "").Return([]models.Job{}, int64(0), services.ErrPlaceNotFound)
+
+	r := httptest.NewRequest(http.MethodGet, "/jobs?near=Atlantis", nil)
+	w := httptest.NewRecorder()
+
+	h.GetAllJobs(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Place not found")
+}
+
+func TestJobHandler_GetAllJobs_InvalidLocationSearch(t *testing.T) {
+	tests := map[string]string{
+		"lat without lng":    "/jobs?lat=41.99",
+		"lat out of range":   "/jobs?lat=91&lng=21.42",
+		"lng not a number":   "/jobs?lat=41.99&lng=east",
+		"radius without lat": "/jobs?radius_km=30",
+		"negative radius":    "/jobs?lat=41.99&lng=21.42&radius_km=-5",
+		"near and lat":       "/jobs?near=Skopje&lat=41.99&lng=21.42",
+		"with text search":   "/jobs?q=golang&near=Skopje",
+	}
+	for name, target := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockSvc := new(mocks.MockJobService)
+			h := handlers.NewJobHandler(mockSvc)
+
+			r := httptest.NewRequest(http.MethodGet, target, nil)
+			w := httptest.NewRecorder()
+
+			h.GetAllJobs(w, r)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockSvc.AssertNotCalled(t, "GetAllJobs", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestJobHandler_GetAllJobs_Expand(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	jobs := []models.Job{{ID: bson.NewObjectID(), Title: "Go Developer"}}
+	mockSvc.On("GetAllJobs", mock.Anything, 1, 10, mock.Anything, "", "").Return(jobs, int64(1), nil)
+	mockSvc.On("ExpandJobs", mock.Anything, jobs, []string{models.ExpandCategory, models.ExpandRecruiter}).
+		Run(func(args mock.Arguments) {
+			expanded := args.Get(1).([]models.Job)
+			expanded[0].Category = &models.JobCategory{Name: "Engineering"}
+			expanded[0].Recruiter = &models.UserResponse{Email: "recruiter@example.com"}
+		}).
+		Return(nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/jobs?expand=category,recruiter,category", nil)
+	w := httptest.NewRecorder()
+
+	h.GetAllJobs(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"Engineering"`)
+	assert.Contains(t, w.Body.String(), `"email":"recruiter@example.com"`)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestJobHandler_GetAllJobs_InvalidExpand(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	r := httptest.NewRequest(http.MethodGet, "/jobs?expand=category,candidate", nil)
+	w := httptest.NewRecorder()
+
+	h.GetAllJobs(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid expand: candidate")
+	mockSvc.AssertNotCalled(t, "GetAllJobs", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestJobHandler_GetAllJobs_ExpandError(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	jobs := []models.Job{{ID: bson.NewObjectID()}}
+	mockSvc.On("GetAllJobs", mock.Anything, 1, 10, mock.Anything, "", "").Return(jobs, int64(1), nil)
+	mockSvc.On("ExpandJobs", mock.Anything, jobs, []string{models.ExpandSkills}).Return(errors.New("db error"))
+
+	r := httptest.NewRequest(http.MethodGet, "/jobs?expand=skills", nil)
+	w := httptest.NewRecorder()
+
+	h.GetAllJobs(w, r)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
 func TestJobHandler_GetJobByID_Success(t *testing.T) {
 	mockSvc := new(mocks.MockJobService)
 	h := handlers.NewJobHandler(mockSvc)
@@ -64,6 +400,31 @@ func TestJobHandler_GetJobByID_Success(t *testing.T) {
 	mockSvc.AssertExpectations(t)
 }
 
+func TestJobHandler_GetJobByID_Expand(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	id := bson.NewObjectID()
+	job := &models.Job{ID: id, Title: "Go Developer"}
+	mockSvc.On("GetJobByID", mock.Anything, id.Hex()).Return(job, nil)
+	mockSvc.On("ExpandJobs", mock.Anything, []models.Job{*job}, []string{models.ExpandSkills}).
+		Run(func(args mock.Arguments) {
+			args.Get(1).([]models.Job)[0].Skills = []models.JobSkillInfo{{Name: "Go", IsRequired: true}}
+		}).
+		Return(nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/jobs/"+id.Hex()+"?expand=skills", nil)
+	r = addChiURLParam(r, "id", id.Hex())
+	w := httptest.NewRecorder()
+
+	h.GetJobByID(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"skills":[{`)
+	assert.Contains(t, w.Body.String(), `"name":"Go"`)
+	mockSvc.AssertExpectations(t)
+}
+
 func TestJobHandler_GetJobByID_NotFound(t *testing.T) {
 	mockSvc := new(mocks.MockJobService)
 	h := handlers.NewJobHandler(mockSvc)
@@ -111,9 +472,10 @@ func TestJobHandler_CreateJob_Success(t *testing.T) {
 		"user_id":"` + userID.Hex() + `",
 		"category_id":"` + categoryID.Hex() + `",
 		"location":"New York",
-		"job_type":"full-time",
+		"job_type_id":"` + bson.NewObjectID().Hex() + `",
 		"salary_min":80000,
 		"salary_max":120000,
+		"salary_currency":"USD",
 		"status":"active"
 	}`
 	r := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewBufferString(body))
@@ -126,54 +488,109 @@ func TestJobHandler_CreateJob_Success(t *testing.T) {
 	mockSvc.AssertExpectations(t)
 }
 
-func TestJobHandler_CreateJob_InvalidBody(t *testing.T) {
+func TestJobHandler_CreateJob_SalaryDefaults(t *testing.T) {
 	mockSvc := new(mocks.MockJobService)
 	h := handlers.NewJobHandler(mockSvc)
 
-	r := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewBufferString("not-json"))
+	mockSvc.On("CreateJob", mock.Anything, mock.MatchedBy(func(j *models.Job) bool {
+		return j.SalaryCurrency == "EUR" && j.SalaryPeriod == models.SalaryPeriodYearly
+	})).Return(nil)
+
+	body := `{
+		"title":"Go Developer",
+		"description":"We need a Go developer with at least 3 years of experience",
+		"user_id":"` + bson.NewObjectID().Hex() + `",
+		"category_id":"` + bson.NewObjectID().Hex() + `",
+		"location":"Berlin",
+		"job_type_id":"` + bson.NewObjectID().Hex() + `",
+		"salary_min":60000,
+		"salary_max":80000,
+		"salary_currency":"eur"
+	}`
+	r := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewBufferString(body))
 	w := httptest.NewRecorder()
 
 	h.CreateJob(w, r)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockSvc.AssertExpectations(t)
 }
 
-func TestJobHandler_CreateJob_SalaryValidation(t *testing.T) {
+func TestJobHandler_CreateJob_SalaryCurrencyValidation(t *testing.T) {
 	mockSvc := new(mocks.MockJobService)
 	h := handlers.NewJobHandler(mockSvc)
 
-	recruiterID := bson.NewObjectID()
-	companyID := bson.NewObjectID()
-	categoryID := bson.NewObjectID()
 	body := `{
 		"title":"Go Developer",
 		"description":"We need a Go developer with at least 3 years of experience",
-		"recruiter_id":"` + recruiterID.Hex() + `",
-		"company_id":"` + companyID.Hex() + `",
-		"category_id":"` + categoryID.Hex() + `",
-		"location":"New York",
-		"job_type":"full-time",
-		"salary_min":120000,
+		"user_id":"` + bson.NewObjectID().Hex() + `",
+		"category_id":"` + bson.NewObjectID().Hex() + `",
+		"location":"Berlin",
+		"job_type_id":"` + bson.NewObjectID().Hex() + `",
+		"salary_min":60000,
 		"salary_max":80000,
-		"status":"active"
+		"salary_currency":"ABC",
+		"salary_period":"weekly"
 	}`
 	r := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewBufferString(body))
-	r.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	h.CreateJob(w, r)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "SalaryCurrency")
+	assert.Contains(t, w.Body.String(), "SalaryPeriod")
+	mockSvc.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything)
 }
 
-func TestJobHandler_DeleteJob_Success(t *testing.T) {
+func TestJobHandler_CreateJob_InvalidBody(t *testing.T) {
 	mockSvc := new(mocks.MockJobService)
 	h := handlers.NewJobHandler(mockSvc)
 
-	mockSvc.On("DeleteJob", mock.Anything, "job-id").Return(nil)
-
-	r := httptest.NewRequest(http.MethodDelete, "/jobs/job-id", nil)
-	r = addChiURLParam(r, "id", "job-id")
+	r := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewBufferString("not-json"))
+	w := httptest.NewRecorder()
+
+	h.CreateJob(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestJobHandler_CreateJob_SalaryValidation(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	recruiterID := bson.NewObjectID()
+	companyID := bson.NewObjectID()
+	categoryID := bson.NewObjectID()
+	body := `{
+		"title":"Go Developer",
+		"description":"We need a Go developer with at least 3 years of experience",
+		"recruiter_id":"` + recruiterID.Hex() + `",
+		"company_id":"` + companyID.Hex() + `",
+		"category_id":"` + categoryID.Hex() + `",
+		"location":"New York",
+		"job_type_id":"` + bson.NewObjectID().Hex() + `",
+		"salary_min":120000,
+		"salary_max":80000,
+		"status":"active"
+	}`
+	r := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewBufferString(body))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	h.CreateJob(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestJobHandler_DeleteJob_Success(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("DeleteJob", mock.Anything, "job-id").Return(nil)
+
+	r := httptest.NewRequest(http.MethodDelete, "/jobs/job-id", nil)
+	r = addChiURLParam(r, "id", "job-id")
 	w := httptest.NewRecorder()
 
 	h.DeleteJob(w, r)
@@ -196,3 +613,599 @@ func TestJobHandler_DeleteJob_NotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
+
+func TestJobHandler_DeleteJob_Forbidden(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("DeleteJob", mock.Anything, "job-id").Return(services.ErrForbidden)
+
+	r := httptest.NewRequest(http.MethodDelete, "/jobs/job-id", nil)
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.DeleteJob(w, r)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func existingJob() *models.Job {
+	return &models.Job{
+		ID:             bson.NewObjectID(),
+		Title:          "Go Developer",
+		Description:    "We need a Go developer with at least 3 years of experience",
+		UserID:         bson.NewObjectID(),
+		CategoryID:     bson.NewObjectID(),
+		Location:       "New York",
+		JobTypeID:      bson.NewObjectID(),
+		SalaryMin:      80000,
+		SalaryMax:      120000,
+		SalaryCurrency: "USD",
+		SalaryPeriod:   "yearly",
+		Status:         "active",
+		Active:         true,
+		Audit:          models.Audit{CreatedBy: "creator"},
+	}
+}
+
+func TestJobHandler_UpdateJob_Success(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	job := existingJob()
+	categoryID := bson.NewObjectID()
+	jobTypeID := bson.NewObjectID()
+	mockSvc.On("GetJobByID", mock.Anything, "job-id").Return(job, nil)
+	mockSvc.On("UpdateJob", mock.Anything, "job-id", mock.MatchedBy(func(j *models.Job) bool {
+		return j.Title == "Senior Go Developer" && j.CategoryID == categoryID && j.JobTypeID == jobTypeID &&
+			j.Status == "active" && j.UserID == job.UserID && j.CreatedBy == "creator"
+	})).Return(job, nil)
+
+	body := `{
+		"title":"Senior Go Developer",
+		"description":"We need a Go developer with at least 5 years of experience",
+		"category_id":"` + categoryID.Hex() + `",
+		"location":"Remote",
+		"job_type_id":"` + jobTypeID.Hex() + `",
+		"salary_min":90000,
+		"salary_max":130000,
+		"salary_currency":"EUR",
+		"salary_period":"monthly"
+	}`
+	r := httptest.NewRequest(http.MethodPut, "/jobs/job-id", bytes.NewBufferString(body))
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.UpdateJob(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestJobHandler_UpdateJob_MissingFields(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("GetJobByID", mock.Anything, "job-id").Return(existingJob(), nil)
+
+	r := httptest.NewRequest(http.MethodPut, "/jobs/job-id", bytes.NewBufferString(`{"title":"Senior Go Developer"}`))
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.UpdateJob(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "UpdateJob", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestJobHandler_PatchJob_MergesFields(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	job := existingJob()
+	mockSvc.On("GetJobByID", mock.Anything, "job-id").Return(job, nil)
+	mockSvc.On("UpdateJob", mock.Anything, "job-id", mock.MatchedBy(func(j *models.Job) bool {
+		return j.Title == "Senior Go Developer" && j.SalaryMax == 150000 &&
+			j.Description == job.Description && j.CategoryID == job.CategoryID && j.Active
+	})).Return(job, nil)
+
+	r := httptest.NewRequest(http.MethodPatch, "/jobs/job-id", bytes.NewBufferString(`{"title":"Senior Go Developer","salary_max":150000}`))
+	r.Header.Set("Content-Type", "application/merge-patch+json")
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.PatchJob(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestJobHandler_PatchJob_LocationChangeClearsCoordinates(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	job := existingJob()
+	job.Coordinates = models.NewGeoPoint(40.7128, -74.006)
+	mockSvc.On("GetJobByID", mock.Anything, "job-id").Return(job, nil)
+	mockSvc.On("UpdateJob", mock.Anything, "job-id", mock.MatchedBy(func(j *models.Job) bool {
+		return j.Location == "Skopje" && j.Coordinates == nil
+	})).Return(job, nil)
+
+	r := httptest.NewRequest(http.MethodPatch, "/jobs/job-id", bytes.NewBufferString(`{"location":"Skopje"}`))
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.PatchJob(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestJobHandler_PatchJob_InvalidCoordinates(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("GetJobByID", mock.Anything, "job-id").Return(existingJob(), nil)
+
+	r := httptest.NewRequest(http.MethodPatch, "/jobs/job-id", bytes.NewBufferString(`{"coordinates":{"type":"Point","coordinates":[200,41.99]}}`))
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.PatchJob(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "coordinates")
+	mockSvc.AssertNotCalled(t, "UpdateJob", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestJobHandler_PatchJob_ScreeningQuestions(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	job := existingJob()
+	mockSvc.On("GetJobByID", mock.Anything, "job-id").Return(job, nil)
+	mockSvc.On("UpdateJob", mock.Anything, "job-id", mock.MatchedBy(func(j *models.Job) bool {
+		return len(j.ScreeningQuestions) == 2 && j.ScreeningQuestions[1].Knockout != nil &&
+			*j.ScreeningQuestions[1].Knockout.Min == 3 && j.Title == job.Title
+	})).Return(job, nil)
+
+	body := `{"screening_questions":[
+		{"key":"why","question":"Why do you want this role?","type":"text"},
+		{"key":"years_go","question":"Years of Go experience","type":"number","knockout":{"min":3}}
+	]}`
+	r := httptest.NewRequest(http.MethodPatch, "/jobs/job-id", bytes.NewBufferString(body))
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.PatchJob(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestJobHandler_PatchJob_InvalidScreeningQuestions(t *testing.T) {
+	tests := []struct {
+		name      string
+		questions string
+	}{
+		{"unknown type", `[{"key":"a","question":"Salary?","type":"salary"}]`},
+		{"duplicate keys", `[{"key":"a","question":"One?","type":"text"},{"key":"a","question":"Two?","type":"text"}]`},
+		{"choice without options", `[{"key":"a","question":"Shift?","type":"multiple_choice","options":["day"]}]`},
+		{"options on yes/no", `[{"key":"a","question":"Relocate?","type":"yes_no","options":["yes","no"]}]`},
+		{"text knockout", `[{"key":"a","question":"Why?","type":"text","knockout":{"accepted":["because"]}}]`},
+		{"yes/no knockout without expected", `[{"key":"a","question":"Relocate?","type":"yes_no","knockout":{"min":1}}]`},
+		{"accepted option not offered", `[{"key":"a","question":"Shift?","type":"multiple_choice","options":["day","night"],"knockout":{"accepted":["weekend"]}}]`},
+		{"number range reversed", `[{"key":"a","question":"Years?","type":"number","knockout":{"min":5,"max":2}}]`},
+		{"number knockout without range", `[{"key":"a","question":"Years?","type":"number","knockout":{}}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockJobService)
+			h := handlers.NewJobHandler(mockSvc)
+
+			mockSvc.On("GetJobByID", mock.Anything, "job-id").Return(existingJob(), nil)
+
+			r := httptest.NewRequest(http.MethodPatch, "/jobs/job-id", bytes.NewBufferString(`{"screening_questions":`+tt.questions+`}`))
+			r = addChiURLParam(r, "id", "job-id")
+			w := httptest.NewRecorder()
+
+			h.PatchJob(w, r)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockSvc.AssertNotCalled(t, "UpdateJob", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestJobHandler_PatchJob_NullRemovesRequiredField(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("GetJobByID", mock.Anything, "job-id").Return(existingJob(), nil)
+
+	r := httptest.NewRequest(http.MethodPatch, "/jobs/job-id", bytes.NewBufferString(`{"location":null}`))
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.PatchJob(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Location")
+	mockSvc.AssertNotCalled(t, "UpdateJob", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestJobHandler_PatchJob_SalaryValidation(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("GetJobByID", mock.Anything, "job-id").Return(existingJob(), nil)
+
+	r := httptest.NewRequest(http.MethodPatch, "/jobs/job-id", bytes.NewBufferString(`{"salary_min":200000}`))
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.PatchJob(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "salary_max")
+}
+
+func TestJobHandler_PatchJob_ReadOnlyField(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	body := `{"title":"Senior Go Developer","user_id":"` + bson.NewObjectID().Hex() + `"}`
+	r := httptest.NewRequest(http.MethodPatch, "/jobs/job-id", bytes.NewBufferString(body))
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.PatchJob(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "user_id")
+	mockSvc.AssertNotCalled(t, "GetJobByID", mock.Anything, mock.Anything)
+}
+
+func TestJobHandler_PatchJob_InvalidBody(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	r := httptest.NewRequest(http.MethodPatch, "/jobs/job-id", bytes.NewBufferString(`["title"]`))
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.PatchJob(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestJobHandler_PatchJob_NotFound(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("GetJobByID", mock.Anything, "bad-id").Return(nil, errors.New("not found"))
+
+	r := httptest.NewRequest(http.MethodPatch, "/jobs/bad-id", bytes.NewBufferString(`{"title":"Senior Go Developer"}`))
+	r = addChiURLParam(r, "id", "bad-id")
+	w := httptest.NewRecorder()
+
+	h.PatchJob(w, r)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestJobHandler_PatchJob_NotOwner(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("GetJobByID", mock.Anything, "job-id").Return(existingJob(), nil)
+	mockSvc.On("UpdateJob", mock.Anything, "job-id", mock.Anything).Return(nil, services.ErrForbidden)
+
+	r := httptest.NewRequest(http.MethodPatch, "/jobs/job-id", bytes.NewBufferString(`{"title":"Senior Go Developer"}`))
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.PatchJob(w, r)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestJobHandler_PatchJob_CategoryNotFound(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("GetJobByID", mock.Anything, "job-id").Return(existingJob(), nil)
+	mockSvc.On("UpdateJob", mock.Anything, "job-id", mock.Anything).Return(nil, services.ErrCategoryNotFound)
+
+	body := `{"category_id":"` + bson.NewObjectID().Hex() + `"}`
+	r := httptest.NewRequest(http.MethodPatch, "/jobs/job-id", bytes.NewBufferString(body))
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.PatchJob(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestJobHandler_PatchJob_ReferenceNotFound(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("GetJobByID", mock.Anything, "job-id").Return(existingJob(), nil)
+	mockSvc.On("UpdateJob", mock.Anything, "job-id", mock.Anything).Return(nil, services.ErrLocationAvailabilityNotFound)
+
+	body := `{"location_availability_id":"` + bson.NewObjectID().Hex() + `"}`
+	r := httptest.NewRequest(http.MethodPatch, "/jobs/job-id", bytes.NewBufferString(body))
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.PatchJob(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Location availability not found")
+}
+
+func TestJobHandler_PatchJob_StatusNotUpdatable(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	r := httptest.NewRequest(http.MethodPatch, "/jobs/job-id", bytes.NewBufferString(`{"status":"closed"}`))
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.PatchJob(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "status")
+	mockSvc.AssertNotCalled(t, "GetJobByID", mock.Anything, mock.Anything)
+}
+
+func TestJobHandler_PatchJob_ClearsDeadline(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	job := existingJob()
+	closesAt := time.Now().Add(24 * time.Hour)
+	job.ClosesAt = &closesAt
+	mockSvc.On("GetJobByID", mock.Anything, "job-id").Return(job, nil)
+	mockSvc.On("UpdateJob", mock.Anything, "job-id", mock.MatchedBy(func(j *models.Job) bool {
+		return j.ClosesAt == nil
+	})).Return(job, nil)
+
+	r := httptest.NewRequest(http.MethodPatch, "/jobs/job-id", bytes.NewBufferString(`{"closes_at":null}`))
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.PatchJob(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestJobHandler_PublishJob_WithDeadline(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	closesAt := time.Date(2030, 1, 31, 0, 0, 0, 0, time.UTC)
+	mockSvc.On("PublishJob", mock.Anything, "job-id", mock.MatchedBy(func(t *time.Time) bool {
+		return t != nil && t.Equal(closesAt)
+	})).Return(&models.Job{Status: models.JobStatusActive}, nil)
+
+	r := httptest.NewRequest(http.MethodPost, "/jobs/job-id/publish", bytes.NewBufferString(`{"closes_at":"2030-01-31T00:00:00Z"}`))
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.PublishJob(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"active"`)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestJobHandler_PublishJob_EmptyBody(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("PublishJob", mock.Anything, "job-id", (*time.Time)(nil)).Return(&models.Job{Status: models.JobStatusActive}, nil)
+
+	r := httptest.NewRequest(http.MethodPost, "/jobs/job-id/publish", nil)
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.PublishJob(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestJobHandler_PublishJob_InvalidBody(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	r := httptest.NewRequest(http.MethodPost, "/jobs/job-id/publish", bytes.NewBufferString(`{"closes_at":"tomorrow"}`))
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.PublishJob(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "PublishJob", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestJobHandler_PublishJob_PastDeadline(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("PublishJob", mock.Anything, "job-id", mock.Anything).Return(nil, services.ErrInvalidClosesAt)
+
+	r := httptest.NewRequest(http.MethodPost, "/jobs/job-id/publish", bytes.NewBufferString(`{"closes_at":"2020-01-01T00:00:00Z"}`))
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.PublishJob(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestJobHandler_CloseJob_InvalidTransition(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("CloseJob", mock.Anything, "job-id").Return(nil, fmt.Errorf("%w: draft to closed", services.ErrInvalidJobTransition))
+
+	r := httptest.NewRequest(http.MethodPost, "/jobs/job-id/close", nil)
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.CloseJob(w, r)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestJobHandler_ReopenJob_Success(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("ReopenJob", mock.Anything, "job-id", (*time.Time)(nil)).Return(&models.Job{Status: models.JobStatusActive}, nil)
+
+	r := httptest.NewRequest(http.MethodPost, "/jobs/job-id/reopen", nil)
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.ReopenJob(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestJobHandler_ArchiveJob_NotFound(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("ArchiveJob", mock.Anything, "bad-id").Return(nil, mongo.ErrNoDocuments)
+
+	r := httptest.NewRequest(http.MethodPost, "/jobs/bad-id/archive", nil)
+	r = addChiURLParam(r, "id", "bad-id")
+	w := httptest.NewRecorder()
+
+	h.ArchiveJob(w, r)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestJobHandler_DeleteJob_HasDependents(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("DeleteJob", mock.Anything, "job-id").Return(&models.DependentsError{Dependents: map[string]int64{"applications": 5}})
+
+	r := httptest.NewRequest(http.MethodDelete, "/jobs/job-id", nil)
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.DeleteJob(w, r)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"applications":5`)
+}
+
+func TestJobHandler_RestoreJob_ParentDeleted(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("RestoreJob", mock.Anything, "job-id").Return(models.ErrParentDeleted)
+
+	r := httptest.NewRequest(http.MethodPost, "/jobs/job-id/restore", nil)
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.RestoreJob(w, r)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestJobHandler_SetJobStages(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	mockSvc.On("SetJobStages", mock.Anything, "job-id", mock.MatchedBy(func(stages []models.PipelineStage) bool {
+		return len(stages) == 2 && stages[1].Outcome == models.ApplicationStatusAccepted
+	}), "").Return(&models.Job{Stages: []models.PipelineStage{{Key: "screen", Name: "Screening"}}}, nil)
+
+	body := `{"stages":[{"key":"screen","name":"Screening"},{"key":"hired","name":"Hired","outcome":"accepted"}]}`
+	r := httptest.NewRequest(http.MethodPut, "/jobs/job-id/stages", bytes.NewBufferString(body))
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.SetJobStages(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"stages":[`)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestJobHandler_SetJobStages_FromTemplate(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	templateID := bson.NewObjectID().Hex()
+	mockSvc.On("SetJobStages", mock.Anything, "job-id", []models.PipelineStage(nil), templateID).Return(&models.Job{}, nil)
+
+	r := httptest.NewRequest(http.MethodPut, "/jobs/job-id/stages", bytes.NewBufferString(`{"template_id":"`+templateID+`"}`))
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.SetJobStages(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestJobHandler_SetJobStages_TemplateNotFound(t *testing.T) {
+	mockSvc := new(mocks.MockJobService)
+	h := handlers.NewJobHandler(mockSvc)
+
+	templateID := bson.NewObjectID().Hex()
+	mockSvc.On("SetJobStages", mock.Anything, "job-id", []models.PipelineStage(nil), templateID).Return(nil, services.ErrPipelineTemplateNotFound)
+
+	r := httptest.NewRequest(http.MethodPut, "/jobs/job-id/stages", bytes.NewBufferString(`{"template_id":"`+templateID+`"}`))
+	r = addChiURLParam(r, "id", "job-id")
+	w := httptest.NewRecorder()
+
+	h.SetJobStages(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Pipeline template not found")
+}
+
+func TestJobHandler_SetJobStages_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"stages and template", `{"stages":[{"key":"screen","name":"Screening"}],"template_id":"` + bson.NewObjectID().Hex() + `"}`},
+		{"invalid template id", `{"template_id":"not-an-id"}`},
+		{"missing name", `{"stages":[{"key":"screen"}]}`},
+		{"unknown outcome", `{"stages":[{"key":"screen","name":"Screening"},{"key":"hold","name":"On hold","outcome":"withdrawn"}]}`},
+		{"first stage outcome", `{"stages":[{"key":"hired","name":"Hired","outcome":"accepted"}]}`},
+		{"duplicate keys", `{"stages":[{"key":"screen","name":"Screening"},{"key":"screen","name":"Phone screen"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockJobService)
+			h := handlers.NewJobHandler(mockSvc)
+
+			r := httptest.NewRequest(http.MethodPut, "/jobs/job-id/stages", bytes.NewBufferString(tt.body))
+			r = addChiURLParam(r, "id", "job-id")
+			w := httptest.NewRecorder()
+
+			h.SetJobStages(w, r)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockSvc.AssertNotCalled(t, "SetJobStages", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
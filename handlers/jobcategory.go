@@ -8,7 +8,6 @@ import (
 	"log"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -49,6 +48,9 @@ func (h *JobCategoryHandler) GetAllJobCategories(w http.ResponseWriter, r *http.
 		"name":        r.URL.Query().Get("name"),
 		"description": r.URL.Query().Get("description"),
 	}
+	if includeDeleted(r) {
+		filters[models.FilterIncludeDeleted] = "true"
+	}
 
 	sort := r.URL.Query().Get("sort")
 	order := r.URL.Query().Get("order")
@@ -118,14 +120,9 @@ func (h *JobCategoryHandler) CreateJobCategory(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	jobCategory.CreatedTime = time.Now()
-	jobCategory.UpdatedTime = time.Now()
-	jobCategory.CreatedBy = "system"
-	jobCategory.UpdatedBy = "system"
-
 	err = h.service.CreateJobCategory(ctx, &jobCategory)
 	if err != nil {
-		http.Error(w, "Failed to create job category", http.StatusInternalServerError)
+		writeServiceError(w, err, "Failed to create job category", http.StatusInternalServerError)
 		return
 	}
 
@@ -148,9 +145,6 @@ func (h *JobCategoryHandler) UpdateJobCategory(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	jobCategory.UpdatedTime = time.Now()
-	jobCategory.UpdatedBy = "system"
-
 	updated, err := h.service.UpdateJobCategory(ctx, jobCategoryID, &jobCategory)
 	if err != nil {
 		http.Error(w, "Job category not found", http.StatusNotFound)
@@ -172,7 +166,21 @@ func (h *JobCategoryHandler) DeleteJobCategory(w http.ResponseWriter, r *http.Re
 
 	err := h.service.DeleteJobCategory(ctx, jobCategoryID)
 	if err != nil {
-		http.Error(w, "Job category not found or has related jobs", http.StatusNotFound)
+		writeServiceError(w, err, "Job category not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreJobCategory handles POST /jobcategories/{id}/restore request
+func (h *JobCategoryHandler) RestoreJobCategory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	jobCategoryID := chi.URLParam(r, "id")
+
+	err := h.service.RestoreJobCategory(ctx, jobCategoryID)
+	if err != nil {
+		writeServiceError(w, err, "Deleted job category not found", http.StatusNotFound)
 		return
 	}
 
@@ -200,3 +200,19 @@ func TestJobCategoryHandler_UpdateJobCategory_InvalidBody(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func TestJobCategoryHandler_DeleteJobCategory_HasDependents(t *testing.T) {
+	mockSvc := new(mocks.MockJobCategoryService)
+	h := handlers.NewJobCategoryHandler(mockSvc)
+
+	mockSvc.On("DeleteJobCategory", mock.Anything, "cat-id").Return(&models.DependentsError{Dependents: map[string]int64{"jobs": 4}})
+
+	r := httptest.NewRequest(http.MethodDelete, "/jobcategories/cat-id", nil)
+	r = addChiURLParam(r, "id", "cat-id")
+	w := httptest.NewRecorder()
+
+	h.DeleteJobCategory(w, r)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"jobs":4`)
+}
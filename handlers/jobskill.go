@@ -8,7 +8,6 @@ import (
 	"log"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -50,6 +49,9 @@ func (h *JobSkillHandler) GetAllJobSkills(w http.ResponseWriter, r *http.Request
 		"skill_id":                   r.URL.Query().Get("skill_id"),
 		"proficiency_level_required": r.URL.Query().Get("proficiency_level_required"),
 	}
+	if includeDeleted(r) {
+		filters[models.FilterIncludeDeleted] = "true"
+	}
 
 	sort := r.URL.Query().Get("sort")
 	order := r.URL.Query().Get("order")
@@ -138,14 +140,9 @@ func (h *JobSkillHandler) CreateJobSkill(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	jobSkill.CreatedTime = time.Now()
-	jobSkill.UpdatedTime = time.Now()
-	jobSkill.CreatedBy = "system"
-	jobSkill.UpdatedBy = "system"
-
 	err = h.service.CreateJobSkill(ctx, &jobSkill)
 	if err != nil {
-		http.Error(w, "Failed to create job skill", http.StatusInternalServerError)
+		writeServiceError(w, err, "Failed to create job skill", http.StatusInternalServerError)
 		return
 	}
 
@@ -173,7 +170,7 @@ func (h *JobSkillHandler) UpdateJobSkillProficiencyLevel(w http.ResponseWriter,
 
 	err = h.service.UpdateJobSkillProficiencyLevel(ctx, jobSkillID, request.ProficiencyLevelRequired)
 	if err != nil {
-		http.Error(w, "Failed to update job skill", http.StatusInternalServerError)
+		writeServiceError(w, err, "Failed to update job skill", http.StatusInternalServerError)
 		return
 	}
 
@@ -188,7 +185,21 @@ func (h *JobSkillHandler) DeleteJobSkill(w http.ResponseWriter, r *http.Request)
 
 	err := h.service.DeleteJobSkill(ctx, jobSkillID)
 	if err != nil {
-		http.Error(w, "Job skill not found", http.StatusNotFound)
+		writeServiceError(w, err, "Job skill not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreJobSkill handles POST /jobskills/{id}/restore request
+func (h *JobSkillHandler) RestoreJobSkill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	jobSkillID := chi.URLParam(r, "id")
+
+	err := h.service.RestoreJobSkill(ctx, jobSkillID)
+	if err != nil {
+		writeServiceError(w, err, "Deleted job skill not found", http.StatusNotFound)
 		return
 	}
 
@@ -10,6 +10,7 @@ import (
 	"go-mongodb-api/handlers"
 	"go-mongodb-api/mocks"
 	"go-mongodb-api/models"
+	"go-mongodb-api/services"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -188,3 +189,18 @@ func TestJobSkillHandler_DeleteJobSkill_NotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
+
+func TestJobSkillHandler_DeleteJobSkill_Forbidden(t *testing.T) {
+	mockSvc := new(mocks.MockJobSkillService)
+	h := handlers.NewJobSkillHandler(mockSvc)
+
+	mockSvc.On("DeleteJobSkill", mock.Anything, "js-id").Return(services.ErrForbidden)
+
+	r := httptest.NewRequest(http.MethodDelete, "/jobskills/js-id", nil)
+	r = addChiURLParam(r, "id", "js-id")
+	w := httptest.NewRecorder()
+
+	h.DeleteJobSkill(w, r)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -8,7 +8,6 @@ import (
 	"log"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -43,6 +42,9 @@ func (h *JobTypeHandler) GetAllJobTypes(w http.ResponseWriter, r *http.Request)
 	filters := map[string]string{
 		"title": r.URL.Query().Get("title"),
 	}
+	if includeDeleted(r) {
+		filters[models.FilterIncludeDeleted] = "true"
+	}
 
 	sort := r.URL.Query().Get("sort")
 	order := r.URL.Query().Get("order")
@@ -107,11 +109,6 @@ func (h *JobTypeHandler) CreateJobType(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jobType.CreatedTime = time.Now()
-	jobType.UpdatedTime = time.Now()
-	jobType.CreatedBy = "system"
-	jobType.UpdatedBy = "system"
-
 	err = h.service.CreateJobType(ctx, &jobType)
 	if err != nil {
 		http.Error(w, "Failed to create job type", http.StatusInternalServerError)
@@ -136,9 +133,6 @@ func (h *JobTypeHandler) UpdateJobType(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jobType.UpdatedTime = time.Now()
-	jobType.UpdatedBy = "system"
-
 	updated, err := h.service.UpdateJobType(ctx, jobTypeID, &jobType)
 	if err != nil {
 		http.Error(w, "Job type not found", http.StatusNotFound)
@@ -159,7 +153,20 @@ func (h *JobTypeHandler) DeleteJobType(w http.ResponseWriter, r *http.Request) {
 
 	err := h.service.DeleteJobType(ctx, jobTypeID)
 	if err != nil {
-		http.Error(w, "Job type not found", http.StatusNotFound)
+		writeServiceError(w, err, "Job type not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *JobTypeHandler) RestoreJobType(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	jobTypeID := chi.URLParam(r, "id")
+
+	err := h.service.RestoreJobType(ctx, jobTypeID)
+	if err != nil {
+		writeServiceError(w, err, "Deleted job type not found", http.StatusNotFound)
 		return
 	}
 
@@ -200,3 +200,19 @@ func TestJobTypeHandler_DeleteJobType_Success(t *testing.T) {
 	assert.Equal(t, http.StatusNoContent, w.Code)
 	mockSvc.AssertExpectations(t)
 }
+
+func TestJobTypeHandler_DeleteJobType_HasDependents(t *testing.T) {
+	mockSvc := new(mocks.MockJobTypeService)
+	h := handlers.NewJobTypeHandler(mockSvc)
+
+	mockSvc.On("DeleteJobType", mock.Anything, "jobtype-id").Return(&models.DependentsError{Dependents: map[string]int64{"jobs": 7}})
+
+	r := httptest.NewRequest(http.MethodDelete, "/jobtypes/jobtype-id", nil)
+	r = addChiURLParam(r, "id", "jobtype-id")
+	w := httptest.NewRecorder()
+
+	h.DeleteJobType(w, r)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"jobs":7`)
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-mongodb-api/middleware"
+	"log"
+	"net/http"
+)
+
+type JWKSHandler struct {
+	keys *middleware.KeySet
+}
+
+func NewJWKSHandler(keys *middleware.KeySet) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// GetJWKS handles GET /.well-known/jwks.json, publishing the public keys other services verify access tokens with
+func (h *JWKSHandler) GetJWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	// Short cache lifetime so a newly added key is picked up well before it becomes active
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	if err := json.NewEncoder(w).Encode(h.keys.JWKS()); err != nil {
+		log.Printf("error encoding jwks response: %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package handlers_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-mongodb-api/handlers"
+	"go-mongodb-api/middleware"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWKSHandler_GetJWKS(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	key, err := middleware.NewSigningKey("ed-1", privateKey)
+	assert.NoError(t, err)
+	keys, err := middleware.NewKeySet("", []middleware.SigningKey{key}, "")
+	assert.NoError(t, err)
+	h := handlers.NewJWKSHandler(keys)
+
+	r := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+
+	h.GetJWKS(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var resp middleware.JWKS
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Len(t, resp.Keys, 1)
+	assert.Equal(t, "ed-1", resp.Keys[0].KeyID)
+	assert.Equal(t, "OKP", resp.Keys[0].KeyType)
+}
+
+func TestJWKSHandler_GetJWKS_HMACOnly(t *testing.T) {
+	h := handlers.NewJWKSHandler(middleware.NewHMACKeySet("secret"))
+
+	r := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+
+	h.GetJWKS(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"keys":[]}`, w.Body.String())
+}
@@ -8,7 +8,6 @@ import (
 	"log"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -43,6 +42,9 @@ func (h *KnowledgeLevelHandler) GetAllKnowledgeLevels(w http.ResponseWriter, r *
 	filters := map[string]string{
 		"title": r.URL.Query().Get("title"),
 	}
+	if includeDeleted(r) {
+		filters[models.FilterIncludeDeleted] = "true"
+	}
 
 	sort := r.URL.Query().Get("sort")
 	order := r.URL.Query().Get("order")
@@ -107,11 +109,6 @@ func (h *KnowledgeLevelHandler) CreateKnowledgeLevel(w http.ResponseWriter, r *h
 		return
 	}
 
-	knowledgeLevel.CreatedTime = time.Now()
-	knowledgeLevel.UpdatedTime = time.Now()
-	knowledgeLevel.CreatedBy = "system"
-	knowledgeLevel.UpdatedBy = "system"
-
 	err = h.service.CreateKnowledgeLevel(ctx, &knowledgeLevel)
 	if err != nil {
 		http.Error(w, "Failed to create knowledge level", http.StatusInternalServerError)
@@ -136,9 +133,6 @@ func (h *KnowledgeLevelHandler) UpdateKnowledgeLevel(w http.ResponseWriter, r *h
 		return
 	}
 
-	knowledgeLevel.UpdatedTime = time.Now()
-	knowledgeLevel.UpdatedBy = "system"
-
 	updated, err := h.service.UpdateKnowledgeLevel(ctx, knowledgeLevelID, &knowledgeLevel)
 	if err != nil {
 		http.Error(w, "Knowledge level not found", http.StatusNotFound)
@@ -165,3 +159,16 @@ func (h *KnowledgeLevelHandler) DeleteKnowledgeLevel(w http.ResponseWriter, r *h
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (h *KnowledgeLevelHandler) RestoreKnowledgeLevel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	knowledgeLevelID := chi.URLParam(r, "id")
+
+	err := h.service.RestoreKnowledgeLevel(ctx, knowledgeLevelID)
+	if err != nil {
+		writeServiceError(w, err, "Deleted knowledge level not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -8,7 +8,6 @@ import (
 	"log"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -43,6 +42,9 @@ func (h *LocationAvailabilityHandler) GetAllLocationAvailabilities(w http.Respon
 	filters := map[string]string{
 		"title": r.URL.Query().Get("title"),
 	}
+	if includeDeleted(r) {
+		filters[models.FilterIncludeDeleted] = "true"
+	}
 
 	sort := r.URL.Query().Get("sort")
 	order := r.URL.Query().Get("order")
@@ -107,11 +109,6 @@ func (h *LocationAvailabilityHandler) CreateLocationAvailability(w http.Response
 		return
 	}
 
-	locationAvailability.CreatedTime = time.Now()
-	locationAvailability.UpdatedTime = time.Now()
-	locationAvailability.CreatedBy = "system"
-	locationAvailability.UpdatedBy = "system"
-
 	err = h.service.CreateLocationAvailability(ctx, &locationAvailability)
 	if err != nil {
 		http.Error(w, "Failed to create location availability", http.StatusInternalServerError)
@@ -136,9 +133,6 @@ func (h *LocationAvailabilityHandler) UpdateLocationAvailability(w http.Response
 		return
 	}
 
-	locationAvailability.UpdatedTime = time.Now()
-	locationAvailability.UpdatedBy = "system"
-
 	updated, err := h.service.UpdateLocationAvailability(ctx, locationAvailabilityID, &locationAvailability)
 	if err != nil {
 		http.Error(w, "Location availability not found", http.StatusNotFound)
@@ -159,7 +153,20 @@ func (h *LocationAvailabilityHandler) DeleteLocationAvailability(w http.Response
 
 	err := h.service.DeleteLocationAvailability(ctx, locationAvailabilityID)
 	if err != nil {
-		http.Error(w, "Location availability not found", http.StatusNotFound)
+		writeServiceError(w, err, "Location availability not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *LocationAvailabilityHandler) RestoreLocationAvailability(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	locationAvailabilityID := chi.URLParam(r, "id")
+
+	err := h.service.RestoreLocationAvailability(ctx, locationAvailabilityID)
+	if err != nil {
+		writeServiceError(w, err, "Deleted location availability not found", http.StatusNotFound)
 		return
 	}
 
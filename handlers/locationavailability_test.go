@@ -200,3 +200,19 @@ func TestLocationAvailabilityHandler_DeleteLocationAvailability_Success(t *testi
 	assert.Equal(t, http.StatusNoContent, w.Code)
 	mockSvc.AssertExpectations(t)
 }
+
+func TestLocationAvailabilityHandler_DeleteLocationAvailability_HasDependents(t *testing.T) {
+	mockSvc := new(mocks.MockLocationAvailabilityService)
+	h := handlers.NewLocationAvailabilityHandler(mockSvc)
+
+	mockSvc.On("DeleteLocationAvailability", mock.Anything, "availability-id").Return(&models.DependentsError{Dependents: map[string]int64{"jobs": 5}})
+
+	r := httptest.NewRequest(http.MethodDelete, "/locationavailabilities/availability-id", nil)
+	r = addChiURLParam(r, "id", "availability-id")
+	w := httptest.NewRecorder()
+
+	h.DeleteLocationAvailability(w, r)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"jobs":5`)
+}
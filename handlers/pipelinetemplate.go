@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-mongodb-api/helpers"
+	"go-mongodb-api/interfaces"
+	"go-mongodb-api/models"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type PipelineTemplateHandler struct {
+	service interfaces.PipelineTemplateService
+}
+
+// NewPipelineTemplateHandler creates a new pipeline template handler
+func NewPipelineTemplateHandler(service interfaces.PipelineTemplateService) *PipelineTemplateHandler {
+	return &PipelineTemplateHandler{service: service}
+}
+
+// GetPipelineTemplates handles GET /pipelinetemplates request
+func (h *PipelineTemplateHandler) GetPipelineTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.service.GetPipelineTemplates(r.Context())
+	if err != nil {
+		writeServiceError(w, err, "Failed to retrieve pipeline templates", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(templates); err != nil {
+		log.Printf("error encoding response: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetPipelineTemplateByID handles GET /pipelinetemplates/{id} request
+func (h *PipelineTemplateHandler) GetPipelineTemplateByID(w http.ResponseWriter, r *http.Request) {
+	template, err := h.service.GetPipelineTemplateByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeServiceError(w, err, "Pipeline template not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(template); err != nil {
+		log.Printf("error encoding response: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// CreatePipelineTemplate handles POST /pipelinetemplates request
+func (h *PipelineTemplateHandler) CreatePipelineTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var template models.PipelineTemplate
+	if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	template.UserID = ownerFromClaims(ctx, template.UserID)
+
+	validationErrors := append(helpers.ValidateStruct(template), validateStages(template.Stages)...)
+	if len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
+		return
+	}
+
+	if err := h.service.CreatePipelineTemplate(ctx, &template); err != nil {
+		writeServiceError(w, err, "Failed to create pipeline template", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(template); err != nil {
+		log.Printf("error encoding response: %v", err)
+		return
+	}
+}
+
+// UpdatePipelineTemplate handles PUT /pipelinetemplates/{id} request, replacing the name and stages
+func (h *PipelineTemplateHandler) UpdatePipelineTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var template models.PipelineTemplate
+	if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// The owner cannot change, so it is not part of the update
+	validationErrors := append(helpers.ValidateStructExcept(template, "UserID"), validateStages(template.Stages)...)
+	if len(validationErrors) > 0 {
+		writeValidationErrors(w, validationErrors)
+		return
+	}
+
+	updated, err := h.service.UpdatePipelineTemplate(ctx, chi.URLParam(r, "id"), &template)
+	if err != nil {
+		writeServiceError(w, err, "Pipeline template not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(updated); err != nil {
+		log.Printf("error encoding response: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeletePipelineTemplate handles DELETE /pipelinetemplates/{id} request
+func (h *PipelineTemplateHandler) DeletePipelineTemplate(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeletePipelineTemplate(r.Context(), chi.URLParam(r, "id")); err != nil {
+		writeServiceError(w, err, "Pipeline template not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateStages checks that pipeline stage keys are unique and that the first stage, where new
+// applications start, has no outcome
+func validateStages(stages []models.PipelineStage) []helpers.ValidationError {
+	var validationErrors []helpers.ValidationError
+	if len(stages) > 0 && stages[0].Outcome != "" {
+		validationErrors = append(validationErrors, helpers.ValidationError{
+			Field:   "stages",
+			Message: "the first stage cannot have an outcome",
+		})
+	}
+	seen := make(map[string]bool, len(stages))
+	for _, stage := range stages {
+		if seen[stage.Key] {
+			validationErrors = append(validationErrors, helpers.ValidationError{
+				Field:   "stages",
+				Message: "duplicate stage key " + stage.Key,
+			})
+		}
+		seen[stage.Key] = true
+	}
+	return validationErrors
+}
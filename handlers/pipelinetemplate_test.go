@@ -0,0 +1,163 @@
+package handlers_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-mongodb-api/handlers"
+	"go-mongodb-api/middleware"
+	"go-mongodb-api/mocks"
+	"go-mongodb-api/models"
+	"go-mongodb-api/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestPipelineTemplateHandler_GetPipelineTemplates_Success(t *testing.T) {
+	mockSvc := new(mocks.MockPipelineTemplateService)
+	h := handlers.NewPipelineTemplateHandler(mockSvc)
+
+	templates := []models.PipelineTemplate{{Name: "Engineering"}}
+	mockSvc.On("GetPipelineTemplates", mock.Anything).Return(templates, nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/pipelinetemplates", nil)
+	w := httptest.NewRecorder()
+
+	h.GetPipelineTemplates(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"Engineering"`)
+}
+
+func TestPipelineTemplateHandler_GetPipelineTemplates_Error(t *testing.T) {
+	mockSvc := new(mocks.MockPipelineTemplateService)
+	h := handlers.NewPipelineTemplateHandler(mockSvc)
+
+	mockSvc.On("GetPipelineTemplates", mock.Anything).Return(nil, errors.New("db error"))
+
+	r := httptest.NewRequest(http.MethodGet, "/pipelinetemplates", nil)
+	w := httptest.NewRecorder()
+
+	h.GetPipelineTemplates(w, r)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestPipelineTemplateHandler_GetPipelineTemplateByID_NotFound(t *testing.T) {
+	mockSvc := new(mocks.MockPipelineTemplateService)
+	h := handlers.NewPipelineTemplateHandler(mockSvc)
+
+	mockSvc.On("GetPipelineTemplateByID", mock.Anything, "template-id").Return(nil, mongo.ErrNoDocuments)
+
+	r := httptest.NewRequest(http.MethodGet, "/pipelinetemplates/template-id", nil)
+	r = addChiURLParam(r, "id", "template-id")
+	w := httptest.NewRecorder()
+
+	h.GetPipelineTemplateByID(w, r)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPipelineTemplateHandler_CreatePipelineTemplate_Success(t *testing.T) {
+	mockSvc := new(mocks.MockPipelineTemplateService)
+	h := handlers.NewPipelineTemplateHandler(mockSvc)
+
+	recruiterID := bson.NewObjectID()
+	mockSvc.On("CreatePipelineTemplate", mock.Anything, mock.MatchedBy(func(t *models.PipelineTemplate) bool {
+		return t.UserID == recruiterID && t.Name == "Engineering" && len(t.Stages) == 2
+	})).Return(nil)
+
+	body := `{"name":"Engineering","stages":[{"key":"screen","name":"Screening"},{"key":"hired","name":"Hired","outcome":"accepted"}]}`
+	r := httptest.NewRequest(http.MethodPost, "/pipelinetemplates", bytes.NewBufferString(body))
+	r = withClaims(r, &middleware.Claims{UserID: recruiterID.Hex(), Role: "recruiter"})
+	w := httptest.NewRecorder()
+
+	h.CreatePipelineTemplate(w, r)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestPipelineTemplateHandler_CreatePipelineTemplate_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"missing name", `{"stages":[{"key":"screen","name":"Screening"}]}`},
+		{"no stages", `{"name":"Engineering","stages":[]}`},
+		{"first stage outcome", `{"name":"Engineering","stages":[{"key":"hired","name":"Hired","outcome":"accepted"}]}`},
+		{"duplicate keys", `{"name":"Engineering","stages":[{"key":"screen","name":"Screening"},{"key":"screen","name":"Phone screen"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockPipelineTemplateService)
+			h := handlers.NewPipelineTemplateHandler(mockSvc)
+
+			r := httptest.NewRequest(http.MethodPost, "/pipelinetemplates", bytes.NewBufferString(tt.body))
+			r = withClaims(r, &middleware.Claims{UserID: bson.NewObjectID().Hex(), Role: "recruiter"})
+			w := httptest.NewRecorder()
+
+			h.CreatePipelineTemplate(w, r)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockSvc.AssertNotCalled(t, "CreatePipelineTemplate", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPipelineTemplateHandler_UpdatePipelineTemplate_Success(t *testing.T) {
+	mockSvc := new(mocks.MockPipelineTemplateService)
+	h := handlers.NewPipelineTemplateHandler(mockSvc)
+
+	mockSvc.On("UpdatePipelineTemplate", mock.Anything, "template-id", mock.MatchedBy(func(t *models.PipelineTemplate) bool {
+		return t.Name == "Sales"
+	})).Return(&models.PipelineTemplate{Name: "Sales"}, nil)
+
+	body := `{"name":"Sales","stages":[{"key":"call","name":"Intro call"}]}`
+	r := httptest.NewRequest(http.MethodPut, "/pipelinetemplates/template-id", bytes.NewBufferString(body))
+	r = addChiURLParam(r, "id", "template-id")
+	w := httptest.NewRecorder()
+
+	h.UpdatePipelineTemplate(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"Sales"`)
+}
+
+func TestPipelineTemplateHandler_UpdatePipelineTemplate_Forbidden(t *testing.T) {
+	mockSvc := new(mocks.MockPipelineTemplateService)
+	h := handlers.NewPipelineTemplateHandler(mockSvc)
+
+	mockSvc.On("UpdatePipelineTemplate", mock.Anything, "template-id", mock.Anything).Return(nil, services.ErrForbidden)
+
+	body := `{"name":"Sales","stages":[{"key":"call","name":"Intro call"}]}`
+	r := httptest.NewRequest(http.MethodPut, "/pipelinetemplates/template-id", bytes.NewBufferString(body))
+	r = addChiURLParam(r, "id", "template-id")
+	w := httptest.NewRecorder()
+
+	h.UpdatePipelineTemplate(w, r)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestPipelineTemplateHandler_DeletePipelineTemplate_Success(t *testing.T) {
+	mockSvc := new(mocks.MockPipelineTemplateService)
+	h := handlers.NewPipelineTemplateHandler(mockSvc)
+
+	mockSvc.On("DeletePipelineTemplate", mock.Anything, "template-id").Return(nil)
+
+	r := httptest.NewRequest(http.MethodDelete, "/pipelinetemplates/template-id", nil)
+	r = addChiURLParam(r, "id", "template-id")
+	w := httptest.NewRecorder()
+
+	h.DeletePipelineTemplate(w, r)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-mongodb-api/helpers"
+	"go-mongodb-api/interfaces"
+	"go-mongodb-api/models"
+	"go-mongodb-api/services"
+	"log"
+	"net/http"
+	"time"
+)
+
+// writeServiceError maps well-known service errors to their HTTP status and
+// falls back to the given message and status for anything else
+func writeServiceError(w http.ResponseWriter, err error, message string, status int) {
+	var dependentsErr *models.DependentsError
+	var cooldownErr *services.ReapplyCooldownError
+	var answersErr *services.ScreeningAnswersError
+	switch {
+	case errors.As(err, &dependentsErr):
+		writeDependentsError(w, dependentsErr)
+	case errors.As(err, &answersErr):
+		writeValidationErrors(w, answersErr.Errors)
+	case errors.As(err, &cooldownErr):
+		http.Error(w, "You can reapply to this job from "+cooldownErr.Until.UTC().Format(time.RFC3339), http.StatusConflict)
+	case errors.Is(err, models.ErrParentDeleted):
+		http.Error(w, "A record this one references is deleted; restore it first", http.StatusConflict)
+	case errors.Is(err, interfaces.ErrDuplicateKey):
+		http.Error(w, "A record with the same unique values already exists", http.StatusConflict)
+	case errors.Is(err, services.ErrForbidden):
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	case errors.Is(err, services.ErrCategoryNotFound):
+		http.Error(w, "Job category not found", http.StatusBadRequest)
+	case errors.Is(err, services.ErrJobTypeNotFound):
+		http.Error(w, "Job type not found", http.StatusBadRequest)
+	case errors.Is(err, services.ErrCountryNotFound):
+		http.Error(w, "Country not found", http.StatusBadRequest)
+	case errors.Is(err, services.ErrEducationLevelNotFound):
+		http.Error(w, "Education level not found", http.StatusBadRequest)
+	case errors.Is(err, services.ErrLocationAvailabilityNotFound):
+		http.Error(w, "Location availability not found", http.StatusBadRequest)
+	case errors.Is(err, services.ErrInvalidClosesAt):
+		http.Error(w, "closes_at must be in the future", http.StatusBadRequest)
+	case errors.Is(err, services.ErrPlaceNotFound):
+		http.Error(w, "Place not found", http.StatusBadRequest)
+	case errors.Is(err, services.ErrBaseCurrencyRate):
+		http.Error(w, "The base currency rate is fixed at 1", http.StatusBadRequest)
+	case errors.Is(err, services.ErrInvalidJobTransition):
+		http.Error(w, "Invalid job status transition", http.StatusConflict)
+	case errors.Is(err, services.ErrPipelineTemplateNotFound):
+		http.Error(w, "Pipeline template not found", http.StatusBadRequest)
+	case errors.Is(err, services.ErrStageNotFound):
+		http.Error(w, "Pipeline stage not found", http.StatusBadRequest)
+	case errors.Is(err, services.ErrRejectionReasonNotFound):
+		http.Error(w, "Rejection reason not found", http.StatusBadRequest)
+	case errors.Is(err, services.ErrInvalidApplicationTransition):
+		http.Error(w, "Invalid application status transition", http.StatusConflict)
+	case errors.Is(err, services.ErrAlreadyApplied):
+		http.Error(w, "You have already applied to this job", http.StatusConflict)
+	case errors.Is(err, services.ErrJobNotOpen):
+		http.Error(w, "Job is not accepting applications", http.StatusConflict)
+	case errors.Is(err, services.ErrEmailTaken):
+		http.Error(w, "Email address already registered", http.StatusConflict)
+	case errors.Is(err, services.ErrRoleNotAllowed):
+		http.Error(w, "Role cannot be self-registered", http.StatusBadRequest)
+	case errors.Is(err, services.ErrInvalidInvitation):
+		http.Error(w, "Invalid or expired invitation", http.StatusBadRequest)
+	case errors.Is(err, services.ErrInvalidMFACode):
+		http.Error(w, "Invalid two-factor code", http.StatusBadRequest)
+	case errors.Is(err, services.ErrMFAAlreadyEnabled):
+		http.Error(w, "Two-factor authentication already enabled", http.StatusConflict)
+	case errors.Is(err, services.ErrMFANotEnabled):
+		http.Error(w, "Two-factor authentication not enabled", http.StatusBadRequest)
+	default:
+		http.Error(w, message, status)
+	}
+}
+
+// writeValidationErrors writes a 400 response listing the failed validation rules
+func writeValidationErrors(w http.ResponseWriter, validationErrors []helpers.ValidationError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	if err := json.NewEncoder(w).Encode(helpers.ErrorResponse{Errors: validationErrors}); err != nil {
+		log.Printf("error encoding validation error response: %v", err)
+	}
+}
+
+// dependentsResponse is the body of a 409 returned when a delete is restricted by dependents
+type dependentsResponse struct {
+	Message    string           `json:"message"`
+	Dependents map[string]int64 `json:"dependents"`
+}
+
+// writeDependentsError writes a 409 response listing the records that still reference the one being deleted
+func writeDependentsError(w http.ResponseWriter, err *models.DependentsError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	response := dependentsResponse{
+		Message:    "Cannot delete a record that is still referenced by other records",
+		Dependents: err.Dependents,
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("error encoding dependents error response: %v", err)
+	}
+}
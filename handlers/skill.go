@@ -8,7 +8,6 @@ import (
 	"log"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -48,6 +47,9 @@ func (h *SkillHandler) GetAllSkills(w http.ResponseWriter, r *http.Request) {
 	filters := map[string]string{
 		"name": r.URL.Query().Get("name"),
 	}
+	if includeDeleted(r) {
+		filters[models.FilterIncludeDeleted] = "true"
+	}
 
 	sort := r.URL.Query().Get("sort")
 	order := r.URL.Query().Get("order")
@@ -117,14 +119,9 @@ func (h *SkillHandler) CreateSkill(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	skill.CreatedTime = time.Now()
-	skill.UpdatedTime = time.Now()
-	skill.CreatedBy = "system"
-	skill.UpdatedBy = "system"
-
 	err = h.service.CreateSkill(ctx, &skill)
 	if err != nil {
-		http.Error(w, "Failed to create skill", http.StatusInternalServerError)
+		writeServiceError(w, err, "Failed to create skill", http.StatusInternalServerError)
 		return
 	}
 
@@ -147,9 +144,6 @@ func (h *SkillHandler) UpdateSkill(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	skill.UpdatedTime = time.Now()
-	skill.UpdatedBy = "system"
-
 	updated, err := h.service.UpdateSkill(ctx, skillID, &skill)
 	if err != nil {
 		http.Error(w, "Skill not found", http.StatusNotFound)
@@ -171,7 +165,21 @@ func (h *SkillHandler) DeleteSkill(w http.ResponseWriter, r *http.Request) {
 
 	err := h.service.DeleteSkill(ctx, skillID)
 	if err != nil {
-		http.Error(w, "Skill not found", http.StatusNotFound)
+		writeServiceError(w, err, "Skill not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreSkill handles POST /skills/{id}/restore request
+func (h *SkillHandler) RestoreSkill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	skillID := chi.URLParam(r, "id")
+
+	err := h.service.RestoreSkill(ctx, skillID)
+	if err != nil {
+		writeServiceError(w, err, "Deleted skill not found", http.StatusNotFound)
 		return
 	}
 
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"go-mongodb-api/handlers"
+	"go-mongodb-api/interfaces"
 	"go-mongodb-api/mocks"
 	"go-mongodb-api/models"
 
@@ -121,6 +122,37 @@ func TestSkillHandler_CreateSkill_ValidationError(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestSkillHandler_CreateSkill_Duplicate(t *testing.T) {
+	mockSvc := new(mocks.MockSkillService)
+	h := handlers.NewSkillHandler(mockSvc)
+
+	mockSvc.On("CreateSkill", mock.Anything, mock.AnythingOfType("*models.Skill")).Return(interfaces.ErrDuplicateKey)
+
+	body := `{"name":"Kubernetes","description":"Container orchestration tool"}`
+	r := httptest.NewRequest(http.MethodPost, "/skills", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	h.CreateSkill(w, r)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestSkillHandler_RestoreSkill_LiveDuplicate(t *testing.T) {
+	mockSvc := new(mocks.MockSkillService)
+	h := handlers.NewSkillHandler(mockSvc)
+
+	mockSvc.On("RestoreSkill", mock.Anything, "skill-id").Return(interfaces.ErrDuplicateKey)
+
+	r := httptest.NewRequest(http.MethodPost, "/skills/skill-id/restore", nil)
+	r = addChiURLParam(r, "id", "skill-id")
+	w := httptest.NewRecorder()
+
+	h.RestoreSkill(w, r)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "same unique values")
+}
+
 func TestSkillHandler_DeleteSkill_Success(t *testing.T) {
 	mockSvc := new(mocks.MockSkillService)
 	h := handlers.NewSkillHandler(mockSvc)
@@ -200,3 +232,19 @@ func TestSkillHandler_UpdateSkill_InvalidBody(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func TestSkillHandler_DeleteSkill_HasDependents(t *testing.T) {
+	mockSvc := new(mocks.MockSkillService)
+	h := handlers.NewSkillHandler(mockSvc)
+
+	mockSvc.On("DeleteSkill", mock.Anything, "skill-id").Return(&models.DependentsError{Dependents: map[string]int64{"jobskills": 1, "candidateskills": 3}})
+
+	r := httptest.NewRequest(http.MethodDelete, "/skills/skill-id", nil)
+	r = addChiURLParam(r, "id", "skill-id")
+	w := httptest.NewRecorder()
+
+	h.DeleteSkill(w, r)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"candidateskills":3`)
+}
@@ -8,7 +8,6 @@ import (
 	"log"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -51,6 +50,9 @@ func (h *UserHandler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
 		"email":      r.URL.Query().Get("email"),
 		"role":       r.URL.Query().Get("role"),
 	}
+	if includeDeleted(r) {
+		filters[models.FilterIncludeDeleted] = "true"
+	}
 
 	sort := r.URL.Query().Get("sort")
 	order := r.URL.Query().Get("order")
@@ -68,7 +70,7 @@ func (h *UserHandler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
 
 	responses := make([]models.UserResponse, len(users))
 	for i := range users {
-		responses[i] = users[i].ToResponse()
+		responses[i] = userResponse(ctx, &users[i])
 	}
 	response := helpers.PaginatedResponse{
 		Data:       responses,
@@ -95,7 +97,7 @@ func (h *UserHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(user.ToResponse()); err != nil {
+	if err := json.NewEncoder(w).Encode(userResponse(ctx, user)); err != nil {
 		log.Printf("error encoding response: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
@@ -124,20 +126,15 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user.CreatedTime = time.Now()
-	user.UpdatedTime = time.Now()
-	user.CreatedBy = "system"
-	user.UpdatedBy = "system"
-
 	err = h.service.CreateUser(ctx, &user)
 	if err != nil {
-		http.Error(w, "Failed to create user", http.StatusInternalServerError)
+		writeServiceError(w, err, "Failed to create user", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(user.ToResponse()); err != nil {
+	if err := json.NewEncoder(w).Encode(userResponse(ctx, &user)); err != nil {
 		log.Printf("error encoding response: %v", err)
 		return
 	}
@@ -154,9 +151,6 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user.UpdatedTime = time.Now()
-	user.UpdatedBy = "system"
-
 	updated, err := h.service.UpdateUser(ctx, userID, &user)
 	if err != nil {
 		http.Error(w, "User not found", http.StatusNotFound)
@@ -164,7 +158,7 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(updated.ToResponse()); err != nil {
+	if err := json.NewEncoder(w).Encode(userResponse(ctx, updated)); err != nil {
 		log.Printf("error encoding response: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
@@ -178,7 +172,21 @@ func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 
 	err := h.service.DeleteUser(ctx, userID)
 	if err != nil {
-		http.Error(w, "User not found", http.StatusNotFound)
+		writeServiceError(w, err, "User not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreUser handles POST /users/{id}/restore request
+func (h *UserHandler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := chi.URLParam(r, "id")
+
+	err := h.service.RestoreUser(ctx, userID)
+	if err != nil {
+		writeServiceError(w, err, "Deleted user not found", http.StatusNotFound)
 		return
 	}
 
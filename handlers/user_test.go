@@ -10,6 +10,8 @@ import (
 	"testing"
 
 	"go-mongodb-api/handlers"
+	"go-mongodb-api/interfaces"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/mocks"
 	"go-mongodb-api/models"
 
@@ -17,6 +19,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 func addChiURLParam(r *http.Request, key, value string) *http.Request {
@@ -75,6 +78,47 @@ func TestUserHandler_GetUserByID_Success(t *testing.T) {
 	mockSvc.AssertExpectations(t)
 }
 
+func TestUserHandler_GetUserByID_MFAEnabled(t *testing.T) {
+	id := bson.NewObjectID()
+	tests := []struct {
+		name   string
+		claims *middleware.Claims
+		shown  bool
+	}{
+		{"anonymous", nil, false},
+		{"other user", &middleware.Claims{UserID: bson.NewObjectID().Hex(), Role: "candidate"}, false},
+		{"same user", &middleware.Claims{UserID: id.Hex(), Role: "recruiter"}, true},
+		{"admin", &middleware.Claims{UserID: bson.NewObjectID().Hex(), Role: "admin"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockUserService)
+			h := handlers.NewUserHandler(mockSvc)
+
+			mockSvc.On("GetUserByID", mock.Anything, id.Hex()).Return(&models.User{ID: id, MFAEnabled: true}, nil)
+
+			r := httptest.NewRequest(http.MethodGet, "/users/"+id.Hex(), nil)
+			r = addChiURLParam(r, "id", id.Hex())
+			if tt.claims != nil {
+				r = withClaims(r, tt.claims)
+			}
+			w := httptest.NewRecorder()
+
+			h.GetUserByID(w, r)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			var body map[string]any
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			mfaEnabled, present := body["mfa_enabled"]
+			assert.Equal(t, tt.shown, present)
+			if tt.shown {
+				assert.Equal(t, true, mfaEnabled)
+			}
+		})
+	}
+}
+
 func TestUserHandler_GetUserByID_NotFound(t *testing.T) {
 	mockSvc := new(mocks.MockUserService)
 	h := handlers.NewUserHandler(mockSvc)
@@ -268,3 +312,102 @@ func TestUserHandler_GetUserByID_ResponseBody(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "alice@example.com", resp.Email)
 }
+
+func TestUserHandler_DeleteUser_HasDependents(t *testing.T) {
+	mockSvc := new(mocks.MockUserService)
+	h := handlers.NewUserHandler(mockSvc)
+
+	mockSvc.On("DeleteUser", mock.Anything, "user-id").Return(&models.DependentsError{Dependents: map[string]int64{"jobs": 2}})
+
+	r := httptest.NewRequest(http.MethodDelete, "/users/user-id", nil)
+	r = addChiURLParam(r, "id", "user-id")
+	w := httptest.NewRecorder()
+
+	h.DeleteUser(w, r)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"jobs":2`)
+}
+
+func TestUserHandler_GetAllUsers_IncludeDeletedAdmin(t *testing.T) {
+	mockSvc := new(mocks.MockUserService)
+	h := handlers.NewUserHandler(mockSvc)
+
+	mockSvc.On("GetAllUsers", mock.Anything, 1, 10, mock.MatchedBy(func(f map[string]string) bool {
+		return f[models.FilterIncludeDeleted] == "true"
+	}), "", "").Return([]models.User{}, int64(0), nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/users?include_deleted=true", nil)
+	r = withClaims(r, &middleware.Claims{UserID: "admin-id", Role: "admin"})
+	w := httptest.NewRecorder()
+
+	h.GetAllUsers(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestUserHandler_GetAllUsers_IncludeDeletedIgnoredForNonAdmin(t *testing.T) {
+	mockSvc := new(mocks.MockUserService)
+	h := handlers.NewUserHandler(mockSvc)
+
+	mockSvc.On("GetAllUsers", mock.Anything, 1, 10, mock.MatchedBy(func(f map[string]string) bool {
+		_, ok := f[models.FilterIncludeDeleted]
+		return !ok
+	}), "", "").Return([]models.User{}, int64(0), nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/users?include_deleted=true", nil)
+	r = withClaims(r, &middleware.Claims{UserID: "user-id", Role: "recruiter"})
+	w := httptest.NewRecorder()
+
+	h.GetAllUsers(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestUserHandler_RestoreUser_Success(t *testing.T) {
+	mockSvc := new(mocks.MockUserService)
+	h := handlers.NewUserHandler(mockSvc)
+
+	mockSvc.On("RestoreUser", mock.Anything, "some-id").Return(nil)
+
+	r := httptest.NewRequest(http.MethodPost, "/users/some-id/restore", nil)
+	r = addChiURLParam(r, "id", "some-id")
+	w := httptest.NewRecorder()
+
+	h.RestoreUser(w, r)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestUserHandler_RestoreUser_LiveDuplicate(t *testing.T) {
+	mockSvc := new(mocks.MockUserService)
+	h := handlers.NewUserHandler(mockSvc)
+
+	mockSvc.On("RestoreUser", mock.Anything, "some-id").Return(interfaces.ErrDuplicateKey)
+
+	r := httptest.NewRequest(http.MethodPost, "/users/some-id/restore", nil)
+	r = addChiURLParam(r, "id", "some-id")
+	w := httptest.NewRecorder()
+
+	h.RestoreUser(w, r)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestUserHandler_RestoreUser_NotFound(t *testing.T) {
+	mockSvc := new(mocks.MockUserService)
+	h := handlers.NewUserHandler(mockSvc)
+
+	mockSvc.On("RestoreUser", mock.Anything, "missing-id").Return(mongo.ErrNoDocuments)
+
+	r := httptest.NewRequest(http.MethodPost, "/users/missing-id/restore", nil)
+	r = addChiURLParam(r, "id", "missing-id")
+	w := httptest.NewRecorder()
+
+	h.RestoreUser(w, r)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,107 @@
+package helpers
+
+import (
+	"errors"
+	"strings"
+)
+
+// MaxSearchQueryLength caps the length of a full-text search query
+const MaxSearchQueryLength = 256
+
+var (
+	ErrSearchQueryTooLong = errors.New("search query is too long")
+	ErrSearchQueryNoTerms = errors.New("search query must include at least one term that is not excluded")
+)
+
+// SearchQuery is a parsed full-text search. Documents match when they contain any of the terms and
+// every phrase, and none of the excluded terms or phrases.
+type SearchQuery struct {
+	Terms           []string
+	Phrases         []string
+	ExcludedTerms   []string
+	ExcludedPhrases []string
+}
+
+// ParseSearchQuery parses a search query of plain terms, "quoted phrases" and terms or phrases
+// prefixed with - to exclude them. An unterminated quote runs to the end of the query.
+func ParseSearchQuery(q string) (*SearchQuery, error) {
+	if len(q) > MaxSearchQueryLength {
+		return nil, ErrSearchQueryTooLong
+	}
+
+	// Backslashes would escape the quotes the query is rebuilt with
+	q = strings.ReplaceAll(q, `\`, " ")
+
+	query := &SearchQuery{}
+	for i := 0; i < len(q); {
+		if q[i] == ' ' || q[i] == '\t' {
+			i++
+			continue
+		}
+
+		excluded := false
+		if q[i] == '-' {
+			excluded = true
+			i++
+			if i == len(q) {
+				break
+			}
+		}
+
+		if q[i] == '"' {
+			end := strings.IndexByte(q[i+1:], '"')
+			var phrase string
+			if end < 0 {
+				phrase, i = q[i+1:], len(q)
+			} else {
+				phrase, i = q[i+1:i+1+end], i+end+2
+			}
+			phrase = strings.Join(strings.Fields(phrase), " ")
+			if phrase == "" {
+				continue
+			}
+			if excluded {
+				query.ExcludedPhrases = append(query.ExcludedPhrases, phrase)
+			} else {
+				query.Phrases = append(query.Phrases, phrase)
+			}
+			continue
+		}
+
+		end := strings.IndexAny(q[i:], " \t\"")
+		if end < 0 {
+			end = len(q) - i
+		}
+		term := strings.Trim(q[i:i+end], "-")
+		i += end
+		if term == "" {
+			continue
+		}
+		if excluded {
+			query.ExcludedTerms = append(query.ExcludedTerms, term)
+		} else {
+			query.Terms = append(query.Terms, term)
+		}
+	}
+
+	if len(query.Terms) == 0 && len(query.Phrases) == 0 {
+		return nil, ErrSearchQueryNoTerms
+	}
+	return query, nil
+}
+
+// String returns the query in MongoDB $text $search syntax
+func (s *SearchQuery) String() string {
+	parts := make([]string, 0, len(s.Terms)+len(s.Phrases)+len(s.ExcludedTerms)+len(s.ExcludedPhrases))
+	for _, phrase := range s.Phrases {
+		parts = append(parts, `"`+phrase+`"`)
+	}
+	parts = append(parts, s.Terms...)
+	for _, term := range s.ExcludedTerms {
+		parts = append(parts, "-"+term)
+	}
+	for _, phrase := range s.ExcludedPhrases {
+		parts = append(parts, `-"`+phrase+`"`)
+	}
+	return strings.Join(parts, " ")
+}
@@ -17,7 +17,16 @@ var validate = validator.New()
 
 // ValidateStruct validates a struct and returns structured error messages
 func ValidateStruct(data interface{}) []ValidationError {
-	err := validate.Struct(data)
+	return validationErrors(validate.Struct(data))
+}
+
+// ValidateStructExcept validates a struct like ValidateStruct, skipping the named fields
+func ValidateStructExcept(data interface{}, fields ...string) []ValidationError {
+	return validationErrors(validate.StructExcept(data, fields...))
+}
+
+// validationErrors converts the error returned by the validator into structured error messages
+func validationErrors(err error) []ValidationError {
 	if err == nil {
 		return nil
 	}
@@ -35,7 +44,7 @@ func ValidateStruct(data interface{}) []ValidationError {
 // getErrorMessage returns a user-friendly error message based on the validation tag
 func getErrorMessage(err validator.FieldError) string {
 	switch err.Tag() {
-	case "required":
+	case "required", "required_if":
 		return "This field is required"
 	case "email":
 		return "Invalid email format"
@@ -0,0 +1,6 @@
+package interfaces
+
+import "errors"
+
+// ErrDuplicateKey is returned by repositories when a write is rejected by a unique index
+var ErrDuplicateKey = errors.New("duplicate key")
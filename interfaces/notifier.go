@@ -0,0 +1,8 @@
+package interfaces
+
+import "context"
+
+// Notifier delivers out-of-band messages, such as password reset links, to users
+type Notifier interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
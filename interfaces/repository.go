@@ -3,6 +3,7 @@ package interfaces
 import (
 	"context"
 	"go-mongodb-api/models"
+	"time"
 )
 
 type UserRepository interface {
@@ -11,7 +12,16 @@ type UserRepository interface {
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
 	Create(ctx context.Context, user *models.User) error
 	Update(ctx context.Context, id string, user *models.User) (*models.User, error)
-	Delete(ctx context.Context, id string) error
+	UpdatePassword(ctx context.Context, id string, hashedPassword string, updated models.Audit) error
+	MarkVerified(ctx context.Context, id string, updated models.Audit) error
+	UpdateLastLogin(ctx context.Context, id string, loginTime time.Time) error
+	SetMFASecret(ctx context.Context, id string, secret string, updated models.Audit) error
+	EnableMFA(ctx context.Context, id string, recoveryCodeHashes []string, step int64, updated models.Audit) error
+	DisableMFA(ctx context.Context, id string, updated models.Audit) error
+	UseMFAStep(ctx context.Context, id string, step int64) error
+	UseRecoveryCode(ctx context.Context, id string, codeHash string) error
+	Delete(ctx context.Context, id string, deletedBy string) error
+	Restore(ctx context.Context, id string, restored models.Audit) error
 }
 
 type ApplicationRepository interface {
@@ -19,17 +29,31 @@ type ApplicationRepository interface {
 	GetByID(ctx context.Context, id string) (*models.Application, error)
 	GetByJobID(ctx context.Context, jobID string) ([]models.Application, error)
 	GetByUserID(ctx context.Context, userID string) ([]models.Application, error)
+	Expand(ctx context.Context, applications []models.Application, expand []string) error
+	CountByStage(ctx context.Context, jobID string) (map[string]int64, error)
+	GetLatestAttempt(ctx context.Context, jobID, userID string) (*models.Application, error)
 	Create(ctx context.Context, application *models.Application) error
-	UpdateStatus(ctx context.Context, id string, status string) error
-	Delete(ctx context.Context, id string) error
+	UpdateStatus(ctx context.Context, id string, from string, application *models.Application, change models.ApplicationStatusChange) (*models.Application, error)
+	BulkUpdate(ctx context.Context, updates []models.ApplicationBulkUpdate) ([]string, error)
+	Delete(ctx context.Context, id string, deletedBy string) error
+	Restore(ctx context.Context, id string, restored models.Audit) error
 }
 
 type JobRepository interface {
 	GetAll(ctx context.Context, page, limit int, filters map[string]string, sort, order string) ([]models.Job, int64, error)
+	Facets(ctx context.Context, filters map[string]string, salaryBands []int) (*models.JobFacets, error)
 	GetByID(ctx context.Context, id string) (*models.Job, error)
 	GetByUserID(ctx context.Context, userID string) ([]models.Job, error)
+	Expand(ctx context.Context, jobs []models.Job, expand []string) error
 	Create(ctx context.Context, job *models.Job) error
-	Delete(ctx context.Context, id string) error
+	Update(ctx context.Context, id string, job *models.Job) (*models.Job, error)
+	UpdateStatus(ctx context.Context, id string, from string, job *models.Job) (*models.Job, error)
+	UpdateStages(ctx context.Context, id string, job *models.Job) (*models.Job, error)
+	CloseExpired(ctx context.Context, now time.Time, updatedBy string) (int64, error)
+	NormalizeSalaries(ctx context.Context, currency string, rate float64) (int64, error)
+	ClearNormalizedSalaries(ctx context.Context, currency string) (int64, error)
+	Delete(ctx context.Context, id string, deletedBy string) error
+	Restore(ctx context.Context, id string, restored models.Audit) error
 }
 
 type SkillRepository interface {
@@ -37,7 +61,8 @@ type SkillRepository interface {
 	GetByID(ctx context.Context, id string) (*models.Skill, error)
 	Create(ctx context.Context, skill *models.Skill) error
 	Update(ctx context.Context, id string, skill *models.Skill) (*models.Skill, error)
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, deletedBy string) error
+	Restore(ctx context.Context, id string, restored models.Audit) error
 }
 
 type JobCategoryRepository interface {
@@ -45,7 +70,8 @@ type JobCategoryRepository interface {
 	GetByID(ctx context.Context, id string) (*models.JobCategory, error)
 	Create(ctx context.Context, jobCategory *models.JobCategory) error
 	Update(ctx context.Context, id string, jobCategory *models.JobCategory) (*models.JobCategory, error)
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, deletedBy string) error
+	Restore(ctx context.Context, id string, restored models.Audit) error
 }
 
 type ArticleRepository interface {
@@ -53,15 +79,18 @@ type ArticleRepository interface {
 	GetByID(ctx context.Context, id string) (*models.Article, error)
 	Create(ctx context.Context, article *models.Article) error
 	Update(ctx context.Context, id string, article *models.Article) (*models.Article, error)
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, deletedBy string) error
+	Restore(ctx context.Context, id string, restored models.Audit) error
 }
 
 type CountryRepository interface {
 	GetAll(ctx context.Context, page, limit int, filters map[string]string, sort, order string) ([]models.Country, int64, error)
 	GetByID(ctx context.Context, id string) (*models.Country, error)
+	GetByName(ctx context.Context, name string) (*models.Country, error)
 	Create(ctx context.Context, country *models.Country) error
 	Update(ctx context.Context, id string, country *models.Country) (*models.Country, error)
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, deletedBy string) error
+	Restore(ctx context.Context, id string, restored models.Audit) error
 }
 
 type EducationLevelRepository interface {
@@ -69,7 +98,8 @@ type EducationLevelRepository interface {
 	GetByID(ctx context.Context, id string) (*models.EducationLevel, error)
 	Create(ctx context.Context, educationLevel *models.EducationLevel) error
 	Update(ctx context.Context, id string, educationLevel *models.EducationLevel) (*models.EducationLevel, error)
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, deletedBy string) error
+	Restore(ctx context.Context, id string, restored models.Audit) error
 }
 
 type JobTypeRepository interface {
@@ -77,7 +107,8 @@ type JobTypeRepository interface {
 	GetByID(ctx context.Context, id string) (*models.JobType, error)
 	Create(ctx context.Context, jobType *models.JobType) error
 	Update(ctx context.Context, id string, jobType *models.JobType) (*models.JobType, error)
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, deletedBy string) error
+	Restore(ctx context.Context, id string, restored models.Audit) error
 }
 
 type KnowledgeLevelRepository interface {
@@ -85,7 +116,8 @@ type KnowledgeLevelRepository interface {
 	GetByID(ctx context.Context, id string) (*models.KnowledgeLevel, error)
 	Create(ctx context.Context, knowledgeLevel *models.KnowledgeLevel) error
 	Update(ctx context.Context, id string, knowledgeLevel *models.KnowledgeLevel) (*models.KnowledgeLevel, error)
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, deletedBy string) error
+	Restore(ctx context.Context, id string, restored models.Audit) error
 }
 
 type LocationAvailabilityRepository interface {
@@ -93,7 +125,8 @@ type LocationAvailabilityRepository interface {
 	GetByID(ctx context.Context, id string) (*models.LocationAvailability, error)
 	Create(ctx context.Context, locationAvailability *models.LocationAvailability) error
 	Update(ctx context.Context, id string, locationAvailability *models.LocationAvailability) (*models.LocationAvailability, error)
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, deletedBy string) error
+	Restore(ctx context.Context, id string, restored models.Audit) error
 }
 
 type CandidateSkillRepository interface {
@@ -101,8 +134,9 @@ type CandidateSkillRepository interface {
 	GetByID(ctx context.Context, id string) (*models.CandidateSkill, error)
 	GetByUserID(ctx context.Context, userID string) ([]models.CandidateSkill, error)
 	Create(ctx context.Context, candidateSkill *models.CandidateSkill) error
-	UpdateProficiencyLevel(ctx context.Context, id string, proficiencyLevel string) error
-	Delete(ctx context.Context, id string) error
+	UpdateProficiencyLevel(ctx context.Context, id string, proficiencyLevel string, updated models.Audit) error
+	Delete(ctx context.Context, id string, deletedBy string) error
+	Restore(ctx context.Context, id string, restored models.Audit) error
 }
 
 type JobSkillRepository interface {
@@ -110,6 +144,58 @@ type JobSkillRepository interface {
 	GetByID(ctx context.Context, id string) (*models.JobSkill, error)
 	GetByJobID(ctx context.Context, jobID string) ([]models.JobSkill, error)
 	Create(ctx context.Context, jobSkill *models.JobSkill) error
-	UpdateProficiencyLevel(ctx context.Context, id string, proficiencyLevel string) error
+	UpdateProficiencyLevel(ctx context.Context, id string, proficiencyLevel string, updated models.Audit) error
+	Delete(ctx context.Context, id string, deletedBy string) error
+	Restore(ctx context.Context, id string, restored models.Audit) error
+}
+
+type SessionRepository interface {
+	GetByID(ctx context.Context, id string) (*models.Session, error)
+	GetByPreviousHash(ctx context.Context, tokenHash string) (*models.Session, error)
+	Create(ctx context.Context, session *models.Session) error
+	Rotate(ctx context.Context, oldHash, newHash string, expiresTime time.Time) (*models.Session, error)
+	Revoke(ctx context.Context, id string, reason string) error
+	RevokeAllForUser(ctx context.Context, userID string, reason string) error
+}
+
+type UserTokenRepository interface {
+	Create(ctx context.Context, token *models.UserToken) error
+	Consume(ctx context.Context, tokenHash, purpose string) (*models.UserToken, error)
+	DeleteByUser(ctx context.Context, userID, purpose string) error
+	DeleteByEmail(ctx context.Context, email, purpose string) error
+}
+
+type LoginAttemptRepository interface {
+	Get(ctx context.Context, scope, value string) (*models.LoginAttempt, error)
+	RecordFailure(ctx context.Context, scope, value string, expiresTime time.Time) (*models.LoginAttempt, error)
+	Lock(ctx context.Context, scope, value string, until time.Time) error
+	Reset(ctx context.Context, scope, value string) error
+}
+
+type APIKeyRepository interface {
+	GetAll(ctx context.Context) ([]models.APIKey, error)
+	GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	Create(ctx context.Context, key *models.APIKey) error
+	Revoke(ctx context.Context, id string, revoked models.Audit) error
+	TouchLastUsed(ctx context.Context, id string, usedTime time.Time) error
+}
+
+type ExchangeRateRepository interface {
+	GetAll(ctx context.Context) ([]models.ExchangeRate, error)
+	GetByCurrency(ctx context.Context, currency string) (*models.ExchangeRate, error)
+	Upsert(ctx context.Context, rate *models.ExchangeRate) (*models.ExchangeRate, error)
+	Delete(ctx context.Context, currency string) error
+}
+
+type CityRepository interface {
+	FindByName(ctx context.Context, name string, countryID string) (*models.City, error)
+	Upsert(ctx context.Context, city *models.City) (bool, error)
+}
+
+type PipelineTemplateRepository interface {
+	GetAll(ctx context.Context, userID, companyName string) ([]models.PipelineTemplate, error)
+	GetByID(ctx context.Context, id string) (*models.PipelineTemplate, error)
+	Create(ctx context.Context, template *models.PipelineTemplate) error
+	Update(ctx context.Context, id string, template *models.PipelineTemplate) (*models.PipelineTemplate, error)
 	Delete(ctx context.Context, id string) error
 }
@@ -3,6 +3,7 @@ package interfaces
 import (
 	"context"
 	"go-mongodb-api/models"
+	"time"
 )
 
 type UserService interface {
@@ -11,12 +12,35 @@ type UserService interface {
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 	CreateUser(ctx context.Context, user *models.User) error
 	UpdateUser(ctx context.Context, id string, user *models.User) (*models.User, error)
+	UpdatePassword(ctx context.Context, id string, password string) error
+	MarkVerified(ctx context.Context, id string) error
+	RecordLogin(ctx context.Context, id string) error
+	SetMFASecret(ctx context.Context, id string, secret string) error
+	EnableMFA(ctx context.Context, id string, recoveryCodeHashes []string, step int64) error
+	DisableMFA(ctx context.Context, id string) error
+	UseMFAStep(ctx context.Context, id string, step int64) error
+	UseRecoveryCode(ctx context.Context, id string, codeHash string) error
 	DeleteUser(ctx context.Context, id string) error
+	RestoreUser(ctx context.Context, id string) error
 }
 
 type AuthService interface {
-	Login(ctx context.Context, email, password string) (string, error)
+	Login(ctx context.Context, email, password, clientIP string) (*models.AuthTokens, error)
 	Register(ctx context.Context, user *models.User) error
+	Refresh(ctx context.Context, refreshToken string) (*models.AuthTokens, error)
+	Logout(ctx context.Context, sessionID string) error
+	LogoutAll(ctx context.Context, userID string) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, password string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+	Invite(ctx context.Context, email string) error
+	AcceptInvitation(ctx context.Context, token string, user *models.User) error
+	UnlockAccount(ctx context.Context, userID string) error
+	VerifyMFALogin(ctx context.Context, mfaToken, code, clientIP string) (*models.AuthTokens, error)
+	EnrollMFA(ctx context.Context, userID string) (*models.MFAEnrollment, error)
+	ConfirmMFA(ctx context.Context, userID, code string) ([]string, error)
+	DisableMFA(ctx context.Context, userID, code string) error
 }
 
 type ApplicationService interface {
@@ -24,17 +48,34 @@ type ApplicationService interface {
 	GetApplicationByID(ctx context.Context, id string) (*models.Application, error)
 	GetApplicationsByJobID(ctx context.Context, jobID string) ([]models.Application, error)
 	GetApplicationsByUserID(ctx context.Context, userID string) ([]models.Application, error)
+	ExpandApplications(ctx context.Context, applications []models.Application, expand []string) error
+	GetApplicationStageCounts(ctx context.Context, jobID string) ([]models.StageCount, error)
 	CreateApplication(ctx context.Context, application *models.Application) error
-	UpdateApplicationStatus(ctx context.Context, id string, status string) error
+	GetApplicationHistory(ctx context.Context, id string) ([]models.ApplicationStatusChange, error)
+	UpdateApplicationStatus(ctx context.Context, id string, status string, note string) (*models.Application, error)
+	WithdrawApplication(ctx context.Context, id string, reason string) (*models.Application, error)
+	MoveApplicationStage(ctx context.Context, id string, stage string, note string) (*models.Application, error)
+	GetRejectionReasons(ctx context.Context) []models.RejectionReason
+	BulkUpdateApplications(ctx context.Context, jobID string, action models.ApplicationBulkAction) ([]models.ApplicationBulkResult, error)
 	DeleteApplication(ctx context.Context, id string) error
+	RestoreApplication(ctx context.Context, id string) error
 }
 
 type JobService interface {
 	GetAllJobs(ctx context.Context, page, limit int, filters map[string]string, sort, order string) ([]models.Job, int64, error)
+	GetJobFacets(ctx context.Context, filters map[string]string) (*models.JobFacets, error)
 	GetJobByID(ctx context.Context, id string) (*models.Job, error)
 	GetJobsByUser(ctx context.Context, userID string) ([]models.Job, error)
+	ExpandJobs(ctx context.Context, jobs []models.Job, expand []string) error
 	CreateJob(ctx context.Context, job *models.Job) error
+	UpdateJob(ctx context.Context, id string, job *models.Job) (*models.Job, error)
+	PublishJob(ctx context.Context, id string, closesAt *time.Time) (*models.Job, error)
+	CloseJob(ctx context.Context, id string) (*models.Job, error)
+	ReopenJob(ctx context.Context, id string, closesAt *time.Time) (*models.Job, error)
+	ArchiveJob(ctx context.Context, id string) (*models.Job, error)
+	SetJobStages(ctx context.Context, id string, stages []models.PipelineStage, templateID string) (*models.Job, error)
 	DeleteJob(ctx context.Context, id string) error
+	RestoreJob(ctx context.Context, id string) error
 }
 
 type SkillService interface {
@@ -43,6 +84,7 @@ type SkillService interface {
 	CreateSkill(ctx context.Context, skill *models.Skill) error
 	UpdateSkill(ctx context.Context, id string, skill *models.Skill) (*models.Skill, error)
 	DeleteSkill(ctx context.Context, id string) error
+	RestoreSkill(ctx context.Context, id string) error
 }
 
 type JobCategoryService interface {
@@ -51,6 +93,7 @@ type JobCategoryService interface {
 	CreateJobCategory(ctx context.Context, jobCategory *models.JobCategory) error
 	UpdateJobCategory(ctx context.Context, id string, jobCategory *models.JobCategory) (*models.JobCategory, error)
 	DeleteJobCategory(ctx context.Context, id string) error
+	RestoreJobCategory(ctx context.Context, id string) error
 }
 
 type ArticleService interface {
@@ -59,6 +102,7 @@ type ArticleService interface {
 	CreateArticle(ctx context.Context, article *models.Article) error
 	UpdateArticle(ctx context.Context, id string, article *models.Article) (*models.Article, error)
 	DeleteArticle(ctx context.Context, id string) error
+	RestoreArticle(ctx context.Context, id string) error
 }
 
 type CountryService interface {
@@ -67,6 +111,7 @@ type CountryService interface {
 	CreateCountry(ctx context.Context, country *models.Country) error
 	UpdateCountry(ctx context.Context, id string, country *models.Country) (*models.Country, error)
 	DeleteCountry(ctx context.Context, id string) error
+	RestoreCountry(ctx context.Context, id string) error
 }
 
 type EducationLevelService interface {
@@ -75,6 +120,7 @@ type EducationLevelService interface {
 	CreateEducationLevel(ctx context.Context, educationLevel *models.EducationLevel) error
 	UpdateEducationLevel(ctx context.Context, id string, educationLevel *models.EducationLevel) (*models.EducationLevel, error)
 	DeleteEducationLevel(ctx context.Context, id string) error
+	RestoreEducationLevel(ctx context.Context, id string) error
 }
 
 type JobTypeService interface {
@@ -83,6 +129,7 @@ type JobTypeService interface {
 	CreateJobType(ctx context.Context, jobType *models.JobType) error
 	UpdateJobType(ctx context.Context, id string, jobType *models.JobType) (*models.JobType, error)
 	DeleteJobType(ctx context.Context, id string) error
+	RestoreJobType(ctx context.Context, id string) error
 }
 
 type KnowledgeLevelService interface {
@@ -91,6 +138,7 @@ type KnowledgeLevelService interface {
 	CreateKnowledgeLevel(ctx context.Context, knowledgeLevel *models.KnowledgeLevel) error
 	UpdateKnowledgeLevel(ctx context.Context, id string, knowledgeLevel *models.KnowledgeLevel) (*models.KnowledgeLevel, error)
 	DeleteKnowledgeLevel(ctx context.Context, id string) error
+	RestoreKnowledgeLevel(ctx context.Context, id string) error
 }
 
 type LocationAvailabilityService interface {
@@ -99,6 +147,7 @@ type LocationAvailabilityService interface {
 	CreateLocationAvailability(ctx context.Context, locationAvailability *models.LocationAvailability) error
 	UpdateLocationAvailability(ctx context.Context, id string, locationAvailability *models.LocationAvailability) (*models.LocationAvailability, error)
 	DeleteLocationAvailability(ctx context.Context, id string) error
+	RestoreLocationAvailability(ctx context.Context, id string) error
 }
 
 type CandidateSkillService interface {
@@ -108,6 +157,7 @@ type CandidateSkillService interface {
 	CreateCandidateSkill(ctx context.Context, candidateSkill *models.CandidateSkill) error
 	UpdateCandidateSkillProficiencyLevel(ctx context.Context, id string, proficiencyLevel string) error
 	DeleteCandidateSkill(ctx context.Context, id string) error
+	RestoreCandidateSkill(ctx context.Context, id string) error
 }
 
 type JobSkillService interface {
@@ -117,4 +167,29 @@ type JobSkillService interface {
 	CreateJobSkill(ctx context.Context, jobSkill *models.JobSkill) error
 	UpdateJobSkillProficiencyLevel(ctx context.Context, id string, proficiencyLevel string) error
 	DeleteJobSkill(ctx context.Context, id string) error
+	RestoreJobSkill(ctx context.Context, id string) error
+}
+
+type APIKeyService interface {
+	GetAllAPIKeys(ctx context.Context) ([]models.APIKey, error)
+	CreateAPIKey(ctx context.Context, key *models.APIKey) (*models.CreatedAPIKey, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+}
+
+type ExchangeRateService interface {
+	GetExchangeRates(ctx context.Context) (*models.ExchangeRates, error)
+	SetExchangeRate(ctx context.Context, currency string, rate float64) (*models.ExchangeRate, error)
+	DeleteExchangeRate(ctx context.Context, currency string) error
+}
+
+type Geocoder interface {
+	Geocode(ctx context.Context, place string) (*models.City, error)
+}
+
+type PipelineTemplateService interface {
+	GetPipelineTemplates(ctx context.Context) ([]models.PipelineTemplate, error)
+	GetPipelineTemplateByID(ctx context.Context, id string) (*models.PipelineTemplate, error)
+	CreatePipelineTemplate(ctx context.Context, template *models.PipelineTemplate) error
+	UpdatePipelineTemplate(ctx context.Context, id string, template *models.PipelineTemplate) (*models.PipelineTemplate, error)
+	DeletePipelineTemplate(ctx context.Context, id string) error
 }
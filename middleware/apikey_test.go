@@ -0,0 +1,138 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-mongodb-api/middleware"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAPIKey = "jak_testkey"
+
+type stubAPIKeyVerifier struct {
+	claims *middleware.Claims
+	err    error
+}
+
+func (s stubAPIKeyVerifier) VerifyAPIKey(ctx context.Context, key string) (*middleware.Claims, error) {
+	if key != testAPIKey {
+		return nil, errors.New("unknown key")
+	}
+	return s.claims, s.err
+}
+
+// apiKeyRouter mounts Authenticate in a group the way cmd/main.go does, so route patterns are resolved
+func apiKeyRouter(verifier middleware.APIKeyVerifier, checker middleware.SessionChecker) http.Handler {
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Authenticate(testSecret,
+			middleware.WithSessionChecker(checker),
+			middleware.WithAPIKeys(verifier, middleware.RouteScopes{
+				"GET /jobs/{jobId}/applications": "applications:read",
+				"POST /jobs":                     "jobs:write",
+			}),
+		))
+		r.Get("/jobs/{jobId}/applications", okHandler)
+		r.With(middleware.RequireVerified("admin")).Post("/jobs", okHandler)
+		r.Delete("/users/{id}", okHandler)
+	})
+	return r
+}
+
+func apiKeyClaims(scopes ...string) *middleware.Claims {
+	return &middleware.Claims{UserID: "apikey:1", Role: "admin", Verified: true, Scopes: scopes, APIKeyID: "1"}
+}
+
+func TestAuthenticate_APIKeyWithScope(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/jobs/42/applications", nil)
+	r.Header.Set("Authorization", "Bearer "+testAPIKey)
+	w := httptest.NewRecorder()
+
+	// the session checker is not consulted for API keys
+	apiKeyRouter(stubAPIKeyVerifier{claims: apiKeyClaims("applications:read")}, stubSessionChecker{active: false}).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuthenticate_APIKeyWithScopeOnNestedRoute(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/jobs", nil)
+	r.Header.Set("Authorization", "Bearer "+testAPIKey)
+	w := httptest.NewRecorder()
+
+	apiKeyRouter(stubAPIKeyVerifier{claims: apiKeyClaims("jobs:write")}, stubSessionChecker{}).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuthenticate_APIKeyMissingScope(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/jobs", nil)
+	r.Header.Set("Authorization", "Bearer "+testAPIKey)
+	w := httptest.NewRecorder()
+
+	apiKeyRouter(stubAPIKeyVerifier{claims: apiKeyClaims("jobs:read")}, stubSessionChecker{}).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "jobs:write")
+}
+
+func TestAuthenticate_APIKeyRouteNotAllowed(t *testing.T) {
+	r := httptest.NewRequest(http.MethodDelete, "/users/1", nil)
+	r.Header.Set("Authorization", "Bearer "+testAPIKey)
+	w := httptest.NewRecorder()
+
+	apiKeyRouter(stubAPIKeyVerifier{claims: apiKeyClaims("jobs:write", "applications:read")}, stubSessionChecker{}).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAuthenticate_APIKeyInvalid(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/jobs/42/applications", nil)
+	r.Header.Set("Authorization", "Bearer jak_unknown")
+	w := httptest.NewRecorder()
+
+	apiKeyRouter(stubAPIKeyVerifier{claims: apiKeyClaims("applications:read")}, stubSessionChecker{}).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthenticate_APIKeyWithoutOption(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+testAPIKey)
+	w := httptest.NewRecorder()
+
+	middleware.Authenticate(testSecret)(http.HandlerFunc(okHandler)).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthenticate_JWTWithAPIKeysEnabled(t *testing.T) {
+	r := httptest.NewRequest(http.MethodDelete, "/users/1", nil)
+	r.Header.Set("Authorization", "Bearer "+makeSessionToken(t, "session-1"))
+	w := httptest.NewRecorder()
+
+	apiKeyRouter(stubAPIKeyVerifier{}, stubSessionChecker{active: true}).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequireMFA_APIKeyNotEnforced(t *testing.T) {
+	r := withClaims(httptest.NewRequest(http.MethodPost, "/jobs", nil), apiKeyClaims("jobs:write"))
+	w := httptest.NewRecorder()
+
+	middleware.RequireMFA("admin")(http.HandlerFunc(okHandler)).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestClaims_HasScope(t *testing.T) {
+	claims := apiKeyClaims("jobs:read")
+
+	assert.True(t, claims.HasScope("jobs:read"))
+	assert.False(t, claims.HasScope("jobs:write"))
+}
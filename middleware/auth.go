@@ -13,43 +13,121 @@ type contextKey string
 
 const ClaimsKey contextKey = "claims"
 
+const actorKey contextKey = "actor"
+
 type Claims struct {
-	UserID string `json:"sub"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID    string `json:"sub"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid,omitempty"`
+	Verified  bool   `json:"verified"`
+	MFA       bool   `json:"mfa,omitempty"`
+
+	// Scopes and APIKeyID are only set for requests authenticated with an API key
+	Scopes   []string `json:"-"`
+	APIKeyID string   `json:"-"`
 	jwt.RegisteredClaims
 }
 
+// SessionChecker reports whether the server-side session behind an access token is still active.
+type SessionChecker interface {
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+}
+
+// AuthOption configures optional behaviour of Authenticate.
+type AuthOption func(*authOptions)
+
+type authOptions struct {
+	sessions    SessionChecker
+	keys        *KeySet
+	apiKeys     APIKeyVerifier
+	routeScopes RouteScopes
+	anonymous   bool
+}
+
+// WithSessionChecker makes Authenticate reject tokens whose session is missing, revoked or expired.
+func WithSessionChecker(checker SessionChecker) AuthOption {
+	return func(o *authOptions) {
+		o.sessions = checker
+	}
+}
+
+// WithKeySet makes Authenticate verify tokens against a key set instead of the HMAC secret alone,
+// accepting RS256/EdDSA tokens signed by any key in the set.
+func WithKeySet(keys *KeySet) AuthOption {
+	return func(o *authOptions) {
+		o.keys = keys
+	}
+}
+
+// WithAnonymousFallback makes Authenticate serve requests without valid credentials anonymously instead
+// of rejecting them, so a public route can identify the callers who present a token or API key. A missing,
+// invalid, expired or revoked token and an API key without the route's scope all fall through unauthenticated.
+func WithAnonymousFallback() AuthOption {
+	return func(o *authOptions) {
+		o.anonymous = true
+	}
+}
+
 // Authenticate parses and validates the Bearer token, stores Claims in context.
-func Authenticate(jwtSecret string) func(http.Handler) http.Handler {
-	secret := []byte(jwtSecret)
+// Without WithKeySet only HS256 tokens signed with jwtSecret are accepted.
+// With WithAPIKeys, Bearer values starting with APIKeyPrefix are verified as API keys instead.
+func Authenticate(jwtSecret string, opts ...AuthOption) func(http.Handler) http.Handler {
+	var options authOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.keys == nil {
+		options.keys = NewHMACKeySet(jwtSecret)
+	}
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-				writeError(w, http.StatusUnauthorized, "missing or invalid Authorization header")
-				return
-			}
-			tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-
-			claims := &Claims{}
-			token, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (interface{}, error) {
-				if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, jwt.ErrSignatureInvalid
+			claims, status, message := authenticate(r, &options)
+			if claims == nil {
+				if options.anonymous {
+					next.ServeHTTP(w, r)
+					return
 				}
-				return secret, nil
-			})
-			if err != nil || !token.Valid {
-				writeError(w, http.StatusUnauthorized, "invalid or expired token")
+				writeError(w, status, message)
 				return
 			}
-
 			ctx := context.WithValue(r.Context(), ClaimsKey, claims)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// authenticate resolves the Bearer credential of a request to its claims, or returns nil claims with the
+// status and message of the error response.
+func authenticate(r *http.Request, options *authOptions) (*Claims, int, string) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
+		return nil, http.StatusUnauthorized, "missing or invalid Authorization header"
+	}
+	tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
+
+	if options.apiKeys != nil && strings.HasPrefix(tokenStr, APIKeyPrefix) {
+		return authenticateAPIKey(r, options, tokenStr)
+	}
+
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, options.keys.Keyfunc)
+	if err != nil || !token.Valid {
+		return nil, http.StatusUnauthorized, "invalid or expired token"
+	}
+
+	if options.sessions != nil {
+		if claims.SessionID == "" {
+			return nil, http.StatusUnauthorized, "invalid or expired token"
+		}
+		active, err := options.sessions.IsSessionActive(r.Context(), claims.SessionID)
+		if err != nil || !active {
+			return nil, http.StatusUnauthorized, "session has been revoked"
+		}
+	}
+	return claims, http.StatusOK, ""
+}
+
 // RequireRoles allows only requests whose token role is in the given list.
 func RequireRoles(roles ...string) func(http.Handler) http.Handler {
 	allowed := make(map[string]struct{}, len(roles))
@@ -72,12 +150,98 @@ func RequireRoles(roles ...string) func(http.Handler) http.Handler {
 	}
 }
 
+// RequireVerified rejects requests from callers whose role is in the given list and whose email is not verified.
+// Roles not listed pass through, so an empty list disables the policy.
+func RequireVerified(roles ...string) func(http.Handler) http.Handler {
+	enforced := make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		enforced[role] = struct{}{}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := GetClaims(r.Context())
+			if !ok {
+				writeError(w, http.StatusUnauthorized, "unauthenticated")
+				return
+			}
+			if _, applies := enforced[claims.Role]; applies && !claims.Verified {
+				writeError(w, http.StatusForbidden, "email address not verified")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireMFA rejects requests from callers whose role is in the given list and whose session did not pass
+// two-factor authentication. Roles not listed pass through, so an empty list disables the policy.
+// API keys have no session and are not subject to the policy.
+func RequireMFA(roles ...string) func(http.Handler) http.Handler {
+	enforced := make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		enforced[role] = struct{}{}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := GetClaims(r.Context())
+			if !ok {
+				writeError(w, http.StatusUnauthorized, "unauthenticated")
+				return
+			}
+			if _, applies := enforced[claims.Role]; applies && !claims.MFA && !claims.IsAPIKey() {
+				writeError(w, http.StatusForbidden, "two-factor authentication required")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WhenQuery applies the given middlewares only to requests that set the query parameter, so a public
+// route can require authentication for a privileged option such as ?include_deleted=true.
+func WhenQuery(param string, middlewares ...func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		guarded := next
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			guarded = middlewares[i](guarded)
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Has(param) {
+				guarded.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // GetClaims extracts Claims from the request context.
 func GetClaims(ctx context.Context) (*Claims, bool) {
 	claims, ok := ctx.Value(ClaimsKey).(*Claims)
 	return claims, ok
 }
 
+// SystemActor is recorded in audit fields when a change is not made by an authenticated user.
+const SystemActor = "system"
+
+// WithActor returns a copy of ctx whose ActorID is actorID, for changes made on a user's behalf
+// outside an authenticated request (self-registration, accepting an invitation).
+func WithActor(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, actorKey, actorID)
+}
+
+// ActorID returns the actor set with WithActor or the user ID of the authenticated caller for audit
+// fields, or SystemActor when the context carries neither.
+func ActorID(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+	if claims, ok := GetClaims(ctx); ok && claims.UserID != "" {
+		return claims.UserID
+	}
+	return SystemActor
+}
+
 func writeError(w http.ResponseWriter, status int, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
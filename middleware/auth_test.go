@@ -1,6 +1,8 @@
 package middleware_test
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -8,6 +10,7 @@ import (
 
 	"go-mongodb-api/middleware"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 )
@@ -176,3 +179,262 @@ func TestGetClaims_Present(t *testing.T) {
 	assert.Equal(t, "user", capturedClaims.Role)
 	assert.Equal(t, "uid-1", capturedClaims.UserID)
 }
+
+type stubSessionChecker struct {
+	active bool
+	err    error
+}
+
+func (s stubSessionChecker) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	return s.active, s.err
+}
+
+func makeSessionToken(t *testing.T, sessionID string) string {
+	t.Helper()
+	claims := &middleware.Claims{
+		UserID:    "user-id-123",
+		Email:     "alice@example.com",
+		Role:      "candidate",
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(15 * time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func TestAuthenticate_ActiveSession(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+makeSessionToken(t, "session-1"))
+	w := httptest.NewRecorder()
+
+	handler := middleware.Authenticate(testSecret, middleware.WithSessionChecker(stubSessionChecker{active: true}))(http.HandlerFunc(okHandler))
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuthenticate_RevokedSession(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+makeSessionToken(t, "session-1"))
+	w := httptest.NewRecorder()
+
+	handler := middleware.Authenticate(testSecret, middleware.WithSessionChecker(stubSessionChecker{active: false}))(http.HandlerFunc(okHandler))
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthenticate_SessionLookupError(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+makeSessionToken(t, "session-1"))
+	w := httptest.NewRecorder()
+
+	handler := middleware.Authenticate(testSecret, middleware.WithSessionChecker(stubSessionChecker{err: errors.New("db down")}))(http.HandlerFunc(okHandler))
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthenticate_MissingSessionIDWithChecker(t *testing.T) {
+	token := makeToken(t, "candidate", "alice@example.com", "user-id-123", false)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	handler := middleware.Authenticate(testSecret, middleware.WithSessionChecker(stubSessionChecker{active: true}))(http.HandlerFunc(okHandler))
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func withClaims(r *http.Request, claims *middleware.Claims) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), middleware.ClaimsKey, claims))
+}
+
+func TestRequireVerified_UnverifiedEnforcedRole(t *testing.T) {
+	r := withClaims(httptest.NewRequest(http.MethodPost, "/jobs", nil), &middleware.Claims{Role: "recruiter", Verified: false})
+	w := httptest.NewRecorder()
+
+	middleware.RequireVerified("candidate", "recruiter")(http.HandlerFunc(okHandler)).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestRequireVerified_VerifiedEnforcedRole(t *testing.T) {
+	r := withClaims(httptest.NewRequest(http.MethodPost, "/jobs", nil), &middleware.Claims{Role: "recruiter", Verified: true})
+	w := httptest.NewRecorder()
+
+	middleware.RequireVerified("candidate", "recruiter")(http.HandlerFunc(okHandler)).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequireVerified_RoleNotEnforced(t *testing.T) {
+	r := withClaims(httptest.NewRequest(http.MethodPost, "/jobs", nil), &middleware.Claims{Role: "admin", Verified: false})
+	w := httptest.NewRecorder()
+
+	middleware.RequireVerified("candidate", "recruiter")(http.HandlerFunc(okHandler)).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequireVerified_Disabled(t *testing.T) {
+	r := withClaims(httptest.NewRequest(http.MethodPost, "/applications", nil), &middleware.Claims{Role: "candidate", Verified: false})
+	w := httptest.NewRecorder()
+
+	middleware.RequireVerified()(http.HandlerFunc(okHandler)).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequireVerified_NoClaims(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/applications", nil)
+	w := httptest.NewRecorder()
+
+	middleware.RequireVerified("candidate")(http.HandlerFunc(okHandler)).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRequireMFA_WithoutMFAEnforcedRole(t *testing.T) {
+	r := withClaims(httptest.NewRequest(http.MethodDelete, "/users/1", nil), &middleware.Claims{Role: "admin", MFA: false})
+	w := httptest.NewRecorder()
+
+	middleware.RequireMFA("admin")(http.HandlerFunc(okHandler)).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestRequireMFA_WithMFAEnforcedRole(t *testing.T) {
+	r := withClaims(httptest.NewRequest(http.MethodDelete, "/users/1", nil), &middleware.Claims{Role: "admin", MFA: true})
+	w := httptest.NewRecorder()
+
+	middleware.RequireMFA("admin")(http.HandlerFunc(okHandler)).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequireMFA_RoleNotEnforced(t *testing.T) {
+	r := withClaims(httptest.NewRequest(http.MethodPost, "/jobs", nil), &middleware.Claims{Role: "recruiter", MFA: false})
+	w := httptest.NewRecorder()
+
+	middleware.RequireMFA("admin")(http.HandlerFunc(okHandler)).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequireMFA_Disabled(t *testing.T) {
+	r := withClaims(httptest.NewRequest(http.MethodPost, "/users", nil), &middleware.Claims{Role: "admin", MFA: false})
+	w := httptest.NewRecorder()
+
+	middleware.RequireMFA()(http.HandlerFunc(okHandler)).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequireMFA_NoClaims(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/users", nil)
+	w := httptest.NewRecorder()
+
+	middleware.RequireMFA("admin")(http.HandlerFunc(okHandler)).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestActorID_WithClaims(t *testing.T) {
+	r := withClaims(httptest.NewRequest(http.MethodGet, "/", nil), &middleware.Claims{UserID: "user-id-123"})
+	assert.Equal(t, "user-id-123", middleware.ActorID(r.Context()))
+}
+
+func TestActorID_WithoutClaims(t *testing.T) {
+	assert.Equal(t, middleware.SystemActor, middleware.ActorID(context.Background()))
+}
+
+func TestActorID_WithActor(t *testing.T) {
+	r := withClaims(httptest.NewRequest(http.MethodGet, "/", nil), &middleware.Claims{UserID: "user-id-123"})
+	assert.Equal(t, "inviter-id", middleware.ActorID(middleware.WithActor(r.Context(), "inviter-id")))
+}
+
+func TestWhenQuery_ParamAbsent(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/jobs", nil)
+	w := httptest.NewRecorder()
+
+	middleware.WhenQuery("include_deleted", middleware.Authenticate(testSecret))(http.HandlerFunc(okHandler)).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestWhenQuery_ParamRequiresAuth(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/jobs?include_deleted=true", nil)
+	w := httptest.NewRecorder()
+
+	middleware.WhenQuery("include_deleted", middleware.Authenticate(testSecret))(http.HandlerFunc(okHandler)).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestWhenQuery_AppliesInOrder(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/jobs?include_deleted=true", nil)
+	r.Header.Set("Authorization", "Bearer "+makeToken(t, "candidate", "alice@example.com", "user-id-123", false))
+	w := httptest.NewRecorder()
+
+	guard := middleware.WhenQuery("include_deleted", middleware.Authenticate(testSecret), middleware.RequireRoles("admin"))
+	guard(http.HandlerFunc(okHandler)).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+// publicJobsRouter serves GET /jobs the way cmd/main.go does, identifying callers without requiring them
+// to authenticate. The response body is the caller's user ID, or "anonymous".
+func publicJobsRouter(verifier middleware.APIKeyVerifier) http.Handler {
+	r := chi.NewRouter()
+	r.With(middleware.Authenticate(testSecret,
+		middleware.WithAPIKeys(verifier, middleware.RouteScopes{"GET /jobs": "jobs:read"}),
+		middleware.WithAnonymousFallback(),
+	)).Get("/jobs", func(w http.ResponseWriter, r *http.Request) {
+		caller := "anonymous"
+		if claims, ok := middleware.GetClaims(r.Context()); ok {
+			caller = claims.UserID
+		}
+		_, _ = w.Write([]byte(caller))
+	})
+	return r
+}
+
+func TestAuthenticate_AnonymousFallback(t *testing.T) {
+	tests := []struct {
+		name       string
+		credential string
+		verifier   middleware.APIKeyVerifier
+		caller     string
+	}{
+		{"no credential", "", stubAPIKeyVerifier{}, "anonymous"},
+		{"valid token", makeToken(t, "recruiter", "bob@example.com", "user-id-456", false), stubAPIKeyVerifier{}, "user-id-456"},
+		{"expired token", makeToken(t, "recruiter", "bob@example.com", "user-id-456", true), stubAPIKeyVerifier{}, "anonymous"},
+		{"invalid token", "not-a-token", stubAPIKeyVerifier{}, "anonymous"},
+		{"API key with scope", testAPIKey, stubAPIKeyVerifier{claims: apiKeyClaims("jobs:read")}, "apikey:1"},
+		{"API key without scope", testAPIKey, stubAPIKeyVerifier{claims: apiKeyClaims("applications:read")}, "anonymous"},
+		{"unknown API key", "jak_unknown", stubAPIKeyVerifier{claims: apiKeyClaims("jobs:read")}, "anonymous"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/jobs", nil)
+			if tc.credential != "" {
+				r.Header.Set("Authorization", "Bearer "+tc.credential)
+			}
+			w := httptest.NewRecorder()
+
+			publicJobsRouter(tc.verifier).ServeHTTP(w, r)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tc.caller, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrUnknownKey is returned when a token names a kid that is not in the key set.
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// SigningKey is an asymmetric key access tokens are signed with, identified by its kid.
+type SigningKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.Signer
+}
+
+// NewSigningKey wraps an RSA (RS256) or Ed25519 (EdDSA) private key.
+func NewSigningKey(id string, privateKey crypto.Signer) (SigningKey, error) {
+	if id == "" {
+		return SigningKey{}, errors.New("signing key id is required")
+	}
+	switch privateKey.(type) {
+	case *rsa.PrivateKey:
+		return SigningKey{ID: id, Method: jwt.SigningMethodRS256, PrivateKey: privateKey}, nil
+	case ed25519.PrivateKey:
+		return SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, PrivateKey: privateKey}, nil
+	default:
+		return SigningKey{}, fmt.Errorf("signing key %q: unsupported key type %T", id, privateKey)
+	}
+}
+
+// ParseSigningKey reads a PEM encoded RSA or Ed25519 private key (PKCS#8, or PKCS#1 for RSA).
+func ParseSigningKey(id string, pemBytes []byte) (SigningKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return SigningKey{}, fmt.Errorf("signing key %q: no PEM data found", id)
+	}
+
+	var privateKey any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("signing key %q: %w", id, err)
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return SigningKey{}, fmt.Errorf("signing key %q: unsupported key type %T", id, privateKey)
+	}
+	return NewSigningKey(id, signer)
+}
+
+// KeySet holds the keys access tokens are signed and verified with.
+//
+// New tokens are signed with a single active key and carry its kid in the header. Every key in the set
+// is accepted for verification, so rotating means adding a new key, making it active and removing the
+// old one once the tokens it signed have expired. An optional HMAC secret signs tokens when there is
+// no asymmetric key and verifies HS256 tokens without a kid.
+type KeySet struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+	order  []string
+	secret []byte
+}
+
+// NewHMACKeySet returns a key set that signs and verifies HS256 tokens with a shared secret.
+func NewHMACKeySet(secret string) *KeySet {
+	return &KeySet{keys: map[string]*SigningKey{}, secret: []byte(secret)}
+}
+
+// NewKeySet returns a key set that signs with the key named activeID and verifies with all given keys.
+// When activeID is empty the last key is active. hmacSecret may be empty; when set, HS256 tokens
+// are still accepted, which allows moving from HS256 without logging everyone out.
+func NewKeySet(activeID string, keys []SigningKey, hmacSecret string) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*SigningKey, len(keys))}
+	if hmacSecret != "" {
+		ks.secret = []byte(hmacSecret)
+	}
+	for i := range keys {
+		key := keys[i]
+		if _, exists := ks.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate signing key id %q", key.ID)
+		}
+		ks.keys[key.ID] = &key
+		ks.order = append(ks.order, key.ID)
+	}
+
+	if len(keys) == 0 {
+		if ks.secret == nil {
+			return nil, errors.New("key set needs a signing key or an HMAC secret")
+		}
+		return ks, nil
+	}
+	if activeID == "" {
+		activeID = ks.order[len(ks.order)-1]
+	}
+	active, ok := ks.keys[activeID]
+	if !ok {
+		return nil, fmt.Errorf("active signing key %q not found", activeID)
+	}
+	ks.active = active
+	return ks, nil
+}
+
+// Sign returns a signed token for the claims, using the active key or, without one, the HMAC secret.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	if ks.active == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(ks.secret)
+	}
+	token := jwt.NewWithClaims(ks.active.Method, claims)
+	token.Header["kid"] = ks.active.ID
+	return token.SignedString(ks.active.PrivateKey)
+}
+
+// Keyfunc resolves the verification key of a token for jwt.Parse. Tokens with a kid must use the
+// algorithm of that key; tokens without one are only accepted as HMAC tokens when a secret is set.
+func (ks *KeySet) Keyfunc(t *jwt.Token) (interface{}, error) {
+	if kid, ok := t.Header["kid"].(string); ok && kid != "" {
+		key, found := ks.keys[kid]
+		if !found {
+			return nil, ErrUnknownKey
+		}
+		if t.Method.Alg() != key.Method.Alg() {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return key.PrivateKey.Public(), nil
+	}
+	if _, ok := t.Method.(*jwt.SigningMethodHMAC); ok && ks.secret != nil {
+		return ks.secret, nil
+	}
+	return nil, jwt.ErrSignatureInvalid
+}
+
+// JWK is the public part of a signing key in JSON Web Key format (RFC 7517).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set as served from /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of the set. The HMAC secret is never published.
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, id := range ks.order {
+		key := ks.keys[id]
+		jwk := JWK{KeyID: key.ID, Use: "sig", Algorithm: key.Method.Alg()}
+		switch public := key.PrivateKey.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
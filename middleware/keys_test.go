@@ -0,0 +1,219 @@
+package middleware_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-mongodb-api/middleware"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRSAKey(t *testing.T, id string) middleware.SigningKey {
+	t.Helper()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	key, err := middleware.NewSigningKey(id, privateKey)
+	assert.NoError(t, err)
+	return key
+}
+
+func newEd25519Key(t *testing.T, id string) middleware.SigningKey {
+	t.Helper()
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	key, err := middleware.NewSigningKey(id, privateKey)
+	assert.NoError(t, err)
+	return key
+}
+
+func testClaims() *middleware.Claims {
+	return &middleware.Claims{
+		UserID: "user-id-123",
+		Role:   "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+}
+
+func authenticateWith(keys *middleware.KeySet, token string) int {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	middleware.Authenticate("", middleware.WithKeySet(keys))(http.HandlerFunc(okHandler)).ServeHTTP(w, r)
+	return w.Code
+}
+
+func TestKeySet_SignRS256(t *testing.T) {
+	keys, err := middleware.NewKeySet("", []middleware.SigningKey{newRSAKey(t, "2026-10")}, "")
+	assert.NoError(t, err)
+
+	signed, err := keys.Sign(testClaims())
+	assert.NoError(t, err)
+
+	token, _, err := jwt.NewParser().ParseUnverified(signed, &middleware.Claims{})
+	assert.NoError(t, err)
+	assert.Equal(t, "RS256", token.Method.Alg())
+	assert.Equal(t, "2026-10", token.Header["kid"])
+	assert.Equal(t, http.StatusOK, authenticateWith(keys, signed))
+}
+
+func TestKeySet_SignEdDSA(t *testing.T) {
+	keys, err := middleware.NewKeySet("", []middleware.SigningKey{newEd25519Key(t, "ed-1")}, "")
+	assert.NoError(t, err)
+
+	signed, err := keys.Sign(testClaims())
+	assert.NoError(t, err)
+
+	token, _, err := jwt.NewParser().ParseUnverified(signed, &middleware.Claims{})
+	assert.NoError(t, err)
+	assert.Equal(t, "EdDSA", token.Method.Alg())
+	assert.Equal(t, http.StatusOK, authenticateWith(keys, signed))
+}
+
+func TestKeySet_RotationKeepsOldTokensValid(t *testing.T) {
+	oldKey := newEd25519Key(t, "old")
+	newKey := newEd25519Key(t, "new")
+
+	before, err := middleware.NewKeySet("", []middleware.SigningKey{oldKey}, "")
+	assert.NoError(t, err)
+	oldToken, err := before.Sign(testClaims())
+	assert.NoError(t, err)
+
+	after, err := middleware.NewKeySet("new", []middleware.SigningKey{oldKey, newKey}, "")
+	assert.NoError(t, err)
+	newToken, err := after.Sign(testClaims())
+	assert.NoError(t, err)
+
+	token, _, err := jwt.NewParser().ParseUnverified(newToken, &middleware.Claims{})
+	assert.NoError(t, err)
+	assert.Equal(t, "new", token.Header["kid"])
+	assert.Equal(t, http.StatusOK, authenticateWith(after, oldToken))
+	assert.Equal(t, http.StatusOK, authenticateWith(after, newToken))
+
+	// Once the old key is removed its tokens are rejected
+	retired, err := middleware.NewKeySet("new", []middleware.SigningKey{newKey}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, authenticateWith(retired, oldToken))
+}
+
+func TestKeySet_RejectsAlgorithmMismatch(t *testing.T) {
+	rsaKey := newRSAKey(t, "shared-kid")
+	keys, err := middleware.NewKeySet("", []middleware.SigningKey{rsaKey}, "")
+	assert.NoError(t, err)
+
+	// A token claiming the RSA key's kid but signed with another algorithm must not verify
+	other := newEd25519Key(t, "shared-kid")
+	otherKeys, err := middleware.NewKeySet("", []middleware.SigningKey{other}, "")
+	assert.NoError(t, err)
+	forged, err := otherKeys.Sign(testClaims())
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnauthorized, authenticateWith(keys, forged))
+}
+
+func TestKeySet_HMACTokensDuringMigration(t *testing.T) {
+	hmacToken := makeToken(t, "admin", "alice@example.com", "user-id-123", false)
+	key := newEd25519Key(t, "ed-1")
+
+	withSecret, err := middleware.NewKeySet("", []middleware.SigningKey{key}, testSecret)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, authenticateWith(withSecret, hmacToken))
+
+	withoutSecret, err := middleware.NewKeySet("", []middleware.SigningKey{key}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, authenticateWith(withoutSecret, hmacToken))
+}
+
+func TestKeySet_HMACOnly(t *testing.T) {
+	keys := middleware.NewHMACKeySet(testSecret)
+
+	signed, err := keys.Sign(testClaims())
+	assert.NoError(t, err)
+
+	token, _, err := jwt.NewParser().ParseUnverified(signed, &middleware.Claims{})
+	assert.NoError(t, err)
+	assert.Equal(t, "HS256", token.Method.Alg())
+	assert.NotContains(t, token.Header, "kid")
+	assert.Equal(t, http.StatusOK, authenticateWith(keys, signed))
+	assert.Empty(t, keys.JWKS().Keys)
+}
+
+func TestNewKeySet_Errors(t *testing.T) {
+	_, err := middleware.NewKeySet("", nil, "")
+	assert.Error(t, err)
+
+	key := newEd25519Key(t, "ed-1")
+	_, err = middleware.NewKeySet("missing", []middleware.SigningKey{key}, "")
+	assert.Error(t, err)
+
+	_, err = middleware.NewKeySet("", []middleware.SigningKey{key, key}, "")
+	assert.Error(t, err)
+}
+
+func TestParseSigningKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	pkcs8RSA, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	assert.NoError(t, err)
+	pkcs8Ed, err := x509.MarshalPKCS8PrivateKey(edKey)
+	assert.NoError(t, err)
+
+	cases := []struct {
+		name string
+		pem  []byte
+		alg  string
+	}{
+		{"pkcs8 rsa", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8RSA}), "RS256"},
+		{"pkcs1 rsa", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}), "RS256"},
+		{"pkcs8 ed25519", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Ed}), "EdDSA"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := middleware.ParseSigningKey("k1", tc.pem)
+			assert.NoError(t, err)
+			assert.Equal(t, "k1", key.ID)
+			assert.Equal(t, tc.alg, key.Method.Alg())
+		})
+	}
+
+	_, err = middleware.ParseSigningKey("k1", []byte("not a pem file"))
+	assert.Error(t, err)
+}
+
+func TestKeySet_JWKS(t *testing.T) {
+	rsaKey := newRSAKey(t, "rsa-1")
+	edKey := newEd25519Key(t, "ed-1")
+	keys, err := middleware.NewKeySet("ed-1", []middleware.SigningKey{rsaKey, edKey}, testSecret)
+	assert.NoError(t, err)
+
+	set := keys.JWKS()
+	assert.Len(t, set.Keys, 2)
+
+	assert.Equal(t, "RSA", set.Keys[0].KeyType)
+	assert.Equal(t, "rsa-1", set.Keys[0].KeyID)
+	assert.Equal(t, "RS256", set.Keys[0].Algorithm)
+	assert.Equal(t, "AQAB", set.Keys[0].E)
+	n, err := base64.RawURLEncoding.DecodeString(set.Keys[0].N)
+	assert.NoError(t, err)
+	assert.Equal(t, rsaKey.PrivateKey.(*rsa.PrivateKey).N.Bytes(), n)
+
+	assert.Equal(t, "OKP", set.Keys[1].KeyType)
+	assert.Equal(t, "Ed25519", set.Keys[1].Curve)
+	assert.Equal(t, "EdDSA", set.Keys[1].Algorithm)
+	x, err := base64.RawURLEncoding.DecodeString(set.Keys[1].X)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(edKey.PrivateKey.Public().(ed25519.PublicKey)), x)
+}
@@ -3,6 +3,7 @@ package mocks
 import (
 	"context"
 	"go-mongodb-api/models"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -46,8 +47,53 @@ func (m *MockUserRepository) Update(ctx context.Context, id string, user *models
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
-func (m *MockUserRepository) Delete(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id string, hashedPassword string, updated models.Audit) error {
+	args := m.Called(ctx, id, hashedPassword, updated)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) MarkVerified(ctx context.Context, id string, updated models.Audit) error {
+	args := m.Called(ctx, id, updated)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateLastLogin(ctx context.Context, id string, loginTime time.Time) error {
+	args := m.Called(ctx, id, loginTime)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SetMFASecret(ctx context.Context, id string, secret string, updated models.Audit) error {
+	args := m.Called(ctx, id, secret, updated)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) EnableMFA(ctx context.Context, id string, recoveryCodeHashes []string, step int64, updated models.Audit) error {
+	args := m.Called(ctx, id, recoveryCodeHashes, step, updated)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) DisableMFA(ctx context.Context, id string, updated models.Audit) error {
+	args := m.Called(ctx, id, updated)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UseMFAStep(ctx context.Context, id string, step int64) error {
+	args := m.Called(ctx, id, step)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UseRecoveryCode(ctx context.Context, id string, codeHash string) error {
+	args := m.Called(ctx, id, codeHash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	args := m.Called(ctx, id, deletedBy)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	args := m.Called(ctx, id, restored)
 	return args.Error(0)
 }
 
@@ -79,18 +125,55 @@ func (m *MockApplicationRepository) GetByUserID(ctx context.Context, userID stri
 	return args.Get(0).([]models.Application), args.Error(1)
 }
 
+func (m *MockApplicationRepository) Expand(ctx context.Context, applications []models.Application, expand []string) error {
+	args := m.Called(ctx, applications, expand)
+	return args.Error(0)
+}
+
+func (m *MockApplicationRepository) CountByStage(ctx context.Context, jobID string) (map[string]int64, error) {
+	args := m.Called(ctx, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int64), args.Error(1)
+}
+
+func (m *MockApplicationRepository) GetLatestAttempt(ctx context.Context, jobID, userID string) (*models.Application, error) {
+	args := m.Called(ctx, jobID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Application), args.Error(1)
+}
+
 func (m *MockApplicationRepository) Create(ctx context.Context, application *models.Application) error {
 	args := m.Called(ctx, application)
 	return args.Error(0)
 }
 
-func (m *MockApplicationRepository) UpdateStatus(ctx context.Context, id string, status string) error {
-	args := m.Called(ctx, id, status)
+func (m *MockApplicationRepository) UpdateStatus(ctx context.Context, id string, from string, application *models.Application, change models.ApplicationStatusChange) (*models.Application, error) {
+	args := m.Called(ctx, id, from, application, change)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Application), args.Error(1)
+}
+
+func (m *MockApplicationRepository) BulkUpdate(ctx context.Context, updates []models.ApplicationBulkUpdate) ([]string, error) {
+	args := m.Called(ctx, updates)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockApplicationRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	args := m.Called(ctx, id, deletedBy)
 	return args.Error(0)
 }
 
-func (m *MockApplicationRepository) Delete(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *MockApplicationRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	args := m.Called(ctx, id, restored)
 	return args.Error(0)
 }
 
@@ -104,6 +187,14 @@ func (m *MockJobRepository) GetAll(ctx context.Context, page, limit int, filters
 	return args.Get(0).([]models.Job), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockJobRepository) Facets(ctx context.Context, filters map[string]string, salaryBands []int) (*models.JobFacets, error) {
+	args := m.Called(ctx, filters, salaryBands)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.JobFacets), args.Error(1)
+}
+
 func (m *MockJobRepository) GetByID(ctx context.Context, id string) (*models.Job, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -117,13 +208,62 @@ func (m *MockJobRepository) GetByUserID(ctx context.Context, userID string) ([]m
 	return args.Get(0).([]models.Job), args.Error(1)
 }
 
+func (m *MockJobRepository) Expand(ctx context.Context, jobs []models.Job, expand []string) error {
+	args := m.Called(ctx, jobs, expand)
+	return args.Error(0)
+}
+
 func (m *MockJobRepository) Create(ctx context.Context, job *models.Job) error {
 	args := m.Called(ctx, job)
 	return args.Error(0)
 }
 
-func (m *MockJobRepository) Delete(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *MockJobRepository) Update(ctx context.Context, id string, job *models.Job) (*models.Job, error) {
+	args := m.Called(ctx, id, job)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Job), args.Error(1)
+}
+
+func (m *MockJobRepository) UpdateStatus(ctx context.Context, id string, from string, job *models.Job) (*models.Job, error) {
+	args := m.Called(ctx, id, from, job)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Job), args.Error(1)
+}
+
+func (m *MockJobRepository) UpdateStages(ctx context.Context, id string, job *models.Job) (*models.Job, error) {
+	args := m.Called(ctx, id, job)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Job), args.Error(1)
+}
+
+func (m *MockJobRepository) CloseExpired(ctx context.Context, now time.Time, updatedBy string) (int64, error) {
+	args := m.Called(ctx, now, updatedBy)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockJobRepository) NormalizeSalaries(ctx context.Context, currency string, rate float64) (int64, error) {
+	args := m.Called(ctx, currency, rate)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockJobRepository) ClearNormalizedSalaries(ctx context.Context, currency string) (int64, error) {
+	args := m.Called(ctx, currency)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockJobRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	args := m.Called(ctx, id, deletedBy)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	args := m.Called(ctx, id, restored)
 	return args.Error(0)
 }
 
@@ -158,8 +298,13 @@ func (m *MockSkillRepository) Update(ctx context.Context, id string, skill *mode
 	return args.Get(0).(*models.Skill), args.Error(1)
 }
 
-func (m *MockSkillRepository) Delete(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *MockSkillRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	args := m.Called(ctx, id, deletedBy)
+	return args.Error(0)
+}
+
+func (m *MockSkillRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	args := m.Called(ctx, id, restored)
 	return args.Error(0)
 }
 
@@ -194,8 +339,13 @@ func (m *MockJobCategoryRepository) Update(ctx context.Context, id string, jobCa
 	return args.Get(0).(*models.JobCategory), args.Error(1)
 }
 
-func (m *MockJobCategoryRepository) Delete(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *MockJobCategoryRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	args := m.Called(ctx, id, deletedBy)
+	return args.Error(0)
+}
+
+func (m *MockJobCategoryRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	args := m.Called(ctx, id, restored)
 	return args.Error(0)
 }
 
@@ -230,8 +380,13 @@ func (m *MockArticleRepository) Update(ctx context.Context, id string, article *
 	return args.Get(0).(*models.Article), args.Error(1)
 }
 
-func (m *MockArticleRepository) Delete(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *MockArticleRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	args := m.Called(ctx, id, deletedBy)
+	return args.Error(0)
+}
+
+func (m *MockArticleRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	args := m.Called(ctx, id, restored)
 	return args.Error(0)
 }
 
@@ -253,6 +408,14 @@ func (m *MockCountryRepository) GetByID(ctx context.Context, id string) (*models
 	return args.Get(0).(*models.Country), args.Error(1)
 }
 
+func (m *MockCountryRepository) GetByName(ctx context.Context, name string) (*models.Country, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Country), args.Error(1)
+}
+
 func (m *MockCountryRepository) Create(ctx context.Context, country *models.Country) error {
 	args := m.Called(ctx, country)
 	return args.Error(0)
@@ -266,8 +429,13 @@ func (m *MockCountryRepository) Update(ctx context.Context, id string, country *
 	return args.Get(0).(*models.Country), args.Error(1)
 }
 
-func (m *MockCountryRepository) Delete(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *MockCountryRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	args := m.Called(ctx, id, deletedBy)
+	return args.Error(0)
+}
+
+func (m *MockCountryRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	args := m.Called(ctx, id, restored)
 	return args.Error(0)
 }
 
@@ -302,8 +470,13 @@ func (m *MockEducationLevelRepository) Update(ctx context.Context, id string, ed
 	return args.Get(0).(*models.EducationLevel), args.Error(1)
 }
 
-func (m *MockEducationLevelRepository) Delete(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *MockEducationLevelRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	args := m.Called(ctx, id, deletedBy)
+	return args.Error(0)
+}
+
+func (m *MockEducationLevelRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	args := m.Called(ctx, id, restored)
 	return args.Error(0)
 }
 
@@ -338,8 +511,13 @@ func (m *MockJobTypeRepository) Update(ctx context.Context, id string, jobType *
 	return args.Get(0).(*models.JobType), args.Error(1)
 }
 
-func (m *MockJobTypeRepository) Delete(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *MockJobTypeRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	args := m.Called(ctx, id, deletedBy)
+	return args.Error(0)
+}
+
+func (m *MockJobTypeRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	args := m.Called(ctx, id, restored)
 	return args.Error(0)
 }
 
@@ -374,8 +552,13 @@ func (m *MockKnowledgeLevelRepository) Update(ctx context.Context, id string, kn
 	return args.Get(0).(*models.KnowledgeLevel), args.Error(1)
 }
 
-func (m *MockKnowledgeLevelRepository) Delete(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *MockKnowledgeLevelRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	args := m.Called(ctx, id, deletedBy)
+	return args.Error(0)
+}
+
+func (m *MockKnowledgeLevelRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	args := m.Called(ctx, id, restored)
 	return args.Error(0)
 }
 
@@ -410,8 +593,13 @@ func (m *MockLocationAvailabilityRepository) Update(ctx context.Context, id stri
 	return args.Get(0).(*models.LocationAvailability), args.Error(1)
 }
 
-func (m *MockLocationAvailabilityRepository) Delete(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *MockLocationAvailabilityRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	args := m.Called(ctx, id, deletedBy)
+	return args.Error(0)
+}
+
+func (m *MockLocationAvailabilityRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	args := m.Called(ctx, id, restored)
 	return args.Error(0)
 }
 
@@ -443,13 +631,18 @@ func (m *MockCandidateSkillRepository) Create(ctx context.Context, candidateSkil
 	return args.Error(0)
 }
 
-func (m *MockCandidateSkillRepository) UpdateProficiencyLevel(ctx context.Context, id string, proficiencyLevel string) error {
-	args := m.Called(ctx, id, proficiencyLevel)
+func (m *MockCandidateSkillRepository) UpdateProficiencyLevel(ctx context.Context, id string, proficiencyLevel string, updated models.Audit) error {
+	args := m.Called(ctx, id, proficiencyLevel, updated)
 	return args.Error(0)
 }
 
-func (m *MockCandidateSkillRepository) Delete(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *MockCandidateSkillRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	args := m.Called(ctx, id, deletedBy)
+	return args.Error(0)
+}
+
+func (m *MockCandidateSkillRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	args := m.Called(ctx, id, restored)
 	return args.Error(0)
 }
 
@@ -481,12 +674,238 @@ func (m *MockJobSkillRepository) Create(ctx context.Context, jobSkill *models.Jo
 	return args.Error(0)
 }
 
-func (m *MockJobSkillRepository) UpdateProficiencyLevel(ctx context.Context, id string, proficiencyLevel string) error {
-	args := m.Called(ctx, id, proficiencyLevel)
+func (m *MockJobSkillRepository) UpdateProficiencyLevel(ctx context.Context, id string, proficiencyLevel string, updated models.Audit) error {
+	args := m.Called(ctx, id, proficiencyLevel, updated)
 	return args.Error(0)
 }
 
-func (m *MockJobSkillRepository) Delete(ctx context.Context, id string) error {
+func (m *MockJobSkillRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	args := m.Called(ctx, id, deletedBy)
+	return args.Error(0)
+}
+
+func (m *MockJobSkillRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	args := m.Called(ctx, id, restored)
+	return args.Error(0)
+}
+
+// MockSessionRepository is a mock for interfaces.SessionRepository
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) GetByID(ctx context.Context, id string) (*models.Session, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) GetByPreviousHash(ctx context.Context, tokenHash string) (*models.Session, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) Create(ctx context.Context, session *models.Session) error {
+	args := m.Called(ctx, session)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) Rotate(ctx context.Context, oldHash, newHash string, expiresTime time.Time) (*models.Session, error) {
+	args := m.Called(ctx, oldHash, newHash, expiresTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) Revoke(ctx context.Context, id string, reason string) error {
+	args := m.Called(ctx, id, reason)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) RevokeAllForUser(ctx context.Context, userID string, reason string) error {
+	args := m.Called(ctx, userID, reason)
+	return args.Error(0)
+}
+
+// MockUserTokenRepository is a mock for interfaces.UserTokenRepository
+type MockUserTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserTokenRepository) Create(ctx context.Context, token *models.UserToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockUserTokenRepository) Consume(ctx context.Context, tokenHash, purpose string) (*models.UserToken, error) {
+	args := m.Called(ctx, tokenHash, purpose)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserToken), args.Error(1)
+}
+
+func (m *MockUserTokenRepository) DeleteByUser(ctx context.Context, userID, purpose string) error {
+	args := m.Called(ctx, userID, purpose)
+	return args.Error(0)
+}
+
+func (m *MockUserTokenRepository) DeleteByEmail(ctx context.Context, email, purpose string) error {
+	args := m.Called(ctx, email, purpose)
+	return args.Error(0)
+}
+
+// MockLoginAttemptRepository is a mock for interfaces.LoginAttemptRepository
+type MockLoginAttemptRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginAttemptRepository) Get(ctx context.Context, scope, value string) (*models.LoginAttempt, error) {
+	args := m.Called(ctx, scope, value)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginAttempt), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) RecordFailure(ctx context.Context, scope, value string, expiresTime time.Time) (*models.LoginAttempt, error) {
+	args := m.Called(ctx, scope, value, expiresTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginAttempt), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) Lock(ctx context.Context, scope, value string, until time.Time) error {
+	args := m.Called(ctx, scope, value, until)
+	return args.Error(0)
+}
+
+func (m *MockLoginAttemptRepository) Reset(ctx context.Context, scope, value string) error {
+	args := m.Called(ctx, scope, value)
+	return args.Error(0)
+}
+
+// MockAPIKeyRepository is a mock for interfaces.APIKeyRepository
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) GetAll(ctx context.Context) ([]models.APIKey, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	args := m.Called(ctx, keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, id string, revoked models.Audit) error {
+	args := m.Called(ctx, id, revoked)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(ctx context.Context, id string, usedTime time.Time) error {
+	args := m.Called(ctx, id, usedTime)
+	return args.Error(0)
+}
+
+// MockExchangeRateRepository is a mock for interfaces.ExchangeRateRepository
+type MockExchangeRateRepository struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateRepository) GetAll(ctx context.Context) ([]models.ExchangeRate, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) GetByCurrency(ctx context.Context, currency string) (*models.ExchangeRate, error) {
+	args := m.Called(ctx, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) Upsert(ctx context.Context, rate *models.ExchangeRate) (*models.ExchangeRate, error) {
+	args := m.Called(ctx, rate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) Delete(ctx context.Context, currency string) error {
+	args := m.Called(ctx, currency)
+	return args.Error(0)
+}
+
+// MockCityRepository is a mock for interfaces.CityRepository
+type MockCityRepository struct {
+	mock.Mock
+}
+
+func (m *MockCityRepository) FindByName(ctx context.Context, name string, countryID string) (*models.City, error) {
+	args := m.Called(ctx, name, countryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.City), args.Error(1)
+}
+
+func (m *MockCityRepository) Upsert(ctx context.Context, city *models.City) (bool, error) {
+	args := m.Called(ctx, city)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockPipelineTemplateRepository is a mock for interfaces.PipelineTemplateRepository
+type MockPipelineTemplateRepository struct {
+	mock.Mock
+}
+
+func (m *MockPipelineTemplateRepository) GetAll(ctx context.Context, userID, companyName string) ([]models.PipelineTemplate, error) {
+	args := m.Called(ctx, userID, companyName)
+	return args.Get(0).([]models.PipelineTemplate), args.Error(1)
+}
+
+func (m *MockPipelineTemplateRepository) GetByID(ctx context.Context, id string) (*models.PipelineTemplate, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PipelineTemplate), args.Error(1)
+}
+
+func (m *MockPipelineTemplateRepository) Create(ctx context.Context, template *models.PipelineTemplate) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *MockPipelineTemplateRepository) Update(ctx context.Context, id string, template *models.PipelineTemplate) (*models.PipelineTemplate, error) {
+	args := m.Called(ctx, id, template)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PipelineTemplate), args.Error(1)
+}
+
+func (m *MockPipelineTemplateRepository) Delete(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
@@ -3,6 +3,7 @@ package mocks
 import (
 	"context"
 	"go-mongodb-api/models"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -46,19 +47,67 @@ func (m *MockUserService) UpdateUser(ctx context.Context, id string, user *model
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
+func (m *MockUserService) UpdatePassword(ctx context.Context, id string, password string) error {
+	args := m.Called(ctx, id, password)
+	return args.Error(0)
+}
+
+func (m *MockUserService) MarkVerified(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserService) RecordLogin(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserService) SetMFASecret(ctx context.Context, id string, secret string) error {
+	args := m.Called(ctx, id, secret)
+	return args.Error(0)
+}
+
+func (m *MockUserService) EnableMFA(ctx context.Context, id string, recoveryCodeHashes []string, step int64) error {
+	args := m.Called(ctx, id, recoveryCodeHashes, step)
+	return args.Error(0)
+}
+
+func (m *MockUserService) DisableMFA(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserService) UseMFAStep(ctx context.Context, id string, step int64) error {
+	args := m.Called(ctx, id, step)
+	return args.Error(0)
+}
+
+func (m *MockUserService) UseRecoveryCode(ctx context.Context, id string, codeHash string) error {
+	args := m.Called(ctx, id, codeHash)
+	return args.Error(0)
+}
+
 func (m *MockUserService) DeleteUser(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockUserService) RestoreUser(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // MockAuthService is a mock for interfaces.AuthService
 type MockAuthService struct {
 	mock.Mock
 }
 
-func (m *MockAuthService) Login(ctx context.Context, email, password string) (string, error) {
-	args := m.Called(ctx, email, password)
-	return args.String(0), args.Error(1)
+func (m *MockAuthService) Login(ctx context.Context, email, password, clientIP string) (*models.AuthTokens, error) {
+	args := m.Called(ctx, email, password, clientIP)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AuthTokens), args.Error(1)
 }
 
 func (m *MockAuthService) Register(ctx context.Context, user *models.User) error {
@@ -66,6 +115,85 @@ func (m *MockAuthService) Register(ctx context.Context, user *models.User) error
 	return args.Error(0)
 }
 
+func (m *MockAuthService) Refresh(ctx context.Context, refreshToken string) (*models.AuthTokens, error) {
+	args := m.Called(ctx, refreshToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AuthTokens), args.Error(1)
+}
+
+func (m *MockAuthService) Logout(ctx context.Context, sessionID string) error {
+	args := m.Called(ctx, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) LogoutAll(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ForgotPassword(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ResetPassword(ctx context.Context, token, password string) error {
+	args := m.Called(ctx, token, password)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) VerifyEmail(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ResendVerification(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) Invite(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) AcceptInvitation(ctx context.Context, token string, user *models.User) error {
+	args := m.Called(ctx, token, user)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) UnlockAccount(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) VerifyMFALogin(ctx context.Context, mfaToken, code, clientIP string) (*models.AuthTokens, error) {
+	args := m.Called(ctx, mfaToken, code, clientIP)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AuthTokens), args.Error(1)
+}
+
+func (m *MockAuthService) EnrollMFA(ctx context.Context, userID string) (*models.MFAEnrollment, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MFAEnrollment), args.Error(1)
+}
+
+func (m *MockAuthService) ConfirmMFA(ctx context.Context, userID, code string) ([]string, error) {
+	args := m.Called(ctx, userID, code)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockAuthService) DisableMFA(ctx context.Context, userID, code string) error {
+	args := m.Called(ctx, userID, code)
+	return args.Error(0)
+}
+
 // MockApplicationService is a mock for interfaces.ApplicationService
 type MockApplicationService struct {
 	mock.Mock
@@ -94,14 +222,67 @@ func (m *MockApplicationService) GetApplicationsByUserID(ctx context.Context, us
 	return args.Get(0).([]models.Application), args.Error(1)
 }
 
+func (m *MockApplicationService) ExpandApplications(ctx context.Context, applications []models.Application, expand []string) error {
+	args := m.Called(ctx, applications, expand)
+	return args.Error(0)
+}
+
+func (m *MockApplicationService) GetApplicationStageCounts(ctx context.Context, jobID string) ([]models.StageCount, error) {
+	args := m.Called(ctx, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.StageCount), args.Error(1)
+}
+
+func (m *MockApplicationService) MoveApplicationStage(ctx context.Context, id string, stage string, note string) (*models.Application, error) {
+	args := m.Called(ctx, id, stage, note)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Application), args.Error(1)
+}
+
+func (m *MockApplicationService) GetRejectionReasons(ctx context.Context) []models.RejectionReason {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.RejectionReason)
+}
+
+func (m *MockApplicationService) BulkUpdateApplications(ctx context.Context, jobID string, action models.ApplicationBulkAction) ([]models.ApplicationBulkResult, error) {
+	args := m.Called(ctx, jobID, action)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ApplicationBulkResult), args.Error(1)
+}
+
 func (m *MockApplicationService) CreateApplication(ctx context.Context, application *models.Application) error {
 	args := m.Called(ctx, application)
 	return args.Error(0)
 }
 
-func (m *MockApplicationService) UpdateApplicationStatus(ctx context.Context, id string, status string) error {
-	args := m.Called(ctx, id, status)
-	return args.Error(0)
+func (m *MockApplicationService) GetApplicationHistory(ctx context.Context, id string) ([]models.ApplicationStatusChange, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ApplicationStatusChange), args.Error(1)
+}
+
+func (m *MockApplicationService) UpdateApplicationStatus(ctx context.Context, id string, status string, note string) (*models.Application, error) {
+	args := m.Called(ctx, id, status, note)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Application), args.Error(1)
+}
+
+func (m *MockApplicationService) WithdrawApplication(ctx context.Context, id string, reason string) (*models.Application, error) {
+	args := m.Called(ctx, id, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Application), args.Error(1)
 }
 
 func (m *MockApplicationService) DeleteApplication(ctx context.Context, id string) error {
@@ -109,6 +290,11 @@ func (m *MockApplicationService) DeleteApplication(ctx context.Context, id strin
 	return args.Error(0)
 }
 
+func (m *MockApplicationService) RestoreApplication(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // MockJobService is a mock for interfaces.JobService
 type MockJobService struct {
 	mock.Mock
@@ -119,6 +305,14 @@ func (m *MockJobService) GetAllJobs(ctx context.Context, page, limit int, filter
 	return args.Get(0).([]models.Job), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockJobService) GetJobFacets(ctx context.Context, filters map[string]string) (*models.JobFacets, error) {
+	args := m.Called(ctx, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.JobFacets), args.Error(1)
+}
+
 func (m *MockJobService) GetJobByID(ctx context.Context, id string) (*models.Job, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -132,16 +326,74 @@ func (m *MockJobService) GetJobsByUser(ctx context.Context, userID string) ([]mo
 	return args.Get(0).([]models.Job), args.Error(1)
 }
 
+func (m *MockJobService) ExpandJobs(ctx context.Context, jobs []models.Job, expand []string) error {
+	args := m.Called(ctx, jobs, expand)
+	return args.Error(0)
+}
+
 func (m *MockJobService) CreateJob(ctx context.Context, job *models.Job) error {
 	args := m.Called(ctx, job)
 	return args.Error(0)
 }
 
+func (m *MockJobService) UpdateJob(ctx context.Context, id string, job *models.Job) (*models.Job, error) {
+	args := m.Called(ctx, id, job)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Job), args.Error(1)
+}
+
+func (m *MockJobService) PublishJob(ctx context.Context, id string, closesAt *time.Time) (*models.Job, error) {
+	args := m.Called(ctx, id, closesAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Job), args.Error(1)
+}
+
+func (m *MockJobService) CloseJob(ctx context.Context, id string) (*models.Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Job), args.Error(1)
+}
+
+func (m *MockJobService) SetJobStages(ctx context.Context, id string, stages []models.PipelineStage, templateID string) (*models.Job, error) {
+	args := m.Called(ctx, id, stages, templateID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Job), args.Error(1)
+}
+
+func (m *MockJobService) ReopenJob(ctx context.Context, id string, closesAt *time.Time) (*models.Job, error) {
+	args := m.Called(ctx, id, closesAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Job), args.Error(1)
+}
+
+func (m *MockJobService) ArchiveJob(ctx context.Context, id string) (*models.Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Job), args.Error(1)
+}
+
 func (m *MockJobService) DeleteJob(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockJobService) RestoreJob(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // MockSkillService is a mock for interfaces.SkillService
 type MockSkillService struct {
 	mock.Mock
@@ -178,6 +430,11 @@ func (m *MockSkillService) DeleteSkill(ctx context.Context, id string) error {
 	return args.Error(0)
 }
 
+func (m *MockSkillService) RestoreSkill(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // MockJobCategoryService is a mock for interfaces.JobCategoryService
 type MockJobCategoryService struct {
 	mock.Mock
@@ -214,6 +471,11 @@ func (m *MockJobCategoryService) DeleteJobCategory(ctx context.Context, id strin
 	return args.Error(0)
 }
 
+func (m *MockJobCategoryService) RestoreJobCategory(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // MockArticleService is a mock for interfaces.ArticleService
 type MockArticleService struct {
 	mock.Mock
@@ -250,6 +512,11 @@ func (m *MockArticleService) DeleteArticle(ctx context.Context, id string) error
 	return args.Error(0)
 }
 
+func (m *MockArticleService) RestoreArticle(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // MockCountryService is a mock for interfaces.CountryService
 type MockCountryService struct {
 	mock.Mock
@@ -286,6 +553,11 @@ func (m *MockCountryService) DeleteCountry(ctx context.Context, id string) error
 	return args.Error(0)
 }
 
+func (m *MockCountryService) RestoreCountry(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // MockEducationLevelService is a mock for interfaces.EducationLevelService
 type MockEducationLevelService struct {
 	mock.Mock
@@ -322,6 +594,11 @@ func (m *MockEducationLevelService) DeleteEducationLevel(ctx context.Context, id
 	return args.Error(0)
 }
 
+func (m *MockEducationLevelService) RestoreEducationLevel(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // MockJobTypeService is a mock for interfaces.JobTypeService
 type MockJobTypeService struct {
 	mock.Mock
@@ -358,6 +635,11 @@ func (m *MockJobTypeService) DeleteJobType(ctx context.Context, id string) error
 	return args.Error(0)
 }
 
+func (m *MockJobTypeService) RestoreJobType(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // MockKnowledgeLevelService is a mock for interfaces.KnowledgeLevelService
 type MockKnowledgeLevelService struct {
 	mock.Mock
@@ -394,6 +676,11 @@ func (m *MockKnowledgeLevelService) DeleteKnowledgeLevel(ctx context.Context, id
 	return args.Error(0)
 }
 
+func (m *MockKnowledgeLevelService) RestoreKnowledgeLevel(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // MockLocationAvailabilityService is a mock for interfaces.LocationAvailabilityService
 type MockLocationAvailabilityService struct {
 	mock.Mock
@@ -430,6 +717,11 @@ func (m *MockLocationAvailabilityService) DeleteLocationAvailability(ctx context
 	return args.Error(0)
 }
 
+func (m *MockLocationAvailabilityService) RestoreLocationAvailability(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // MockCandidateSkillService is a mock for interfaces.CandidateSkillService
 type MockCandidateSkillService struct {
 	mock.Mock
@@ -468,6 +760,11 @@ func (m *MockCandidateSkillService) DeleteCandidateSkill(ctx context.Context, id
 	return args.Error(0)
 }
 
+func (m *MockCandidateSkillService) RestoreCandidateSkill(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // MockJobSkillService is a mock for interfaces.JobSkillService
 type MockJobSkillService struct {
 	mock.Mock
@@ -505,3 +802,119 @@ func (m *MockJobSkillService) DeleteJobSkill(ctx context.Context, id string) err
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
+
+func (m *MockJobSkillService) RestoreJobSkill(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockNotifier is a mock for interfaces.Notifier
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) Send(ctx context.Context, to, subject, body string) error {
+	args := m.Called(ctx, to, subject, body)
+	return args.Error(0)
+}
+
+// MockAPIKeyService is a mock for interfaces.APIKeyService
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyService) GetAllAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) CreateAPIKey(ctx context.Context, key *models.APIKey) (*models.CreatedAPIKey, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreatedAPIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) RevokeAPIKey(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockExchangeRateService is a mock for interfaces.ExchangeRateService
+type MockExchangeRateService struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateService) GetExchangeRates(ctx context.Context) (*models.ExchangeRates, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ExchangeRates), args.Error(1)
+}
+
+func (m *MockExchangeRateService) SetExchangeRate(ctx context.Context, currency string, rate float64) (*models.ExchangeRate, error) {
+	args := m.Called(ctx, currency, rate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateService) DeleteExchangeRate(ctx context.Context, currency string) error {
+	args := m.Called(ctx, currency)
+	return args.Error(0)
+}
+
+// MockGeocoder is a mock for interfaces.Geocoder
+type MockGeocoder struct {
+	mock.Mock
+}
+
+func (m *MockGeocoder) Geocode(ctx context.Context, place string) (*models.City, error) {
+	args := m.Called(ctx, place)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.City), args.Error(1)
+}
+
+// MockPipelineTemplateService is a mock for interfaces.PipelineTemplateService
+type MockPipelineTemplateService struct {
+	mock.Mock
+}
+
+func (m *MockPipelineTemplateService) GetPipelineTemplates(ctx context.Context) ([]models.PipelineTemplate, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PipelineTemplate), args.Error(1)
+}
+
+func (m *MockPipelineTemplateService) GetPipelineTemplateByID(ctx context.Context, id string) (*models.PipelineTemplate, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PipelineTemplate), args.Error(1)
+}
+
+func (m *MockPipelineTemplateService) CreatePipelineTemplate(ctx context.Context, template *models.PipelineTemplate) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *MockPipelineTemplateService) UpdatePipelineTemplate(ctx context.Context, id string, template *models.PipelineTemplate) (*models.PipelineTemplate, error) {
+	args := m.Called(ctx, id, template)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PipelineTemplate), args.Error(1)
+}
+
+func (m *MockPipelineTemplateService) DeletePipelineTemplate(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
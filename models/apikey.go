@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Scopes an API key can be granted
+const (
+	ScopeJobsRead          = "jobs:read"
+	ScopeJobsWrite         = "jobs:write"
+	ScopeApplicationsRead  = "applications:read"
+	ScopeApplicationsWrite = "applications:write"
+	ScopeCandidatesRead    = "candidates:read"
+	ScopeCandidatesWrite   = "candidates:write"
+)
+
+// APIKey is an admin-managed credential for machine-to-machine integrations.
+// Only the SHA-256 hash of the key is stored; Prefix is kept so a key can be recognised in listings and logs.
+type APIKey struct {
+	ID           bson.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Name         string        `bson:"name" json:"name" validate:"required,min=2,max=100"`
+	Prefix       string        `bson:"prefix" json:"prefix"`
+	KeyHash      string        `bson:"key_hash" json:"-"`
+	Scopes       []string      `bson:"scopes" json:"scopes" validate:"required,min=1,dive,oneof=jobs:read jobs:write applications:read applications:write candidates:read candidates:write"`
+	ExpiresTime  *time.Time    `bson:"expires_time,omitempty" json:"expires_time,omitempty"`
+	LastUsedTime *time.Time    `bson:"last_used_time,omitempty" json:"last_used_time,omitempty"`
+	RevokedTime  *time.Time    `bson:"revoked_time,omitempty" json:"revoked_time,omitempty"`
+	Audit        `bson:",inline"`
+}
+
+// CreatedAPIKey is returned once when a key is created; the plaintext key cannot be retrieved again
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
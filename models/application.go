@@ -6,14 +6,120 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
+// Application statuses. Applications start as applied, are put under review and then accepted or
+// rejected by the recruiter; the candidate can withdraw until then.
+const (
+	ApplicationStatusApplied     = "applied"
+	ApplicationStatusUnderReview = "under_review"
+	ApplicationStatusAccepted    = "accepted"
+	ApplicationStatusRejected    = "rejected"
+	ApplicationStatusWithdrawn   = "withdrawn"
+)
+
+// Application is a candidate's application to a job
 type Application struct {
-	ID            bson.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	JobID         bson.ObjectID `bson:"job_id" json:"job_id" validate:"required"`
-	UserID        bson.ObjectID `bson:"user_id" json:"user_id" validate:"required"`
-	Status        string        `bson:"status" json:"status" validate:"required,oneof=applied under_review rejected accepted withdrawn"`
-	RecruiterNote string        `bson:"recruiter_note" json:"recruiter_note"`
-	AppliedTime   time.Time     `bson:"applied_time" json:"applied_time"`
-	UpdatedTime   time.Time     `bson:"updated_time" json:"updated_time"`
-	CreatedBy     string        `bson:"created_by" json:"created_by"`
-	UpdatedBy     string        `bson:"updated_by" json:"updated_by"`
+	ID               bson.ObjectID             `bson:"_id,omitempty" json:"id,omitempty"`
+	JobID            bson.ObjectID             `bson:"job_id" json:"job_id" validate:"required"`
+	UserID           bson.ObjectID             `bson:"user_id" json:"user_id" validate:"required"`
+	Attempt          int                       `bson:"attempt,omitempty" json:"attempt,omitempty"`
+	Status           string                    `bson:"status" json:"status" validate:"required,oneof=applied under_review rejected accepted withdrawn"`
+	CoverLetter      string                    `bson:"cover_letter,omitempty" json:"cover_letter,omitempty" validate:"max=10000"`
+	Answers          []ScreeningAnswer         `bson:"answers,omitempty" json:"answers,omitempty" validate:"max=50,dive"`
+	RecruiterNote    string                    `bson:"recruiter_note" json:"recruiter_note"`
+	Stage            string                    `bson:"stage,omitempty" json:"stage,omitempty"`
+	Tags             []string                  `bson:"tags,omitempty" json:"tags,omitempty"`
+	RejectionReason  string                    `bson:"rejection_reason,omitempty" json:"rejection_reason,omitempty"`
+	WithdrawalReason string                    `bson:"withdrawal_reason,omitempty" json:"withdrawal_reason,omitempty"`
+	ClosedTime       *time.Time                `bson:"closed_time,omitempty" json:"closed_time,omitempty"`
+	History          []ApplicationStatusChange `bson:"history,omitempty" json:"-"` // append-only, returned by its own endpoint
+	AppliedTime      time.Time                 `bson:"applied_time" json:"applied_time"`
+	UpdatedTime      time.Time                 `bson:"updated_time" json:"updated_time"`
+	CreatedBy        string                    `bson:"created_by" json:"created_by"`
+	UpdatedBy        string                    `bson:"updated_by" json:"updated_by"`
+	SoftDelete       `bson:",inline"`
+	Job              *Job          `bson:"-" json:"job,omitempty"`
+	Candidate        *UserResponse `bson:"-" json:"candidate,omitempty"`
+}
+
+// SetCreated records by as the applicant and last updater of the application; its creation time is AppliedTime
+func (a *Application) SetCreated(by string, at time.Time) {
+	a.AppliedTime, a.CreatedBy = at, by
+	a.SetUpdated(by, at)
+}
+
+// SetUpdated records by as the last updater of the application, at the given time
+func (a *Application) SetUpdated(by string, at time.Time) {
+	a.UpdatedTime, a.UpdatedBy = at, by
+}
+
+// ApplicationStatusChange is an entry of an application's status history: the status and stage it
+// moved to from FromStatus and FromStage (empty for the first entry), who changed it, when, and an
+// optional note
+type ApplicationStatusChange struct {
+	Status      string    `bson:"status" json:"status"`
+	FromStatus  string    `bson:"from_status,omitempty" json:"from_status,omitempty"`
+	Stage       string    `bson:"stage,omitempty" json:"stage,omitempty"`
+	FromStage   string    `bson:"from_stage,omitempty" json:"from_stage,omitempty"`
+	Note        string    `bson:"note,omitempty" json:"note,omitempty"`
+	ChangedBy   string    `bson:"changed_by" json:"changed_by"`
+	ChangedTime time.Time `bson:"changed_time" json:"changed_time"`
+}
+
+// RejectionReason is a reason applications can be rejected with in bulk. The text becomes the note of
+// the rejection, with {job_title} replaced by the title of the job.
+type RejectionReason struct {
+	Key  string `json:"key"`
+	Text string `json:"text"`
+}
+
+// DefaultRejectionReasons are the rejection reasons, text by key, used unless others are configured
+var DefaultRejectionReasons = map[string]string{
+	"not_qualified":    "Thank you for applying to {job_title}. We have decided to move forward with candidates whose experience more closely matches the role.",
+	"position_filled":  "Thank you for applying to {job_title}. The position has now been filled.",
+	"location":         "Thank you for applying to {job_title}. Unfortunately we cannot consider candidates in your location for this role.",
+	"salary_mismatch":  "Thank you for applying to {job_title}. Unfortunately your salary expectations are outside the range of this role.",
+	"no_longer_hiring": "Thank you for applying to {job_title}. We are no longer hiring for this role.",
+}
+
+// Bulk application actions: move applications to a pipeline stage, reject them with a reason, or tag them
+const (
+	ApplicationActionMove   = "move"
+	ApplicationActionReject = "reject"
+	ApplicationActionTag    = "tag"
+)
+
+// ApplicationBulkAction is an action on many applications to one job. Stage is the stage to move them
+// to, Reason the key of the rejection reason and Tags the tags to add; Note is added to a move or a
+// rejection.
+type ApplicationBulkAction struct {
+	Action         string   `json:"action" validate:"required,oneof=move reject tag"`
+	ApplicationIDs []string `json:"application_ids" validate:"required,min=1,max=500,dive,required"`
+	Stage          string   `json:"stage" validate:"required_if=Action move,max=50"`
+	Reason         string   `json:"reason" validate:"required_if=Action reject"`
+	Note           string   `json:"note" validate:"max=2000"`
+	Tags           []string `json:"tags" validate:"max=20,dive,required,max=50"`
+}
+
+// Outcomes of a bulk action for one application
+const (
+	BulkResultUpdated   = "updated"
+	BulkResultUnchanged = "unchanged"
+	BulkResultFailed    = "failed"
+)
+
+// ApplicationBulkResult is the outcome of a bulk action for one application, with the reason it failed
+type ApplicationBulkResult struct {
+	ID     string `json:"id"`
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ApplicationBulkUpdate is the write of a bulk action to one application. With a Change, the
+// application moves to the status, stage, recruiter note and rejection reason of Application while it
+// is still in FromStatus, and Change is appended to its history. Tags are added to its tags.
+type ApplicationBulkUpdate struct {
+	Application *Application
+	FromStatus  string
+	Change      *ApplicationStatusChange
+	Tags        []string
 }
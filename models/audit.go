@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Audit records when a record was created and last updated, and by whom. Records embed it, and the
+// services stamp it through Auditable.
+type Audit struct {
+	CreatedTime time.Time `bson:"created_time" json:"created_time"`
+	UpdatedTime time.Time `bson:"updated_time" json:"updated_time"`
+	CreatedBy   string    `bson:"created_by" json:"created_by"`
+	UpdatedBy   string    `bson:"updated_by" json:"updated_by"`
+}
+
+// Auditable is a record whose creation and last update are recorded
+type Auditable interface {
+	SetCreated(by string, at time.Time)
+	SetUpdated(by string, at time.Time)
+}
+
+// SetCreated records by as the creator and last updater of the record, at the given time
+func (a *Audit) SetCreated(by string, at time.Time) {
+	a.CreatedTime, a.CreatedBy = at, by
+	a.SetUpdated(by, at)
+}
+
+// SetUpdated records by as the last updater of the record, at the given time
+func (a *Audit) SetUpdated(by string, at time.Time) {
+	a.UpdatedTime, a.UpdatedBy = at, by
+}
+
+// SoftDelete records when a record was soft deleted, and by whom. Records embed it; it is only set by
+// the repositories' Delete and cleared by Restore.
+type SoftDelete struct {
+	DeletedTime *time.Time `bson:"deleted_time,omitempty" json:"deleted_time,omitempty"`
+	DeletedBy   string     `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
+}
+
+// SoftDeletable is a record that can be soft deleted
+type SoftDeletable interface {
+	ClearDeleted()
+}
+
+// ClearDeleted resets the soft delete fields, so a new record cannot be created as deleted
+func (d *SoftDelete) ClearDeleted() {
+	d.DeletedTime, d.DeletedBy = nil, ""
+}
@@ -0,0 +1,14 @@
+package models
+
+import "go.mongodb.org/mongo-driver/v2/bson"
+
+// City is an entry of the offline geocoding dataset, imported with cmd/importcities. Job locations
+// are resolved to the coordinates of the matching city.
+type City struct {
+	ID         bson.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Name       string        `bson:"name" json:"name" validate:"required,max=100"`
+	CountryID  bson.ObjectID `bson:"country_id" json:"country_id" validate:"required"`
+	Location   GeoPoint      `bson:"location" json:"location"`
+	Population int           `bson:"population" json:"population" validate:"gte=0"`
+	Audit      `bson:",inline"`
+}
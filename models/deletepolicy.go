@@ -0,0 +1,57 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Delete policies decide what happens to documents that reference a record being deleted
+const (
+	DeleteRestrict = "restrict" // refuse the delete while dependents exist
+	DeleteCascade  = "cascade"  // delete the dependents as well
+	DeleteNullify  = "nullify"  // remove the reference from the dependents
+)
+
+// SoftDeleteCollections are the collections whose documents are soft deleted: deleting sets
+// deleted_time and deleted_by, and only a purge removes them for good. Other collections reached by a
+// cascade (sessions, tokens) are deleted right away.
+var SoftDeleteCollections = []string{
+	"users", "jobs", "applications", "candidateskills", "jobskills", "skills", "jobcategories",
+	"articles", "countries", "educationlevels", "jobtypes", "knowledgelevels", "locationavailabilities",
+}
+
+// Relation is a reference from documents in Collection to a document in Parent through Field
+type Relation struct {
+	Parent     string
+	Collection string
+	Field      string
+	Policy     string
+}
+
+// Name identifies the relation in configuration, e.g. "jobs.applications"
+func (r Relation) Name() string {
+	return r.Parent + "." + r.Collection
+}
+
+// ErrParentDeleted is returned when restoring a record that references a record which is still deleted
+var ErrParentDeleted = errors.New("referenced record is deleted")
+
+// DependentsError is returned when a restricted relation still has documents referencing the
+// record being deleted. Dependents holds their number per collection.
+type DependentsError struct {
+	Dependents map[string]int64
+}
+
+func (e *DependentsError) Error() string {
+	var parts []string
+	for _, collection := range slices.Sorted(maps.Keys(e.Dependents)) {
+		parts = append(parts, fmt.Sprintf("%s (%d)", collection, e.Dependents[collection]))
+	}
+	return "record is still referenced by " + strings.Join(parts, ", ")
+}
+
+// FilterIncludeDeleted is the list filter that includes soft-deleted records when set to "true"
+const FilterIncludeDeleted = "include_deleted"
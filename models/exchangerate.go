@@ -0,0 +1,18 @@
+package models
+
+import "go.mongodb.org/mongo-driver/v2/bson"
+
+// ExchangeRate is the number of units of Currency that one unit of the base currency buys. Salaries
+// are normalized to the base currency by dividing by the rate.
+type ExchangeRate struct {
+	ID       bson.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Currency string        `bson:"currency" json:"currency" validate:"required,iso4217"`
+	Rate     float64       `bson:"rate" json:"rate" validate:"required,gt=0"`
+	Audit    `bson:",inline"`
+}
+
+// ExchangeRates lists the exchange rates against the base currency
+type ExchangeRates struct {
+	BaseCurrency string         `json:"base_currency"`
+	Rates        []ExchangeRate `json:"rates"`
+}
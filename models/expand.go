@@ -0,0 +1,24 @@
+package models
+
+// FilterExpand is the query parameter listing, comma-separated, the references to embed in the
+// returned records instead of leaving them as bare IDs
+const FilterExpand = "expand"
+
+// References a job listing can expand: the job category, the recruiter who posted the job and the
+// skills it requires
+const (
+	ExpandCategory  = "category"
+	ExpandRecruiter = "recruiter"
+	ExpandSkills    = "skills"
+)
+
+// References an application listing can expand: the job applied to and the candidate who applied
+const (
+	ExpandJob       = "job"
+	ExpandCandidate = "candidate"
+)
+
+var (
+	JobExpansions         = []string{ExpandCategory, ExpandRecruiter, ExpandSkills}
+	ApplicationExpansions = []string{ExpandJob, ExpandCandidate}
+)
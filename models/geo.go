@@ -0,0 +1,22 @@
+package models
+
+// GeoJSONPoint is the GeoJSON type of a single position
+const GeoJSONPoint = "Point"
+
+// GeoPoint is a GeoJSON point. Coordinates are [longitude, latitude], in that order.
+type GeoPoint struct {
+	Type        string    `bson:"type" json:"type"`
+	Coordinates []float64 `bson:"coordinates" json:"coordinates"`
+}
+
+// NewGeoPoint creates a point at the given latitude and longitude
+func NewGeoPoint(lat, lng float64) *GeoPoint {
+	return &GeoPoint{Type: GeoJSONPoint, Coordinates: []float64{lng, lat}}
+}
+
+// Valid reports whether the point is a GeoJSON Point with a longitude and latitude in range
+func (p *GeoPoint) Valid() bool {
+	return p.Type == GeoJSONPoint && len(p.Coordinates) == 2 &&
+		p.Coordinates[0] >= -180 && p.Coordinates[0] <= 180 &&
+		p.Coordinates[1] >= -90 && p.Coordinates[1] <= 90
+}
@@ -1,25 +1,123 @@
 package models
 
 import (
+	"slices"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
+// Job lifecycle statuses. Jobs start as drafts, are published (active), closed by the recruiter or when
+// ClosesAt passes, and can then be reopened or archived.
+const (
+	JobStatusDraft    = "draft"
+	JobStatusActive   = "active"
+	JobStatusClosed   = "closed"
+	JobStatusArchived = "archived"
+)
+
+// PublicJobStatuses are the statuses of the jobs anyone can see. Drafts and archived jobs are only
+// shown to their recruiter and to admins.
+var PublicJobStatuses = []string{JobStatusActive, JobStatusClosed}
+
+// FilterVisibleTo limits a job listing to the public jobs and those posted by the user whose ID it
+// holds, or to the public jobs alone when it is empty. The job service sets it for non-admin callers.
+const FilterVisibleTo = "visible_to"
+
+// Salary pay periods, and how many of each make up a year (40 hours a week, 52 weeks)
+const (
+	SalaryPeriodHourly  = "hourly"
+	SalaryPeriodMonthly = "monthly"
+	SalaryPeriodYearly  = "yearly"
+)
+
+var PeriodsPerYear = map[string]int{
+	SalaryPeriodHourly:  2080,
+	SalaryPeriodMonthly: 12,
+	SalaryPeriodYearly:  1,
+}
+
+// Job list filters on salary. FilterSalaryMin and FilterSalaryMax select jobs whose salary range
+// reaches the given amounts; with FilterNormalizeSalary set to "true" the amounts are annual and in
+// the base currency, compared against the normalized salaries.
+const (
+	FilterSalaryMin       = "salary_min"
+	FilterSalaryMax       = "salary_max"
+	FilterNormalizeSalary = "normalize_salary"
+)
+
+// Job list filters on location. FilterLatitude and FilterLongitude select the jobs near a point,
+// within FilterRadiusKm kilometres when set; FilterNear names a place ("Skopje, North Macedonia") to
+// use as the point instead. SortDistance orders them nearest first, which is the default.
+const (
+	FilterLatitude  = "lat"
+	FilterLongitude = "lng"
+	FilterRadiusKm  = "radius_km"
+	FilterNear      = "near"
+	SortDistance    = "distance"
+)
+
+// JobReferenceFilters are the job list filters matching a reference to another collection by ID
+var JobReferenceFilters = []string{"category_id", "job_type_id", "country_id", "education_level_id", "location_availability_id"}
+
+// FilterSearch is the job list filter holding a full-text search in MongoDB $search syntax, and
+// SortRelevance orders its matches by text score. Matched jobs carry their score in Job.Score.
+const (
+	FilterSearch  = "q"
+	SortRelevance = "relevance"
+)
+
+// Job is a job posting
 type Job struct {
-	ID          bson.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	Title       string        `bson:"title" json:"title" validate:"required,min=5,max=255"`
-	Description string        `bson:"description" json:"description" validate:"required,min=20"`
-	UserID      bson.ObjectID `bson:"user_id" json:"user_id" validate:"required"`
-	CategoryID  bson.ObjectID `bson:"category_id" json:"category_id" validate:"required"`
-	Location    string        `bson:"location" json:"location" validate:"required,min=3"`
-	JobType     string        `bson:"job_type" json:"job_type" validate:"required,oneof=full-time part-time contract freelance"`
-	SalaryMin   int           `bson:"salary_min" json:"salary_min" validate:"required,gt=0"`
-	SalaryMax   int           `bson:"salary_max" json:"salary_max" validate:"required,gt=0"`
-	Status      string        `bson:"status" json:"status" validate:"required,oneof=active closed draft"`
-	Active      bool          `bson:"active" json:"active"`
-	CreatedTime time.Time     `bson:"created_time" json:"created_time"`
-	UpdatedTime time.Time     `bson:"updated_time" json:"updated_time"`
-	CreatedBy   string        `bson:"created_by" json:"created_by"`
-	UpdatedBy   string        `bson:"updated_by" json:"updated_by"`
+	ID                     bson.ObjectID       `bson:"_id,omitempty" json:"id,omitempty"`
+	Title                  string              `bson:"title" json:"title" validate:"required,min=5,max=255"`
+	Description            string              `bson:"description" json:"description" validate:"required,min=20"`
+	UserID                 bson.ObjectID       `bson:"user_id" json:"user_id" validate:"required"`
+	CategoryID             bson.ObjectID       `bson:"category_id" json:"category_id" validate:"required"`
+	Location               string              `bson:"location" json:"location" validate:"required,min=3"`
+	CountryID              *bson.ObjectID      `bson:"country_id,omitempty" json:"country_id,omitempty"`
+	EducationLevelID       *bson.ObjectID      `bson:"education_level_id,omitempty" json:"education_level_id,omitempty"`
+	LocationAvailabilityID *bson.ObjectID      `bson:"location_availability_id,omitempty" json:"location_availability_id,omitempty"`
+	JobTypeID              bson.ObjectID       `bson:"job_type_id" json:"job_type_id" validate:"required"`
+	SalaryMin              int                 `bson:"salary_min" json:"salary_min" validate:"required,gt=0"`
+	SalaryMax              int                 `bson:"salary_max" json:"salary_max" validate:"required,gt=0"`
+	SalaryCurrency         string              `bson:"salary_currency" json:"salary_currency" validate:"required,iso4217"`
+	SalaryPeriod           string              `bson:"salary_period" json:"salary_period" validate:"required,oneof=hourly monthly yearly"`
+	SalaryAnnualMin        *int                `bson:"salary_annual_min,omitempty" json:"salary_annual_min,omitempty"` // normalized: per year, base currency
+	SalaryAnnualMax        *int                `bson:"salary_annual_max,omitempty" json:"salary_annual_max,omitempty"` // normalized: per year, base currency
+	Coordinates            *GeoPoint           `bson:"coordinates,omitempty" json:"coordinates,omitempty"`
+	Stages                 []PipelineStage     `bson:"stages,omitempty" json:"stages,omitempty" validate:"max=20,dive"`
+	ScreeningQuestions     []ScreeningQuestion `bson:"screening_questions,omitempty" json:"screening_questions,omitempty" validate:"max=20,dive"` // knockouts hidden from candidates
+	Status                 string              `bson:"status" json:"status" validate:"required,oneof=draft active closed archived"`
+	Active                 bool                `bson:"active" json:"active"`
+	PublishedTime          *time.Time          `bson:"published_time,omitempty" json:"published_time,omitempty"`
+	ClosesAt               *time.Time          `bson:"closes_at,omitempty" json:"closes_at,omitempty"`
+	ClosedTime             *time.Time          `bson:"closed_time,omitempty" json:"closed_time,omitempty"`
+	Audit                  `bson:",inline"`
+	SoftDelete             `bson:",inline"`
+	Score                  float64        `bson:"-" json:"score,omitempty"`
+	DistanceKm             *float64       `bson:"-" json:"distance_km,omitempty"`
+	Category               *JobCategory   `bson:"-" json:"category,omitempty"`
+	Recruiter              *UserResponse  `bson:"-" json:"recruiter,omitempty"`
+	Skills                 []JobSkillInfo `bson:"-" json:"skills,omitempty"`
+}
+
+// Stage returns the pipeline stage of the job with the given key, or nil
+func (j *Job) Stage(key string) *PipelineStage {
+	for i := range j.Stages {
+		if j.Stages[i].Key == key {
+			return &j.Stages[i]
+		}
+	}
+	return nil
+}
+
+// IsPublic reports whether anyone can see the job
+func (j *Job) IsPublic() bool {
+	return slices.Contains(PublicJobStatuses, j.Status)
+}
+
+// IsOpen reports whether the job accepts applications at the given time
+func (j *Job) IsOpen(now time.Time) bool {
+	return j.Status == JobStatusActive && (j.ClosesAt == nil || j.ClosesAt.After(now))
 }
@@ -0,0 +1,27 @@
+package models
+
+// FilterFacets asks a job listing to also return facet counts when set to "true"
+const FilterFacets = "facets"
+
+// FacetCount is the number of jobs sharing a field value
+type FacetCount struct {
+	Value string `bson:"_id" json:"value"`
+	Count int64  `bson:"count" json:"count"`
+}
+
+// SalaryBandCount is the number of jobs whose salary range overlaps the band from Min up to, but not
+// including, Max. The last band has no Max.
+type SalaryBandCount struct {
+	Min   int   `json:"min"`
+	Max   *int  `json:"max,omitempty"`
+	Count int64 `json:"count"`
+}
+
+// JobFacets holds the counts shown next to a job listing, computed over the listing's filters
+type JobFacets struct {
+	Categories  []FacetCount      `json:"categories"`
+	JobTypes    []FacetCount      `json:"job_types"`
+	Locations   []FacetCount      `json:"locations"`
+	Statuses    []FacetCount      `json:"statuses"`
+	SalaryBands []SalaryBandCount `json:"salary_bands"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Scopes failed login attempts are counted in
+const (
+	LoginScopeAccount = "account"
+	LoginScopeIP      = "ip"
+)
+
+// LoginAttempt counts consecutive failed logins for an email address or a client IP
+type LoginAttempt struct {
+	ID              bson.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Scope           string        `bson:"scope" json:"scope"`
+	Value           string        `bson:"value" json:"value"`
+	Failures        int           `bson:"failures" json:"failures"`
+	LockedUntil     *time.Time    `bson:"locked_until,omitempty" json:"locked_until,omitempty"`
+	LastFailureTime time.Time     `bson:"last_failure_time" json:"last_failure_time"`
+	ExpiresTime     time.Time     `bson:"expires_time" json:"expires_time"`
+	CreatedTime     time.Time     `bson:"created_time" json:"created_time"`
+}
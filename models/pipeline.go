@@ -0,0 +1,36 @@
+package models
+
+import "go.mongodb.org/mongo-driver/v2/bson"
+
+// FilterStageCounts asks a job's application listing to also return the number of applications in
+// each pipeline stage when set to "true"
+const FilterStageCounts = "stage_counts"
+
+// PipelineStage is a step of a job's hiring pipeline, identified by Key. Moving an application into
+// a stage puts it under review, or accepts or rejects it when the stage has that Outcome.
+type PipelineStage struct {
+	Key     string `bson:"key" json:"key" validate:"required,max=50"`
+	Name    string `bson:"name" json:"name" validate:"required,max=100"`
+	Outcome string `bson:"outcome,omitempty" json:"outcome,omitempty" validate:"omitempty,oneof=accepted rejected"`
+}
+
+// PipelineTemplate is a reusable list of pipeline stages shared by the recruiters of a company, the
+// company of the recruiter who created it. Jobs get a copy of the stages, so later changes to the
+// template do not affect them.
+type PipelineTemplate struct {
+	ID          bson.ObjectID   `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID      bson.ObjectID   `bson:"user_id" json:"user_id" validate:"required"`
+	CompanyName string          `bson:"company_name,omitempty" json:"company_name,omitempty"`
+	Name        string          `bson:"name" json:"name" validate:"required,max=100"`
+	Stages      []PipelineStage `bson:"stages" json:"stages" validate:"required,min=1,max=20,dive"`
+	Audit       `bson:",inline"`
+}
+
+// StageCount is the number of applications to a job in a pipeline stage. Applications in no stage,
+// or in one the job no longer has, are counted under an empty Key.
+type StageCount struct {
+	Key     string `json:"key"`
+	Name    string `json:"name,omitempty"`
+	Outcome string `json:"outcome,omitempty"`
+	Count   int64  `json:"count"`
+}
@@ -0,0 +1,63 @@
+package models
+
+// Screening question types: free text, yes/no, one of the question's options, or a number
+const (
+	QuestionTypeText           = "text"
+	QuestionTypeYesNo          = "yes_no"
+	QuestionTypeMultipleChoice = "multiple_choice"
+	QuestionTypeNumber         = "number"
+)
+
+// ScreeningQuestion is a question candidates answer when applying to a job, identified by Key.
+// Options are the choices of a multiple choice question. A question with a Knockout rule must be
+// answered, and applications whose answer fails the rule are rejected when they are submitted.
+type ScreeningQuestion struct {
+	Key      string        `bson:"key" json:"key" validate:"required,max=50"`
+	Question string        `bson:"question" json:"question" validate:"required,max=500"`
+	Type     string        `bson:"type" json:"type" validate:"required,oneof=text yes_no multiple_choice number"`
+	Required bool          `bson:"required" json:"required"`
+	Options  []string      `bson:"options,omitempty" json:"options,omitempty" validate:"max=20,dive,required,max=200"`
+	Knockout *KnockoutRule `bson:"knockout,omitempty" json:"knockout,omitempty"`
+}
+
+// KnockoutRule is the answer a screening question needs for an application to go ahead: Expected for
+// a yes/no question, one of Accepted for a multiple choice question, and at least Min and at most Max
+// for a number question. Text questions have no knockout rule.
+type KnockoutRule struct {
+	Expected *bool    `bson:"expected,omitempty" json:"expected,omitempty"`
+	Accepted []string `bson:"accepted,omitempty" json:"accepted,omitempty" validate:"max=20,dive,required,max=200"`
+	Min      *float64 `bson:"min,omitempty" json:"min,omitempty"`
+	Max      *float64 `bson:"max,omitempty" json:"max,omitempty"`
+}
+
+// Passes reports whether answer meets the rule
+func (k *KnockoutRule) Passes(answer ScreeningAnswer) bool {
+	switch {
+	case k.Expected != nil:
+		return answer.Yes != nil && *answer.Yes == *k.Expected
+	case len(k.Accepted) > 0:
+		for _, option := range k.Accepted {
+			if answer.Text == option {
+				return true
+			}
+		}
+		return false
+	default:
+		return answer.Number != nil &&
+			(k.Min == nil || *answer.Number >= *k.Min) &&
+			(k.Max == nil || *answer.Number <= *k.Max)
+	}
+}
+
+// ScreeningAnswer is a candidate's answer to the screening question of the job with key Key: Text for
+// free text and multiple choice questions (the option chosen), Yes for yes/no questions and Number for
+// number questions
+type ScreeningAnswer struct {
+	Key    string   `bson:"key" json:"key" validate:"required,max=50"`
+	Text   string   `bson:"text,omitempty" json:"text,omitempty" validate:"max=5000"`
+	Yes    *bool    `bson:"yes,omitempty" json:"yes,omitempty"`
+	Number *float64 `bson:"number,omitempty" json:"number,omitempty"`
+}
+
+// RejectionReasonScreening is the rejection reason of applications rejected by a knockout rule
+const RejectionReasonScreening = "screening"
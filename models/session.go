@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Session is a server-side login session backing a rotating refresh token.
+// Only hashes of refresh tokens are stored; previous hashes are kept so reuse of a rotated token can be detected.
+type Session struct {
+	ID             bson.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID         bson.ObjectID `bson:"user_id" json:"user_id"`
+	TokenHash      string        `bson:"token_hash" json:"-"`
+	PreviousHashes []string      `bson:"previous_hashes" json:"-"`
+	ExpiresTime    time.Time     `bson:"expires_time" json:"expires_time"`
+	RevokedTime    *time.Time    `bson:"revoked_time,omitempty" json:"revoked_time,omitempty"`
+	RevokedReason  string        `bson:"revoked_reason,omitempty" json:"revoked_reason,omitempty"`
+	MFA            bool          `bson:"mfa" json:"mfa"`
+	CreatedTime    time.Time     `bson:"created_time" json:"created_time"`
+	UpdatedTime    time.Time     `bson:"updated_time" json:"updated_time"`
+}
+
+// AuthTokens is the token pair returned by login and refresh. When the account has two-factor
+// authentication enabled, login instead returns only an MFA challenge token, and ExpiresIn is its lifetime.
+type AuthTokens struct {
+	AccessToken  string `json:"token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	MFARequired  bool   `json:"mfa_required,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// MFAEnrollment is the pending TOTP secret returned when a user starts enrolling an authenticator app
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
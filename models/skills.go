@@ -1,17 +1,11 @@
 package models
 
-import (
-	"time"
-
-	"go.mongodb.org/mongo-driver/v2/bson"
-)
+import "go.mongodb.org/mongo-driver/v2/bson"
 
 type Skill struct {
 	ID          bson.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
 	Name        string        `bson:"name" json:"name" validate:"required,min=2,max=100"`
 	Description string        `bson:"description" json:"description" validate:"min=5"`
-	CreatedTime time.Time     `bson:"created_time" json:"created_time"`
-	UpdatedTime time.Time     `bson:"updated_time" json:"updated_time"`
-	CreatedBy   string        `bson:"created_by" json:"created_by"`
-	UpdatedBy   string        `bson:"updated_by" json:"updated_by"`
+	Audit       `bson:",inline"`
+	SoftDelete  `bson:",inline"`
 }
@@ -6,6 +6,13 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
+// Roles a user can hold
+const (
+	RoleAdmin     = "admin"
+	RoleCandidate = "candidate"
+	RoleRecruiter = "recruiter"
+)
+
 // User represents a platform user with a specific role (admin, candidate, recruiter)
 type User struct {
 	ID                bson.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
@@ -21,10 +28,12 @@ type User struct {
 	TermsAccepted     bool          `bson:"terms_accepted" json:"terms_accepted"`
 	LastTermsAccepted *time.Time    `bson:"last_terms_accepted,omitempty" json:"last_terms_accepted,omitempty"`
 	LastLoginTime     *time.Time    `bson:"last_login_time,omitempty" json:"last_login_time,omitempty"`
-	CreatedTime       time.Time     `bson:"created_time" json:"created_time"`
-	UpdatedTime       time.Time     `bson:"updated_time" json:"updated_time"`
-	CreatedBy         string        `bson:"created_by,omitempty" json:"created_by,omitempty"`
-	UpdatedBy         string        `bson:"updated_by,omitempty" json:"updated_by,omitempty"`
+	MFAEnabled        bool          `bson:"mfa_enabled" json:"-"`
+	MFASecret         string        `bson:"mfa_secret,omitempty" json:"-"`
+	MFARecoveryCodes  []string      `bson:"mfa_recovery_codes,omitempty" json:"-"`
+	MFALastStep       int64         `bson:"mfa_last_step,omitempty" json:"-"`
+	Audit             `bson:",inline"`
+	SoftDelete        `bson:",inline"`
 }
 
 type UserResponse struct {
@@ -40,10 +49,13 @@ type UserResponse struct {
 	TermsAccepted     bool          `json:"terms_accepted"`
 	LastTermsAccepted *time.Time    `json:"last_terms_accepted,omitempty"`
 	LastLoginTime     *time.Time    `json:"last_login_time,omitempty"`
+	MFAEnabled        *bool         `json:"mfa_enabled,omitempty"` // only set for the user and admins
 	CreatedTime       time.Time     `json:"created_time"`
 	UpdatedTime       time.Time     `json:"updated_time"`
 	CreatedBy         string        `json:"created_by,omitempty"`
 	UpdatedBy         string        `json:"updated_by,omitempty"`
+	DeletedTime       *time.Time    `json:"deleted_time,omitempty"`
+	DeletedBy         string        `json:"deleted_by,omitempty"`
 }
 
 func (u *User) ToResponse() UserResponse {
@@ -64,5 +76,7 @@ func (u *User) ToResponse() UserResponse {
 		UpdatedTime:       u.UpdatedTime,
 		CreatedBy:         u.CreatedBy,
 		UpdatedBy:         u.UpdatedBy,
+		DeletedTime:       u.DeletedTime,
+		DeletedBy:         u.DeletedBy,
 	}
 }
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Purposes a UserToken can be issued for
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposeInvitation        = "invitation"
+	TokenPurposeMFAChallenge      = "mfa_challenge"
+)
+
+// UserToken is a hashed, single-use, expiring token sent to a user out of band.
+// Invitations are issued before the account exists, so they carry the invited email and role instead of a user ID.
+type UserToken struct {
+	ID          bson.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID      bson.ObjectID `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	Purpose     string        `bson:"purpose" json:"purpose"`
+	TokenHash   string        `bson:"token_hash" json:"-"`
+	Email       string        `bson:"email,omitempty" json:"email,omitempty"`
+	Role        string        `bson:"role,omitempty" json:"role,omitempty"`
+	ExpiresTime time.Time     `bson:"expires_time" json:"expires_time"`
+	UsedTime    *time.Time    `bson:"used_time,omitempty" json:"used_time,omitempty"`
+	CreatedTime time.Time     `bson:"created_time" json:"created_time"`
+	CreatedBy   string        `bson:"created_by,omitempty" json:"created_by,omitempty"`
+}
@@ -0,0 +1,21 @@
+package notifiers
+
+import (
+	"context"
+	"log"
+)
+
+// LogNotifier writes messages to the application log instead of delivering them.
+// It is intended for local development.
+type LogNotifier struct{}
+
+// NewLogNotifier creates a notifier that logs every message
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// Send logs the message
+func (n *LogNotifier) Send(ctx context.Context, to, subject, body string) error {
+	log.Printf("notification to=%s subject=%q\n%s", to, subject, body)
+	return nil
+}
@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"go-mongodb-api/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type APIKeyRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *mongo.Database) *APIKeyRepository {
+	return &APIKeyRepository{
+		collection: db.Collection("apikeys"),
+	}
+}
+
+// GetAll retrieves all API keys, newest first
+func (r *APIKeyRepository) GetAll(ctx context.Context) ([]models.APIKey, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_time", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	keys := []models.APIKey{}
+	if err = cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// GetByHash retrieves the unrevoked API key with the given key hash
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.collection.FindOne(ctx, bson.M{
+		"key_hash":     keyHash,
+		"revoked_time": bson.M{"$exists": false},
+	}).Decode(&key)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Create inserts a new API key
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	result, err := r.collection.InsertOne(ctx, key)
+	if err != nil {
+		return err
+	}
+	objID, ok := result.InsertedID.(bson.ObjectID)
+	if !ok {
+		return nil
+	}
+	key.ID = objID
+	return nil
+}
+
+// Revoke marks an API key as revoked at the time of the revoked audit stamp. It returns mongo.ErrNoDocuments when no active key has the ID.
+func (r *APIKeyRepository) Revoke(ctx context.Context, id string, revoked models.Audit) error {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objID, "revoked_time": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_time": revoked.UpdatedTime, "updated_time": revoked.UpdatedTime, "updated_by": revoked.UpdatedBy}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// TouchLastUsed records the time an API key was last used. Keys used within the last minute
+// are left alone so busy integrations do not write on every request.
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id string, usedTime time.Time) error {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"_id": objID,
+			"$or": bson.A{
+				bson.M{"last_used_time": bson.M{"$exists": false}},
+				bson.M{"last_used_time": bson.M{"$lt": usedTime.Add(-time.Minute)}},
+			},
+		},
+		bson.M{"$set": bson.M{"last_used_time": usedTime}},
+	)
+	return err
+}
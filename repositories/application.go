@@ -2,7 +2,9 @@ package repositories
 
 import (
 	"context"
+	"fmt"
 	"go-mongodb-api/helpers"
+	"go-mongodb-api/interfaces"
 	"go-mongodb-api/models"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -12,12 +14,14 @@ import (
 
 type ApplicationRepository struct {
 	collection *mongo.Collection
+	deleter    *Deleter
 }
 
 // NewApplicationRepository creates a new application repository
-func NewApplicationRepository(db *mongo.Database) *ApplicationRepository {
+func NewApplicationRepository(db *mongo.Database, deleter *Deleter) *ApplicationRepository {
 	return &ApplicationRepository{
 		collection: db.Collection("applications"),
+		deleter:    deleter,
 	}
 }
 
@@ -26,7 +30,7 @@ func (r *ApplicationRepository) GetAll(ctx context.Context, page, limit int, fil
 	pagination := helpers.NewPagination(page, limit)
 
 	// Build filter query
-	filter := bson.M{}
+	filter := listFilter(filters)
 
 	// Search by status (partial match)
 	if status, exists := filters["status"]; exists && status != "" {
@@ -98,7 +102,7 @@ func (r *ApplicationRepository) GetByID(ctx context.Context, id string) (*models
 	}
 
 	var application models.Application
-	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&application)
+	err = r.collection.FindOne(ctx, notDeleted(bson.M{"_id": objID})).Decode(&application)
 	if err != nil {
 		return nil, err
 	}
@@ -111,7 +115,7 @@ func (r *ApplicationRepository) GetByJobID(ctx context.Context, jobID string) ([
 	if err != nil {
 		return nil, err
 	}
-	cursor, err := r.collection.Find(ctx, bson.M{"job_id": objID})
+	cursor, err := r.collection.Find(ctx, notDeleted(bson.M{"job_id": objID}))
 	if err != nil {
 		return nil, err
 	}
@@ -133,7 +137,7 @@ func (r *ApplicationRepository) GetByUserID(ctx context.Context, userID string)
 	if err != nil {
 		return nil, err
 	}
-	cursor, err := r.collection.Find(ctx, bson.M{"user_id": objID})
+	cursor, err := r.collection.Find(ctx, notDeleted(bson.M{"user_id": objID}))
 	if err != nil {
 		return nil, err
 	}
@@ -149,9 +153,134 @@ func (r *ApplicationRepository) GetByUserID(ctx context.Context, userID string)
 	return applications, nil
 }
 
-// Create inserts a new application
+// Expand embeds the references named in expand (models.ApplicationExpansions) into applications,
+// resolving them for all the applications in one aggregation. References to soft-deleted documents
+// are left unset.
+func (r *ApplicationRepository) Expand(ctx context.Context, applications []models.Application, expand []string) error {
+	if len(applications) == 0 || len(expand) == 0 {
+		return nil
+	}
+
+	ids := make([]bson.ObjectID, len(applications))
+	for i, application := range applications {
+		ids[i] = application.ID
+	}
+
+	var lookups []bson.D
+	for _, field := range expand {
+		switch field {
+		case models.ExpandJob:
+			lookups = append(lookups, lookup("jobs", "job_id", field))
+		case models.ExpandCandidate:
+			lookups = append(lookups, lookupUser("user_id", field))
+		default:
+			return fmt.Errorf("unknown application expansion %q", field)
+		}
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, expandPipeline(ids, lookups, expand))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var docs []struct {
+		ID        bson.ObjectID `bson:"_id"`
+		Job       []models.Job  `bson:"job"`
+		Candidate []models.User `bson:"candidate"`
+	}
+	if err = cursor.All(ctx, &docs); err != nil {
+		return err
+	}
+
+	byID := make(map[bson.ObjectID]int, len(docs))
+	for i, doc := range docs {
+		byID[doc.ID] = i
+	}
+	for i := range applications {
+		d, ok := byID[applications[i].ID]
+		if !ok {
+			continue
+		}
+		doc := docs[d]
+		if len(doc.Job) > 0 {
+			applications[i].Job = &doc.Job[0]
+		}
+		if len(doc.Candidate) > 0 {
+			candidate := doc.Candidate[0].ToResponse()
+			applications[i].Candidate = &candidate
+		}
+	}
+	return nil
+}
+
+// CountByStage counts the applications to a job by pipeline stage key. Applications in no stage are
+// counted under the empty key.
+func (r *ApplicationRepository) CountByStage(ctx context.Context, jobID string) (map[string]int64, error) {
+	objID, err := bson.ObjectIDFromHex(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: notDeleted(bson.M{"job_id": objID})}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"$ifNull": bson.A{"$stage", ""}},
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var docs []struct {
+		Stage string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(docs))
+	for _, doc := range docs {
+		counts[doc.Stage] = doc.Count
+	}
+	return counts, nil
+}
+
+// GetLatestAttempt retrieves the candidate's last application to a job, deleted or not
+func (r *ApplicationRepository) GetLatestAttempt(ctx context.Context, jobID, userID string) (*models.Application, error) {
+	jobObjID, err := bson.ObjectIDFromHex(jobID)
+	if err != nil {
+		return nil, err
+	}
+	userObjID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "attempt", Value: -1}})
+	var application models.Application
+	err = r.collection.FindOne(ctx, bson.M{"job_id": jobObjID, "user_id": userObjID}, opts).Decode(&application)
+	if err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+// Create inserts a new application. It returns interfaces.ErrDuplicateKey when the candidate already
+// has an application to the job with the same attempt.
 func (r *ApplicationRepository) Create(ctx context.Context, application *models.Application) error {
 	result, err := r.collection.InsertOne(ctx, application)
+	if mongo.IsDuplicateKeyError(err) {
+		return interfaces.ErrDuplicateKey
+	}
 	if err != nil {
 		return err
 	}
@@ -163,39 +292,138 @@ func (r *ApplicationRepository) Create(ctx context.Context, application *models.
 	return nil
 }
 
-// UpdateStatus updates the status of an application
-func (r *ApplicationRepository) UpdateStatus(ctx context.Context, id string, status string) error {
-	if status == "" {
-		return mongo.ErrNoDocuments
-	}
+// UpdateStatus moves an application still in status from to the status, stage, recruiter note,
+// rejection and withdrawal reasons, closed time and update audit fields of application, appends
+// change to its history and returns the updated application. It returns mongo.ErrNoDocuments when
+// the application does not exist or is no longer in status from.
+func (r *ApplicationRepository) UpdateStatus(ctx context.Context, id string, from string, application *models.Application, change models.ApplicationStatusChange) (*models.Application, error) {
 	objID, err := bson.ObjectIDFromHex(id)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	_, err = r.collection.UpdateOne(
-		ctx,
-		bson.M{"_id": objID},
-		bson.M{"$set": bson.M{"status": status}},
-	)
-	return err
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated models.Application
+	err = r.collection.FindOneAndUpdate(ctx, notDeleted(bson.M{"_id": objID, "status": from}), statusUpdate(application, change), opts).Decode(&updated)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
 }
 
-// Delete removes an application by ID
-func (r *ApplicationRepository) Delete(ctx context.Context, id string) error {
-	objID, err := bson.ObjectIDFromHex(id)
+// BulkUpdate applies the updates in one unordered bulk write and returns the IDs of the applications
+// it updated; status changes skip applications that are no longer in their FromStatus. All updates
+// must carry the same update time and author, which tell the updated applications apart when some
+// were skipped.
+func (r *ApplicationRepository) BulkUpdate(ctx context.Context, updates []models.ApplicationBulkUpdate) ([]string, error) {
+	if len(updates) == 0 {
+		return []string{}, nil
+	}
+
+	ids := make([]bson.ObjectID, 0, len(updates))
+	writes := make([]mongo.WriteModel, 0, len(updates))
+	for _, u := range updates {
+		application := u.Application
+		ids = append(ids, application.ID)
+
+		filter := bson.M{"_id": application.ID}
+		var update bson.M
+		if u.Change != nil {
+			filter["status"] = u.FromStatus
+			update = statusUpdate(application, *u.Change)
+		} else {
+			update = bson.M{"$set": bson.M{
+				"updated_time": application.UpdatedTime,
+				"updated_by":   application.UpdatedBy,
+			}}
+		}
+		if len(u.Tags) > 0 {
+			update["$addToSet"] = bson.M{"tags": bson.M{"$each": u.Tags}}
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().SetFilter(notDeleted(filter)).SetUpdate(update))
+	}
+
+	result, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if result.MatchedCount == int64(len(updates)) {
+		return hexIDs(ids), nil
 	}
 
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
+	// Some applications were skipped: find those the write stamped
+	first := updates[0].Application
+	filter := bson.M{"_id": bson.M{"$in": ids}, "updated_time": first.UpdatedTime, "updated_by": first.UpdatedBy}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
 
-	if result.DeletedCount == 0 {
-		return mongo.ErrNoDocuments
+	var stamped []struct {
+		ID bson.ObjectID `bson:"_id"`
 	}
+	if err = cursor.All(ctx, &stamped); err != nil {
+		return nil, err
+	}
+	updated := make([]string, 0, len(stamped))
+	for _, s := range stamped {
+		updated = append(updated, s.ID.Hex())
+	}
+	return updated, nil
+}
 
-	return nil
+// hexIDs returns the hex strings of ids
+func hexIDs(ids []bson.ObjectID) []string {
+	hex := make([]string, len(ids))
+	for i, id := range ids {
+		hex[i] = id.Hex()
+	}
+	return hex
+}
+
+// statusUpdate sets the status, stage, recruiter note, rejection and withdrawal reasons, closed time
+// and update audit fields of application and appends change to the history
+func statusUpdate(application *models.Application, change models.ApplicationStatusChange) bson.M {
+	set := bson.M{
+		"status":         application.Status,
+		"recruiter_note": application.RecruiterNote,
+		"updated_time":   application.UpdatedTime,
+		"updated_by":     application.UpdatedBy,
+	}
+	unset := bson.M{}
+	if application.Stage != "" {
+		set["stage"] = application.Stage
+	} else {
+		unset["stage"] = ""
+	}
+	if application.RejectionReason != "" {
+		set["rejection_reason"] = application.RejectionReason
+	} else {
+		unset["rejection_reason"] = ""
+	}
+	if application.WithdrawalReason != "" {
+		set["withdrawal_reason"] = application.WithdrawalReason
+	}
+	if application.ClosedTime != nil {
+		set["closed_time"] = application.ClosedTime
+	}
+	update := bson.M{"$set": set, "$push": bson.M{"history": change}}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	return update
+}
+
+// Delete soft deletes a application by ID, applying the delete policies of its relations
+func (r *ApplicationRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	return r.deleter.Delete(ctx, "applications", id, deletedBy)
+}
+
+// Restore undoes the soft delete of a application and of the records deleted along with it
+func (r *ApplicationRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	return r.deleter.Restore(ctx, "applications", id, restored)
 }
@@ -12,18 +12,20 @@ import (
 
 type ArticleRepository struct {
 	collection *mongo.Collection
+	deleter    *Deleter
 }
 
-func NewArticleRepository(db *mongo.Database) *ArticleRepository {
+func NewArticleRepository(db *mongo.Database, deleter *Deleter) *ArticleRepository {
 	return &ArticleRepository{
 		collection: db.Collection("articles"),
+		deleter:    deleter,
 	}
 }
 
 func (r *ArticleRepository) GetAll(ctx context.Context, page, limit int, filters map[string]string, sort, order string) ([]models.Article, int64, error) {
 	pagination := helpers.NewPagination(page, limit)
 
-	filter := bson.M{}
+	filter := listFilter(filters)
 	if name, exists := filters["name"]; exists && name != "" {
 		filter["title"] = bson.M{"$regex": name, "$options": "i"}
 	}
@@ -76,7 +78,7 @@ func (r *ArticleRepository) GetByID(ctx context.Context, id string) (*models.Art
 	}
 
 	var article models.Article
-	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&article)
+	err = r.collection.FindOne(ctx, notDeleted(bson.M{"_id": objID})).Decode(&article)
 	if err != nil {
 		return nil, err
 	}
@@ -115,27 +117,19 @@ func (r *ArticleRepository) Update(ctx context.Context, id string, article *mode
 
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 	var updated models.Article
-	err = r.collection.FindOneAndUpdate(ctx, bson.M{"_id": objID}, update, opts).Decode(&updated)
+	err = r.collection.FindOneAndUpdate(ctx, notDeleted(bson.M{"_id": objID}), update, opts).Decode(&updated)
 	if err != nil {
 		return nil, err
 	}
 	return &updated, nil
 }
 
-func (r *ArticleRepository) Delete(ctx context.Context, id string) error {
-	objID, err := bson.ObjectIDFromHex(id)
-	if err != nil {
-		return err
-	}
-
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
-	if err != nil {
-		return err
-	}
-
-	if result.DeletedCount == 0 {
-		return mongo.ErrNoDocuments
-	}
+// Delete soft deletes a article by ID, applying the delete policies of its relations
+func (r *ArticleRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	return r.deleter.Delete(ctx, "articles", id, deletedBy)
+}
 
-	return nil
+// Restore undoes the soft delete of a article and of the records deleted along with it
+func (r *ArticleRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	return r.deleter.Restore(ctx, "articles", id, restored)
 }
@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"go-mongodb-api/helpers"
+	"go-mongodb-api/interfaces"
 	"go-mongodb-api/models"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -12,12 +13,14 @@ import (
 
 type CandidateSkillRepository struct {
 	collection *mongo.Collection
+	deleter    *Deleter
 }
 
 // NewCandidateSkillRepository creates a new candidate skill repository
-func NewCandidateSkillRepository(db *mongo.Database) *CandidateSkillRepository {
+func NewCandidateSkillRepository(db *mongo.Database, deleter *Deleter) *CandidateSkillRepository {
 	return &CandidateSkillRepository{
 		collection: db.Collection("candidateskills"),
+		deleter:    deleter,
 	}
 }
 
@@ -26,7 +29,7 @@ func (r *CandidateSkillRepository) GetAll(ctx context.Context, page, limit int,
 	pagination := helpers.NewPagination(page, limit)
 
 	// Build filter query
-	filter := bson.M{}
+	filter := listFilter(filters)
 
 	// Search by user_id (exact match)
 	if userID, exists := filters["user_id"]; exists && userID != "" {
@@ -98,7 +101,7 @@ func (r *CandidateSkillRepository) GetByID(ctx context.Context, id string) (*mod
 	}
 
 	var candidateSkill models.CandidateSkill
-	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&candidateSkill)
+	err = r.collection.FindOne(ctx, notDeleted(bson.M{"_id": objID})).Decode(&candidateSkill)
 	if err != nil {
 		return nil, err
 	}
@@ -111,7 +114,7 @@ func (r *CandidateSkillRepository) GetByUserID(ctx context.Context, userID strin
 	if err != nil {
 		return nil, err
 	}
-	cursor, err := r.collection.Find(ctx, bson.M{"user_id": objID})
+	cursor, err := r.collection.Find(ctx, notDeleted(bson.M{"user_id": objID}))
 	if err != nil {
 		return nil, err
 	}
@@ -127,9 +130,13 @@ func (r *CandidateSkillRepository) GetByUserID(ctx context.Context, userID strin
 	return candidateSkills, nil
 }
 
-// Create inserts a new candidate skill
+// Create inserts a new candidate skill. It returns interfaces.ErrDuplicateKey when the candidate
+// already has the skill.
 func (r *CandidateSkillRepository) Create(ctx context.Context, candidateSkill *models.CandidateSkill) error {
 	result, err := r.collection.InsertOne(ctx, candidateSkill)
+	if mongo.IsDuplicateKeyError(err) {
+		return interfaces.ErrDuplicateKey
+	}
 	if err != nil {
 		return err
 	}
@@ -141,8 +148,8 @@ func (r *CandidateSkillRepository) Create(ctx context.Context, candidateSkill *m
 	return nil
 }
 
-// UpdateProficiencyLevel updates the proficiency level of a candidate skill
-func (r *CandidateSkillRepository) UpdateProficiencyLevel(ctx context.Context, id string, proficiencyLevel string) error {
+// UpdateProficiencyLevel updates the proficiency level of a candidate skill and records who changed it
+func (r *CandidateSkillRepository) UpdateProficiencyLevel(ctx context.Context, id string, proficiencyLevel string, updated models.Audit) error {
 	if proficiencyLevel == "" {
 		return mongo.ErrNoDocuments
 	}
@@ -153,27 +160,18 @@ func (r *CandidateSkillRepository) UpdateProficiencyLevel(ctx context.Context, i
 
 	_, err = r.collection.UpdateOne(
 		ctx,
-		bson.M{"_id": objID},
-		bson.M{"$set": bson.M{"proficiency_level": proficiencyLevel}},
+		notDeleted(bson.M{"_id": objID}),
+		bson.M{"$set": bson.M{"proficiency_level": proficiencyLevel, "updated_time": updated.UpdatedTime, "updated_by": updated.UpdatedBy}},
 	)
 	return err
 }
 
-// Delete removes a candidate skill by ID
-func (r *CandidateSkillRepository) Delete(ctx context.Context, id string) error {
-	objID, err := bson.ObjectIDFromHex(id)
-	if err != nil {
-		return err
-	}
-
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
-	if err != nil {
-		return err
-	}
-
-	if result.DeletedCount == 0 {
-		return mongo.ErrNoDocuments
-	}
+// Delete soft deletes a candidate skill by ID, applying the delete policies of its relations
+func (r *CandidateSkillRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	return r.deleter.Delete(ctx, "candidateskills", id, deletedBy)
+}
 
-	return nil
+// Restore undoes the soft delete of a candidate skill and of the records deleted along with it
+func (r *CandidateSkillRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	return r.deleter.Restore(ctx, "candidateskills", id, restored)
 }
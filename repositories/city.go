@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"go-mongodb-api/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// caseInsensitive is the collation names are matched with, shared with the name indexes that
+// serve those lookups
+var caseInsensitive = &options.Collation{Locale: "en", Strength: 2}
+
+type CityRepository struct {
+	collection *mongo.Collection
+}
+
+// NewCityRepository creates a new city repository
+func NewCityRepository(db *mongo.Database) *CityRepository {
+	return &CityRepository{
+		collection: db.Collection("cities"),
+	}
+}
+
+// FindByName retrieves the most populous city with the given name, ignoring case, in the country with
+// countryID or in any country when it is empty
+func (r *CityRepository) FindByName(ctx context.Context, name string, countryID string) (*models.City, error) {
+	filter := bson.M{"name": name}
+	if countryID != "" {
+		objID, err := bson.ObjectIDFromHex(countryID)
+		if err != nil {
+			return nil, err
+		}
+		filter["country_id"] = objID
+	}
+	opts := options.FindOne().
+		SetCollation(caseInsensitive).
+		SetSort(bson.D{{Key: "population", Value: -1}})
+
+	var city models.City
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&city)
+	if err != nil {
+		return nil, err
+	}
+	return &city, nil
+}
+
+// Upsert creates or replaces the city with the same name and country and reports whether it was
+// created
+func (r *CityRepository) Upsert(ctx context.Context, city *models.City) (bool, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"name":         city.Name,
+			"location":     city.Location,
+			"population":   city.Population,
+			"updated_time": city.UpdatedTime,
+			"updated_by":   city.UpdatedBy,
+		},
+		"$setOnInsert": bson.M{
+			"created_time": city.UpdatedTime,
+			"created_by":   city.UpdatedBy,
+		},
+	}
+	opts := options.UpdateOne().SetUpsert(true).SetCollation(caseInsensitive)
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"name": city.Name, "country_id": city.CountryID}, update, opts)
+	if err != nil {
+		return false, err
+	}
+	return result.UpsertedCount > 0, nil
+}
@@ -12,18 +12,20 @@ import (
 
 type CountryRepository struct {
 	collection *mongo.Collection
+	deleter    *Deleter
 }
 
-func NewCountryRepository(db *mongo.Database) *CountryRepository {
+func NewCountryRepository(db *mongo.Database, deleter *Deleter) *CountryRepository {
 	return &CountryRepository{
 		collection: db.Collection("countries"),
+		deleter:    deleter,
 	}
 }
 
 func (r *CountryRepository) GetAll(ctx context.Context, page, limit int, filters map[string]string, sort, order string) ([]models.Country, int64, error) {
 	pagination := helpers.NewPagination(page, limit)
 
-	filter := bson.M{}
+	filter := listFilter(filters)
 	if name, exists := filters["name"]; exists && name != "" {
 		filter["name"] = bson.M{"$regex": name, "$options": "i"}
 	}
@@ -76,7 +78,18 @@ func (r *CountryRepository) GetByID(ctx context.Context, id string) (*models.Cou
 	}
 
 	var country models.Country
-	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&country)
+	err = r.collection.FindOne(ctx, notDeleted(bson.M{"_id": objID})).Decode(&country)
+	if err != nil {
+		return nil, err
+	}
+	return &country, nil
+}
+
+// GetByName retrieves a country by name, ignoring case
+func (r *CountryRepository) GetByName(ctx context.Context, name string) (*models.Country, error) {
+	var country models.Country
+	opts := options.FindOne().SetCollation(caseInsensitive)
+	err := r.collection.FindOne(ctx, notDeleted(bson.M{"name": name}), opts).Decode(&country)
 	if err != nil {
 		return nil, err
 	}
@@ -112,27 +125,19 @@ func (r *CountryRepository) Update(ctx context.Context, id string, country *mode
 
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 	var updated models.Country
-	err = r.collection.FindOneAndUpdate(ctx, bson.M{"_id": objID}, update, opts).Decode(&updated)
+	err = r.collection.FindOneAndUpdate(ctx, notDeleted(bson.M{"_id": objID}), update, opts).Decode(&updated)
 	if err != nil {
 		return nil, err
 	}
 	return &updated, nil
 }
 
-func (r *CountryRepository) Delete(ctx context.Context, id string) error {
-	objID, err := bson.ObjectIDFromHex(id)
-	if err != nil {
-		return err
-	}
-
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
-	if err != nil {
-		return err
-	}
-
-	if result.DeletedCount == 0 {
-		return mongo.ErrNoDocuments
-	}
+// Delete soft deletes a country by ID, applying the delete policies of its relations
+func (r *CountryRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	return r.deleter.Delete(ctx, "countries", id, deletedBy)
+}
 
-	return nil
+// Restore undoes the soft delete of a country and of the records deleted along with it
+func (r *CountryRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	return r.deleter.Restore(ctx, "countries", id, restored)
 }
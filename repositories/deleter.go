@@ -0,0 +1,324 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"go-mongodb-api/interfaces"
+	"go-mongodb-api/models"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// defaultRelations lists every reference between collections with its default delete policy.
+// Reference data (categories, skills, job types, countries, education levels, location availabilities)
+// is protected, while records that only make sense together with their parent are removed along with it.
+var defaultRelations = []models.Relation{
+	{Parent: "jobcategories", Collection: "jobs", Field: "category_id", Policy: models.DeleteRestrict},
+	{Parent: "jobs", Collection: "applications", Field: "job_id", Policy: models.DeleteCascade},
+	{Parent: "jobs", Collection: "jobskills", Field: "job_id", Policy: models.DeleteCascade},
+	{Parent: "users", Collection: "jobs", Field: "user_id", Policy: models.DeleteRestrict},
+	{Parent: "users", Collection: "applications", Field: "user_id", Policy: models.DeleteCascade},
+	{Parent: "users", Collection: "candidateskills", Field: "user_id", Policy: models.DeleteCascade},
+	{Parent: "users", Collection: "sessions", Field: "user_id", Policy: models.DeleteCascade},
+	{Parent: "users", Collection: "usertokens", Field: "user_id", Policy: models.DeleteCascade},
+	{Parent: "skills", Collection: "jobskills", Field: "skill_id", Policy: models.DeleteRestrict},
+	{Parent: "skills", Collection: "candidateskills", Field: "skill_id", Policy: models.DeleteRestrict},
+	{Parent: "countries", Collection: "cities", Field: "country_id", Policy: models.DeleteRestrict},
+	{Parent: "jobtypes", Collection: "jobs", Field: "job_type_id", Policy: models.DeleteRestrict},
+	{Parent: "countries", Collection: "jobs", Field: "country_id", Policy: models.DeleteRestrict},
+	{Parent: "educationlevels", Collection: "jobs", Field: "education_level_id", Policy: models.DeleteRestrict},
+	{Parent: "locationavailabilities", Collection: "jobs", Field: "location_availability_id", Policy: models.DeleteRestrict},
+}
+
+// DeleteRelations returns the default relations with the given policies applied, keyed by relation
+// name (e.g. "jobs.applications")
+func DeleteRelations(policies map[string]string) ([]models.Relation, error) {
+	relations := make([]models.Relation, len(defaultRelations))
+	copy(relations, defaultRelations)
+
+	for name, policy := range policies {
+		if policy != models.DeleteRestrict && policy != models.DeleteCascade && policy != models.DeleteNullify {
+			return nil, fmt.Errorf("invalid delete policy %q for %s: must be restrict, cascade or nullify", policy, name)
+		}
+		found := false
+		for i := range relations {
+			if relations[i].Name() == name {
+				relations[i].Policy = policy
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown relation %q", name)
+		}
+	}
+	return relations, nil
+}
+
+// notDeleted adds the condition that the document has not been soft deleted to filter
+func notDeleted(filter bson.M) bson.M {
+	filter["deleted_time"] = bson.M{"$exists": false}
+	return filter
+}
+
+// listFilter returns the base filter of a GetAll query: documents that have not been soft deleted,
+// or every document when the list filters set include_deleted to "true"
+func listFilter(filters map[string]string) bson.M {
+	if filters[models.FilterIncludeDeleted] == "true" {
+		return bson.M{}
+	}
+	return notDeleted(bson.M{})
+}
+
+// Deleter soft deletes and restores records together with the documents that reference them,
+// following the delete policy of each relation. Every delete and restore runs in a transaction, so
+// a refused or failed one leaves no partial changes behind.
+type Deleter struct {
+	db        *mongo.Database
+	relations []models.Relation
+}
+
+// NewDeleter creates a new deleter for the given relations
+func NewDeleter(db *mongo.Database, relations []models.Relation) *Deleter {
+	return &Deleter{
+		db:        db,
+		relations: relations,
+	}
+}
+
+// Delete soft deletes the document with the given ID from collection and applies the delete
+// policies to its dependents: restricted ones block the delete and cascaded ones are soft deleted
+// with the same deleted_time, so Restore can bring them back together. Nullified references are
+// only cleared when the document is purged. It returns mongo.ErrNoDocuments when the document does
+// not exist and a *models.DependentsError when a restricted relation still has dependents.
+func (d *Deleter) Delete(ctx context.Context, collection string, id string, deletedBy string) error {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	return d.transaction(ctx, func(ctx context.Context) error {
+		now := time.Now()
+		result, err := d.db.Collection(collection).UpdateOne(ctx,
+			notDeleted(bson.M{"_id": objID}),
+			bson.M{"$set": bson.M{"deleted_time": now, "deleted_by": deletedBy}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return mongo.ErrNoDocuments
+		}
+
+		dependents := map[string]int64{}
+		if err := d.deleteDependents(ctx, collection, []bson.ObjectID{objID}, now, deletedBy, dependents); err != nil {
+			return err
+		}
+		if len(dependents) > 0 {
+			// Returning an error aborts the transaction, undoing the deletes made so far
+			return &models.DependentsError{Dependents: dependents}
+		}
+		return nil
+	})
+}
+
+// deleteDependents applies the policy of every relation of collection to the live documents
+// referencing ids. Restricted dependents are counted into dependents instead of being changed.
+func (d *Deleter) deleteDependents(ctx context.Context, collection string, ids []bson.ObjectID, now time.Time, deletedBy string, dependents map[string]int64) error {
+	for _, relation := range d.relations {
+		if relation.Parent != collection {
+			continue
+		}
+
+		children := d.db.Collection(relation.Collection)
+		filter := notDeleted(bson.M{relation.Field: bson.M{"$in": ids}})
+
+		switch relation.Policy {
+		case models.DeleteRestrict:
+			count, err := children.CountDocuments(ctx, filter)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				dependents[relation.Collection] += count
+			}
+		case models.DeleteCascade:
+			childIDs, err := findIDs(ctx, children, filter)
+			if err != nil {
+				return err
+			}
+			if len(childIDs) == 0 {
+				continue
+			}
+			byID := bson.M{"_id": bson.M{"$in": childIDs}}
+			if slices.Contains(models.SoftDeleteCollections, relation.Collection) {
+				_, err = children.UpdateMany(ctx, byID, bson.M{"$set": bson.M{"deleted_time": now, "deleted_by": deletedBy}})
+			} else {
+				_, err = children.DeleteMany(ctx, byID)
+			}
+			if err != nil {
+				return err
+			}
+			if err := d.deleteDependents(ctx, relation.Collection, childIDs, now, deletedBy, dependents); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Restore undoes the soft delete of the document with the given ID and of the dependents that were
+// cascaded with it. It returns mongo.ErrNoDocuments when no deleted document has the ID,
+// models.ErrParentDeleted when a record the document references is still deleted and
+// interfaces.ErrDuplicateKey when a live document has since taken one of their unique values.
+func (d *Deleter) Restore(ctx context.Context, collection string, id string, restored models.Audit) error {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	err = d.transaction(ctx, func(ctx context.Context) error {
+		var doc bson.M
+		err := d.db.Collection(collection).FindOne(ctx, bson.M{"_id": objID, "deleted_time": bson.M{"$exists": true}}).Decode(&doc)
+		if err != nil {
+			return err
+		}
+
+		for _, relation := range d.relations {
+			parentID, ok := doc[relation.Field].(bson.ObjectID)
+			if relation.Collection != collection || !ok {
+				continue
+			}
+			count, err := d.db.Collection(relation.Parent).CountDocuments(ctx, bson.M{"_id": parentID, "deleted_time": bson.M{"$exists": true}})
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return models.ErrParentDeleted
+			}
+		}
+
+		deletedTime := doc["deleted_time"]
+		if err := d.restoreDocuments(ctx, collection, bson.M{"_id": objID}, restored); err != nil {
+			return err
+		}
+		return d.restoreDependents(ctx, collection, []bson.ObjectID{objID}, deletedTime, restored)
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return interfaces.ErrDuplicateKey
+	}
+	return err
+}
+
+// restoreDependents restores the documents cascaded from ids, recognised by sharing the deleted_time
+// of the record they were deleted with
+func (d *Deleter) restoreDependents(ctx context.Context, collection string, ids []bson.ObjectID, deletedTime interface{}, restored models.Audit) error {
+	for _, relation := range d.relations {
+		if relation.Parent != collection || relation.Policy != models.DeleteCascade || !slices.Contains(models.SoftDeleteCollections, relation.Collection) {
+			continue
+		}
+
+		children := d.db.Collection(relation.Collection)
+		childIDs, err := findIDs(ctx, children, bson.M{relation.Field: bson.M{"$in": ids}, "deleted_time": deletedTime})
+		if err != nil {
+			return err
+		}
+		if len(childIDs) == 0 {
+			continue
+		}
+		if err := d.restoreDocuments(ctx, relation.Collection, bson.M{"_id": bson.M{"$in": childIDs}}, restored); err != nil {
+			return err
+		}
+		if err := d.restoreDependents(ctx, relation.Collection, childIDs, deletedTime, restored); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreDocuments undoes the soft delete of the documents matching filter, recording the update audit
+// fields stamped by the service
+func (d *Deleter) restoreDocuments(ctx context.Context, collection string, filter bson.M, restored models.Audit) error {
+	_, err := d.db.Collection(collection).UpdateMany(ctx, filter, bson.M{
+		"$unset": bson.M{"deleted_time": "", "deleted_by": ""},
+		"$set":   bson.M{"updated_time": restored.UpdatedTime, "updated_by": restored.UpdatedBy},
+	})
+	return err
+}
+
+// Purge permanently removes the documents that were soft deleted at or before cutoff, clearing the
+// references to them on nullify relations, and returns the number removed per collection. Each
+// collection is purged separately; running Purge again finishes an interrupted run.
+func (d *Deleter) Purge(ctx context.Context, cutoff time.Time) (map[string]int64, error) {
+	purged := map[string]int64{}
+	for _, collection := range models.SoftDeleteCollections {
+		ids, err := findIDs(ctx, d.db.Collection(collection), bson.M{"deleted_time": bson.M{"$lte": cutoff}})
+		if err != nil {
+			return purged, err
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		for _, relation := range d.relations {
+			if relation.Parent != collection || relation.Policy != models.DeleteNullify {
+				continue
+			}
+			_, err := d.db.Collection(relation.Collection).UpdateMany(ctx,
+				bson.M{relation.Field: bson.M{"$in": ids}},
+				bson.M{"$unset": bson.M{relation.Field: ""}},
+			)
+			if err != nil {
+				return purged, err
+			}
+		}
+
+		result, err := d.db.Collection(collection).DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+		if err != nil {
+			return purged, err
+		}
+		purged[collection] = result.DeletedCount
+	}
+	return purged, nil
+}
+
+// transaction runs fn in a MongoDB transaction, aborting it when fn returns an error
+func (d *Deleter) transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := d.db.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(ctx context.Context) (interface{}, error) {
+		return nil, fn(ctx)
+	})
+	return err
+}
+
+// findIDs returns the IDs of the documents matching filter
+func findIDs(ctx context.Context, collection *mongo.Collection, filter bson.M) ([]bson.ObjectID, error) {
+	cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var docs []struct {
+		ID bson.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	ids := make([]bson.ObjectID, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	return ids, nil
+}
@@ -12,18 +12,20 @@ import (
 
 type EducationLevelRepository struct {
 	collection *mongo.Collection
+	deleter    *Deleter
 }
 
-func NewEducationLevelRepository(db *mongo.Database) *EducationLevelRepository {
+func NewEducationLevelRepository(db *mongo.Database, deleter *Deleter) *EducationLevelRepository {
 	return &EducationLevelRepository{
 		collection: db.Collection("educationlevels"),
+		deleter:    deleter,
 	}
 }
 
 func (r *EducationLevelRepository) GetAll(ctx context.Context, page, limit int, filters map[string]string, sort, order string) ([]models.EducationLevel, int64, error) {
 	pagination := helpers.NewPagination(page, limit)
 
-	filter := bson.M{}
+	filter := listFilter(filters)
 	if title, exists := filters["title"]; exists && title != "" {
 		filter["title"] = bson.M{"$regex": title, "$options": "i"}
 	}
@@ -76,7 +78,7 @@ func (r *EducationLevelRepository) GetByID(ctx context.Context, id string) (*mod
 	}
 
 	var educationLevel models.EducationLevel
-	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&educationLevel)
+	err = r.collection.FindOne(ctx, notDeleted(bson.M{"_id": objID})).Decode(&educationLevel)
 	if err != nil {
 		return nil, err
 	}
@@ -112,27 +114,19 @@ func (r *EducationLevelRepository) Update(ctx context.Context, id string, educat
 
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 	var updated models.EducationLevel
-	err = r.collection.FindOneAndUpdate(ctx, bson.M{"_id": objID}, update, opts).Decode(&updated)
+	err = r.collection.FindOneAndUpdate(ctx, notDeleted(bson.M{"_id": objID}), update, opts).Decode(&updated)
 	if err != nil {
 		return nil, err
 	}
 	return &updated, nil
 }
 
-func (r *EducationLevelRepository) Delete(ctx context.Context, id string) error {
-	objID, err := bson.ObjectIDFromHex(id)
-	if err != nil {
-		return err
-	}
-
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
-	if err != nil {
-		return err
-	}
-
-	if result.DeletedCount == 0 {
-		return mongo.ErrNoDocuments
-	}
+// Delete soft deletes a education level by ID, applying the delete policies of its relations
+func (r *EducationLevelRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	return r.deleter.Delete(ctx, "educationlevels", id, deletedBy)
+}
 
-	return nil
+// Restore undoes the soft delete of a education level and of the records deleted along with it
+func (r *EducationLevelRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	return r.deleter.Restore(ctx, "educationlevels", id, restored)
 }
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"go-mongodb-api/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type ExchangeRateRepository struct {
+	collection *mongo.Collection
+}
+
+// NewExchangeRateRepository creates a new exchange rate repository
+func NewExchangeRateRepository(db *mongo.Database) *ExchangeRateRepository {
+	return &ExchangeRateRepository{
+		collection: db.Collection("exchangerates"),
+	}
+}
+
+// GetAll retrieves all exchange rates ordered by currency
+func (r *ExchangeRateRepository) GetAll(ctx context.Context) ([]models.ExchangeRate, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "currency", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	rates := []models.ExchangeRate{}
+	if err = cursor.All(ctx, &rates); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// GetByCurrency retrieves the exchange rate of a currency
+func (r *ExchangeRateRepository) GetByCurrency(ctx context.Context, currency string) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	err := r.collection.FindOne(ctx, bson.M{"currency": currency}).Decode(&rate)
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// Upsert creates or replaces the exchange rate of a currency and returns the stored document
+func (r *ExchangeRateRepository) Upsert(ctx context.Context, rate *models.ExchangeRate) (*models.ExchangeRate, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"rate":         rate.Rate,
+			"updated_time": rate.UpdatedTime,
+			"updated_by":   rate.UpdatedBy,
+		},
+		"$setOnInsert": bson.M{
+			"created_time": rate.UpdatedTime,
+			"created_by":   rate.UpdatedBy,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var stored models.ExchangeRate
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"currency": rate.Currency}, update, opts).Decode(&stored)
+	if err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// Delete removes the exchange rate of a currency. It returns mongo.ErrNoDocuments when the currency
+// has no rate.
+func (r *ExchangeRateRepository) Delete(ctx context.Context, currency string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"currency": currency})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// matchNotDeleted is the $match stage keeping the documents that have not been soft deleted
+var matchNotDeleted = bson.D{{Key: "$match", Value: notDeleted(bson.M{})}}
+
+// userResponseFields projects a user onto the fields of models.UserResponse, leaving out the
+// password and everything about two-factor authentication
+var userResponseFields = bson.M{
+	"first_name": 1, "last_name": 1, "email": 1, "phone": 1, "role": 1, "company_name": 1,
+	"verified": 1, "active": 1, "terms_accepted": 1, "last_terms_accepted": 1, "last_login_time": 1,
+	"created_time": 1, "updated_time": 1, "created_by": 1, "updated_by": 1,
+}
+
+// lookup returns a $lookup stage collecting into the array field as the documents of from whose _id
+// is localField and that have not been soft deleted, run through pipeline
+func lookup(from, localField, as string, pipeline ...bson.D) bson.D {
+	return bson.D{{Key: "$lookup", Value: bson.M{
+		"from":         from,
+		"localField":   localField,
+		"foreignField": "_id",
+		"pipeline":     append(mongo.Pipeline{matchNotDeleted}, pipeline...),
+		"as":           as,
+	}}}
+}
+
+// lookupUser returns a $lookup stage collecting the user referenced by localField into as, with only
+// the fields that are safe to return
+func lookupUser(localField, as string) bson.D {
+	return lookup("users", localField, as, bson.D{{Key: "$project", Value: userResponseFields}})
+}
+
+// expandPipeline returns the aggregation resolving the lookups for the documents with the given IDs,
+// keeping only the looked-up fields
+func expandPipeline(ids []bson.ObjectID, lookups []bson.D, fields []string) mongo.Pipeline {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{"_id": bson.M{"$in": ids}}}}}
+	pipeline = append(pipeline, lookups...)
+	project := bson.M{}
+	for _, field := range fields {
+		project[field] = 1
+	}
+	return append(pipeline, bson.D{{Key: "$project", Value: project}})
+}
@@ -2,8 +2,13 @@ package repositories
 
 import (
 	"context"
+	"fmt"
 	"go-mongodb-api/helpers"
 	"go-mongodb-api/models"
+	"math"
+	"regexp"
+	"strconv"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -12,28 +17,33 @@ import (
 
 type JobRepository struct {
 	collection *mongo.Collection
+	deleter    *Deleter
 }
 
 // NewJobRepository creates a new job repository
-func NewJobRepository(db *mongo.Database) *JobRepository {
+func NewJobRepository(db *mongo.Database, deleter *Deleter) *JobRepository {
 	return &JobRepository{
 		collection: db.Collection("jobs"),
+		deleter:    deleter,
 	}
 }
 
-// GetAll retrieves all jobs with pagination, filtering, and sorting
+// GetAll retrieves all jobs with pagination, filtering, and sorting. The "q" filter runs a
+// full-text search in MongoDB $search syntax; sort "relevance" orders its matches by score. The "lat"
+// and "lng" filters search by location instead, ordering by distance unless sorted otherwise.
 func (r *JobRepository) GetAll(ctx context.Context, page, limit int, filters map[string]string, sort, order string) ([]models.Job, int64, error) {
 	// Create pagination instance with validation
 	pagination := helpers.NewPagination(page, limit)
 
 	// Build filter query
-	filter := bson.M{}
-	searchableFields := []string{"title", "description", "location", "job_type", "status"}
-	for _, field := range searchableFields {
-		if value, exists := filters[field]; exists && value != "" {
-			// Case-insensitive partial match using regex
-			filter[field] = bson.M{"$regex": value, "$options": "i"}
-		}
+	filter, err := jobFilter(filters)
+	if err != nil {
+		return nil, 0, err
+	}
+	search := filters[models.FilterSearch]
+	geo, err := parseGeoSearch(filters)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	// Count total documents matching filter
@@ -42,42 +52,226 @@ func (r *JobRepository) GetAll(ctx context.Context, page, limit int, filters map
 		return nil, 0, err
 	}
 
-	// Build sort options
-	sortableFields := []string{"title", "description", "location", "job_type", "status", "created_time"}
-	sortField := "created_time"
-	if sort != "" {
-		for _, field := range sortableFields {
-			if field == sort {
-				sortField = sort
-				break
+	sortOrder := int32(-1) // desc
+	if order == "asc" {
+		sortOrder = 1
+	}
+
+	// Build sort options. Text matches default to relevance, best match first, and location
+	// matches to distance, nearest first.
+	var sortSpec bson.D
+	switch {
+	case search != "" && (sort == "" || sort == models.SortRelevance):
+		sortSpec = bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "created_time", Value: -1}}
+	case geo != nil && (sort == "" || sort == models.SortDistance):
+		// $geoNear returns the jobs nearest first
+	default:
+		sortableFields := []string{"title", "description", "location", "status", "created_time"}
+		sortField := "created_time"
+		if sort != "" {
+			for _, field := range sortableFields {
+				if field == sort {
+					sortField = sort
+					break
+				}
 			}
 		}
+		sortSpec = bson.D{{Key: sortField, Value: sortOrder}}
 	}
 
-	sortOrder := int32(-1) // desc
-	if order == "asc" {
-		sortOrder = 1
+	var jobs []models.Job
+	if geo != nil {
+		jobs, err = r.getAllNear(ctx, pagination, filter, geo, sortSpec)
+	} else {
+		jobs, err = r.getAll(ctx, pagination, filter, search != "", sortSpec)
 	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return jobs, total, nil
+}
 
+func (r *JobRepository) getAll(ctx context.Context, pagination *helpers.Pagination, filter bson.M, scored bool, sortSpec bson.D) ([]models.Job, error) {
 	// Query with skip, limit, and sort
 	opts := options.Find().
 		SetSkip(int64(pagination.GetSkip())).
 		SetLimit(int64(pagination.Limit)).
-		SetSort(bson.M{sortField: sortOrder})
+		SetSort(sortSpec)
+	if scored {
+		opts.SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}})
+	}
 	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer func() {
 		_ = cursor.Close(ctx)
 	}()
 
-	var jobs []models.Job
-	if err = cursor.All(ctx, &jobs); err != nil {
-		return nil, 0, err
+	// The text score is projected next to the job fields
+	var docs []struct {
+		models.Job `bson:",inline"`
+		Score      float64 `bson:"score"`
+	}
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
 	}
 
-	return jobs, total, nil
+	jobs := make([]models.Job, len(docs))
+	for i, doc := range docs {
+		jobs[i] = doc.Job
+		jobs[i].Score = doc.Score
+	}
+	return jobs, nil
+}
+
+// getAllNear lists the jobs matching filter through $geoNear, which adds the distance from the search
+// point in metres. An empty sortSpec keeps the nearest first order.
+func (r *JobRepository) getAllNear(ctx context.Context, pagination *helpers.Pagination, filter bson.M, geo *geoSearch, sortSpec bson.D) ([]models.Job, error) {
+	// $geoNear applies the radius itself; its query may not hold another condition on the key
+	query := bson.M{}
+	for key, value := range filter {
+		if key != "coordinates" {
+			query[key] = value
+		}
+	}
+	geoNear := bson.M{
+		"near":          geo.point,
+		"key":           "coordinates",
+		"distanceField": "distance",
+		"spherical":     true,
+		"query":         query,
+	}
+	if geo.radiusKm > 0 {
+		geoNear["maxDistance"] = geo.radiusKm * 1000
+	}
+
+	pipeline := mongo.Pipeline{{{Key: "$geoNear", Value: geoNear}}}
+	if len(sortSpec) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$sort", Value: sortSpec}})
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$skip", Value: int64(pagination.GetSkip())}},
+		bson.D{{Key: "$limit", Value: int64(pagination.Limit)}},
+	)
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var docs []struct {
+		models.Job `bson:",inline"`
+		Distance   float64 `bson:"distance"`
+	}
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	jobs := make([]models.Job, len(docs))
+	for i, doc := range docs {
+		jobs[i] = doc.Job
+		distanceKm := math.Round(doc.Distance/10) / 100
+		jobs[i].DistanceKm = &distanceKm
+	}
+	return jobs, nil
+}
+
+// maxLocationFacets caps the number of locations returned as facets, most common first
+const maxLocationFacets = 20
+
+// Facets counts the jobs matching filters by category, job type, location, status and salary band.
+// salaryBands holds the ascending lower bounds of the bands; each band ends where the next begins.
+func (r *JobRepository) Facets(ctx context.Context, filters map[string]string, salaryBands []int) (*models.JobFacets, error) {
+	filter, err := jobFilter(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	// A job counts towards every band its salary range overlaps
+	minField, maxField := salaryFields(filters)
+	bandCounts := bson.M{"_id": nil}
+	for i, lower := range salaryBands {
+		overlaps := bson.A{bson.M{"$gte": bson.A{"$" + maxField, lower}}}
+		if i+1 < len(salaryBands) {
+			overlaps = append(overlaps, bson.M{"$lt": bson.A{"$" + minField, salaryBands[i+1]}})
+		}
+		bandCounts[fmt.Sprintf("band%d", i)] = bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$and": overlaps}, 1, 0}}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$facet", Value: bson.M{
+			"categories": countBy(bson.M{"$toString": "$category_id"}),
+			"job_types":  countBy(bson.M{"$toString": "$job_type_id"}),
+			"locations":  append(countBy("$location"), bson.M{"$limit": maxLocationFacets}),
+			"statuses":   countBy("$status"),
+			"salary_bands": bson.A{
+				bson.M{"$group": bandCounts},
+				bson.M{"$project": bson.M{"_id": 0}},
+			},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var results []struct {
+		Categories  []models.FacetCount `bson:"categories"`
+		JobTypes    []models.FacetCount `bson:"job_types"`
+		Locations   []models.FacetCount `bson:"locations"`
+		Statuses    []models.FacetCount `bson:"statuses"`
+		SalaryBands []map[string]int64  `bson:"salary_bands"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	facets := &models.JobFacets{
+		Categories:  []models.FacetCount{},
+		JobTypes:    []models.FacetCount{},
+		Locations:   []models.FacetCount{},
+		Statuses:    []models.FacetCount{},
+		SalaryBands: make([]models.SalaryBandCount, len(salaryBands)),
+	}
+	for i, lower := range salaryBands {
+		facets.SalaryBands[i].Min = lower
+		if i+1 < len(salaryBands) {
+			upper := salaryBands[i+1]
+			facets.SalaryBands[i].Max = &upper
+		}
+	}
+	if len(results) == 0 {
+		return facets, nil
+	}
+
+	result := results[0]
+	if result.Categories != nil {
+		facets.Categories = result.Categories
+	}
+	if result.JobTypes != nil {
+		facets.JobTypes = result.JobTypes
+	}
+	if result.Locations != nil {
+		facets.Locations = result.Locations
+	}
+	if result.Statuses != nil {
+		facets.Statuses = result.Statuses
+	}
+	if len(result.SalaryBands) > 0 {
+		for i := range facets.SalaryBands {
+			facets.SalaryBands[i].Count = result.SalaryBands[0][fmt.Sprintf("band%d", i)]
+		}
+	}
+	return facets, nil
 }
 
 // GetByID retrieves a job by ID
@@ -88,7 +282,7 @@ func (r *JobRepository) GetByID(ctx context.Context, id string) (*models.Job, er
 	}
 
 	var job models.Job
-	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&job)
+	err = r.collection.FindOne(ctx, notDeleted(bson.M{"_id": objID})).Decode(&job)
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +295,7 @@ func (r *JobRepository) GetByUserID(ctx context.Context, userID string) ([]model
 	if err != nil {
 		return nil, err
 	}
-	cursor, err := r.collection.Find(ctx, bson.M{"user_id": objID})
+	cursor, err := r.collection.Find(ctx, notDeleted(bson.M{"user_id": objID}))
 	if err != nil {
 		return nil, err
 	}
@@ -117,6 +311,89 @@ func (r *JobRepository) GetByUserID(ctx context.Context, userID string) ([]model
 	return jobs, nil
 }
 
+// Expand embeds the references named in expand (models.JobExpansions) into jobs, resolving them for
+// all the jobs in one aggregation. References to soft-deleted documents are left unset.
+func (r *JobRepository) Expand(ctx context.Context, jobs []models.Job, expand []string) error {
+	if len(jobs) == 0 || len(expand) == 0 {
+		return nil
+	}
+
+	ids := make([]bson.ObjectID, len(jobs))
+	for i, job := range jobs {
+		ids[i] = job.ID
+	}
+
+	var lookups []bson.D
+	for _, field := range expand {
+		switch field {
+		case models.ExpandCategory:
+			lookups = append(lookups, lookup("jobcategories", "category_id", field))
+		case models.ExpandRecruiter:
+			lookups = append(lookups, lookupUser("user_id", field))
+		case models.ExpandSkills:
+			lookups = append(lookups, bson.D{{Key: "$lookup", Value: bson.M{
+				"from":         "jobskills",
+				"localField":   "_id",
+				"foreignField": "job_id",
+				"pipeline": mongo.Pipeline{
+					matchNotDeleted,
+					lookup("skills", "skill_id", "skill"),
+					{{Key: "$unwind", Value: "$skill"}},
+					{{Key: "$project", Value: bson.M{
+						"skill_id":                   1,
+						"name":                       "$skill.name",
+						"proficiency_level_required": 1,
+						"is_required":                1,
+					}}},
+					{{Key: "$sort", Value: bson.D{{Key: "is_required", Value: -1}, {Key: "name", Value: 1}}}},
+				},
+				"as": field,
+			}}})
+		default:
+			return fmt.Errorf("unknown job expansion %q", field)
+		}
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, expandPipeline(ids, lookups, expand))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var docs []struct {
+		ID        bson.ObjectID         `bson:"_id"`
+		Category  []models.JobCategory  `bson:"category"`
+		Recruiter []models.User         `bson:"recruiter"`
+		Skills    []models.JobSkillInfo `bson:"skills"`
+	}
+	if err = cursor.All(ctx, &docs); err != nil {
+		return err
+	}
+
+	byID := make(map[bson.ObjectID]int, len(docs))
+	for i, doc := range docs {
+		byID[doc.ID] = i
+	}
+	for i := range jobs {
+		d, ok := byID[jobs[i].ID]
+		if !ok {
+			continue
+		}
+		doc := docs[d]
+		if len(doc.Category) > 0 {
+			jobs[i].Category = &doc.Category[0]
+		}
+		if len(doc.Recruiter) > 0 {
+			recruiter := doc.Recruiter[0].ToResponse()
+			jobs[i].Recruiter = &recruiter
+		}
+		jobs[i].Skills = doc.Skills
+	}
+	return nil
+}
+
 // Create inserts a new job
 func (r *JobRepository) Create(ctx context.Context, job *models.Job) error {
 	result, err := r.collection.InsertOne(ctx, job)
@@ -131,21 +408,334 @@ func (r *JobRepository) Create(ctx context.Context, job *models.Job) error {
 	return nil
 }
 
-// Delete removes a job by ID
-func (r *JobRepository) Delete(ctx context.Context, id string) error {
+// Update replaces the editable fields of a job and returns the updated document.
+// The owner, lifecycle status and creation audit fields are never changed.
+func (r *JobRepository) Update(ctx context.Context, id string, job *models.Job) (*models.Job, error) {
 	objID, err := bson.ObjectIDFromHex(id)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	set := bson.M{
+		"title":           job.Title,
+		"description":     job.Description,
+		"category_id":     job.CategoryID,
+		"location":        job.Location,
+		"job_type_id":     job.JobTypeID,
+		"salary_min":      job.SalaryMin,
+		"salary_max":      job.SalaryMax,
+		"salary_currency": job.SalaryCurrency,
+		"salary_period":   job.SalaryPeriod,
+		"updated_time":    job.UpdatedTime,
+		"updated_by":      job.UpdatedBy,
+	}
+	unset := bson.M{}
+	if job.ClosesAt != nil {
+		set["closes_at"] = job.ClosesAt
+	} else {
+		unset["closes_at"] = ""
+	}
+	for field, value := range map[string]*bson.ObjectID{
+		"country_id":               job.CountryID,
+		"education_level_id":       job.EducationLevelID,
+		"location_availability_id": job.LocationAvailabilityID,
+	} {
+		if value != nil {
+			set[field] = value
+		} else {
+			unset[field] = ""
+		}
+	}
+	if job.Coordinates != nil {
+		set["coordinates"] = job.Coordinates
+	} else {
+		unset["coordinates"] = ""
+	}
+	if job.SalaryAnnualMin != nil && job.SalaryAnnualMax != nil {
+		set["salary_annual_min"] = job.SalaryAnnualMin
+		set["salary_annual_max"] = job.SalaryAnnualMax
+	} else {
+		unset["salary_annual_min"] = ""
+		unset["salary_annual_max"] = ""
+	}
+	if len(job.ScreeningQuestions) > 0 {
+		set["screening_questions"] = job.ScreeningQuestions
+	} else {
+		unset["screening_questions"] = ""
+	}
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
 	}
 
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
+	return r.findOneAndUpdate(ctx, notDeleted(bson.M{"_id": objID}), update)
+}
+
+// UpdateStatus writes the lifecycle fields of a job, provided its status is still from, and returns the
+// updated document. Unset time fields are removed. It returns mongo.ErrNoDocuments when the job does not
+// exist or its status has changed in the meantime.
+func (r *JobRepository) UpdateStatus(ctx context.Context, id string, from string, job *models.Job) (*models.Job, error) {
+	objID, err := bson.ObjectIDFromHex(id)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if result.DeletedCount == 0 {
-		return mongo.ErrNoDocuments
+	set := bson.M{
+		"status":       job.Status,
+		"active":       job.Active,
+		"updated_time": job.UpdatedTime,
+		"updated_by":   job.UpdatedBy,
+	}
+	unset := bson.M{}
+	for field, value := range map[string]*time.Time{
+		"published_time": job.PublishedTime,
+		"closes_at":      job.ClosesAt,
+		"closed_time":    job.ClosedTime,
+	} {
+		if value != nil {
+			set[field] = value
+		} else {
+			unset[field] = ""
+		}
 	}
 
-	return nil
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	return r.findOneAndUpdate(ctx, notDeleted(bson.M{"_id": objID, "status": from}), update)
+}
+
+// UpdateStages replaces the pipeline stages of a job with those of job and returns the updated job
+func (r *JobRepository) UpdateStages(ctx context.Context, id string, job *models.Job) (*models.Job, error) {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	set := bson.M{
+		"updated_time": job.UpdatedTime,
+		"updated_by":   job.UpdatedBy,
+	}
+	update := bson.M{"$set": set}
+	if len(job.Stages) > 0 {
+		set["stages"] = job.Stages
+	} else {
+		update["$unset"] = bson.M{"stages": ""}
+	}
+	return r.findOneAndUpdate(ctx, notDeleted(bson.M{"_id": objID}), update)
+}
+
+// CloseExpired closes every active job whose closes_at deadline is at or before now
+// and returns the number of jobs closed
+func (r *JobRepository) CloseExpired(ctx context.Context, now time.Time, updatedBy string) (int64, error) {
+	result, err := r.collection.UpdateMany(
+		ctx,
+		notDeleted(bson.M{"status": models.JobStatusActive, "closes_at": bson.M{"$lte": now}}),
+		bson.M{"$set": bson.M{
+			"status":       models.JobStatusClosed,
+			"active":       false,
+			"closed_time":  now,
+			"updated_time": now,
+			"updated_by":   updatedBy,
+		}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// NormalizeSalaries recomputes the annual base-currency salaries of the jobs paid in currency from its
+// exchange rate and returns the number of jobs updated
+func (r *JobRepository) NormalizeSalaries(ctx context.Context, currency string, rate float64) (int64, error) {
+	branches := bson.A{}
+	for period, count := range models.PeriodsPerYear {
+		branches = append(branches, bson.M{"case": bson.M{"$eq": bson.A{"$salary_period", period}}, "then": count})
+	}
+	periodsPerYear := bson.M{"$switch": bson.M{"branches": branches, "default": 1}}
+	annual := func(field string) bson.M {
+		return bson.M{"$toLong": bson.M{"$round": bson.A{
+			bson.M{"$divide": bson.A{bson.M{"$multiply": bson.A{field, periodsPerYear}}, rate}}, 0,
+		}}}
+	}
+
+	result, err := r.collection.UpdateMany(ctx, bson.M{"salary_currency": currency}, mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"salary_annual_min": annual("$salary_min"),
+			"salary_annual_max": annual("$salary_max"),
+		}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// ClearNormalizedSalaries removes the annual base-currency salaries of the jobs paid in currency, once
+// it no longer has an exchange rate, and returns the number of jobs updated
+func (r *JobRepository) ClearNormalizedSalaries(ctx context.Context, currency string) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"salary_currency": currency},
+		bson.M{"$unset": bson.M{"salary_annual_min": "", "salary_annual_max": ""}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *JobRepository) findOneAndUpdate(ctx context.Context, filter, update bson.M) (*models.Job, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated models.Job
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// Delete soft deletes a job by ID, applying the delete policies of its relations
+func (r *JobRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	return r.deleter.Delete(ctx, "jobs", id, deletedBy)
+}
+
+// Restore undoes the soft delete of a job and of the records deleted along with it
+func (r *JobRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	return r.deleter.Restore(ctx, "jobs", id, restored)
+}
+
+// prefixMatch matches values starting with prefix, ignoring case. The prefix is escaped so it is
+// matched literally.
+func prefixMatch(prefix string) bson.M {
+	return bson.M{"$regex": "^" + regexp.QuoteMeta(prefix), "$options": "i"}
+}
+
+// jobFilter builds the query shared by the job listing and its facets: the soft-delete filter, the
+// "q" full-text search, exact references by ID, the jobs visible to the caller, prefix matches on the
+// text fields, the salary range and the area of a location search.
+func jobFilter(filters map[string]string) (bson.M, error) {
+	filter := listFilter(filters)
+	if search := filters[models.FilterSearch]; search != "" {
+		filter["$text"] = bson.M{"$search": search}
+	}
+	for _, field := range models.JobReferenceFilters {
+		if value := filters[field]; value != "" {
+			objID, err := bson.ObjectIDFromHex(value)
+			if err != nil {
+				return nil, err
+			}
+			filter[field] = objID
+		}
+	}
+	if owner, exists := filters[models.FilterVisibleTo]; exists {
+		visible := bson.A{bson.M{"status": bson.M{"$in": models.PublicJobStatuses}}}
+		if owner != "" {
+			objID, err := bson.ObjectIDFromHex(owner)
+			if err != nil {
+				return nil, err
+			}
+			visible = append(visible, bson.M{"user_id": objID})
+		}
+		filter["$or"] = visible
+	}
+	prefixFields := []string{"title", "description", "location", "status"}
+	for _, field := range prefixFields {
+		if value, exists := filters[field]; exists && value != "" {
+			filter[field] = prefixMatch(value)
+		}
+	}
+	for _, field := range []string{"salary_currency", "salary_period"} {
+		if value := filters[field]; value != "" {
+			filter[field] = value
+		}
+	}
+
+	// A job matches a salary range when its own range overlaps it
+	minField, maxField := salaryFields(filters)
+	if value := filters[models.FilterSalaryMin]; value != "" {
+		amount, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, err
+		}
+		filter[maxField] = bson.M{"$gte": amount}
+	}
+	if value := filters[models.FilterSalaryMax]; value != "" {
+		amount, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, err
+		}
+		filter[minField] = bson.M{"$lte": amount}
+	}
+
+	geo, err := parseGeoSearch(filters)
+	if err != nil {
+		return nil, err
+	}
+	if geo != nil {
+		filter["coordinates"] = geo.within()
+	}
+	return filter, nil
+}
+
+// earthRadiusKm is the radius $centerSphere distances are measured with
+const earthRadiusKm = 6378.1
+
+// geoSearch is the point and optional radius of a location search
+type geoSearch struct {
+	point    *models.GeoPoint
+	radiusKm float64
+}
+
+// parseGeoSearch reads the location search from the lat, lng and radius_km filters. It returns nil
+// when the filters have no location.
+func parseGeoSearch(filters map[string]string) (*geoSearch, error) {
+	latValue, lngValue := filters[models.FilterLatitude], filters[models.FilterLongitude]
+	if latValue == "" || lngValue == "" {
+		return nil, nil
+	}
+	lat, err := strconv.ParseFloat(latValue, 64)
+	if err != nil {
+		return nil, err
+	}
+	lng, err := strconv.ParseFloat(lngValue, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	geo := &geoSearch{point: models.NewGeoPoint(lat, lng)}
+	if radius := filters[models.FilterRadiusKm]; radius != "" {
+		if geo.radiusKm, err = strconv.ParseFloat(radius, 64); err != nil {
+			return nil, err
+		}
+	}
+	return geo, nil
+}
+
+// within is the condition on coordinates matching the jobs inside the search radius, or every job
+// with coordinates when there is no radius
+func (g *geoSearch) within() bson.M {
+	if g.radiusKm <= 0 {
+		return bson.M{"$exists": true}
+	}
+	return bson.M{"$geoWithin": bson.M{
+		"$centerSphere": bson.A{g.point.Coordinates, g.radiusKm / earthRadiusKm},
+	}}
+}
+
+// salaryFields returns the salary range fields the filters compare against: the annual base-currency
+// amounts when normalize_salary is set, the salaries as posted otherwise
+func salaryFields(filters map[string]string) (string, string) {
+	if filters[models.FilterNormalizeSalary] == "true" {
+		return "salary_annual_min", "salary_annual_max"
+	}
+	return "salary_min", "salary_max"
+}
+
+// countBy returns the $facet stages counting documents by the value of expr, most common first
+func countBy(expr interface{}) bson.A {
+	return bson.A{
+		bson.M{"$group": bson.M{"_id": expr, "count": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+	}
 }
@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"go-mongodb-api/helpers"
+	"go-mongodb-api/interfaces"
 	"go-mongodb-api/models"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -12,14 +13,14 @@ import (
 
 type JobCategoryRepository struct {
 	collection *mongo.Collection
-	db         *mongo.Database
+	deleter    *Deleter
 }
 
 // NewJobCategoryRepository creates a new job category repository
-func NewJobCategoryRepository(db *mongo.Database) *JobCategoryRepository {
+func NewJobCategoryRepository(db *mongo.Database, deleter *Deleter) *JobCategoryRepository {
 	return &JobCategoryRepository{
 		collection: db.Collection("jobcategories"),
-		db:         db,
+		deleter:    deleter,
 	}
 }
 
@@ -28,7 +29,7 @@ func (r *JobCategoryRepository) GetAll(ctx context.Context, page, limit int, fil
 	pagination := helpers.NewPagination(page, limit)
 
 	// Build filter query
-	filter := bson.M{}
+	filter := listFilter(filters)
 
 	// Search by name (partial match)
 	if name, exists := filters["name"]; exists && name != "" {
@@ -89,16 +90,20 @@ func (r *JobCategoryRepository) GetByID(ctx context.Context, id string) (*models
 	}
 
 	var jobCategory models.JobCategory
-	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&jobCategory)
+	err = r.collection.FindOne(ctx, notDeleted(bson.M{"_id": objID})).Decode(&jobCategory)
 	if err != nil {
 		return nil, err
 	}
 	return &jobCategory, nil
 }
 
-// Create inserts a new job category
+// Create inserts a new job category. It returns interfaces.ErrDuplicateKey when a live category has
+// the same name.
 func (r *JobCategoryRepository) Create(ctx context.Context, jobCategory *models.JobCategory) error {
 	result, err := r.collection.InsertOne(ctx, jobCategory)
+	if mongo.IsDuplicateKeyError(err) {
+		return interfaces.ErrDuplicateKey
+	}
 	if err != nil {
 		return err
 	}
@@ -128,38 +133,19 @@ func (r *JobCategoryRepository) Update(ctx context.Context, id string, jobCatego
 
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 	var updated models.JobCategory
-	err = r.collection.FindOneAndUpdate(ctx, bson.M{"_id": objID}, update, opts).Decode(&updated)
+	err = r.collection.FindOneAndUpdate(ctx, notDeleted(bson.M{"_id": objID}), update, opts).Decode(&updated)
 	if err != nil {
 		return nil, err
 	}
 	return &updated, nil
 }
 
-// Delete removes a job category by ID only if no jobs use it
-func (r *JobCategoryRepository) Delete(ctx context.Context, id string) error {
-	objID, err := bson.ObjectIDFromHex(id)
-	if err != nil {
-		return err
-	}
-
-	jobsCollection := r.db.Collection("jobs")
-	jobCount, err := jobsCollection.CountDocuments(ctx, bson.M{"category_id": objID})
-	if err != nil {
-		return err
-	}
-
-	if jobCount > 0 {
-		return mongo.ErrNoDocuments
-	}
-
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
-	if err != nil {
-		return err
-	}
-
-	if result.DeletedCount == 0 {
-		return mongo.ErrNoDocuments
-	}
+// Delete soft deletes a job category by ID, applying the delete policies of its relations
+func (r *JobCategoryRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	return r.deleter.Delete(ctx, "jobcategories", id, deletedBy)
+}
 
-	return nil
+// Restore undoes the soft delete of a job category and of the records deleted along with it
+func (r *JobCategoryRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	return r.deleter.Restore(ctx, "jobcategories", id, restored)
 }
@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"go-mongodb-api/helpers"
+	"go-mongodb-api/interfaces"
 	"go-mongodb-api/models"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -12,12 +13,14 @@ import (
 
 type JobSkillRepository struct {
 	collection *mongo.Collection
+	deleter    *Deleter
 }
 
 // NewJobSkillRepository creates a new job skill repository
-func NewJobSkillRepository(db *mongo.Database) *JobSkillRepository {
+func NewJobSkillRepository(db *mongo.Database, deleter *Deleter) *JobSkillRepository {
 	return &JobSkillRepository{
 		collection: db.Collection("jobskills"),
+		deleter:    deleter,
 	}
 }
 
@@ -26,7 +29,7 @@ func (r *JobSkillRepository) GetAll(ctx context.Context, page, limit int, filter
 	pagination := helpers.NewPagination(page, limit)
 
 	// Build filter query
-	filter := bson.M{}
+	filter := listFilter(filters)
 
 	// Search by job_id (exact match)
 	if jobID, exists := filters["job_id"]; exists && jobID != "" {
@@ -98,7 +101,7 @@ func (r *JobSkillRepository) GetByID(ctx context.Context, id string) (*models.Jo
 	}
 
 	var jobSkill models.JobSkill
-	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&jobSkill)
+	err = r.collection.FindOne(ctx, notDeleted(bson.M{"_id": objID})).Decode(&jobSkill)
 	if err != nil {
 		return nil, err
 	}
@@ -111,7 +114,7 @@ func (r *JobSkillRepository) GetByJobID(ctx context.Context, jobID string) ([]mo
 	if err != nil {
 		return nil, err
 	}
-	cursor, err := r.collection.Find(ctx, bson.M{"job_id": objID})
+	cursor, err := r.collection.Find(ctx, notDeleted(bson.M{"job_id": objID}))
 	if err != nil {
 		return nil, err
 	}
@@ -127,9 +130,13 @@ func (r *JobSkillRepository) GetByJobID(ctx context.Context, jobID string) ([]mo
 	return jobSkills, nil
 }
 
-// Create inserts a new job skill
+// Create inserts a new job skill. It returns interfaces.ErrDuplicateKey when the job already
+// requires the skill.
 func (r *JobSkillRepository) Create(ctx context.Context, jobSkill *models.JobSkill) error {
 	result, err := r.collection.InsertOne(ctx, jobSkill)
+	if mongo.IsDuplicateKeyError(err) {
+		return interfaces.ErrDuplicateKey
+	}
 	if err != nil {
 		return err
 	}
@@ -141,8 +148,8 @@ func (r *JobSkillRepository) Create(ctx context.Context, jobSkill *models.JobSki
 	return nil
 }
 
-// UpdateProficiencyLevel updates the proficiency level required for a job skill
-func (r *JobSkillRepository) UpdateProficiencyLevel(ctx context.Context, id string, proficiencyLevel string) error {
+// UpdateProficiencyLevel updates the proficiency level required for a job skill and records who changed it
+func (r *JobSkillRepository) UpdateProficiencyLevel(ctx context.Context, id string, proficiencyLevel string, updated models.Audit) error {
 	if proficiencyLevel == "" {
 		return mongo.ErrNoDocuments
 	}
@@ -153,27 +160,18 @@ func (r *JobSkillRepository) UpdateProficiencyLevel(ctx context.Context, id stri
 
 	_, err = r.collection.UpdateOne(
 		ctx,
-		bson.M{"_id": objID},
-		bson.M{"$set": bson.M{"proficiency_level_required": proficiencyLevel}},
+		notDeleted(bson.M{"_id": objID}),
+		bson.M{"$set": bson.M{"proficiency_level_required": proficiencyLevel, "updated_time": updated.UpdatedTime, "updated_by": updated.UpdatedBy}},
 	)
 	return err
 }
 
-// Delete removes a job skill by ID
-func (r *JobSkillRepository) Delete(ctx context.Context, id string) error {
-	objID, err := bson.ObjectIDFromHex(id)
-	if err != nil {
-		return err
-	}
-
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
-	if err != nil {
-		return err
-	}
-
-	if result.DeletedCount == 0 {
-		return mongo.ErrNoDocuments
-	}
+// Delete soft deletes a job skill by ID, applying the delete policies of its relations
+func (r *JobSkillRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	return r.deleter.Delete(ctx, "jobskills", id, deletedBy)
+}
 
-	return nil
+// Restore undoes the soft delete of a job skill and of the records deleted along with it
+func (r *JobSkillRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	return r.deleter.Restore(ctx, "jobskills", id, restored)
 }
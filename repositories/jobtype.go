@@ -12,18 +12,20 @@ import (
 
 type JobTypeRepository struct {
 	collection *mongo.Collection
+	deleter    *Deleter
 }
 
-func NewJobTypeRepository(db *mongo.Database) *JobTypeRepository {
+func NewJobTypeRepository(db *mongo.Database, deleter *Deleter) *JobTypeRepository {
 	return &JobTypeRepository{
 		collection: db.Collection("jobtypes"),
+		deleter:    deleter,
 	}
 }
 
 func (r *JobTypeRepository) GetAll(ctx context.Context, page, limit int, filters map[string]string, sort, order string) ([]models.JobType, int64, error) {
 	pagination := helpers.NewPagination(page, limit)
 
-	filter := bson.M{}
+	filter := listFilter(filters)
 	if title, exists := filters["title"]; exists && title != "" {
 		filter["title"] = bson.M{"$regex": title, "$options": "i"}
 	}
@@ -76,7 +78,7 @@ func (r *JobTypeRepository) GetByID(ctx context.Context, id string) (*models.Job
 	}
 
 	var jobType models.JobType
-	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&jobType)
+	err = r.collection.FindOne(ctx, notDeleted(bson.M{"_id": objID})).Decode(&jobType)
 	if err != nil {
 		return nil, err
 	}
@@ -112,27 +114,19 @@ func (r *JobTypeRepository) Update(ctx context.Context, id string, jobType *mode
 
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 	var updated models.JobType
-	err = r.collection.FindOneAndUpdate(ctx, bson.M{"_id": objID}, update, opts).Decode(&updated)
+	err = r.collection.FindOneAndUpdate(ctx, notDeleted(bson.M{"_id": objID}), update, opts).Decode(&updated)
 	if err != nil {
 		return nil, err
 	}
 	return &updated, nil
 }
 
-func (r *JobTypeRepository) Delete(ctx context.Context, id string) error {
-	objID, err := bson.ObjectIDFromHex(id)
-	if err != nil {
-		return err
-	}
-
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
-	if err != nil {
-		return err
-	}
-
-	if result.DeletedCount == 0 {
-		return mongo.ErrNoDocuments
-	}
+// Delete soft deletes a job type by ID, applying the delete policies of its relations
+func (r *JobTypeRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	return r.deleter.Delete(ctx, "jobtypes", id, deletedBy)
+}
 
-	return nil
+// Restore undoes the soft delete of a job type and of the records deleted along with it
+func (r *JobTypeRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	return r.deleter.Restore(ctx, "jobtypes", id, restored)
 }
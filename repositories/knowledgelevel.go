@@ -12,18 +12,20 @@ import (
 
 type KnowledgeLevelRepository struct {
 	collection *mongo.Collection
+	deleter    *Deleter
 }
 
-func NewKnowledgeLevelRepository(db *mongo.Database) *KnowledgeLevelRepository {
+func NewKnowledgeLevelRepository(db *mongo.Database, deleter *Deleter) *KnowledgeLevelRepository {
 	return &KnowledgeLevelRepository{
 		collection: db.Collection("knowledgelevels"),
+		deleter:    deleter,
 	}
 }
 
 func (r *KnowledgeLevelRepository) GetAll(ctx context.Context, page, limit int, filters map[string]string, sort, order string) ([]models.KnowledgeLevel, int64, error) {
 	pagination := helpers.NewPagination(page, limit)
 
-	filter := bson.M{}
+	filter := listFilter(filters)
 	if title, exists := filters["title"]; exists && title != "" {
 		filter["title"] = bson.M{"$regex": title, "$options": "i"}
 	}
@@ -76,7 +78,7 @@ func (r *KnowledgeLevelRepository) GetByID(ctx context.Context, id string) (*mod
 	}
 
 	var knowledgeLevel models.KnowledgeLevel
-	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&knowledgeLevel)
+	err = r.collection.FindOne(ctx, notDeleted(bson.M{"_id": objID})).Decode(&knowledgeLevel)
 	if err != nil {
 		return nil, err
 	}
@@ -112,27 +114,19 @@ func (r *KnowledgeLevelRepository) Update(ctx context.Context, id string, knowle
 
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 	var updated models.KnowledgeLevel
-	err = r.collection.FindOneAndUpdate(ctx, bson.M{"_id": objID}, update, opts).Decode(&updated)
+	err = r.collection.FindOneAndUpdate(ctx, notDeleted(bson.M{"_id": objID}), update, opts).Decode(&updated)
 	if err != nil {
 		return nil, err
 	}
 	return &updated, nil
 }
 
-func (r *KnowledgeLevelRepository) Delete(ctx context.Context, id string) error {
-	objID, err := bson.ObjectIDFromHex(id)
-	if err != nil {
-		return err
-	}
-
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
-	if err != nil {
-		return err
-	}
-
-	if result.DeletedCount == 0 {
-		return mongo.ErrNoDocuments
-	}
+// Delete soft deletes a knowledge level by ID, applying the delete policies of its relations
+func (r *KnowledgeLevelRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	return r.deleter.Delete(ctx, "knowledgelevels", id, deletedBy)
+}
 
-	return nil
+// Restore undoes the soft delete of a knowledge level and of the records deleted along with it
+func (r *KnowledgeLevelRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	return r.deleter.Restore(ctx, "knowledgelevels", id, restored)
 }
@@ -12,18 +12,20 @@ import (
 
 type LocationAvailabilityRepository struct {
 	collection *mongo.Collection
+	deleter    *Deleter
 }
 
-func NewLocationAvailabilityRepository(db *mongo.Database) *LocationAvailabilityRepository {
+func NewLocationAvailabilityRepository(db *mongo.Database, deleter *Deleter) *LocationAvailabilityRepository {
 	return &LocationAvailabilityRepository{
 		collection: db.Collection("locationavailabilities"),
+		deleter:    deleter,
 	}
 }
 
 func (r *LocationAvailabilityRepository) GetAll(ctx context.Context, page, limit int, filters map[string]string, sort, order string) ([]models.LocationAvailability, int64, error) {
 	pagination := helpers.NewPagination(page, limit)
 
-	filter := bson.M{}
+	filter := listFilter(filters)
 	if title, exists := filters["title"]; exists && title != "" {
 		filter["title"] = bson.M{"$regex": title, "$options": "i"}
 	}
@@ -76,7 +78,7 @@ func (r *LocationAvailabilityRepository) GetByID(ctx context.Context, id string)
 	}
 
 	var locationAvailability models.LocationAvailability
-	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&locationAvailability)
+	err = r.collection.FindOne(ctx, notDeleted(bson.M{"_id": objID})).Decode(&locationAvailability)
 	if err != nil {
 		return nil, err
 	}
@@ -112,27 +114,19 @@ func (r *LocationAvailabilityRepository) Update(ctx context.Context, id string,
 
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 	var updated models.LocationAvailability
-	err = r.collection.FindOneAndUpdate(ctx, bson.M{"_id": objID}, update, opts).Decode(&updated)
+	err = r.collection.FindOneAndUpdate(ctx, notDeleted(bson.M{"_id": objID}), update, opts).Decode(&updated)
 	if err != nil {
 		return nil, err
 	}
 	return &updated, nil
 }
 
-func (r *LocationAvailabilityRepository) Delete(ctx context.Context, id string) error {
-	objID, err := bson.ObjectIDFromHex(id)
-	if err != nil {
-		return err
-	}
-
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
-	if err != nil {
-		return err
-	}
-
-	if result.DeletedCount == 0 {
-		return mongo.ErrNoDocuments
-	}
+// Delete soft deletes a location availability by ID, applying the delete policies of its relations
+func (r *LocationAvailabilityRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	return r.deleter.Delete(ctx, "locationavailabilities", id, deletedBy)
+}
 
-	return nil
+// Restore undoes the soft delete of a location availability and of the records deleted along with it
+func (r *LocationAvailabilityRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	return r.deleter.Restore(ctx, "locationavailabilities", id, restored)
 }
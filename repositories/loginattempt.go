@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"go-mongodb-api/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type LoginAttemptRepository struct {
+	collection *mongo.Collection
+}
+
+// NewLoginAttemptRepository creates a new login attempt repository
+func NewLoginAttemptRepository(db *mongo.Database) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		collection: db.Collection("loginattempts"),
+	}
+}
+
+// Get retrieves the failed attempt counter for a scope and value
+func (r *LoginAttemptRepository) Get(ctx context.Context, scope, value string) (*models.LoginAttempt, error) {
+	var attempt models.LoginAttempt
+	err := r.collection.FindOne(ctx, bson.M{"scope": scope, "value": value}).Decode(&attempt)
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// RecordFailure atomically increments the failed attempt counter, creating it when missing,
+// and returns the updated document
+func (r *LoginAttemptRepository) RecordFailure(ctx context.Context, scope, value string, expiresTime time.Time) (*models.LoginAttempt, error) {
+	now := time.Now()
+	update := bson.M{
+		"$inc":         bson.M{"failures": 1},
+		"$set":         bson.M{"last_failure_time": now},
+		"$max":         bson.M{"expires_time": expiresTime},
+		"$setOnInsert": bson.M{"created_time": now},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var attempt models.LoginAttempt
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"scope": scope, "value": value}, update, opts).Decode(&attempt)
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// Lock blocks logins for a scope and value until the given time
+func (r *LoginAttemptRepository) Lock(ctx context.Context, scope, value string, until time.Time) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"scope": scope, "value": value},
+		bson.M{
+			"$set": bson.M{"locked_until": until},
+			"$max": bson.M{"expires_time": until},
+		},
+	)
+	return err
+}
+
+// Reset clears the failed attempt counter and any lock for a scope and value
+func (r *LoginAttemptRepository) Reset(ctx context.Context, scope, value string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"scope": scope, "value": value})
+	return err
+}
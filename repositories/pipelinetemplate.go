@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"go-mongodb-api/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type PipelineTemplateRepository struct {
+	collection *mongo.Collection
+}
+
+// NewPipelineTemplateRepository creates a new pipeline template repository
+func NewPipelineTemplateRepository(db *mongo.Database) *PipelineTemplateRepository {
+	return &PipelineTemplateRepository{
+		collection: db.Collection("pipelinetemplates"),
+	}
+}
+
+// GetAll retrieves the pipeline templates of a company and those created by userID, ordered by name,
+// or every template when both are empty
+func (r *PipelineTemplateRepository) GetAll(ctx context.Context, userID, companyName string) ([]models.PipelineTemplate, error) {
+	filter := bson.M{}
+	if userID != "" {
+		objID, err := bson.ObjectIDFromHex(userID)
+		if err != nil {
+			return nil, err
+		}
+		owned := bson.A{bson.M{"user_id": objID}}
+		if companyName != "" {
+			owned = append(owned, bson.M{"company_name": companyName})
+		}
+		filter["$or"] = owned
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	templates := []models.PipelineTemplate{}
+	if err = cursor.All(ctx, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// GetByID retrieves a pipeline template by ID
+func (r *PipelineTemplateRepository) GetByID(ctx context.Context, id string) (*models.PipelineTemplate, error) {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var template models.PipelineTemplate
+	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&template)
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// Create inserts a new pipeline template
+func (r *PipelineTemplateRepository) Create(ctx context.Context, template *models.PipelineTemplate) error {
+	result, err := r.collection.InsertOne(ctx, template)
+	if err != nil {
+		return err
+	}
+	objID, ok := result.InsertedID.(bson.ObjectID)
+	if !ok {
+		return nil
+	}
+	template.ID = objID
+	return nil
+}
+
+// Update replaces the name and stages of a pipeline template and returns the updated template
+func (r *PipelineTemplateRepository) Update(ctx context.Context, id string, template *models.PipelineTemplate) (*models.PipelineTemplate, error) {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	update := bson.M{"$set": bson.M{
+		"name":         template.Name,
+		"stages":       template.Stages,
+		"updated_time": template.UpdatedTime,
+		"updated_by":   template.UpdatedBy,
+	}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated models.PipelineTemplate
+	err = r.collection.FindOneAndUpdate(ctx, bson.M{"_id": objID}, update, opts).Decode(&updated)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// Delete removes a pipeline template. Jobs keep the stages copied from it. It returns
+// mongo.ErrNoDocuments when the template does not exist.
+func (r *PipelineTemplateRepository) Delete(ctx context.Context, id string) error {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"go-mongodb-api/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type SessionRepository struct {
+	collection *mongo.Collection
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(db *mongo.Database) *SessionRepository {
+	return &SessionRepository{
+		collection: db.Collection("sessions"),
+	}
+}
+
+// GetByID retrieves a session by ID
+func (r *SessionRepository) GetByID(ctx context.Context, id string) (*models.Session, error) {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var session models.Session
+	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&session)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetByPreviousHash retrieves the session that has already rotated away from the given refresh token hash
+func (r *SessionRepository) GetByPreviousHash(ctx context.Context, tokenHash string) (*models.Session, error) {
+	var session models.Session
+	err := r.collection.FindOne(ctx, bson.M{"previous_hashes": tokenHash}).Decode(&session)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Create inserts a new session
+func (r *SessionRepository) Create(ctx context.Context, session *models.Session) error {
+	result, err := r.collection.InsertOne(ctx, session)
+	if err != nil {
+		return err
+	}
+	objID, ok := result.InsertedID.(bson.ObjectID)
+	if !ok {
+		return nil
+	}
+	session.ID = objID
+	return nil
+}
+
+// Rotate atomically replaces the current refresh token hash of an active, unexpired session
+// and returns the updated session. It returns mongo.ErrNoDocuments when no such session exists.
+func (r *SessionRepository) Rotate(ctx context.Context, oldHash, newHash string, expiresTime time.Time) (*models.Session, error) {
+	now := time.Now()
+	filter := bson.M{
+		"token_hash":   oldHash,
+		"revoked_time": bson.M{"$exists": false},
+		"expires_time": bson.M{"$gt": now},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"token_hash":   newHash,
+			"expires_time": expiresTime,
+			"updated_time": now,
+		},
+		"$push": bson.M{"previous_hashes": oldHash},
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated models.Session
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// Revoke marks a session as revoked
+func (r *SessionRepository) Revoke(ctx context.Context, id string, reason string) error {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objID, "revoked_time": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_time": now, "revoked_reason": reason, "updated_time": now}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// RevokeAllForUser marks every active session of a user as revoked
+func (r *SessionRepository) RevokeAllForUser(ctx context.Context, userID string, reason string) error {
+	objID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = r.collection.UpdateMany(
+		ctx,
+		bson.M{"user_id": objID, "revoked_time": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_time": now, "revoked_reason": reason, "updated_time": now}},
+	)
+	return err
+}
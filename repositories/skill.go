@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"go-mongodb-api/helpers"
+	"go-mongodb-api/interfaces"
 	"go-mongodb-api/models"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -12,12 +13,14 @@ import (
 
 type SkillRepository struct {
 	collection *mongo.Collection
+	deleter    *Deleter
 }
 
 // NewSkillRepository creates a new skill repository
-func NewSkillRepository(db *mongo.Database) *SkillRepository {
+func NewSkillRepository(db *mongo.Database, deleter *Deleter) *SkillRepository {
 	return &SkillRepository{
 		collection: db.Collection("skills"),
+		deleter:    deleter,
 	}
 }
 
@@ -26,7 +29,7 @@ func (r *SkillRepository) GetAll(ctx context.Context, page, limit int, filters m
 	pagination := helpers.NewPagination(page, limit)
 
 	// Build filter query
-	filter := bson.M{}
+	filter := listFilter(filters)
 	if name, exists := filters["name"]; exists && name != "" {
 		filter["name"] = bson.M{"$regex": name, "$options": "i"}
 	}
@@ -80,16 +83,20 @@ func (r *SkillRepository) GetByID(ctx context.Context, id string) (*models.Skill
 	}
 
 	var skill models.Skill
-	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&skill)
+	err = r.collection.FindOne(ctx, notDeleted(bson.M{"_id": objID})).Decode(&skill)
 	if err != nil {
 		return nil, err
 	}
 	return &skill, nil
 }
 
-// Create inserts a new skill
+// Create inserts a new skill. It returns interfaces.ErrDuplicateKey when a live skill has the same
+// name.
 func (r *SkillRepository) Create(ctx context.Context, skill *models.Skill) error {
 	result, err := r.collection.InsertOne(ctx, skill)
+	if mongo.IsDuplicateKeyError(err) {
+		return interfaces.ErrDuplicateKey
+	}
 	if err != nil {
 		return err
 	}
@@ -119,28 +126,19 @@ func (r *SkillRepository) Update(ctx context.Context, id string, skill *models.S
 
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 	var updated models.Skill
-	err = r.collection.FindOneAndUpdate(ctx, bson.M{"_id": objID}, update, opts).Decode(&updated)
+	err = r.collection.FindOneAndUpdate(ctx, notDeleted(bson.M{"_id": objID}), update, opts).Decode(&updated)
 	if err != nil {
 		return nil, err
 	}
 	return &updated, nil
 }
 
-// Delete removes a skill by ID
-func (r *SkillRepository) Delete(ctx context.Context, id string) error {
-	objID, err := bson.ObjectIDFromHex(id)
-	if err != nil {
-		return err
-	}
-
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
-	if err != nil {
-		return err
-	}
-
-	if result.DeletedCount == 0 {
-		return mongo.ErrNoDocuments
-	}
+// Delete soft deletes a skill by ID, applying the delete policies of its relations
+func (r *SkillRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	return r.deleter.Delete(ctx, "skills", id, deletedBy)
+}
 
-	return nil
+// Restore undoes the soft delete of a skill and of the records deleted along with it
+func (r *SkillRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	return r.deleter.Restore(ctx, "skills", id, restored)
 }
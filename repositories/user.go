@@ -3,7 +3,9 @@ package repositories
 import (
 	"context"
 	"go-mongodb-api/helpers"
+	"go-mongodb-api/interfaces"
 	"go-mongodb-api/models"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -12,12 +14,14 @@ import (
 
 type UserRepository struct {
 	collection *mongo.Collection
+	deleter    *Deleter
 }
 
 // NewUserRepository creates a new user repository
-func NewUserRepository(db *mongo.Database) *UserRepository {
+func NewUserRepository(db *mongo.Database, deleter *Deleter) *UserRepository {
 	return &UserRepository{
 		collection: db.Collection("users"),
+		deleter:    deleter,
 	}
 }
 
@@ -26,7 +30,7 @@ func (r *UserRepository) GetAll(ctx context.Context, page, limit int, filters ma
 	pagination := helpers.NewPagination(page, limit)
 
 	// Build filter query
-	filter := bson.M{}
+	filter := listFilter(filters)
 	for _, field := range []string{"first_name", "last_name", "email"} {
 		if value, exists := filters[field]; exists && value != "" {
 			filter[field] = bson.M{"$regex": value, "$options": "i"}
@@ -85,7 +89,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User,
 	}
 
 	var user models.User
-	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&user)
+	err = r.collection.FindOne(ctx, notDeleted(bson.M{"_id": objID})).Decode(&user)
 	if err != nil {
 		return nil, err
 	}
@@ -95,7 +99,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User,
 // GetByEmail retrieves a user by email
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	err := r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	err := r.collection.FindOne(ctx, notDeleted(bson.M{"email": email})).Decode(&user)
 	if err != nil {
 		return nil, err
 	}
@@ -105,6 +109,9 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 // Create inserts a new user
 func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 	result, err := r.collection.InsertOne(ctx, user)
+	if mongo.IsDuplicateKeyError(err) {
+		return interfaces.ErrDuplicateKey
+	}
 	if err != nil {
 		return err
 	}
@@ -137,28 +144,215 @@ func (r *UserRepository) Update(ctx context.Context, id string, user *models.Use
 
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 	var updated models.User
-	err = r.collection.FindOneAndUpdate(ctx, bson.M{"_id": objID}, update, opts).Decode(&updated)
+	err = r.collection.FindOneAndUpdate(ctx, notDeleted(bson.M{"_id": objID}), update, opts).Decode(&updated)
 	if err != nil {
 		return nil, err
 	}
 	return &updated, nil
 }
 
-// Delete removes a user by ID
-func (r *UserRepository) Delete(ctx context.Context, id string) error {
+// UpdatePassword replaces the stored password hash of a user
+func (r *UserRepository) UpdatePassword(ctx context.Context, id string, hashedPassword string, updated models.Audit) error {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{"_id": objID}),
+		bson.M{"$set": bson.M{"password": hashedPassword, "updated_time": updated.UpdatedTime, "updated_by": updated.UpdatedBy}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// MarkVerified flags a user's email address as verified
+func (r *UserRepository) MarkVerified(ctx context.Context, id string, updated models.Audit) error {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{"_id": objID}),
+		bson.M{"$set": bson.M{"verified": true, "updated_time": updated.UpdatedTime, "updated_by": updated.UpdatedBy}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// UpdateLastLogin records the time of a user's last successful login
+func (r *UserRepository) UpdateLastLogin(ctx context.Context, id string, loginTime time.Time) error {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{"_id": objID}),
+		bson.M{"$set": bson.M{"last_login_time": loginTime}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// SetMFASecret stores a pending TOTP secret for a user who has not enabled two-factor authentication yet
+func (r *UserRepository) SetMFASecret(ctx context.Context, id string, secret string, updated models.Audit) error {
 	objID, err := bson.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
 
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objID, "mfa_enabled": bson.M{"$ne": true}},
+		bson.M{"$set": bson.M{"mfa_secret": secret, "updated_time": updated.UpdatedTime, "updated_by": updated.UpdatedBy}},
+	)
 	if err != nil {
 		return err
 	}
 
-	if result.DeletedCount == 0 {
+	if result.MatchedCount == 0 {
 		return mongo.ErrNoDocuments
 	}
 
 	return nil
 }
+
+// EnableMFA turns on two-factor authentication with the pending secret and stores the recovery code hashes.
+// step is the TOTP time step of the code that confirmed the enrollment.
+func (r *UserRepository) EnableMFA(ctx context.Context, id string, recoveryCodeHashes []string, step int64, updated models.Audit) error {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objID, "mfa_enabled": bson.M{"$ne": true}, "mfa_secret": bson.M{"$exists": true}},
+		bson.M{"$set": bson.M{
+			"mfa_enabled":        true,
+			"mfa_recovery_codes": recoveryCodeHashes,
+			"mfa_last_step":      step,
+			"updated_time":       updated.UpdatedTime,
+			"updated_by":         updated.UpdatedBy,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// DisableMFA turns off two-factor authentication and removes the secret and recovery codes
+func (r *UserRepository) DisableMFA(ctx context.Context, id string, updated models.Audit) error {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{"_id": objID}),
+		bson.M{
+			"$set":   bson.M{"mfa_enabled": false, "updated_time": updated.UpdatedTime, "updated_by": updated.UpdatedBy},
+			"$unset": bson.M{"mfa_secret": "", "mfa_recovery_codes": "", "mfa_last_step": ""},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// UseMFAStep records a TOTP time step as used. It returns mongo.ErrNoDocuments when the step,
+// or a later one, was already used, so a code cannot be replayed.
+func (r *UserRepository) UseMFAStep(ctx context.Context, id string, step int64) error {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objID, "mfa_last_step": bson.M{"$not": bson.M{"$gte": step}}},
+		bson.M{"$set": bson.M{"mfa_last_step": step}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// UseRecoveryCode removes a recovery code hash from a user. It returns mongo.ErrNoDocuments
+// when the user has no such code.
+func (r *UserRepository) UseRecoveryCode(ctx context.Context, id string, codeHash string) error {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objID, "mfa_recovery_codes": codeHash},
+		bson.M{"$pull": bson.M{"mfa_recovery_codes": codeHash}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// Delete soft deletes a user by ID, applying the delete policies of its relations
+func (r *UserRepository) Delete(ctx context.Context, id string, deletedBy string) error {
+	return r.deleter.Delete(ctx, "users", id, deletedBy)
+}
+
+// Restore undoes the soft delete of a user and of the records deleted along with it
+func (r *UserRepository) Restore(ctx context.Context, id string, restored models.Audit) error {
+	return r.deleter.Restore(ctx, "users", id, restored)
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+	"go-mongodb-api/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type UserTokenRepository struct {
+	collection *mongo.Collection
+}
+
+// NewUserTokenRepository creates a new user token repository
+func NewUserTokenRepository(db *mongo.Database) *UserTokenRepository {
+	return &UserTokenRepository{
+		collection: db.Collection("usertokens"),
+	}
+}
+
+// Create inserts a new user token
+func (r *UserTokenRepository) Create(ctx context.Context, token *models.UserToken) error {
+	result, err := r.collection.InsertOne(ctx, token)
+	if err != nil {
+		return err
+	}
+	objID, ok := result.InsertedID.(bson.ObjectID)
+	if !ok {
+		return nil
+	}
+	token.ID = objID
+	return nil
+}
+
+// Consume atomically marks an unused, unexpired token as used and returns it.
+// It returns mongo.ErrNoDocuments when the token is unknown, expired or already used.
+func (r *UserTokenRepository) Consume(ctx context.Context, tokenHash, purpose string) (*models.UserToken, error) {
+	now := time.Now()
+	filter := bson.M{
+		"token_hash":   tokenHash,
+		"purpose":      purpose,
+		"used_time":    bson.M{"$exists": false},
+		"expires_time": bson.M{"$gt": now},
+	}
+
+	var token models.UserToken
+	err := r.collection.FindOneAndUpdate(ctx, filter, bson.M{"$set": bson.M{"used_time": now}}).Decode(&token)
+	if err != nil {
+		return nil, err
+	}
+	token.UsedTime = &now
+	return &token, nil
+}
+
+// DeleteByUser removes every token of the given purpose issued to a user
+func (r *UserTokenRepository) DeleteByUser(ctx context.Context, userID, purpose string) error {
+	objID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"user_id": objID, "purpose": purpose})
+	return err
+}
+
+// DeleteByEmail removes every token of the given purpose issued to an email address
+func (r *UserTokenRepository) DeleteByEmail(ctx context.Context, email, purpose string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"email": email, "purpose": purpose})
+	return err
+}
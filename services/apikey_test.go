@@ -0,0 +1,118 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go-mongodb-api/helpers"
+	"go-mongodb-api/mocks"
+	"go-mongodb-api/models"
+	"go-mongodb-api/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestAPIKeyService_CreateAPIKey_StoresOnlyHash(t *testing.T) {
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	svc := services.NewAPIKeyService(mockRepo)
+
+	adminID := bson.NewObjectID().Hex()
+	var stored *models.APIKey
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.APIKey")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*models.APIKey) }).
+		Return(nil)
+
+	key := &models.APIKey{Name: "ATS sync", Scopes: []string{models.ScopeJobsRead}}
+	created, err := svc.CreateAPIKey(claimsContext("admin", adminID), key)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Key, "jak_"))
+	assert.True(t, strings.HasPrefix(created.Key, created.Prefix))
+	assert.Equal(t, helpers.HashToken(created.Key), stored.KeyHash)
+	assert.Equal(t, adminID, stored.CreatedBy)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAPIKeyService_CreateAPIKey_RepoError(t *testing.T) {
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	svc := services.NewAPIKeyService(mockRepo)
+
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+	created, err := svc.CreateAPIKey(context.Background(), &models.APIKey{Name: "ATS sync"})
+	assert.Error(t, err)
+	assert.Nil(t, created)
+}
+
+func TestAPIKeyService_RevokeAPIKey(t *testing.T) {
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	svc := services.NewAPIKeyService(mockRepo)
+
+	id := bson.NewObjectID().Hex()
+	adminID := bson.NewObjectID().Hex()
+	mockRepo.On("Revoke", mock.Anything, id, updatedBy(adminID)).Return(nil)
+
+	err := svc.RevokeAPIKey(claimsContext("admin", adminID), id)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAPIKeyService_VerifyAPIKey_Success(t *testing.T) {
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	svc := services.NewAPIKeyService(mockRepo)
+
+	id := bson.NewObjectID()
+	stored := &models.APIKey{ID: id, Prefix: "jak_abcdefgh", Scopes: []string{models.ScopeApplicationsRead}}
+	mockRepo.On("GetByHash", mock.Anything, helpers.HashToken("jak_secret")).Return(stored, nil)
+	mockRepo.On("TouchLastUsed", mock.Anything, id.Hex(), mock.AnythingOfType("time.Time")).Return(nil)
+
+	claims, err := svc.VerifyAPIKey(context.Background(), "jak_secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "apikey:"+id.Hex(), claims.UserID)
+	assert.Equal(t, models.RoleAdmin, claims.Role)
+	assert.True(t, claims.IsAPIKey())
+	assert.True(t, claims.HasScope(models.ScopeApplicationsRead))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAPIKeyService_VerifyAPIKey_TouchErrorIgnored(t *testing.T) {
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	svc := services.NewAPIKeyService(mockRepo)
+
+	id := bson.NewObjectID()
+	mockRepo.On("GetByHash", mock.Anything, mock.Anything).Return(&models.APIKey{ID: id}, nil)
+	mockRepo.On("TouchLastUsed", mock.Anything, id.Hex(), mock.Anything).Return(errors.New("db error"))
+
+	claims, err := svc.VerifyAPIKey(context.Background(), "jak_secret")
+	assert.NoError(t, err)
+	assert.NotNil(t, claims)
+}
+
+func TestAPIKeyService_VerifyAPIKey_Unknown(t *testing.T) {
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	svc := services.NewAPIKeyService(mockRepo)
+
+	mockRepo.On("GetByHash", mock.Anything, mock.Anything).Return(nil, mongo.ErrNoDocuments)
+
+	claims, err := svc.VerifyAPIKey(context.Background(), "jak_unknown")
+	assert.ErrorIs(t, err, services.ErrInvalidAPIKey)
+	assert.Nil(t, claims)
+}
+
+func TestAPIKeyService_VerifyAPIKey_Expired(t *testing.T) {
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	svc := services.NewAPIKeyService(mockRepo)
+
+	expired := time.Now().Add(-time.Hour)
+	mockRepo.On("GetByHash", mock.Anything, mock.Anything).Return(&models.APIKey{ID: bson.NewObjectID(), ExpiresTime: &expired}, nil)
+
+	claims, err := svc.VerifyAPIKey(context.Background(), "jak_secret")
+	assert.ErrorIs(t, err, services.ErrInvalidAPIKey)
+	assert.Nil(t, claims)
+	mockRepo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -2,24 +2,99 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"go-mongodb-api/helpers"
 	"go-mongodb-api/interfaces"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/models"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var (
+	// ErrJobNotOpen is returned when applying to a job that is not active or whose deadline has passed
+	ErrJobNotOpen                   = errors.New("job is not accepting applications")
+	ErrInvalidApplicationTransition = errors.New("invalid application status transition")
+	ErrStageNotFound                = errors.New("pipeline stage not found")
+	ErrRejectionReasonNotFound      = errors.New("rejection reason not found")
+	// ErrAlreadyApplied is returned when applying to a job the candidate has an application to that is
+	// not withdrawn or rejected
+	ErrAlreadyApplied = errors.New("already applied to this job")
 )
 
+// ReapplyCooldownError is returned when a candidate applies again to a job before the cooldown after
+// their withdrawn or rejected application has passed
+type ReapplyCooldownError struct {
+	Until time.Time
+}
+
+func (e *ReapplyCooldownError) Error() string {
+	return "cannot reapply to this job before " + e.Until.Format(time.RFC3339)
+}
+
+// ScreeningAnswersError is returned when the answers to a job's screening questions are missing,
+// unknown or of the wrong type, with an error for each
+type ScreeningAnswersError struct {
+	Errors []helpers.ValidationError
+}
+
+func (e *ScreeningAnswersError) Error() string {
+	return fmt.Sprintf("invalid screening answers (%d)", len(e.Errors))
+}
+
+// applicationTransitions lists the statuses an application can move to from each status.
+// Accepted, rejected and withdrawn applications are final.
+var applicationTransitions = map[string][]string{
+	models.ApplicationStatusApplied:     {models.ApplicationStatusUnderReview, models.ApplicationStatusRejected, models.ApplicationStatusWithdrawn},
+	models.ApplicationStatusUnderReview: {models.ApplicationStatusAccepted, models.ApplicationStatusRejected, models.ApplicationStatusWithdrawn},
+}
+
 type ApplicationService struct {
-	repo     interfaces.ApplicationRepository
-	jobRepo  interfaces.JobRepository
-	userRepo interfaces.UserRepository
+	repo             interfaces.ApplicationRepository
+	jobRepo          interfaces.JobRepository
+	userRepo         interfaces.UserRepository
+	reapplyCooldown  time.Duration
+	rejectionReasons map[string]string
+}
+
+// ApplicationServiceOption configures optional ApplicationService behaviour
+type ApplicationServiceOption func(*ApplicationService)
+
+// WithReapplyCooldown sets how long candidates wait to apply again to a job after withdrawing or being
+// rejected. Without it they can reapply right away.
+func WithReapplyCooldown(cooldown time.Duration) ApplicationServiceOption {
+	return func(s *ApplicationService) {
+		s.reapplyCooldown = cooldown
+	}
+}
+
+// WithRejectionReasons replaces the reasons, text by key, applications can be rejected with in bulk.
+// An empty map keeps models.DefaultRejectionReasons.
+func WithRejectionReasons(reasons map[string]string) ApplicationServiceOption {
+	return func(s *ApplicationService) {
+		if len(reasons) > 0 {
+			s.rejectionReasons = reasons
+		}
+	}
 }
 
 // NewApplicationService creates a new application service
-func NewApplicationService(repo interfaces.ApplicationRepository, jobRepo interfaces.JobRepository, userRepo interfaces.UserRepository) *ApplicationService {
-	return &ApplicationService{
-		repo:     repo,
-		jobRepo:  jobRepo,
-		userRepo: userRepo,
+func NewApplicationService(repo interfaces.ApplicationRepository, jobRepo interfaces.JobRepository, userRepo interfaces.UserRepository, opts ...ApplicationServiceOption) *ApplicationService {
+	s := &ApplicationService{
+		repo:             repo,
+		jobRepo:          jobRepo,
+		userRepo:         userRepo,
+		rejectionReasons: models.DefaultRejectionReasons,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // GetAllApplications retrieves all applications with pagination and optional filtering
@@ -27,40 +102,631 @@ func (s *ApplicationService) GetAllApplications(ctx context.Context, page, limit
 	return s.repo.GetAll(ctx, page, limit, filters, sort, order)
 }
 
-// GetApplicationByID retrieves an application by ID
+// GetApplicationByID retrieves an application by ID.
+// Candidates may only read their own applications and recruiters only those for their jobs.
 func (s *ApplicationService) GetApplicationByID(ctx context.Context, id string) (*models.Application, error) {
-	return s.repo.GetByID(ctx, id)
+	application, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeUser(ctx, application.UserID.Hex()); err != nil {
+		if err := s.authorizeJobOwner(ctx, application.JobID.Hex()); err != nil {
+			return nil, err
+		}
+	}
+
+	return application, nil
 }
 
-// GetApplicationsByJobID retrieves all applications for a specific job
+// GetApplicationsByJobID retrieves all applications for a specific job owned by the caller
 func (s *ApplicationService) GetApplicationsByJobID(ctx context.Context, jobID string) ([]models.Application, error) {
+	if err := s.authorizeJobOwner(ctx, jobID); err != nil {
+		return nil, err
+	}
 	return s.repo.GetByJobID(ctx, jobID)
 }
 
 // GetApplicationsByUserID retrieves all applications from a specific user
 func (s *ApplicationService) GetApplicationsByUserID(ctx context.Context, userID string) ([]models.Application, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
 	return s.repo.GetByUserID(ctx, userID)
 }
 
-// CreateApplication creates a new application
+// ExpandApplications embeds the references named in expand into applications the caller has already
+// been allowed to read
+func (s *ApplicationService) ExpandApplications(ctx context.Context, applications []models.Application, expand []string) error {
+	if err := s.repo.Expand(ctx, applications, expand); err != nil {
+		return err
+	}
+	for i := range applications {
+		hideKnockouts(ctx, applications[i].Job)
+	}
+	return nil
+}
+
+// CreateApplication creates a new application on behalf of the caller. Candidates apply once to a job,
+// and again after the reapply cooldown once their last application is withdrawn or rejected. The
+// answers must answer the job's screening questions; an answer failing a knockout rule rejects the
+// application as it is created.
 func (s *ApplicationService) CreateApplication(ctx context.Context, application *models.Application) error {
-	if _, err := s.jobRepo.GetByID(ctx, application.JobID.Hex()); err != nil {
+	if err := authorizeUser(ctx, application.UserID.Hex()); err != nil {
+		return err
+	}
+	stampCreated(ctx, application, time.Now())
+
+	job, err := s.jobRepo.GetByID(ctx, application.JobID.Hex())
+	if err != nil {
 		return fmt.Errorf("job not found")
 	}
+	if !job.IsOpen(time.Now()) {
+		return ErrJobNotOpen
+	}
 
 	if _, err := s.userRepo.GetByID(ctx, application.UserID.Hex()); err != nil {
 		return fmt.Errorf("user not found")
 	}
 
-	return s.repo.Create(ctx, application)
+	attempt, err := s.nextAttempt(ctx, application.JobID.Hex(), application.UserID.Hex(), application.AppliedTime)
+	if err != nil {
+		return err
+	}
+	application.Attempt = attempt
+
+	knockedOut, err := checkScreeningAnswers(job.ScreeningQuestions, application.Answers)
+	if err != nil {
+		return err
+	}
+
+	application.Stage = ""
+	if len(job.Stages) > 0 {
+		application.Stage = job.Stages[0].Key
+	}
+	application.History = []models.ApplicationStatusChange{{
+		Status:      application.Status,
+		Stage:       application.Stage,
+		ChangedBy:   application.CreatedBy,
+		ChangedTime: application.AppliedTime,
+	}}
+	if len(knockedOut) > 0 {
+		if err := knockOut(application, job, knockedOut); err != nil {
+			return err
+		}
+	}
+
+	err = s.repo.Create(ctx, application)
+	if errors.Is(err, interfaces.ErrDuplicateKey) {
+		// another application with this attempt was created since the last one was read
+		return ErrAlreadyApplied
+	}
+	return err
+}
+
+// checkScreeningAnswers checks that answers answer the screening questions: at most once each, every
+// required question and every question with a knockout rule, and with the type of answer the question
+// takes. It returns the keys of the questions whose knockout rule an answer fails, or a
+// *ScreeningAnswersError.
+func checkScreeningAnswers(questions []models.ScreeningQuestion, answers []models.ScreeningAnswer) ([]string, error) {
+	var invalid []helpers.ValidationError
+	addError := func(key, message string) {
+		invalid = append(invalid, helpers.ValidationError{Field: "answers", Message: key + ": " + message})
+	}
+
+	byKey := make(map[string]models.ScreeningAnswer, len(answers))
+	for _, answer := range answers {
+		if _, ok := byKey[answer.Key]; ok {
+			addError(answer.Key, "answered more than once")
+		}
+		byKey[answer.Key] = answer
+	}
+	asked := make(map[string]bool, len(questions))
+	for _, question := range questions {
+		asked[question.Key] = true
+	}
+	for _, answer := range answers {
+		if !asked[answer.Key] {
+			addError(answer.Key, "not a screening question of this job")
+		}
+	}
+
+	var knockedOut []string
+	for _, question := range questions {
+		answer, ok := byKey[question.Key]
+		if !ok {
+			if question.Required || question.Knockout != nil {
+				addError(question.Key, "an answer is required")
+			}
+			continue
+		}
+		if message := screeningAnswerError(question, answer); message != "" {
+			addError(question.Key, message)
+			continue
+		}
+		if question.Knockout != nil && !question.Knockout.Passes(answer) {
+			knockedOut = append(knockedOut, question.Key)
+		}
+	}
+
+	if len(invalid) > 0 {
+		return nil, &ScreeningAnswersError{Errors: invalid}
+	}
+	return knockedOut, nil
+}
+
+// screeningAnswerError describes why answer does not fit the type of question, or returns ""
+func screeningAnswerError(question models.ScreeningQuestion, answer models.ScreeningAnswer) string {
+	switch question.Type {
+	case models.QuestionTypeYesNo:
+		if answer.Yes == nil || answer.Text != "" || answer.Number != nil {
+			return "needs a yes/no answer"
+		}
+	case models.QuestionTypeNumber:
+		if answer.Number == nil || answer.Text != "" || answer.Yes != nil {
+			return "needs a number"
+		}
+	case models.QuestionTypeMultipleChoice:
+		if !slices.Contains(question.Options, answer.Text) || answer.Yes != nil || answer.Number != nil {
+			return "needs one of the options"
+		}
+	default:
+		if strings.TrimSpace(answer.Text) == "" || answer.Yes != nil || answer.Number != nil {
+			return "needs a text answer"
+		}
+	}
+	return ""
+}
+
+// knockOut rejects a new application whose answers failed the knockout rules of the job's questions
+// with the given keys, moving it to the job's rejection stage, if any
+func knockOut(application *models.Application, job *models.Job, keys []string) error {
+	note := "Screening answers did not meet the requirements: " + strings.Join(keys, ", ")
+	rejected := models.ApplicationStatusRejected
+	change, err := transition(application, rejected, outcomeStage(job, rejected), note, middleware.SystemActor, application.AppliedTime)
+	if err != nil {
+		return err
+	}
+	application.RejectionReason = models.RejectionReasonScreening
+	application.History = append(application.History, change)
+	return nil
+}
+
+// nextAttempt returns the attempt of a new application by the candidate to the job at now: the first,
+// or the one after the last application when that was withdrawn, rejected or deleted at least the
+// reapply cooldown before
+func (s *ApplicationService) nextAttempt(ctx context.Context, jobID, userID string, now time.Time) (int, error) {
+	last, err := s.repo.GetLatestAttempt(ctx, jobID, userID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	closed := last.ClosedTime
+	if last.DeletedTime != nil {
+		closed = last.DeletedTime
+	} else if last.Status != models.ApplicationStatusWithdrawn && last.Status != models.ApplicationStatusRejected {
+		return 0, ErrAlreadyApplied
+	}
+	if closed == nil {
+		// closed before closed_time was recorded
+		closed = &last.UpdatedTime
+	}
+	if until := closed.Add(s.reapplyCooldown); now.Before(until) {
+		return 0, &ReapplyCooldownError{Until: until}
+	}
+	return max(last.Attempt, 1) + 1, nil
 }
 
-// UpdateApplicationStatus updates the status of an application
-func (s *ApplicationService) UpdateApplicationStatus(ctx context.Context, id string, status string) error {
-	return s.repo.UpdateStatus(ctx, id, status)
+// GetApplicationHistory retrieves the status history of an application the caller may read, oldest
+// change first
+func (s *ApplicationService) GetApplicationHistory(ctx context.Context, id string) ([]models.ApplicationStatusChange, error) {
+	application, err := s.GetApplicationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if application.History == nil {
+		return []models.ApplicationStatusChange{}, nil
+	}
+	return application.History, nil
 }
 
-// DeleteApplication deletes an application by ID
+// UpdateApplicationStatus moves an application to status, recording the change with note in its
+// history. Only the candidate who applied may withdraw, and the note is then the withdrawal reason; the
+// other statuses are set by admins and the recruiter who posted the job, and a note on those also
+// becomes the application's recruiter note. Accepted, rejected and withdrawn applications are closed,
+// and accepted and rejected ones move to the job's first stage with that outcome, if any.
+func (s *ApplicationService) UpdateApplicationStatus(ctx context.Context, id string, status string, note string) (*models.Application, error) {
+	application, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var stage *models.PipelineStage
+	if status == models.ApplicationStatusWithdrawn {
+		if !isCaller(ctx, application.UserID.Hex()) {
+			return nil, ErrForbidden
+		}
+	} else {
+		job, err := s.jobRepo.GetByID(ctx, application.JobID.Hex())
+		if err != nil {
+			return nil, err
+		}
+		if err := authorizeJob(ctx, job); err != nil {
+			return nil, err
+		}
+		stage = outcomeStage(job, status)
+	}
+
+	current := application.Status
+	now := time.Now()
+	change, err := transition(application, status, stage, note, middleware.ActorID(ctx), now)
+	if err != nil {
+		return nil, err
+	}
+	if status == models.ApplicationStatusWithdrawn {
+		application.WithdrawalReason = note
+	} else if note != "" {
+		application.RecruiterNote = note
+	}
+	stampUpdated(ctx, application, now)
+
+	updated, err := s.repo.UpdateStatus(ctx, id, current, application, change)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// the status changed since the application was read
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidApplicationTransition, current, status)
+	}
+	return updated, err
+}
+
+// WithdrawApplication withdraws the caller's application with an optional reason. Withdrawn
+// applications can no longer be moved by recruiters.
+func (s *ApplicationService) WithdrawApplication(ctx context.Context, id string, reason string) (*models.Application, error) {
+	return s.UpdateApplicationStatus(ctx, id, models.ApplicationStatusWithdrawn, reason)
+}
+
+// MoveApplicationStage moves an application to a job owned by the caller into the job's pipeline
+// stage with key stage, recording the move with note in its history. A stage with an outcome accepts
+// or rejects the application and any other stage puts it under review, following the status
+// transitions; a note also becomes the application's recruiter note.
+func (s *ApplicationService) MoveApplicationStage(ctx context.Context, id string, stage string, note string) (*models.Application, error) {
+	application, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.jobRepo.GetByID(ctx, application.JobID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	target := job.Stage(stage)
+	if target == nil {
+		return nil, ErrStageNotFound
+	}
+
+	current := application.Status
+	status, moved, err := stageTransition(application, target)
+	if err != nil {
+		return nil, err
+	}
+	if !moved {
+		return application, nil
+	}
+
+	now := time.Now()
+	actor := middleware.ActorID(ctx)
+	change := models.ApplicationStatusChange{
+		Status:      status,
+		FromStatus:  current,
+		Stage:       stage,
+		FromStage:   application.Stage,
+		Note:        note,
+		ChangedBy:   actor,
+		ChangedTime: now,
+	}
+	application.Status = status
+	application.Stage = stage
+	if note != "" {
+		application.RecruiterNote = note
+	}
+	closeIfFinal(application, now)
+	stampUpdated(ctx, application, now)
+
+	updated, err := s.repo.UpdateStatus(ctx, id, current, application, change)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// the status changed since the application was read
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidApplicationTransition, current, status)
+	}
+	return updated, err
+}
+
+// GetApplicationStageCounts counts the applications to a job owned by the caller in each of the job's
+// pipeline stages, in pipeline order, followed by those in no stage when there are any
+func (s *ApplicationService) GetApplicationStageCounts(ctx context.Context, jobID string) ([]models.StageCount, error) {
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repo.CountByStage(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	stageCounts := make([]models.StageCount, 0, len(job.Stages)+1)
+	for _, stage := range job.Stages {
+		stageCounts = append(stageCounts, models.StageCount{
+			Key:     stage.Key,
+			Name:    stage.Name,
+			Outcome: stage.Outcome,
+			Count:   counts[stage.Key],
+		})
+		delete(counts, stage.Key)
+	}
+	var unstaged int64
+	for _, count := range counts {
+		unstaged += count
+	}
+	if unstaged > 0 {
+		stageCounts = append(stageCounts, models.StageCount{Count: unstaged})
+	}
+	return stageCounts, nil
+}
+
+// GetRejectionReasons lists the reasons applications can be rejected with in bulk, ordered by key
+func (s *ApplicationService) GetRejectionReasons(ctx context.Context) []models.RejectionReason {
+	reasons := make([]models.RejectionReason, 0, len(s.rejectionReasons))
+	for _, key := range slices.Sorted(maps.Keys(s.rejectionReasons)) {
+		reasons = append(reasons, models.RejectionReason{Key: key, Text: s.rejectionReasons[key]})
+	}
+	return reasons
+}
+
+// BulkUpdateApplications applies action to applications to a job owned by the caller and returns the
+// result for each application, in the order given. Moves and rejections follow the status
+// transitions; an application that cannot take the action, is not an application to the job or
+// changes while the action runs fails without stopping the others.
+func (s *ApplicationService) BulkUpdateApplications(ctx context.Context, jobID string, action models.ApplicationBulkAction) ([]models.ApplicationBulkResult, error) {
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// The stage the applications move to, and the note of the change
+	var stage *models.PipelineStage
+	note := action.Note
+	switch action.Action {
+	case models.ApplicationActionMove:
+		if stage = job.Stage(action.Stage); stage == nil {
+			return nil, ErrStageNotFound
+		}
+	case models.ApplicationActionReject:
+		reason, ok := s.rejectionReasons[action.Reason]
+		if !ok {
+			return nil, ErrRejectionReasonNotFound
+		}
+		note = strings.ReplaceAll(reason, "{job_title}", job.Title)
+		if action.Note != "" {
+			note += "\n\n" + action.Note
+		}
+		stage = outcomeStage(job, models.ApplicationStatusRejected)
+	}
+
+	applications, err := s.repo.GetByJobID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.Application, len(applications))
+	for i := range applications {
+		byID[applications[i].ID.Hex()] = &applications[i]
+	}
+
+	now := time.Now()
+	actor := middleware.ActorID(ctx)
+	results := make([]models.ApplicationBulkResult, 0, len(action.ApplicationIDs))
+	seen := make(map[string]bool, len(action.ApplicationIDs))
+	pending := make(map[string]int)
+	var updates []models.ApplicationBulkUpdate
+	for _, id := range action.ApplicationIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		application, ok := byID[id]
+		if !ok {
+			results = append(results, models.ApplicationBulkResult{ID: id, Result: models.BulkResultFailed, Error: "application not found"})
+			continue
+		}
+		update, err := bulkUpdate(application, action, stage, note)
+		if err != nil {
+			results = append(results, models.ApplicationBulkResult{ID: id, Result: models.BulkResultFailed, Error: err.Error()})
+			continue
+		}
+		if update == nil {
+			results = append(results, models.ApplicationBulkResult{ID: id, Result: models.BulkResultUnchanged})
+			continue
+		}
+
+		stampUpdated(ctx, application, now)
+		if update.Change != nil {
+			closeIfFinal(application, now)
+			update.Change.ChangedBy = actor
+			update.Change.ChangedTime = now
+		}
+		pending[id] = len(results)
+		results = append(results, models.ApplicationBulkResult{ID: id, Result: models.BulkResultUpdated})
+		updates = append(updates, *update)
+	}
+	if len(updates) == 0 {
+		return results, nil
+	}
+
+	updated, err := s.repo.BulkUpdate(ctx, updates)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range updated {
+		delete(pending, id)
+	}
+	// the others changed or were deleted since they were read
+	for _, i := range pending {
+		results[i].Result = models.BulkResultFailed
+		results[i].Error = "application changed during the update"
+	}
+	return results, nil
+}
+
+// bulkUpdate returns the write of a bulk action to an application, with stage and note the stage the
+// action moves applications to and the note of the change, or nil when the application is unchanged
+func bulkUpdate(application *models.Application, action models.ApplicationBulkAction, stage *models.PipelineStage, note string) (*models.ApplicationBulkUpdate, error) {
+	if action.Action == models.ApplicationActionTag {
+		var tags []string
+		for _, tag := range action.Tags {
+			if !slices.Contains(application.Tags, tag) && !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		if len(tags) == 0 {
+			return nil, nil
+		}
+		return &models.ApplicationBulkUpdate{Application: application, Tags: tags}, nil
+	}
+
+	current := application.Status
+	var status string
+	if action.Action == models.ApplicationActionMove {
+		var moved bool
+		var err error
+		if status, moved, err = stageTransition(application, stage); err != nil || !moved {
+			return nil, err
+		}
+	} else {
+		status = models.ApplicationStatusRejected
+		if !canTransitionApplication(current, status) {
+			return nil, fmt.Errorf("%w: %s to %s", ErrInvalidApplicationTransition, current, status)
+		}
+		application.RejectionReason = action.Reason
+	}
+
+	change := &models.ApplicationStatusChange{Status: status, FromStatus: current, Note: note}
+	if stage != nil {
+		change.Stage = stage.Key
+		change.FromStage = application.Stage
+		application.Stage = stage.Key
+	}
+	application.Status = status
+	if note != "" {
+		application.RecruiterNote = note
+	}
+	return &models.ApplicationBulkUpdate{Application: application, FromStatus: current, Change: change}, nil
+}
+
+// outcomeStage returns the first pipeline stage of a job with the outcome status, or nil
+func outcomeStage(job *models.Job, status string) *models.PipelineStage {
+	for i := range job.Stages {
+		if job.Stages[i].Outcome != "" && job.Stages[i].Outcome == status {
+			return &job.Stages[i]
+		}
+	}
+	return nil
+}
+
+// stageTransition returns the status an application takes on entering a pipeline stage: the stage's
+// outcome, or under review. moved is false when the application is under review in that stage already.
+func stageTransition(application *models.Application, stage *models.PipelineStage) (status string, moved bool, err error) {
+	status = models.ApplicationStatusUnderReview
+	if stage.Outcome != "" {
+		status = stage.Outcome
+	}
+	if application.Status == models.ApplicationStatusUnderReview && status == application.Status {
+		return status, application.Stage != stage.Key, nil
+	}
+	if !canTransitionApplication(application.Status, status) {
+		return "", false, fmt.Errorf("%w: %s to %s", ErrInvalidApplicationTransition, application.Status, status)
+	}
+	return status, true, nil
+}
+
+// transition moves an application to status, and into stage unless it is nil, and returns the change to
+// record in its history. A status the application's status does not allow returns
+// ErrInvalidApplicationTransition and leaves the application unchanged.
+func transition(application *models.Application, status string, stage *models.PipelineStage, note, actor string, now time.Time) (models.ApplicationStatusChange, error) {
+	current := application.Status
+	if !canTransitionApplication(current, status) {
+		return models.ApplicationStatusChange{}, fmt.Errorf("%w: %s to %s", ErrInvalidApplicationTransition, current, status)
+	}
+
+	change := models.ApplicationStatusChange{
+		Status:      status,
+		FromStatus:  current,
+		Note:        note,
+		ChangedBy:   actor,
+		ChangedTime: now,
+	}
+	if stage != nil {
+		change.Stage = stage.Key
+		change.FromStage = application.Stage
+		application.Stage = stage.Key
+	}
+	application.Status = status
+	closeIfFinal(application, now)
+	return change, nil
+}
+
+// closeIfFinal records now as the time an application reached its status when the status is final
+func closeIfFinal(application *models.Application, now time.Time) {
+	if len(applicationTransitions[application.Status]) == 0 {
+		application.ClosedTime = &now
+	}
+}
+
+// canTransitionApplication reports whether an application may move from one status to another
+func canTransitionApplication(from, to string) bool {
+	return slices.Contains(applicationTransitions[from], to)
+}
+
+// DeleteApplication deletes one of the caller's applications by ID
 func (s *ApplicationService) DeleteApplication(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	application, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeUser(ctx, application.UserID.Hex()); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, id, middleware.ActorID(ctx))
+}
+
+// RestoreApplication restores a soft-deleted application by ID
+func (s *ApplicationService) RestoreApplication(ctx context.Context, id string) error {
+	return s.repo.Restore(ctx, id, updatedAudit(ctx, time.Now()))
+}
+
+// authorizeJobOwner allows admins and the recruiter who posted the job
+func (s *ApplicationService) authorizeJobOwner(ctx context.Context, jobID string) error {
+	if isAdmin(ctx) {
+		return nil
+	}
+
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return err
+	}
+
+	return authorizeJob(ctx, job)
 }
@@ -4,7 +4,11 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"go-mongodb-api/helpers"
+	"go-mongodb-api/interfaces"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/mocks"
 	"go-mongodb-api/models"
 	"go-mongodb-api/services"
@@ -12,8 +16,28 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
+// claimsContext returns a context carrying the claims of an authenticated caller
+func claimsContext(role, userID string) context.Context {
+	return context.WithValue(context.Background(), middleware.ClaimsKey, &middleware.Claims{Role: role, UserID: userID})
+}
+
+// updatedBy matches the audit fields of an update stamped for actor
+func updatedBy(actor string) interface{} {
+	return mock.MatchedBy(func(audit models.Audit) bool {
+		return audit.UpdatedBy == actor && !audit.UpdatedTime.IsZero()
+	})
+}
+
+// actedBy matches a context whose audit actor is actor
+func actedBy(actor string) interface{} {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		return middleware.ActorID(ctx) == actor
+	})
+}
+
 func TestApplicationService_GetAllApplications(t *testing.T) {
 	mockRepo := new(mocks.MockApplicationRepository)
 	svc := services.NewApplicationService(mockRepo, nil, nil)
@@ -33,27 +57,78 @@ func TestApplicationService_GetApplicationByID_Success(t *testing.T) {
 	svc := services.NewApplicationService(mockRepo, nil, nil)
 
 	id := bson.NewObjectID()
-	expected := &models.Application{ID: id, Status: "applied"}
+	userID := bson.NewObjectID()
+	expected := &models.Application{ID: id, UserID: userID, Status: "applied"}
 	mockRepo.On("GetByID", mock.Anything, id.Hex()).Return(expected, nil)
 
-	app, err := svc.GetApplicationByID(context.Background(), id.Hex())
+	app, err := svc.GetApplicationByID(claimsContext("candidate", userID.Hex()), id.Hex())
 	assert.NoError(t, err)
 	assert.Equal(t, "applied", app.Status)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestApplicationService_GetApplicationByID_JobOwner(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	id := bson.NewObjectID()
+	jobID := bson.NewObjectID()
+	recruiterID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, id.Hex()).Return(&models.Application{ID: id, JobID: jobID, UserID: bson.NewObjectID()}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{ID: jobID, UserID: recruiterID}, nil)
+
+	app, err := svc.GetApplicationByID(claimsContext("recruiter", recruiterID.Hex()), id.Hex())
+	assert.NoError(t, err)
+	assert.Equal(t, id, app.ID)
+	mockJobRepo.AssertExpectations(t)
+}
+
+func TestApplicationService_GetApplicationByID_Forbidden(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	id := bson.NewObjectID()
+	jobID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, id.Hex()).Return(&models.Application{ID: id, JobID: jobID, UserID: bson.NewObjectID()}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{ID: jobID, UserID: bson.NewObjectID()}, nil)
+
+	app, err := svc.GetApplicationByID(claimsContext("candidate", bson.NewObjectID().Hex()), id.Hex())
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	assert.Nil(t, app)
+}
+
 func TestApplicationService_GetApplicationsByJobID(t *testing.T) {
 	mockRepo := new(mocks.MockApplicationRepository)
-	svc := services.NewApplicationService(mockRepo, nil, nil)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
 
 	jobID := bson.NewObjectID()
+	recruiterID := bson.NewObjectID()
 	expected := []models.Application{{Status: "applied"}}
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{ID: jobID, UserID: recruiterID}, nil)
 	mockRepo.On("GetByJobID", mock.Anything, jobID.Hex()).Return(expected, nil)
 
-	apps, err := svc.GetApplicationsByJobID(context.Background(), jobID.Hex())
+	apps, err := svc.GetApplicationsByJobID(claimsContext("recruiter", recruiterID.Hex()), jobID.Hex())
 	assert.NoError(t, err)
 	assert.Len(t, apps, 1)
 	mockRepo.AssertExpectations(t)
+	mockJobRepo.AssertExpectations(t)
+}
+
+func TestApplicationService_GetApplicationsByJobID_NotJobOwner(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{ID: jobID, UserID: bson.NewObjectID()}, nil)
+
+	apps, err := svc.GetApplicationsByJobID(claimsContext("recruiter", bson.NewObjectID().Hex()), jobID.Hex())
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	assert.Nil(t, apps)
+	mockRepo.AssertNotCalled(t, "GetByJobID", mock.Anything, mock.Anything)
 }
 
 func TestApplicationService_GetApplicationsByUserID(t *testing.T) {
@@ -64,12 +139,35 @@ func TestApplicationService_GetApplicationsByUserID(t *testing.T) {
 	expected := []models.Application{{Status: "accepted"}}
 	mockRepo.On("GetByUserID", mock.Anything, userID.Hex()).Return(expected, nil)
 
-	apps, err := svc.GetApplicationsByUserID(context.Background(), userID.Hex())
+	apps, err := svc.GetApplicationsByUserID(claimsContext("candidate", userID.Hex()), userID.Hex())
 	assert.NoError(t, err)
 	assert.Len(t, apps, 1)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestApplicationService_GetApplicationsByUserID_OtherCandidate(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	svc := services.NewApplicationService(mockRepo, nil, nil)
+
+	apps, err := svc.GetApplicationsByUserID(claimsContext("candidate", bson.NewObjectID().Hex()), bson.NewObjectID().Hex())
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	assert.Nil(t, apps)
+	mockRepo.AssertNotCalled(t, "GetByUserID", mock.Anything, mock.Anything)
+}
+
+func TestApplicationService_ExpandApplications(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	svc := services.NewApplicationService(mockRepo, nil, nil)
+
+	applications := []models.Application{{ID: bson.NewObjectID()}}
+	expand := []string{models.ExpandJob, models.ExpandCandidate}
+	mockRepo.On("Expand", mock.Anything, applications, expand).Return(errors.New("db error"))
+
+	err := svc.ExpandApplications(context.Background(), applications, expand)
+	assert.EqualError(t, err, "db error")
+	mockRepo.AssertExpectations(t)
+}
+
 func TestApplicationService_CreateApplication_Success(t *testing.T) {
 	mockRepo := new(mocks.MockApplicationRepository)
 	mockJobRepo := new(mocks.MockJobRepository)
@@ -83,17 +181,48 @@ func TestApplicationService_CreateApplication_Success(t *testing.T) {
 		UserID: userID,
 		Status: "applied",
 	}
-	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{Status: models.JobStatusActive}, nil)
 	mockUserRepo.On("GetByID", mock.Anything, userID.Hex()).Return(&models.User{}, nil)
+	mockRepo.On("GetLatestAttempt", mock.Anything, jobID.Hex(), userID.Hex()).Return(nil, mongo.ErrNoDocuments)
 	mockRepo.On("Create", mock.Anything, app).Return(nil)
 
-	err := svc.CreateApplication(context.Background(), app)
+	err := svc.CreateApplication(claimsContext("candidate", app.UserID.Hex()), app)
 	assert.NoError(t, err)
+	assert.Equal(t, 1, app.Attempt)
+	assert.Equal(t, userID.Hex(), app.CreatedBy)
+	assert.Equal(t, userID.Hex(), app.History[0].ChangedBy)
+	assert.False(t, app.AppliedTime.IsZero())
 	mockRepo.AssertExpectations(t)
 	mockJobRepo.AssertExpectations(t)
 	mockUserRepo.AssertExpectations(t)
 }
 
+func TestApplicationService_CreateApplication_StartsInFirstStage(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, mockUserRepo)
+
+	jobID := bson.NewObjectID()
+	userID := bson.NewObjectID()
+	job := &models.Job{Status: models.JobStatusActive, Stages: []models.PipelineStage{
+		{Key: "screen", Name: "Screening"},
+		{Key: "hired", Name: "Hired", Outcome: models.ApplicationStatusAccepted},
+	}}
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(job, nil)
+	mockUserRepo.On("GetByID", mock.Anything, userID.Hex()).Return(&models.User{}, nil)
+	mockRepo.On("GetLatestAttempt", mock.Anything, jobID.Hex(), userID.Hex()).Return(nil, mongo.ErrNoDocuments)
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(a *models.Application) bool {
+		return a.Stage == "screen" && len(a.History) == 1 &&
+			a.History[0].Status == models.ApplicationStatusApplied && a.History[0].Stage == "screen"
+	})).Return(nil)
+
+	app := &models.Application{JobID: jobID, UserID: userID, Status: models.ApplicationStatusApplied, Stage: "hired"}
+	err := svc.CreateApplication(claimsContext("candidate", userID.Hex()), app)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestApplicationService_CreateApplication_JobNotFound(t *testing.T) {
 	mockRepo := new(mocks.MockApplicationRepository)
 	mockJobRepo := new(mocks.MockJobRepository)
@@ -108,7 +237,7 @@ func TestApplicationService_CreateApplication_JobNotFound(t *testing.T) {
 	}
 	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(nil, errors.New("not found"))
 
-	err := svc.CreateApplication(context.Background(), app)
+	err := svc.CreateApplication(claimsContext("candidate", app.UserID.Hex()), app)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "job not found")
 	mockJobRepo.AssertExpectations(t)
@@ -127,32 +256,940 @@ func TestApplicationService_CreateApplication_UserNotFound(t *testing.T) {
 		UserID: userID,
 		Status: "applied",
 	}
-	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{Status: models.JobStatusActive}, nil)
 	mockUserRepo.On("GetByID", mock.Anything, userID.Hex()).Return(nil, errors.New("not found"))
 
-	err := svc.CreateApplication(context.Background(), app)
+	err := svc.CreateApplication(claimsContext("candidate", app.UserID.Hex()), app)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "user not found")
 }
 
+func TestApplicationService_CreateApplication_JobNotOpen(t *testing.T) {
+	expired := time.Now().Add(-time.Hour)
+	jobs := map[string]*models.Job{
+		"draft":    {Status: models.JobStatusDraft},
+		"closed":   {Status: models.JobStatusClosed},
+		"archived": {Status: models.JobStatusArchived},
+		"expired":  {Status: models.JobStatusActive, ClosesAt: &expired},
+	}
+	for name, job := range jobs {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(mocks.MockApplicationRepository)
+			mockJobRepo := new(mocks.MockJobRepository)
+			svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+			app := &models.Application{JobID: bson.NewObjectID(), UserID: bson.NewObjectID(), Status: "applied"}
+			mockJobRepo.On("GetByID", mock.Anything, app.JobID.Hex()).Return(job, nil)
+
+			err := svc.CreateApplication(claimsContext("candidate", app.UserID.Hex()), app)
+			assert.ErrorIs(t, err, services.ErrJobNotOpen)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestApplicationService_CreateApplication_ForAnotherUser(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	svc := services.NewApplicationService(mockRepo, nil, nil)
+
+	app := &models.Application{JobID: bson.NewObjectID(), UserID: bson.NewObjectID(), Status: "applied"}
+
+	err := svc.CreateApplication(claimsContext("candidate", bson.NewObjectID().Hex()), app)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// reapplyService returns an application service with a 30 day reapply cooldown, where the latest
+// application of app's candidate to its open job is last, or lastErr
+func reapplyService(app *models.Application, last *models.Application, lastErr error) (*services.ApplicationService, *mocks.MockApplicationRepository) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, mockUserRepo, services.WithReapplyCooldown(30*24*time.Hour))
+
+	mockJobRepo.On("GetByID", mock.Anything, app.JobID.Hex()).Return(&models.Job{Status: models.JobStatusActive}, nil)
+	mockUserRepo.On("GetByID", mock.Anything, app.UserID.Hex()).Return(&models.User{}, nil)
+	mockRepo.On("GetLatestAttempt", mock.Anything, app.JobID.Hex(), app.UserID.Hex()).Return(last, lastErr)
+	return svc, mockRepo
+}
+
+func TestApplicationService_CreateApplication_ReapplyAfterCooldown(t *testing.T) {
+	now := time.Now()
+	closed := now.Add(-31 * 24 * time.Hour)
+	for _, status := range []string{models.ApplicationStatusWithdrawn, models.ApplicationStatusRejected} {
+		t.Run(status, func(t *testing.T) {
+			app := &models.Application{JobID: bson.NewObjectID(), UserID: bson.NewObjectID(), AppliedTime: now}
+			svc, mockRepo := reapplyService(app, &models.Application{Status: status, Attempt: 1, ClosedTime: &closed}, nil)
+			mockRepo.On("Create", mock.Anything, app).Return(nil)
+
+			err := svc.CreateApplication(claimsContext("candidate", app.UserID.Hex()), app)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, app.Attempt)
+		})
+	}
+}
+
+func TestApplicationService_CreateApplication_ReapplyDuringCooldown(t *testing.T) {
+	now := time.Now()
+	closed := now.Add(-10 * 24 * time.Hour)
+	app := &models.Application{JobID: bson.NewObjectID(), UserID: bson.NewObjectID(), AppliedTime: now}
+	svc, mockRepo := reapplyService(app, &models.Application{Status: models.ApplicationStatusRejected, Attempt: 1, ClosedTime: &closed}, nil)
+
+	err := svc.CreateApplication(claimsContext("candidate", app.UserID.Hex()), app)
+	var cooldownErr *services.ReapplyCooldownError
+	if assert.ErrorAs(t, err, &cooldownErr) {
+		assert.True(t, cooldownErr.Until.Equal(closed.Add(30*24*time.Hour)))
+	}
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestApplicationService_CreateApplication_AfterDelete(t *testing.T) {
+	now := time.Now()
+	deleted := now.Add(-31 * 24 * time.Hour)
+	app := &models.Application{JobID: bson.NewObjectID(), UserID: bson.NewObjectID(), AppliedTime: now}
+	svc, mockRepo := reapplyService(app, &models.Application{Status: models.ApplicationStatusApplied, SoftDelete: models.SoftDelete{DeletedTime: &deleted}}, nil)
+	mockRepo.On("Create", mock.Anything, app).Return(nil)
+
+	err := svc.CreateApplication(claimsContext("candidate", app.UserID.Hex()), app)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, app.Attempt)
+}
+
+func TestApplicationService_CreateApplication_AlreadyApplied(t *testing.T) {
+	for _, status := range []string{models.ApplicationStatusApplied, models.ApplicationStatusUnderReview, models.ApplicationStatusAccepted} {
+		t.Run(status, func(t *testing.T) {
+			app := &models.Application{JobID: bson.NewObjectID(), UserID: bson.NewObjectID(), AppliedTime: time.Now()}
+			svc, mockRepo := reapplyService(app, &models.Application{Status: status, Attempt: 1}, nil)
+
+			err := svc.CreateApplication(claimsContext("candidate", app.UserID.Hex()), app)
+			assert.ErrorIs(t, err, services.ErrAlreadyApplied)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestApplicationService_CreateApplication_DuplicateKey(t *testing.T) {
+	app := &models.Application{JobID: bson.NewObjectID(), UserID: bson.NewObjectID(), AppliedTime: time.Now()}
+	svc, mockRepo := reapplyService(app, nil, mongo.ErrNoDocuments)
+	mockRepo.On("Create", mock.Anything, app).Return(interfaces.ErrDuplicateKey)
+
+	err := svc.CreateApplication(claimsContext("candidate", app.UserID.Hex()), app)
+	assert.ErrorIs(t, err, services.ErrAlreadyApplied)
+}
+
+// screeningService returns an application service where job is open and the candidate has not
+// applied to it before
+func screeningService(app *models.Application, job *models.Job) (*services.ApplicationService, *mocks.MockApplicationRepository) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, mockUserRepo)
+
+	job.Status = models.JobStatusActive
+	mockJobRepo.On("GetByID", mock.Anything, app.JobID.Hex()).Return(job, nil)
+	mockUserRepo.On("GetByID", mock.Anything, app.UserID.Hex()).Return(&models.User{}, nil)
+	mockRepo.On("GetLatestAttempt", mock.Anything, app.JobID.Hex(), app.UserID.Hex()).Return(nil, mongo.ErrNoDocuments)
+	return svc, mockRepo
+}
+
+// screeningJob returns a job asking whether the candidate can relocate (required), their shift (one
+// of day or night, knocking out night) and their years of Go (knocking out under 3)
+func screeningJob() *models.Job {
+	yes := true
+	minYears := 3.0
+	return &models.Job{ScreeningQuestions: []models.ScreeningQuestion{
+		{Key: "relocate", Question: "Can you relocate?", Type: models.QuestionTypeYesNo, Required: true},
+		{Key: "shift", Question: "Which shift?", Type: models.QuestionTypeMultipleChoice, Options: []string{"day", "night"},
+			Knockout: &models.KnockoutRule{Accepted: []string{"day"}}},
+		{Key: "years_go", Question: "Years of Go?", Type: models.QuestionTypeNumber, Knockout: &models.KnockoutRule{Min: &minYears}},
+		{Key: "why", Question: "Why this role?", Type: models.QuestionTypeText},
+		{Key: "visa", Question: "Do you need a visa?", Type: models.QuestionTypeYesNo, Knockout: &models.KnockoutRule{Expected: &yes}},
+	}}
+}
+
+func screeningAnswers(shift string, years float64) []models.ScreeningAnswer {
+	yes := true
+	return []models.ScreeningAnswer{
+		{Key: "relocate", Yes: &yes},
+		{Key: "shift", Text: shift},
+		{Key: "years_go", Number: &years},
+		{Key: "visa", Yes: &yes},
+	}
+}
+
+func TestApplicationService_CreateApplication_ScreeningPassed(t *testing.T) {
+	app := &models.Application{JobID: bson.NewObjectID(), UserID: bson.NewObjectID(), Status: models.ApplicationStatusApplied,
+		Answers: screeningAnswers("day", 5)}
+	svc, mockRepo := screeningService(app, screeningJob())
+	mockRepo.On("Create", mock.Anything, app).Return(nil)
+
+	err := svc.CreateApplication(claimsContext("candidate", app.UserID.Hex()), app)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ApplicationStatusApplied, app.Status)
+	assert.Len(t, app.History, 1)
+	assert.Nil(t, app.ClosedTime)
+}
+
+func TestApplicationService_CreateApplication_KnockedOut(t *testing.T) {
+	job := screeningJob()
+	job.Stages = pipelineJob(bson.NewObjectID(), bson.NewObjectID()).Stages
+	app := &models.Application{JobID: bson.NewObjectID(), UserID: bson.NewObjectID(), Status: models.ApplicationStatusApplied,
+		AppliedTime: time.Now(), CreatedBy: "candidate-id", Answers: screeningAnswers("night", 1)}
+	svc, mockRepo := screeningService(app, job)
+	mockRepo.On("Create", mock.Anything, app).Return(nil)
+
+	err := svc.CreateApplication(claimsContext("candidate", app.UserID.Hex()), app)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ApplicationStatusRejected, app.Status)
+	assert.Equal(t, models.RejectionReasonScreening, app.RejectionReason)
+	assert.Equal(t, "declined", app.Stage)
+	if assert.NotNil(t, app.ClosedTime) {
+		assert.Equal(t, app.AppliedTime, *app.ClosedTime)
+	}
+	if assert.Len(t, app.History, 2) {
+		assert.Equal(t, models.ApplicationStatusApplied, app.History[0].Status)
+		assert.Equal(t, "screen", app.History[0].Stage)
+		rejection := app.History[1]
+		assert.Equal(t, models.ApplicationStatusRejected, rejection.Status)
+		assert.Equal(t, models.ApplicationStatusApplied, rejection.FromStatus)
+		assert.Equal(t, "screen", rejection.FromStage)
+		assert.Equal(t, "declined", rejection.Stage)
+		assert.Equal(t, "Screening answers did not meet the requirements: shift, years_go", rejection.Note)
+		assert.Equal(t, middleware.SystemActor, rejection.ChangedBy)
+	}
+}
+
+func TestApplicationService_CreateApplication_KnockOutFollowsTransitions(t *testing.T) {
+	job := screeningJob()
+	app := &models.Application{JobID: bson.NewObjectID(), UserID: bson.NewObjectID(), Status: models.ApplicationStatusAccepted,
+		AppliedTime: time.Now(), Answers: screeningAnswers("night", 1)}
+	svc, mockRepo := screeningService(app, job)
+
+	err := svc.CreateApplication(claimsContext("candidate", app.UserID.Hex()), app)
+	assert.ErrorIs(t, err, services.ErrInvalidApplicationTransition)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestApplicationService_CreateApplication_InvalidAnswers(t *testing.T) {
+	no := false
+	years := 4.0
+	tests := []struct {
+		name    string
+		answers []models.ScreeningAnswer
+		message string
+	}{
+		{"required missing", screeningAnswers("day", 5)[1:], "relocate: an answer is required"},
+		{"knockout missing", screeningAnswers("day", 5)[:2], "years_go: an answer is required"},
+		{"unknown question", append(screeningAnswers("day", 5), models.ScreeningAnswer{Key: "salary", Text: "100k"}), "salary: not a screening question of this job"},
+		{"answered twice", append(screeningAnswers("day", 5), models.ScreeningAnswer{Key: "relocate", Yes: &no}), "relocate: answered more than once"},
+		{"not an option", screeningAnswers("weekend", 5), "shift: needs one of the options"},
+		{"text for yes/no", append(screeningAnswers("day", 5)[1:], models.ScreeningAnswer{Key: "relocate", Text: "yes"}), "relocate: needs a yes/no answer"},
+		{"blank text", append(screeningAnswers("day", 5), models.ScreeningAnswer{Key: "why", Text: "  "}), "why: needs a text answer"},
+		{"number and text", append(screeningAnswers("day", 5)[:2], models.ScreeningAnswer{Key: "years_go", Text: "four", Number: &years}, screeningAnswers("day", 5)[3]), "years_go: needs a number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &models.Application{JobID: bson.NewObjectID(), UserID: bson.NewObjectID(), Status: models.ApplicationStatusApplied, Answers: tt.answers}
+			svc, mockRepo := screeningService(app, screeningJob())
+
+			err := svc.CreateApplication(claimsContext("candidate", app.UserID.Hex()), app)
+			var answersErr *services.ScreeningAnswersError
+			if assert.ErrorAs(t, err, &answersErr) {
+				assert.Contains(t, answersErr.Errors, helpers.ValidationError{Field: "answers", Message: tt.message})
+			}
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestApplicationService_ExpandApplications_HidesKnockouts(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	svc := services.NewApplicationService(mockRepo, nil, nil)
+
+	candidateID := bson.NewObjectID()
+	applications := []models.Application{{UserID: candidateID}}
+	expand := []string{"job"}
+	mockRepo.On("Expand", mock.Anything, applications, expand).Return(nil).Run(func(args mock.Arguments) {
+		job := screeningJob()
+		job.UserID = bson.NewObjectID()
+		args.Get(1).([]models.Application)[0].Job = job
+	})
+
+	err := svc.ExpandApplications(claimsContext("candidate", candidateID.Hex()), applications, expand)
+	assert.NoError(t, err)
+	for _, question := range applications[0].Job.ScreeningQuestions {
+		assert.Nil(t, question.Knockout)
+	}
+}
+
 func TestApplicationService_UpdateApplicationStatus(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	recruiterID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{JobID: jobID, Status: models.ApplicationStatusUnderReview}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{ID: jobID, UserID: recruiterID}, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, "app-id", models.ApplicationStatusUnderReview,
+		mock.MatchedBy(func(a *models.Application) bool {
+			return a.Status == models.ApplicationStatusAccepted && a.RecruiterNote == "Great fit" && a.UpdatedBy == recruiterID.Hex()
+		}),
+		mock.MatchedBy(func(c models.ApplicationStatusChange) bool {
+			return c.Status == models.ApplicationStatusAccepted && c.FromStatus == models.ApplicationStatusUnderReview &&
+				c.Note == "Great fit" && c.ChangedBy == recruiterID.Hex() && !c.ChangedTime.IsZero()
+		}),
+	).Return(&models.Application{Status: models.ApplicationStatusAccepted}, nil)
+
+	app, err := svc.UpdateApplicationStatus(claimsContext("recruiter", recruiterID.Hex()), "app-id", models.ApplicationStatusAccepted, "Great fit")
+	assert.NoError(t, err)
+	assert.Equal(t, models.ApplicationStatusAccepted, app.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestApplicationService_UpdateApplicationStatus_NotJobOwner(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{JobID: jobID, Status: models.ApplicationStatusUnderReview}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{ID: jobID, UserID: bson.NewObjectID()}, nil)
+
+	_, err := svc.UpdateApplicationStatus(claimsContext("recruiter", bson.NewObjectID().Hex()), "app-id", models.ApplicationStatusAccepted, "")
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApplicationService_UpdateApplicationStatus_Admin(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	job := pipelineJob(bson.NewObjectID(), bson.NewObjectID())
+	mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{JobID: job.ID, Status: models.ApplicationStatusUnderReview, Stage: "interview"}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, job.ID.Hex()).Return(job, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, "app-id", models.ApplicationStatusUnderReview,
+		mock.MatchedBy(func(a *models.Application) bool {
+			return a.Status == models.ApplicationStatusRejected && a.Stage == "declined"
+		}),
+		mock.MatchedBy(func(c models.ApplicationStatusChange) bool {
+			return c.Stage == "declined" && c.FromStage == "interview"
+		}),
+	).Return(&models.Application{Status: models.ApplicationStatusRejected}, nil)
+
+	_, err := svc.UpdateApplicationStatus(claimsContext("admin", bson.NewObjectID().Hex()), "app-id", models.ApplicationStatusRejected, "")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestApplicationService_UpdateApplicationStatus_InvalidTransition(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+	}{
+		{models.ApplicationStatusApplied, models.ApplicationStatusAccepted},
+		{models.ApplicationStatusUnderReview, models.ApplicationStatusApplied},
+		{models.ApplicationStatusAccepted, models.ApplicationStatusRejected},
+		{models.ApplicationStatusRejected, models.ApplicationStatusUnderReview},
+		{models.ApplicationStatusWithdrawn, models.ApplicationStatusUnderReview},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			mockRepo := new(mocks.MockApplicationRepository)
+			mockJobRepo := new(mocks.MockJobRepository)
+			svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+			jobID := bson.NewObjectID()
+			mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{JobID: jobID, Status: tt.from}, nil)
+			mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{ID: jobID}, nil)
+
+			_, err := svc.UpdateApplicationStatus(claimsContext("admin", bson.NewObjectID().Hex()), "app-id", tt.to, "")
+			assert.ErrorIs(t, err, services.ErrInvalidApplicationTransition)
+			mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestApplicationService_UpdateApplicationStatus_ConcurrentChange(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{JobID: jobID, Status: models.ApplicationStatusApplied}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{ID: jobID}, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, "app-id", models.ApplicationStatusApplied, mock.Anything, mock.Anything).Return(nil, mongo.ErrNoDocuments)
+
+	_, err := svc.UpdateApplicationStatus(claimsContext("admin", bson.NewObjectID().Hex()), "app-id", models.ApplicationStatusUnderReview, "")
+	assert.ErrorIs(t, err, services.ErrInvalidApplicationTransition)
+}
+
+func TestApplicationService_UpdateApplicationStatus_NotFound(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	svc := services.NewApplicationService(mockRepo, nil, nil)
+
+	mockRepo.On("GetByID", mock.Anything, "app-id").Return(nil, mongo.ErrNoDocuments)
+
+	_, err := svc.UpdateApplicationStatus(claimsContext("admin", bson.NewObjectID().Hex()), "app-id", models.ApplicationStatusUnderReview, "")
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+}
+
+func TestApplicationService_UpdateApplicationStatus_WithdrawByCandidate(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	candidateID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{UserID: candidateID, Status: models.ApplicationStatusApplied, RecruiterNote: "Call back"}, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, "app-id", models.ApplicationStatusApplied,
+		mock.MatchedBy(func(a *models.Application) bool {
+			return a.Status == models.ApplicationStatusWithdrawn && a.RecruiterNote == "Call back" &&
+				a.WithdrawalReason == "Accepted another offer" && a.ClosedTime != nil
+		}),
+		mock.MatchedBy(func(c models.ApplicationStatusChange) bool { return c.Note == "Accepted another offer" }),
+	).Return(&models.Application{Status: models.ApplicationStatusWithdrawn}, nil)
+
+	_, err := svc.UpdateApplicationStatus(claimsContext("candidate", candidateID.Hex()), "app-id", models.ApplicationStatusWithdrawn, "Accepted another offer")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockJobRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestApplicationService_WithdrawApplication(t *testing.T) {
 	mockRepo := new(mocks.MockApplicationRepository)
 	svc := services.NewApplicationService(mockRepo, nil, nil)
 
-	mockRepo.On("UpdateStatus", mock.Anything, "app-id", "accepted").Return(nil)
+	candidateID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{UserID: candidateID, Status: models.ApplicationStatusUnderReview}, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, "app-id", models.ApplicationStatusUnderReview,
+		mock.MatchedBy(func(a *models.Application) bool {
+			return a.Status == models.ApplicationStatusWithdrawn && a.WithdrawalReason == "" && a.ClosedTime != nil
+		}),
+		mock.MatchedBy(func(c models.ApplicationStatusChange) bool { return c.Status == models.ApplicationStatusWithdrawn }),
+	).Return(&models.Application{Status: models.ApplicationStatusWithdrawn}, nil)
 
-	err := svc.UpdateApplicationStatus(context.Background(), "app-id", "accepted")
+	application, err := svc.WithdrawApplication(claimsContext("candidate", candidateID.Hex()), "app-id", "")
 	assert.NoError(t, err)
+	assert.Equal(t, models.ApplicationStatusWithdrawn, application.Status)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestApplicationService_MoveApplicationStage_Withdrawn(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	recruiterID := bson.NewObjectID()
+	job := pipelineJob(bson.NewObjectID(), recruiterID)
+	mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{JobID: job.ID, Status: models.ApplicationStatusWithdrawn, Stage: "screen"}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, job.ID.Hex()).Return(job, nil)
+
+	_, err := svc.MoveApplicationStage(claimsContext("recruiter", recruiterID.Hex()), "app-id", "interview", "")
+	assert.ErrorIs(t, err, services.ErrInvalidApplicationTransition)
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApplicationService_UpdateApplicationStatus_WithdrawByOthers(t *testing.T) {
+	for _, role := range []string{"recruiter", "admin", "candidate"} {
+		t.Run(role, func(t *testing.T) {
+			mockRepo := new(mocks.MockApplicationRepository)
+			svc := services.NewApplicationService(mockRepo, nil, nil)
+
+			mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{UserID: bson.NewObjectID(), Status: models.ApplicationStatusApplied}, nil)
+
+			_, err := svc.UpdateApplicationStatus(claimsContext(role, bson.NewObjectID().Hex()), "app-id", models.ApplicationStatusWithdrawn, "")
+			assert.ErrorIs(t, err, services.ErrForbidden)
+			mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestApplicationService_UpdateApplicationStatus_CandidateCannotReview(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	candidateID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{JobID: jobID, UserID: candidateID, Status: models.ApplicationStatusUnderReview}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{ID: jobID, UserID: bson.NewObjectID()}, nil)
+
+	_, err := svc.UpdateApplicationStatus(claimsContext("candidate", candidateID.Hex()), "app-id", models.ApplicationStatusAccepted, "")
+	assert.ErrorIs(t, err, services.ErrForbidden)
+}
+
+// pipelineJob returns a job owned by recruiterID with a screening, an interview and two outcome stages
+func pipelineJob(jobID, recruiterID bson.ObjectID) *models.Job {
+	return &models.Job{ID: jobID, UserID: recruiterID, Stages: []models.PipelineStage{
+		{Key: "screen", Name: "Screening"},
+		{Key: "interview", Name: "Interview"},
+		{Key: "hired", Name: "Hired", Outcome: models.ApplicationStatusAccepted},
+		{Key: "declined", Name: "Declined", Outcome: models.ApplicationStatusRejected},
+	}}
+}
+
+func TestApplicationService_MoveApplicationStage(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	recruiterID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{JobID: jobID, Status: models.ApplicationStatusApplied, Stage: "screen"}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(pipelineJob(jobID, recruiterID), nil)
+	mockRepo.On("UpdateStatus", mock.Anything, "app-id", models.ApplicationStatusApplied,
+		mock.MatchedBy(func(a *models.Application) bool {
+			return a.Status == models.ApplicationStatusUnderReview && a.Stage == "interview" && a.RecruiterNote == "Strong CV"
+		}),
+		mock.MatchedBy(func(c models.ApplicationStatusChange) bool {
+			return c.Status == models.ApplicationStatusUnderReview && c.FromStatus == models.ApplicationStatusApplied &&
+				c.Stage == "interview" && c.FromStage == "screen" && c.ChangedBy == recruiterID.Hex()
+		}),
+	).Return(&models.Application{Status: models.ApplicationStatusUnderReview, Stage: "interview"}, nil)
+
+	app, err := svc.MoveApplicationStage(claimsContext("recruiter", recruiterID.Hex()), "app-id", "interview", "Strong CV")
+	assert.NoError(t, err)
+	assert.Equal(t, "interview", app.Stage)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestApplicationService_MoveApplicationStage_OutcomeStage(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	recruiterID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{JobID: jobID, Status: models.ApplicationStatusUnderReview, Stage: "interview"}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(pipelineJob(jobID, recruiterID), nil)
+	mockRepo.On("UpdateStatus", mock.Anything, "app-id", models.ApplicationStatusUnderReview,
+		mock.MatchedBy(func(a *models.Application) bool {
+			return a.Status == models.ApplicationStatusAccepted && a.Stage == "hired"
+		}),
+		mock.Anything,
+	).Return(&models.Application{Status: models.ApplicationStatusAccepted, Stage: "hired"}, nil)
+
+	app, err := svc.MoveApplicationStage(claimsContext("recruiter", recruiterID.Hex()), "app-id", "hired", "")
+	assert.NoError(t, err)
+	assert.Equal(t, models.ApplicationStatusAccepted, app.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestApplicationService_MoveApplicationStage_InvalidTransition(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	recruiterID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{JobID: jobID, Status: models.ApplicationStatusApplied, Stage: "screen"}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(pipelineJob(jobID, recruiterID), nil)
+
+	_, err := svc.MoveApplicationStage(claimsContext("recruiter", recruiterID.Hex()), "app-id", "hired", "")
+	assert.ErrorIs(t, err, services.ErrInvalidApplicationTransition)
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApplicationService_MoveApplicationStage_StageNotFound(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	recruiterID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{JobID: jobID, Status: models.ApplicationStatusApplied}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(pipelineJob(jobID, recruiterID), nil)
+
+	_, err := svc.MoveApplicationStage(claimsContext("recruiter", recruiterID.Hex()), "app-id", "offer", "")
+	assert.ErrorIs(t, err, services.ErrStageNotFound)
+}
+
+func TestApplicationService_MoveApplicationStage_NotJobOwner(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{JobID: jobID, Status: models.ApplicationStatusApplied}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(pipelineJob(jobID, bson.NewObjectID()), nil)
+
+	_, err := svc.MoveApplicationStage(claimsContext("recruiter", bson.NewObjectID().Hex()), "app-id", "interview", "")
+	assert.ErrorIs(t, err, services.ErrForbidden)
+}
+
+func TestApplicationService_MoveApplicationStage_SameStage(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	recruiterID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{JobID: jobID, Status: models.ApplicationStatusUnderReview, Stage: "interview"}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(pipelineJob(jobID, recruiterID), nil)
+
+	app, err := svc.MoveApplicationStage(claimsContext("recruiter", recruiterID.Hex()), "app-id", "interview", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "interview", app.Stage)
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApplicationService_GetApplicationStageCounts(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	recruiterID := bson.NewObjectID()
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(pipelineJob(jobID, recruiterID), nil)
+	mockRepo.On("CountByStage", mock.Anything, jobID.Hex()).Return(map[string]int64{"screen": 4, "hired": 1, "": 2, "removed": 1}, nil)
+
+	counts, err := svc.GetApplicationStageCounts(claimsContext("recruiter", recruiterID.Hex()), jobID.Hex())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.StageCount{
+		{Key: "screen", Name: "Screening", Count: 4},
+		{Key: "interview", Name: "Interview", Count: 0},
+		{Key: "hired", Name: "Hired", Outcome: models.ApplicationStatusAccepted, Count: 1},
+		{Key: "declined", Name: "Declined", Outcome: models.ApplicationStatusRejected, Count: 0},
+		{Count: 3},
+	}, counts)
+}
+
+func TestApplicationService_GetApplicationStageCounts_NotJobOwner(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(pipelineJob(jobID, bson.NewObjectID()), nil)
+
+	_, err := svc.GetApplicationStageCounts(claimsContext("recruiter", bson.NewObjectID().Hex()), jobID.Hex())
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "CountByStage", mock.Anything, mock.Anything)
+}
+
+func TestApplicationService_GetApplicationHistory(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	svc := services.NewApplicationService(mockRepo, nil, nil)
+
+	candidateID := bson.NewObjectID()
+	history := []models.ApplicationStatusChange{
+		{Status: models.ApplicationStatusApplied, ChangedBy: candidateID.Hex()},
+		{Status: models.ApplicationStatusUnderReview, FromStatus: models.ApplicationStatusApplied},
+	}
+	mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{UserID: candidateID, History: history}, nil)
+
+	got, err := svc.GetApplicationHistory(claimsContext("candidate", candidateID.Hex()), "app-id")
+	assert.NoError(t, err)
+	assert.Equal(t, history, got)
+}
+
+func TestApplicationService_GetApplicationHistory_Empty(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	svc := services.NewApplicationService(mockRepo, nil, nil)
+
+	candidateID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{UserID: candidateID}, nil)
+
+	got, err := svc.GetApplicationHistory(claimsContext("candidate", candidateID.Hex()), "app-id")
+	assert.NoError(t, err)
+	assert.NotNil(t, got)
+	assert.Empty(t, got)
+}
+
+func TestApplicationService_GetApplicationHistory_Forbidden(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{JobID: jobID, UserID: bson.NewObjectID()}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{ID: jobID, UserID: bson.NewObjectID()}, nil)
+
+	_, err := svc.GetApplicationHistory(claimsContext("recruiter", bson.NewObjectID().Hex()), "app-id")
+	assert.ErrorIs(t, err, services.ErrForbidden)
+}
+
 func TestApplicationService_DeleteApplication(t *testing.T) {
 	mockRepo := new(mocks.MockApplicationRepository)
 	svc := services.NewApplicationService(mockRepo, nil, nil)
 
-	mockRepo.On("Delete", mock.Anything, "app-id").Return(nil)
+	userID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{UserID: userID}, nil)
+	mockRepo.On("Delete", mock.Anything, "app-id", mock.Anything).Return(nil)
 
-	err := svc.DeleteApplication(context.Background(), "app-id")
+	err := svc.DeleteApplication(claimsContext("candidate", userID.Hex()), "app-id")
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestApplicationService_DeleteApplication_OtherCandidate(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	svc := services.NewApplicationService(mockRepo, nil, nil)
+
+	mockRepo.On("GetByID", mock.Anything, "app-id").Return(&models.Application{UserID: bson.NewObjectID()}, nil)
+
+	err := svc.DeleteApplication(claimsContext("candidate", bson.NewObjectID().Hex()), "app-id")
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestApplicationService_BulkUpdateApplications_Move(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	recruiterID := bson.NewObjectID()
+	applied := models.Application{ID: bson.NewObjectID(), JobID: jobID, Status: models.ApplicationStatusApplied, Stage: "screen"}
+	interviewing := models.Application{ID: bson.NewObjectID(), JobID: jobID, Status: models.ApplicationStatusUnderReview, Stage: "interview"}
+	accepted := models.Application{ID: bson.NewObjectID(), JobID: jobID, Status: models.ApplicationStatusAccepted, Stage: "hired"}
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(pipelineJob(jobID, recruiterID), nil)
+	mockRepo.On("GetByJobID", mock.Anything, jobID.Hex()).Return([]models.Application{applied, interviewing, accepted}, nil)
+	mockRepo.On("BulkUpdate", mock.Anything, mock.MatchedBy(func(updates []models.ApplicationBulkUpdate) bool {
+		if len(updates) != 1 {
+			return false
+		}
+		u := updates[0]
+		return u.Application.ID == applied.ID && u.FromStatus == models.ApplicationStatusApplied &&
+			u.Application.Status == models.ApplicationStatusUnderReview && u.Application.Stage == "interview" &&
+			u.Application.UpdatedBy == recruiterID.Hex() && u.Change.Stage == "interview" && u.Change.FromStage == "screen" &&
+			u.Change.Note == "Phone screen passed" && u.Change.ChangedBy == recruiterID.Hex()
+	})).Return([]string{applied.ID.Hex()}, nil)
+
+	missing := bson.NewObjectID().Hex()
+	results, err := svc.BulkUpdateApplications(claimsContext("recruiter", recruiterID.Hex()), jobID.Hex(), models.ApplicationBulkAction{
+		Action:         models.ApplicationActionMove,
+		ApplicationIDs: []string{applied.ID.Hex(), interviewing.ID.Hex(), accepted.ID.Hex(), missing, applied.ID.Hex()},
+		Stage:          "interview",
+		Note:           "Phone screen passed",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, 4)
+	assert.Equal(t, models.ApplicationBulkResult{ID: applied.ID.Hex(), Result: models.BulkResultUpdated}, results[0])
+	assert.Equal(t, models.ApplicationBulkResult{ID: interviewing.ID.Hex(), Result: models.BulkResultUnchanged}, results[1])
+	assert.Equal(t, models.BulkResultFailed, results[2].Result)
+	assert.Contains(t, results[2].Error, "accepted to under_review")
+	assert.Equal(t, models.ApplicationBulkResult{ID: missing, Result: models.BulkResultFailed, Error: "application not found"}, results[3])
+	mockRepo.AssertExpectations(t)
+}
+
+func TestApplicationService_BulkUpdateApplications_Reject(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	recruiterID := bson.NewObjectID()
+	job := pipelineJob(jobID, recruiterID)
+	job.Title = "Go Developer"
+	application := models.Application{ID: bson.NewObjectID(), JobID: jobID, Status: models.ApplicationStatusUnderReview, Stage: "interview"}
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(job, nil)
+	mockRepo.On("GetByJobID", mock.Anything, jobID.Hex()).Return([]models.Application{application}, nil)
+	mockRepo.On("BulkUpdate", mock.Anything, mock.MatchedBy(func(updates []models.ApplicationBulkUpdate) bool {
+		a := updates[0].Application
+		return a.Status == models.ApplicationStatusRejected && a.Stage == "declined" && a.RejectionReason == "position_filled" &&
+			a.RecruiterNote == "Thank you for applying to Go Developer. The position has now been filled.\n\nKept on file" &&
+			updates[0].Change.Note == a.RecruiterNote
+	})).Return([]string{application.ID.Hex()}, nil)
+
+	results, err := svc.BulkUpdateApplications(claimsContext("recruiter", recruiterID.Hex()), jobID.Hex(), models.ApplicationBulkAction{
+		Action:         models.ApplicationActionReject,
+		ApplicationIDs: []string{application.ID.Hex()},
+		Reason:         "position_filled",
+		Note:           "Kept on file",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.BulkResultUpdated, results[0].Result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestApplicationService_BulkUpdateApplications_RejectApplied(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	recruiterID := bson.NewObjectID()
+	first := models.Application{ID: bson.NewObjectID(), JobID: jobID, Status: models.ApplicationStatusApplied, Stage: "screen"}
+	second := models.Application{ID: bson.NewObjectID(), JobID: jobID, Status: models.ApplicationStatusApplied, Stage: "screen"}
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(pipelineJob(jobID, recruiterID), nil)
+	mockRepo.On("GetByJobID", mock.Anything, jobID.Hex()).Return([]models.Application{first, second}, nil)
+	mockRepo.On("BulkUpdate", mock.Anything, mock.MatchedBy(func(updates []models.ApplicationBulkUpdate) bool {
+		if len(updates) != 2 {
+			return false
+		}
+		for _, u := range updates {
+			if u.FromStatus != models.ApplicationStatusApplied || u.Application.Status != models.ApplicationStatusRejected ||
+				u.Application.Stage != "declined" || u.Change.FromStage != "screen" || u.Application.ClosedTime == nil {
+				return false
+			}
+		}
+		return true
+	})).Return([]string{first.ID.Hex(), second.ID.Hex()}, nil)
+
+	results, err := svc.BulkUpdateApplications(claimsContext("recruiter", recruiterID.Hex()), jobID.Hex(), models.ApplicationBulkAction{
+		Action:         models.ApplicationActionReject,
+		ApplicationIDs: []string{first.ID.Hex(), second.ID.Hex()},
+		Reason:         "not_qualified",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ApplicationBulkResult{
+		{ID: first.ID.Hex(), Result: models.BulkResultUpdated},
+		{ID: second.ID.Hex(), Result: models.BulkResultUpdated},
+	}, results)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestApplicationService_BulkUpdateApplications_ConfiguredReason(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil, services.WithRejectionReasons(map[string]string{
+		"visa": "We cannot sponsor a visa for {job_title}.",
+	}))
+
+	jobID := bson.NewObjectID()
+	recruiterID := bson.NewObjectID()
+	job := pipelineJob(jobID, recruiterID)
+	job.Title = "Go Developer"
+	application := models.Application{ID: bson.NewObjectID(), JobID: jobID, Status: models.ApplicationStatusUnderReview}
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(job, nil)
+	mockRepo.On("GetByJobID", mock.Anything, jobID.Hex()).Return([]models.Application{application}, nil)
+	mockRepo.On("BulkUpdate", mock.Anything, mock.MatchedBy(func(updates []models.ApplicationBulkUpdate) bool {
+		a := updates[0].Application
+		return a.RejectionReason == "visa" && a.RecruiterNote == "We cannot sponsor a visa for Go Developer."
+	})).Return([]string{application.ID.Hex()}, nil)
+
+	ctx := claimsContext("recruiter", recruiterID.Hex())
+	_, err := svc.BulkUpdateApplications(ctx, jobID.Hex(), models.ApplicationBulkAction{
+		Action:         models.ApplicationActionReject,
+		ApplicationIDs: []string{application.ID.Hex()},
+		Reason:         "visa",
+	})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+
+	// the defaults are replaced, not extended
+	_, err = svc.BulkUpdateApplications(ctx, jobID.Hex(), models.ApplicationBulkAction{
+		Action:         models.ApplicationActionReject,
+		ApplicationIDs: []string{application.ID.Hex()},
+		Reason:         "position_filled",
+	})
+	assert.ErrorIs(t, err, services.ErrRejectionReasonNotFound)
+}
+
+func TestApplicationService_GetRejectionReasons(t *testing.T) {
+	svc := services.NewApplicationService(nil, nil, nil)
+
+	reasons := svc.GetRejectionReasons(context.Background())
+	assert.Len(t, reasons, len(models.DefaultRejectionReasons))
+	assert.Equal(t, "location", reasons[0].Key)
+	assert.Equal(t, models.DefaultRejectionReasons["location"], reasons[0].Text)
+
+	svc = services.NewApplicationService(nil, nil, nil, services.WithRejectionReasons(map[string]string{
+		"visa":      "We cannot sponsor a visa.",
+		"duplicate": "You have applied twice.",
+	}))
+	assert.Equal(t, []models.RejectionReason{
+		{Key: "duplicate", Text: "You have applied twice."},
+		{Key: "visa", Text: "We cannot sponsor a visa."},
+	}, svc.GetRejectionReasons(context.Background()))
+}
+
+func TestApplicationService_BulkUpdateApplications_Tag(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	recruiterID := bson.NewObjectID()
+	untagged := models.Application{ID: bson.NewObjectID(), JobID: jobID, Status: models.ApplicationStatusRejected}
+	tagged := models.Application{ID: bson.NewObjectID(), JobID: jobID, Status: models.ApplicationStatusApplied, Tags: []string{"senior", "remote"}}
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{ID: jobID, UserID: recruiterID}, nil)
+	mockRepo.On("GetByJobID", mock.Anything, jobID.Hex()).Return([]models.Application{untagged, tagged}, nil)
+	mockRepo.On("BulkUpdate", mock.Anything, mock.MatchedBy(func(updates []models.ApplicationBulkUpdate) bool {
+		return len(updates) == 1 && updates[0].Application.ID == untagged.ID && updates[0].Change == nil &&
+			assert.ObjectsAreEqual([]string{"senior", "remote"}, updates[0].Tags)
+	})).Return([]string{untagged.ID.Hex()}, nil)
+
+	results, err := svc.BulkUpdateApplications(claimsContext("recruiter", recruiterID.Hex()), jobID.Hex(), models.ApplicationBulkAction{
+		Action:         models.ApplicationActionTag,
+		ApplicationIDs: []string{untagged.ID.Hex(), tagged.ID.Hex()},
+		Tags:           []string{"senior", "remote", "senior"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.BulkResultUpdated, results[0].Result)
+	assert.Equal(t, models.BulkResultUnchanged, results[1].Result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestApplicationService_BulkUpdateApplications_ChangedDuringUpdate(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	recruiterID := bson.NewObjectID()
+	first := models.Application{ID: bson.NewObjectID(), JobID: jobID, Status: models.ApplicationStatusApplied}
+	second := models.Application{ID: bson.NewObjectID(), JobID: jobID, Status: models.ApplicationStatusApplied}
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(pipelineJob(jobID, recruiterID), nil)
+	mockRepo.On("GetByJobID", mock.Anything, jobID.Hex()).Return([]models.Application{first, second}, nil)
+	mockRepo.On("BulkUpdate", mock.Anything, mock.Anything).Return([]string{second.ID.Hex()}, nil)
+
+	results, err := svc.BulkUpdateApplications(claimsContext("recruiter", recruiterID.Hex()), jobID.Hex(), models.ApplicationBulkAction{
+		Action:         models.ApplicationActionMove,
+		ApplicationIDs: []string{first.ID.Hex(), second.ID.Hex()},
+		Stage:          "interview",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.BulkResultFailed, results[0].Result)
+	assert.NotEmpty(t, results[0].Error)
+	assert.Equal(t, models.BulkResultUpdated, results[1].Result)
+}
+
+func TestApplicationService_BulkUpdateApplications_InvalidAction(t *testing.T) {
+	tests := []struct {
+		name   string
+		action models.ApplicationBulkAction
+		err    error
+	}{
+		{"unknown stage", models.ApplicationBulkAction{Action: models.ApplicationActionMove, Stage: "offer"}, services.ErrStageNotFound},
+		{"unknown reason", models.ApplicationBulkAction{Action: models.ApplicationActionReject, Reason: "too_expensive"}, services.ErrRejectionReasonNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockApplicationRepository)
+			mockJobRepo := new(mocks.MockJobRepository)
+			svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+			jobID := bson.NewObjectID()
+			recruiterID := bson.NewObjectID()
+			mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(pipelineJob(jobID, recruiterID), nil)
+
+			tt.action.ApplicationIDs = []string{bson.NewObjectID().Hex()}
+			_, err := svc.BulkUpdateApplications(claimsContext("recruiter", recruiterID.Hex()), jobID.Hex(), tt.action)
+			assert.ErrorIs(t, err, tt.err)
+			mockRepo.AssertNotCalled(t, "GetByJobID", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestApplicationService_BulkUpdateApplications_NotJobOwner(t *testing.T) {
+	mockRepo := new(mocks.MockApplicationRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewApplicationService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(pipelineJob(jobID, bson.NewObjectID()), nil)
+
+	_, err := svc.BulkUpdateApplications(claimsContext("recruiter", bson.NewObjectID().Hex()), jobID.Hex(), models.ApplicationBulkAction{
+		Action:         models.ApplicationActionTag,
+		ApplicationIDs: []string{bson.NewObjectID().Hex()},
+		Tags:           []string{"senior"},
+	})
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "BulkUpdate", mock.Anything, mock.Anything)
+}
@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"go-mongodb-api/interfaces"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/models"
+	"time"
 )
 
 type ArticleService struct {
@@ -23,13 +25,19 @@ func (s *ArticleService) GetArticleByID(ctx context.Context, id string) (*models
 }
 
 func (s *ArticleService) CreateArticle(ctx context.Context, article *models.Article) error {
+	stampCreated(ctx, article, time.Now())
 	return s.repo.Create(ctx, article)
 }
 
 func (s *ArticleService) UpdateArticle(ctx context.Context, id string, article *models.Article) (*models.Article, error) {
+	stampUpdated(ctx, article, time.Now())
 	return s.repo.Update(ctx, id, article)
 }
 
 func (s *ArticleService) DeleteArticle(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	return s.repo.Delete(ctx, id, middleware.ActorID(ctx))
+}
+
+func (s *ArticleService) RestoreArticle(ctx context.Context, id string) error {
+	return s.repo.Restore(ctx, id, updatedAudit(ctx, time.Now()))
 }
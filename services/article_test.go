@@ -66,11 +66,25 @@ func TestArticleService_CreateArticle(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestArticleService_CreateArticle_RecordsActor(t *testing.T) {
+	mockRepo := new(mocks.MockArticleRepository)
+	svc := services.NewArticleService(mockRepo)
+
+	article := &models.Article{Title: "New Article", Content: "Content", Slug: "new-article", Audit: models.Audit{CreatedBy: "spoofed"}}
+	mockRepo.On("Create", mock.Anything, article).Return(nil)
+
+	err := svc.CreateArticle(claimsContext("admin", "admin-id"), article)
+	assert.NoError(t, err)
+	assert.Equal(t, "admin-id", article.CreatedBy)
+	assert.Equal(t, "admin-id", article.UpdatedBy)
+	assert.False(t, article.CreatedTime.IsZero())
+}
+
 func TestArticleService_DeleteArticle(t *testing.T) {
 	mockRepo := new(mocks.MockArticleRepository)
 	svc := services.NewArticleService(mockRepo)
 
-	mockRepo.On("Delete", mock.Anything, "article-id").Return(nil)
+	mockRepo.On("Delete", mock.Anything, "article-id", mock.Anything).Return(nil)
 
 	err := svc.DeleteArticle(context.Background(), "article-id")
 	assert.NoError(t, err)
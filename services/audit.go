@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"go-mongodb-api/middleware"
+	"go-mongodb-api/models"
+	"time"
+)
+
+// stampCreated records the caller as the creator of a new record, at now. Requests without a caller
+// are recorded as middleware.SystemActor. Soft delete fields sent by the client are dropped.
+func stampCreated(ctx context.Context, record models.Auditable, now time.Time) {
+	record.SetCreated(middleware.ActorID(ctx), now)
+	if deletable, ok := record.(models.SoftDeletable); ok {
+		deletable.ClearDeleted()
+	}
+}
+
+// stampUpdated records the caller as the last updater of a record, at now
+func stampUpdated(ctx context.Context, record models.Auditable, now time.Time) {
+	record.SetUpdated(middleware.ActorID(ctx), now)
+}
+
+// updatedAudit returns the audit fields of an update by the caller at now, for repository writes that
+// change a record without loading it
+func updatedAudit(ctx context.Context, now time.Time) models.Audit {
+	var audit models.Audit
+	stampUpdated(ctx, &audit, now)
+	return audit
+}
@@ -2,59 +2,712 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
+	"go-mongodb-api/helpers"
 	"go-mongodb-api/interfaces"
 	"go-mongodb-api/middleware"
 	"go-mongodb-api/models"
 
 	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	ErrInvalidResetToken   = errors.New("invalid or expired reset token")
+	ErrInvalidVerifyToken  = errors.New("invalid or expired verification token")
+	ErrInvalidInvitation   = errors.New("invalid or expired invitation")
+	ErrRoleNotAllowed      = errors.New("role cannot be self-registered")
+	ErrLoginLocked         = errors.New("too many failed login attempts")
+	ErrInvalidMFAChallenge = errors.New("invalid or expired two-factor challenge")
+	ErrInvalidMFACode      = errors.New("invalid two-factor code")
+	ErrMFAAlreadyEnabled   = errors.New("two-factor authentication already enabled")
+	ErrMFANotEnabled       = errors.New("two-factor authentication not enabled")
+)
+
+// errUserInactive fails the login of a deactivated user; callers only see the generic credentials error
+var errUserInactive = errors.New("user is inactive")
+
+// LoginLockedError is returned by Login while the account or the client IP is locked out.
+// It wraps ErrLoginLocked and carries the time until the next attempt is accepted.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrLoginLocked, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginLockedError) Unwrap() error {
+	return ErrLoginLocked
+}
+
+const (
+	defaultAccessTokenTTL   = 15 * time.Minute
+	defaultRefreshTokenTTL  = 7 * 24 * time.Hour
+	defaultPasswordResetTTL = time.Hour
+	defaultVerificationTTL  = 48 * time.Hour
+	defaultInvitationTTL    = 72 * time.Hour
+	defaultMaxLoginAttempts = 5
+	defaultMaxIPAttempts    = 20
+	defaultLockoutBase      = time.Minute
+	defaultLockoutMax       = time.Hour
+	loginAttemptWindow      = 24 * time.Hour
+	mfaChallengeTTL         = 5 * time.Minute
+	defaultMFAIssuer        = "Jobs API"
+	recoveryCodeCount       = 10
+	recoveryCodeBytes       = 5
+	refreshTokenBytes       = 32
+	userTokenBytes          = 32
+)
+
+// AuthConfig holds the signing keys, token lifetimes and links used by AuthService
+type AuthConfig struct {
+	// Keys signs access tokens. When nil, tokens are signed with JWTSecret using HS256.
+	Keys             *middleware.KeySet
+	JWTSecret        string
+	AccessTokenTTL   time.Duration
+	RefreshTokenTTL  time.Duration
+	PasswordResetTTL time.Duration
+	VerificationTTL  time.Duration
+	InvitationTTL    time.Duration
+	AppBaseURL       string
+
+	// MaxLoginAttempts and MaxIPLoginAttempts are the consecutive failures allowed per account and
+	// per client IP before logins are locked. Each further failure doubles the lockout, starting at
+	// LoginLockoutBase and capped at LoginLockoutMax.
+	MaxLoginAttempts   int
+	MaxIPLoginAttempts int
+	LoginLockoutBase   time.Duration
+	LoginLockoutMax    time.Duration
+
+	// MFAIssuer is the account issuer shown by authenticator apps
+	MFAIssuer string
+}
+
 type AuthService struct {
 	userService interfaces.UserService
-	jwtSecret   []byte
+	sessionRepo interfaces.SessionRepository
+	tokenRepo   interfaces.UserTokenRepository
+	attemptRepo interfaces.LoginAttemptRepository
+	notifier    interfaces.Notifier
+	keys        *middleware.KeySet
+	accessTTL   time.Duration
+	refreshTTL  time.Duration
+	resetTTL    time.Duration
+	verifyTTL   time.Duration
+	inviteTTL   time.Duration
+	appBaseURL  string
+	maxAttempts int
+	maxIPTries  int
+	lockoutBase time.Duration
+	lockoutMax  time.Duration
+	mfaIssuer   string
 }
 
-func NewAuthService(userService interfaces.UserService, jwtSecret string) *AuthService {
+func NewAuthService(userService interfaces.UserService, sessionRepo interfaces.SessionRepository, tokenRepo interfaces.UserTokenRepository, attemptRepo interfaces.LoginAttemptRepository, notifier interfaces.Notifier, cfg AuthConfig) *AuthService {
+	accessTTL := cfg.AccessTokenTTL
+	if accessTTL <= 0 {
+		accessTTL = defaultAccessTokenTTL
+	}
+	refreshTTL := cfg.RefreshTokenTTL
+	if refreshTTL <= 0 {
+		refreshTTL = defaultRefreshTokenTTL
+	}
+	resetTTL := cfg.PasswordResetTTL
+	if resetTTL <= 0 {
+		resetTTL = defaultPasswordResetTTL
+	}
+	verifyTTL := cfg.VerificationTTL
+	if verifyTTL <= 0 {
+		verifyTTL = defaultVerificationTTL
+	}
+	inviteTTL := cfg.InvitationTTL
+	if inviteTTL <= 0 {
+		inviteTTL = defaultInvitationTTL
+	}
+	maxAttempts := cfg.MaxLoginAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxLoginAttempts
+	}
+	maxIPTries := cfg.MaxIPLoginAttempts
+	if maxIPTries <= 0 {
+		maxIPTries = defaultMaxIPAttempts
+	}
+	lockoutBase := cfg.LoginLockoutBase
+	if lockoutBase <= 0 {
+		lockoutBase = defaultLockoutBase
+	}
+	lockoutMax := cfg.LoginLockoutMax
+	if lockoutMax < lockoutBase {
+		lockoutMax = max(defaultLockoutMax, lockoutBase)
+	}
+	keys := cfg.Keys
+	if keys == nil {
+		keys = middleware.NewHMACKeySet(cfg.JWTSecret)
+	}
+	mfaIssuer := cfg.MFAIssuer
+	if mfaIssuer == "" {
+		mfaIssuer = defaultMFAIssuer
+	}
 	return &AuthService{
 		userService: userService,
-		jwtSecret:   []byte(jwtSecret),
+		sessionRepo: sessionRepo,
+		tokenRepo:   tokenRepo,
+		attemptRepo: attemptRepo,
+		notifier:    notifier,
+		keys:        keys,
+		accessTTL:   accessTTL,
+		refreshTTL:  refreshTTL,
+		resetTTL:    resetTTL,
+		verifyTTL:   verifyTTL,
+		inviteTTL:   inviteTTL,
+		appBaseURL:  strings.TrimRight(cfg.AppBaseURL, "/"),
+		maxAttempts: maxAttempts,
+		maxIPTries:  maxIPTries,
+		lockoutBase: lockoutBase,
+		lockoutMax:  lockoutMax,
+		mfaIssuer:   mfaIssuer,
 	}
 }
 
-// Login authenticates any user by email/password and returns a JWT with their role.
-func (s *AuthService) Login(ctx context.Context, email, password string) (string, error) {
+// Login authenticates any user by email/password, opens a session and returns an access/refresh token pair.
+// Failed attempts are counted per account and per client IP; once either is locked out, Login returns
+// a *LoginLockedError without checking the password. For accounts with two-factor authentication enabled
+// it returns only a short-lived MFA challenge token, which VerifyMFALogin exchanges for the token pair.
+func (s *AuthService) Login(ctx context.Context, email, password, clientIP string) (*models.AuthTokens, error) {
+	account := normalizeEmail(email)
+	if err := s.checkLoginLock(ctx, models.LoginScopeAccount, account); err != nil {
+		return nil, err
+	}
+	if clientIP != "" {
+		if err := s.checkLoginLock(ctx, models.LoginScopeIP, clientIP); err != nil {
+			return nil, err
+		}
+	}
+
 	user, err := s.userService.GetUserByEmail(ctx, email)
+	if err == nil {
+		err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	}
+	// a deactivated account fails like a wrong password, so it cannot be told apart from a missing one
+	if err == nil && !user.Active {
+		err = errUserInactive
+	}
+	if err != nil {
+		s.recordLoginFailure(ctx, models.LoginScopeAccount, account, s.maxAttempts)
+		if clientIP != "" {
+			s.recordLoginFailure(ctx, models.LoginScopeIP, clientIP, s.maxIPTries)
+		}
+		return nil, fmt.Errorf("invalid credentials")
+	}
+
+	// The failed attempt counter is kept until the second factor succeeds, so codes cannot be guessed
+	// by logging in again after every wrong one
+	if user.MFAEnabled {
+		token, err := s.issueUserToken(ctx, user, models.TokenPurposeMFAChallenge, mfaChallengeTTL)
+		if err != nil {
+			return nil, err
+		}
+		return &models.AuthTokens{
+			MFARequired: true,
+			MFAToken:    token,
+			ExpiresIn:   int64(mfaChallengeTTL.Seconds()),
+		}, nil
+	}
+	return s.completeLogin(ctx, user, false)
+}
+
+// VerifyMFALogin consumes an MFA challenge token from Login and, when the TOTP or recovery code is valid,
+// opens a session. A challenge can be used once, so a wrong code requires logging in again; wrong codes
+// count as failed logins.
+func (s *AuthService) VerifyMFALogin(ctx context.Context, mfaToken, code, clientIP string) (*models.AuthTokens, error) {
+	challenge, err := s.tokenRepo.Consume(ctx, helpers.HashToken(mfaToken), models.TokenPurposeMFAChallenge)
 	if err != nil {
-		return "", fmt.Errorf("invalid credentials")
+		return nil, ErrInvalidMFAChallenge
 	}
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return "", fmt.Errorf("invalid credentials")
+	user, err := s.userService.GetUserByID(ctx, challenge.UserID.Hex())
+	if err != nil || !user.MFAEnabled || !user.Active {
+		return nil, ErrInvalidMFAChallenge
 	}
-	return s.generateToken(user.ID.Hex(), user.Email, user.Role)
+
+	account := normalizeEmail(user.Email)
+	if err := s.checkLoginLock(ctx, models.LoginScopeAccount, account); err != nil {
+		return nil, err
+	}
+	if clientIP != "" {
+		if err := s.checkLoginLock(ctx, models.LoginScopeIP, clientIP); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.verifyMFACode(ctx, user, code); err != nil {
+		s.recordLoginFailure(ctx, models.LoginScopeAccount, account, s.maxAttempts)
+		if clientIP != "" {
+			s.recordLoginFailure(ctx, models.LoginScopeIP, clientIP, s.maxIPTries)
+		}
+		return nil, err
+	}
+	return s.completeLogin(ctx, user, true)
 }
 
-// Register creates a new user account with a hashed password.
+// EnrollMFA generates a new TOTP secret for the user and returns it with its otpauth URI.
+// Two-factor authentication is only enabled once ConfirmMFA verifies a code from the authenticator app.
+func (s *AuthService) EnrollMFA(ctx context.Context, userID string) (*models.MFAEnrollment, error) {
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := helpers.GenerateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate TOTP secret: %w", err)
+	}
+	if err := s.userService.SetMFASecret(ctx, userID, secret); err != nil {
+		return nil, fmt.Errorf("failed to store TOTP secret: %w", err)
+	}
+	return &models.MFAEnrollment{
+		Secret: secret,
+		URI:    helpers.TOTPURI(s.mfaIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmMFA enables two-factor authentication once the user proves their authenticator app produces
+// valid codes, and returns single-use recovery codes. Only hashes of the recovery codes are stored.
+func (s *AuthService) ConfirmMFA(ctx context.Context, userID, code string) ([]string, error) {
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.MFASecret == "" {
+		return nil, ErrInvalidMFACode
+	}
+
+	step, ok := helpers.ValidateTOTP(user.MFASecret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		raw := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+		}
+		encoded := hex.EncodeToString(raw)
+		codes[i] = encoded[:len(encoded)/2] + "-" + encoded[len(encoded)/2:]
+		hashes[i] = helpers.HashToken(encoded)
+	}
+
+	if err := s.userService.EnableMFA(ctx, userID, hashes, step); err != nil {
+		return nil, fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+	return codes, nil
+}
+
+// DisableMFA turns off two-factor authentication after checking a current TOTP or recovery code
+func (s *AuthService) DisableMFA(ctx context.Context, userID, code string) error {
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.MFAEnabled {
+		return ErrMFANotEnabled
+	}
+	if err := s.verifyMFACode(ctx, user, code); err != nil {
+		return err
+	}
+	return s.userService.DisableMFA(ctx, userID)
+}
+
+// Register creates a new, unverified candidate or recruiter account with a hashed password and sends a verification link.
+// Other roles are rejected with ErrRoleNotAllowed; admins are created through invitations.
 func (s *AuthService) Register(ctx context.Context, user *models.User) error {
+	if user.Role != models.RoleCandidate && user.Role != models.RoleRecruiter {
+		return ErrRoleNotAllowed
+	}
+
+	user.Active = true
+	user.Verified = false
+	if user.TermsAccepted {
+		acceptedTime := time.Now()
+		user.LastTermsAccepted = &acceptedTime
+	}
+
+	// A self-registered user is their own creator, so the ID is assigned before insert
+	user.ID = bson.NewObjectID()
+	if err := s.userService.CreateUser(middleware.WithActor(ctx, user.ID.Hex()), user); err != nil {
+		return err
+	}
+
+	// The account exists at this point; a delivery failure can be recovered through ResendVerification
+	if err := s.sendVerification(ctx, user); err != nil {
+		log.Printf("error sending verification to user %s: %v", user.ID.Hex(), err)
+	}
+	return nil
+}
+
+// Refresh exchanges a refresh token for a new token pair, rotating the refresh token.
+// Presenting a refresh token that was already rotated revokes the whole session.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*models.AuthTokens, error) {
+	if refreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	oldHash := helpers.HashToken(refreshToken)
+	newToken, err := helpers.GenerateToken(refreshTokenBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	session, err := s.sessionRepo.Rotate(ctx, oldHash, helpers.HashToken(newToken), time.Now().Add(s.refreshTTL))
+	if err != nil {
+		reused, lookupErr := s.sessionRepo.GetByPreviousHash(ctx, oldHash)
+		if lookupErr != nil {
+			return nil, ErrInvalidRefreshToken
+		}
+		if err := s.sessionRepo.Revoke(ctx, reused.ID.Hex(), "refresh_token_reuse"); err != nil {
+			log.Printf("error revoking session %s after refresh token reuse: %v", reused.ID.Hex(), err)
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	// The session ends with the account: a deleted or deactivated user cannot refresh
+	user, err := s.userService.GetUserByID(ctx, session.UserID.Hex())
+	if err != nil || !user.Active {
+		if err := s.sessionRepo.Revoke(ctx, session.ID.Hex(), "user_inactive"); err != nil {
+			log.Printf("error revoking session %s of an inactive user: %v", session.ID.Hex(), err)
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+	return s.issueTokens(user, session, newToken)
+}
+
+// Logout revokes a single session
+func (s *AuthService) Logout(ctx context.Context, sessionID string) error {
+	return s.sessionRepo.Revoke(ctx, sessionID, "logout")
+}
+
+// LogoutAll revokes every session of a user
+func (s *AuthService) LogoutAll(ctx context.Context, userID string) error {
+	return s.sessionRepo.RevokeAllForUser(ctx, userID, "logout_all")
+}
+
+// ForgotPassword issues a single-use reset token and sends it to the user.
+// Unknown emails are ignored so the endpoint cannot be used to discover accounts.
+func (s *AuthService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.userService.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil
+	}
+
+	token, err := s.issueUserToken(ctx, user, models.TokenPurposePasswordReset, s.resetTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"Hi %s,\n\nUse the link below to reset your password. It expires in %s and can only be used once.\n\n%s/reset-password?token=%s\n\nIf you did not request a password reset you can ignore this message.",
+		user.FirstName, s.resetTTL, s.appBaseURL, token,
+	)
+	if err := s.notifier.Send(ctx, user.Email, "Reset your password", body); err != nil {
+		return fmt.Errorf("failed to send password reset: %w", err)
+	}
+	return nil
+}
+
+// ResetPassword consumes a reset token, sets the new password and revokes every existing session of the user
+func (s *AuthService) ResetPassword(ctx context.Context, token, password string) error {
+	resetToken, err := s.tokenRepo.Consume(ctx, helpers.HashToken(token), models.TokenPurposePasswordReset)
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+
+	userID := resetToken.UserID.Hex()
+	if err := s.userService.UpdatePassword(middleware.WithActor(ctx, userID), userID, password); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	if err := s.sessionRepo.RevokeAllForUser(ctx, userID, "password_reset"); err != nil {
+		log.Printf("error revoking sessions of user %s after password reset: %v", userID, err)
+	}
+	return nil
+}
+
+// VerifyEmail consumes a verification token and marks the user's email address as verified
+func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
+	verifyToken, err := s.tokenRepo.Consume(ctx, helpers.HashToken(token), models.TokenPurposeEmailVerification)
+	if err != nil {
+		return ErrInvalidVerifyToken
+	}
+	userID := verifyToken.UserID.Hex()
+	if err := s.userService.MarkVerified(middleware.WithActor(ctx, userID), userID); err != nil {
+		return fmt.Errorf("failed to mark user verified: %w", err)
+	}
+	return nil
+}
+
+// ResendVerification sends a fresh verification link, invalidating earlier ones.
+// Unknown or already verified emails are ignored so the endpoint cannot be used to discover accounts.
+func (s *AuthService) ResendVerification(ctx context.Context, email string) error {
+	user, err := s.userService.GetUserByEmail(ctx, email)
+	if err != nil || user.Verified {
+		return nil
+	}
+	return s.sendVerification(ctx, user)
+}
+
+// Invite sends a single-use invitation to create an admin account. Earlier invitations to the same
+// address are invalidated. It returns ErrEmailTaken when an account already uses the address.
+func (s *AuthService) Invite(ctx context.Context, email string) error {
+	if _, err := s.userService.GetUserByEmail(ctx, email); err == nil {
+		return ErrEmailTaken
+	}
+
+	token, err := helpers.GenerateToken(userTokenBytes)
+	if err != nil {
+		return fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	if err := s.tokenRepo.DeleteByEmail(ctx, email, models.TokenPurposeInvitation); err != nil {
+		return fmt.Errorf("failed to invalidate previous invitations: %w", err)
+	}
+
+	now := time.Now()
+	invitation := &models.UserToken{
+		Purpose:     models.TokenPurposeInvitation,
+		TokenHash:   helpers.HashToken(token),
+		Email:       email,
+		Role:        models.RoleAdmin,
+		ExpiresTime: now.Add(s.inviteTTL),
+		CreatedTime: now,
+		CreatedBy:   middleware.ActorID(ctx),
+	}
+	if err := s.tokenRepo.Create(ctx, invitation); err != nil {
+		return fmt.Errorf("failed to store invitation: %w", err)
+	}
+
+	body := fmt.Sprintf(
+		"Hi,\n\nYou have been invited to join as an administrator. Use the link below to set up your account. It expires in %s and can only be used once.\n\n%s/accept-invitation?token=%s",
+		s.inviteTTL, s.appBaseURL, token,
+	)
+	if err := s.notifier.Send(ctx, email, "You have been invited", body); err != nil {
+		return fmt.Errorf("failed to send invitation: %w", err)
+	}
+	return nil
+}
+
+// AcceptInvitation consumes an invitation and creates the invited account. The email address and role
+// come from the invitation; the address counts as verified because the link was delivered to it.
+func (s *AuthService) AcceptInvitation(ctx context.Context, token string, user *models.User) error {
+	invitation, err := s.tokenRepo.Consume(ctx, helpers.HashToken(token), models.TokenPurposeInvitation)
+	if err != nil {
+		return ErrInvalidInvitation
+	}
+
+	user.Email = invitation.Email
+	user.Role = invitation.Role
 	user.Active = true
-	user.CreatedTime = time.Now()
-	user.UpdatedTime = time.Now()
-	return s.userService.CreateUser(ctx, user)
+	user.Verified = true
+	return s.userService.CreateUser(middleware.WithActor(ctx, invitation.CreatedBy), user)
+}
+
+// UnlockAccount clears the failed login counter and any lockout of a user's account
+func (s *AuthService) UnlockAccount(ctx context.Context, userID string) error {
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.attemptRepo.Reset(ctx, models.LoginScopeAccount, normalizeEmail(user.Email))
+}
+
+// IsSessionActive reports whether a session exists and is neither revoked nor expired.
+// It satisfies middleware.SessionChecker.
+func (s *AuthService) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return false, err
+	}
+	return session.RevokedTime == nil && session.ExpiresTime.After(time.Now()), nil
+}
+
+// startSession persists a new session for the user and issues its first token pair.
+// mfa records whether the login passed a second factor; it is carried by every access token of the session.
+func (s *AuthService) startSession(ctx context.Context, user *models.User, mfa bool) (*models.AuthTokens, error) {
+	refreshToken, err := helpers.GenerateToken(refreshTokenBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	now := time.Now()
+	session := &models.Session{
+		UserID:         user.ID,
+		TokenHash:      helpers.HashToken(refreshToken),
+		PreviousHashes: []string{},
+		ExpiresTime:    now.Add(s.refreshTTL),
+		MFA:            mfa,
+		CreatedTime:    now,
+		UpdatedTime:    now,
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	return s.issueTokens(user, session, refreshToken)
+}
+
+// completeLogin clears the account's failed attempt counter, records the login and opens a session.
+// Only the account counter is cleared; one valid login must not reset a client IP that is guessing other accounts.
+func (s *AuthService) completeLogin(ctx context.Context, user *models.User, mfa bool) (*models.AuthTokens, error) {
+	if err := s.attemptRepo.Reset(ctx, models.LoginScopeAccount, normalizeEmail(user.Email)); err != nil {
+		log.Printf("error resetting failed logins of user %s: %v", user.ID.Hex(), err)
+	}
+	if err := s.userService.RecordLogin(ctx, user.ID.Hex()); err != nil {
+		log.Printf("error recording login of user %s: %v", user.ID.Hex(), err)
+	}
+	return s.startSession(ctx, user, mfa)
+}
+
+// verifyMFACode accepts either a TOTP code, which cannot be reused, or one of the user's recovery codes,
+// which is consumed
+func (s *AuthService) verifyMFACode(ctx context.Context, user *models.User, code string) error {
+	code = strings.TrimSpace(code)
+	if step, ok := helpers.ValidateTOTP(user.MFASecret, code, time.Now()); ok {
+		if err := s.userService.UseMFAStep(ctx, user.ID.Hex(), step); err != nil {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	recoveryCode := strings.ToLower(strings.ReplaceAll(code, "-", ""))
+	if len(recoveryCode) != 2*recoveryCodeBytes {
+		return ErrInvalidMFACode
+	}
+	if err := s.userService.UseRecoveryCode(ctx, user.ID.Hex(), helpers.HashToken(recoveryCode)); err != nil {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+// checkLoginLock returns a *LoginLockedError while logins for the scope and value are locked.
+// Lookup failures are logged and let the attempt through so an outage does not lock everyone out.
+func (s *AuthService) checkLoginLock(ctx context.Context, scope, value string) error {
+	attempt, err := s.attemptRepo.Get(ctx, scope, value)
+	if err != nil || attempt.LockedUntil == nil {
+		return nil
+	}
+	if retryAfter := time.Until(*attempt.LockedUntil); retryAfter > 0 {
+		return &LoginLockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed login and, once the threshold is reached, locks the scope and value
+// for a period that doubles with every further failure
+func (s *AuthService) recordLoginFailure(ctx context.Context, scope, value string, threshold int) {
+	now := time.Now()
+	attempt, err := s.attemptRepo.RecordFailure(ctx, scope, value, now.Add(loginAttemptWindow))
+	if err != nil {
+		log.Printf("error recording failed login for %s %q: %v", scope, value, err)
+		return
+	}
+	if attempt.Failures < threshold {
+		return
+	}
+
+	lockout := s.lockoutBase
+	for i := threshold; i < attempt.Failures && lockout < s.lockoutMax; i++ {
+		lockout *= 2
+	}
+	lockout = min(lockout, s.lockoutMax)
+	if err := s.attemptRepo.Lock(ctx, scope, value, now.Add(lockout)); err != nil {
+		log.Printf("error locking logins for %s %q: %v", scope, value, err)
+	}
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func (s *AuthService) sendVerification(ctx context.Context, user *models.User) error {
+	token, err := s.issueUserToken(ctx, user, models.TokenPurposeEmailVerification, s.verifyTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"Hi %s,\n\nPlease confirm your email address using the link below. It expires in %s.\n\n%s/verify-email?token=%s",
+		user.FirstName, s.verifyTTL, s.appBaseURL, token,
+	)
+	if err := s.notifier.Send(ctx, user.Email, "Verify your email address", body); err != nil {
+		return fmt.Errorf("failed to send verification: %w", err)
+	}
+	return nil
+}
+
+// issueUserToken replaces any outstanding token of the same purpose with a new one and returns its plain value
+func (s *AuthService) issueUserToken(ctx context.Context, user *models.User, purpose string, ttl time.Duration) (string, error) {
+	token, err := helpers.GenerateToken(userTokenBytes)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	if err := s.tokenRepo.DeleteByUser(ctx, user.ID.Hex(), purpose); err != nil {
+		return "", fmt.Errorf("failed to invalidate previous tokens: %w", err)
+	}
+
+	now := time.Now()
+	userToken := &models.UserToken{
+		UserID:      user.ID,
+		Purpose:     purpose,
+		TokenHash:   helpers.HashToken(token),
+		ExpiresTime: now.Add(ttl),
+		CreatedTime: now,
+	}
+	if err := s.tokenRepo.Create(ctx, userToken); err != nil {
+		return "", fmt.Errorf("failed to store token: %w", err)
+	}
+	return token, nil
+}
+
+func (s *AuthService) issueTokens(user *models.User, session *models.Session, refreshToken string) (*models.AuthTokens, error) {
+	accessToken, err := s.generateToken(user, session)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AuthTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.accessTTL.Seconds()),
+	}, nil
 }
 
-func (s *AuthService) generateToken(id, email, role string) (string, error) {
+func (s *AuthService) generateToken(user *models.User, session *models.Session) (string, error) {
 	claims := &middleware.Claims{
-		UserID: id,
-		Email:  email,
-		Role:   role,
+		UserID:    user.ID.Hex(),
+		Email:     user.Email,
+		Role:      user.Role,
+		SessionID: session.ID.Hex(),
+		Verified:  user.Verified,
+		MFA:       session.MFA,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.accessTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.jwtSecret)
+	return s.keys.Sign(claims)
 }
@@ -2,16 +2,24 @@ package services_test
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"go-mongodb-api/helpers"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/mocks"
 	"go-mongodb-api/models"
 	"go-mongodb-api/services"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -22,57 +30,213 @@ func makeHashedPassword(plain string) string {
 
 func TestAuthService_Login_Success(t *testing.T) {
 	mockUserSvc := new(mocks.MockUserService)
-	svc := services.NewAuthService(mockUserSvc, "test-secret")
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	mockAttemptRepo := new(mocks.MockLoginAttemptRepository)
+	svc := services.NewAuthService(mockUserSvc, mockSessionRepo, nil, mockAttemptRepo, nil, services.AuthConfig{JWTSecret: "test-secret"})
 
 	user := &models.User{
 		ID:       bson.NewObjectID(),
 		Email:    "alice@example.com",
 		Password: makeHashedPassword("password123"),
+		Active:   true,
 		Role:     "candidate",
 	}
+	mockAttemptRepo.On("Get", mock.Anything, models.LoginScopeAccount, "alice@example.com").Return(nil, errors.New("not found"))
+	mockAttemptRepo.On("Get", mock.Anything, models.LoginScopeIP, "203.0.113.7").Return(nil, errors.New("not found"))
 	mockUserSvc.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(user, nil)
+	mockAttemptRepo.On("Reset", mock.Anything, models.LoginScopeAccount, "alice@example.com").Return(nil)
+	mockUserSvc.On("RecordLogin", mock.Anything, user.ID.Hex()).Return(nil)
+	mockSessionRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Session")).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Session).ID = bson.NewObjectID()
+	}).Return(nil)
 
-	token, err := svc.Login(context.Background(), "alice@example.com", "password123")
+	tokens, err := svc.Login(context.Background(), "alice@example.com", "password123", "203.0.113.7")
 	assert.NoError(t, err)
-	assert.NotEmpty(t, token)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEmpty(t, tokens.RefreshToken)
+	assert.Equal(t, int64(900), tokens.ExpiresIn)
 	mockUserSvc.AssertExpectations(t)
+	mockSessionRepo.AssertExpectations(t)
+	mockAttemptRepo.AssertExpectations(t)
+	mockAttemptRepo.AssertNotCalled(t, "Reset", mock.Anything, models.LoginScopeIP, mock.Anything)
 }
 
 func TestAuthService_Login_UserNotFound(t *testing.T) {
 	mockUserSvc := new(mocks.MockUserService)
-	svc := services.NewAuthService(mockUserSvc, "test-secret")
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	mockAttemptRepo := new(mocks.MockLoginAttemptRepository)
+	svc := services.NewAuthService(mockUserSvc, mockSessionRepo, nil, mockAttemptRepo, nil, services.AuthConfig{JWTSecret: "test-secret"})
 
+	mockAttemptRepo.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
 	mockUserSvc.On("GetUserByEmail", mock.Anything, "nope@example.com").Return(nil, errors.New("not found"))
+	mockAttemptRepo.On("RecordFailure", mock.Anything, models.LoginScopeAccount, "nope@example.com", mock.Anything).Return(&models.LoginAttempt{Failures: 1}, nil)
+	mockAttemptRepo.On("RecordFailure", mock.Anything, models.LoginScopeIP, "203.0.113.7", mock.Anything).Return(&models.LoginAttempt{Failures: 1}, nil)
 
-	token, err := svc.Login(context.Background(), "nope@example.com", "password123")
+	tokens, err := svc.Login(context.Background(), "nope@example.com", "password123", "203.0.113.7")
 	assert.Error(t, err)
-	assert.Empty(t, token)
+	assert.Nil(t, tokens)
 	mockUserSvc.AssertExpectations(t)
+	mockAttemptRepo.AssertExpectations(t)
 }
 
 func TestAuthService_Login_WrongPassword(t *testing.T) {
 	mockUserSvc := new(mocks.MockUserService)
-	svc := services.NewAuthService(mockUserSvc, "test-secret")
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	mockAttemptRepo := new(mocks.MockLoginAttemptRepository)
+	svc := services.NewAuthService(mockUserSvc, mockSessionRepo, nil, mockAttemptRepo, nil, services.AuthConfig{JWTSecret: "test-secret"})
 
 	user := &models.User{
 		ID:       bson.NewObjectID(),
 		Email:    "alice@example.com",
 		Password: makeHashedPassword("correct-password"),
+		Active:   true,
 		Role:     "recruiter",
 	}
+	mockAttemptRepo.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
 	mockUserSvc.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(user, nil)
+	mockAttemptRepo.On("RecordFailure", mock.Anything, models.LoginScopeAccount, "alice@example.com", mock.Anything).Return(&models.LoginAttempt{Failures: 2}, nil)
+	mockAttemptRepo.On("RecordFailure", mock.Anything, models.LoginScopeIP, "203.0.113.7", mock.Anything).Return(&models.LoginAttempt{Failures: 2}, nil)
 
-	token, err := svc.Login(context.Background(), "alice@example.com", "wrong-password")
+	tokens, err := svc.Login(context.Background(), "alice@example.com", "wrong-password", "203.0.113.7")
 	assert.Error(t, err)
-	assert.Empty(t, token)
-	mockUserSvc.AssertExpectations(t)
+	assert.Nil(t, tokens)
+	mockAttemptRepo.AssertNotCalled(t, "Lock", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Login_InactiveUser(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	mockAttemptRepo := new(mocks.MockLoginAttemptRepository)
+	svc := services.NewAuthService(mockUserSvc, mockSessionRepo, nil, mockAttemptRepo, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	user := &models.User{
+		ID:       bson.NewObjectID(),
+		Email:    "alice@example.com",
+		Password: makeHashedPassword("password123"),
+		Role:     "candidate",
+	}
+	mockAttemptRepo.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
+	mockUserSvc.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(user, nil)
+	mockAttemptRepo.On("RecordFailure", mock.Anything, models.LoginScopeAccount, "alice@example.com", mock.Anything).Return(&models.LoginAttempt{Failures: 1}, nil)
+
+	tokens, err := svc.Login(context.Background(), "alice@example.com", "password123", "")
+	assert.EqualError(t, err, "invalid credentials")
+	assert.Nil(t, tokens)
+	mockSessionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockUserSvc.AssertNotCalled(t, "RecordLogin", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Login_LocksAccountAtThreshold(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockAttemptRepo := new(mocks.MockLoginAttemptRepository)
+	svc := services.NewAuthService(mockUserSvc, nil, nil, mockAttemptRepo, nil, services.AuthConfig{
+		JWTSecret:        "test-secret",
+		MaxLoginAttempts: 3,
+		LoginLockoutBase: time.Minute,
+		LoginLockoutMax:  time.Hour,
+	})
+
+	mockAttemptRepo.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
+	mockUserSvc.On("GetUserByEmail", mock.Anything, "Alice@Example.com ").Return(nil, errors.New("not found"))
+	mockAttemptRepo.On("RecordFailure", mock.Anything, models.LoginScopeAccount, "alice@example.com", mock.Anything).Return(&models.LoginAttempt{Failures: 5}, nil)
+	mockAttemptRepo.On("RecordFailure", mock.Anything, models.LoginScopeIP, "203.0.113.7", mock.Anything).Return(&models.LoginAttempt{Failures: 5}, nil)
+	// Two failures past the threshold of 3 double the 1 minute base twice
+	mockAttemptRepo.On("Lock", mock.Anything, models.LoginScopeAccount, "alice@example.com", mock.MatchedBy(func(until time.Time) bool {
+		d := time.Until(until)
+		return d > 3*time.Minute+50*time.Second && d <= 4*time.Minute
+	})).Return(nil)
+
+	_, err := svc.Login(context.Background(), "Alice@Example.com ", "password123", "203.0.113.7")
+	assert.Error(t, err)
+	mockAttemptRepo.AssertExpectations(t)
+	mockAttemptRepo.AssertNotCalled(t, "Lock", mock.Anything, models.LoginScopeIP, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Login_LockoutCapped(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockAttemptRepo := new(mocks.MockLoginAttemptRepository)
+	svc := services.NewAuthService(mockUserSvc, nil, nil, mockAttemptRepo, nil, services.AuthConfig{
+		JWTSecret:        "test-secret",
+		LoginLockoutBase: time.Minute,
+		LoginLockoutMax:  10 * time.Minute,
+	})
+
+	mockAttemptRepo.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
+	mockUserSvc.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(nil, errors.New("not found"))
+	mockAttemptRepo.On("RecordFailure", mock.Anything, models.LoginScopeAccount, "alice@example.com", mock.Anything).Return(&models.LoginAttempt{Failures: 80}, nil)
+	mockAttemptRepo.On("Lock", mock.Anything, models.LoginScopeAccount, "alice@example.com", mock.MatchedBy(func(until time.Time) bool {
+		d := time.Until(until)
+		return d > 9*time.Minute && d <= 10*time.Minute
+	})).Return(nil)
+
+	_, err := svc.Login(context.Background(), "alice@example.com", "password123", "")
+	assert.Error(t, err)
+	mockAttemptRepo.AssertExpectations(t)
+}
+
+func TestAuthService_Login_AccountLocked(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockAttemptRepo := new(mocks.MockLoginAttemptRepository)
+	svc := services.NewAuthService(mockUserSvc, nil, nil, mockAttemptRepo, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	lockedUntil := time.Now().Add(2 * time.Minute)
+	mockAttemptRepo.On("Get", mock.Anything, models.LoginScopeAccount, "alice@example.com").Return(&models.LoginAttempt{Failures: 5, LockedUntil: &lockedUntil}, nil)
+
+	tokens, err := svc.Login(context.Background(), "alice@example.com", "password123", "203.0.113.7")
+	assert.Nil(t, tokens)
+	assert.ErrorIs(t, err, services.ErrLoginLocked)
+	var lockedErr *services.LoginLockedError
+	assert.True(t, errors.As(err, &lockedErr))
+	assert.InDelta(t, 2*time.Minute, lockedErr.RetryAfter, float64(5*time.Second))
+	mockUserSvc.AssertNotCalled(t, "GetUserByEmail", mock.Anything, mock.Anything)
+	mockAttemptRepo.AssertNotCalled(t, "RecordFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Login_IPLocked(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockAttemptRepo := new(mocks.MockLoginAttemptRepository)
+	svc := services.NewAuthService(mockUserSvc, nil, nil, mockAttemptRepo, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	lockedUntil := time.Now().Add(time.Minute)
+	mockAttemptRepo.On("Get", mock.Anything, models.LoginScopeAccount, "alice@example.com").Return(nil, errors.New("not found"))
+	mockAttemptRepo.On("Get", mock.Anything, models.LoginScopeIP, "203.0.113.7").Return(&models.LoginAttempt{Failures: 20, LockedUntil: &lockedUntil}, nil)
+
+	_, err := svc.Login(context.Background(), "alice@example.com", "password123", "203.0.113.7")
+	assert.ErrorIs(t, err, services.ErrLoginLocked)
+	mockUserSvc.AssertNotCalled(t, "GetUserByEmail", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Login_ExpiredLockAllowsAttempt(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockAttemptRepo := new(mocks.MockLoginAttemptRepository)
+	svc := services.NewAuthService(mockUserSvc, nil, nil, mockAttemptRepo, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	lockedUntil := time.Now().Add(-time.Minute)
+	mockAttemptRepo.On("Get", mock.Anything, models.LoginScopeAccount, "alice@example.com").Return(&models.LoginAttempt{Failures: 5, LockedUntil: &lockedUntil}, nil)
+	mockUserSvc.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(nil, errors.New("not found"))
+	mockAttemptRepo.On("RecordFailure", mock.Anything, models.LoginScopeAccount, "alice@example.com", mock.Anything).Return(&models.LoginAttempt{Failures: 6}, nil)
+	mockAttemptRepo.On("Lock", mock.Anything, models.LoginScopeAccount, "alice@example.com", mock.Anything).Return(nil)
+
+	_, err := svc.Login(context.Background(), "alice@example.com", "password123", "")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, services.ErrLoginLocked)
+	mockAttemptRepo.AssertExpectations(t)
 }
 
 func TestAuthService_Register_Success(t *testing.T) {
 	mockUserSvc := new(mocks.MockUserService)
-	svc := services.NewAuthService(mockUserSvc, "test-secret")
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	mockNotifier := new(mocks.MockNotifier)
+	svc := services.NewAuthService(mockUserSvc, mockSessionRepo, mockTokenRepo, nil, mockNotifier, services.AuthConfig{JWTSecret: "test-secret"})
 
-	mockUserSvc.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
+	var actor string
+	mockUserSvc.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.User")).Run(func(args mock.Arguments) {
+		actor = middleware.ActorID(args.Get(0).(context.Context))
+	}).Return(nil)
+	mockTokenRepo.On("DeleteByUser", mock.Anything, mock.Anything, models.TokenPurposeEmailVerification).Return(nil)
+	mockTokenRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.UserToken")).Return(nil)
+	mockNotifier.On("Send", mock.Anything, "alice@example.com", "Verify your email address", mock.Anything).Return(nil)
 
 	user := &models.User{
 		FirstName: "Alice",
@@ -80,17 +244,40 @@ func TestAuthService_Register_Success(t *testing.T) {
 		Email:     "alice@example.com",
 		Password:  "password123",
 		Role:      "candidate",
+		Verified:  true,
 	}
 
 	err := svc.Register(context.Background(), user)
 	assert.NoError(t, err)
 	assert.True(t, user.Active)
+	assert.False(t, user.Verified)
+	assert.False(t, user.ID.IsZero())
+	assert.Equal(t, user.ID.Hex(), actor)
 	mockUserSvc.AssertExpectations(t)
+	mockTokenRepo.AssertExpectations(t)
+	mockNotifier.AssertExpectations(t)
+}
+
+func TestAuthService_Register_NotifierFailureDoesNotFail(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	mockNotifier := new(mocks.MockNotifier)
+	svc := services.NewAuthService(mockUserSvc, nil, mockTokenRepo, nil, mockNotifier, services.AuthConfig{JWTSecret: "test-secret"})
+
+	mockUserSvc.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
+	mockTokenRepo.On("DeleteByUser", mock.Anything, mock.Anything, models.TokenPurposeEmailVerification).Return(nil)
+	mockTokenRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.UserToken")).Return(nil)
+	mockNotifier.On("Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("smtp down"))
+
+	user := &models.User{Email: "alice@example.com", Password: "password123", Role: "candidate"}
+	err := svc.Register(context.Background(), user)
+	assert.NoError(t, err)
 }
 
 func TestAuthService_Register_ServiceError(t *testing.T) {
 	mockUserSvc := new(mocks.MockUserService)
-	svc := services.NewAuthService(mockUserSvc, "test-secret")
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	svc := services.NewAuthService(mockUserSvc, mockSessionRepo, nil, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
 
 	mockUserSvc.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.User")).Return(errors.New("db error"))
 
@@ -99,3 +286,713 @@ func TestAuthService_Register_ServiceError(t *testing.T) {
 	assert.Error(t, err)
 	mockUserSvc.AssertExpectations(t)
 }
+
+func TestAuthService_Refresh_Success(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	svc := services.NewAuthService(mockUserSvc, mockSessionRepo, nil, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	user := &models.User{ID: bson.NewObjectID(), Email: "alice@example.com", Role: "candidate", Active: true}
+	session := &models.Session{ID: bson.NewObjectID(), UserID: user.ID, ExpiresTime: time.Now().Add(time.Hour)}
+	mockSessionRepo.On("Rotate", mock.Anything, helpers.HashToken("old-refresh"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(session, nil)
+	mockUserSvc.On("GetUserByID", mock.Anything, user.ID.Hex()).Return(user, nil)
+
+	tokens, err := svc.Refresh(context.Background(), "old-refresh")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEqual(t, "old-refresh", tokens.RefreshToken)
+	mockSessionRepo.AssertExpectations(t)
+	mockUserSvc.AssertExpectations(t)
+}
+
+func TestAuthService_Refresh_UserGone(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    *models.User
+		userErr error
+	}{
+		{"inactive", &models.User{Email: "alice@example.com", Role: "candidate", Active: false}, nil},
+		{"missing or deleted", nil, mongo.ErrNoDocuments},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserSvc := new(mocks.MockUserService)
+			mockSessionRepo := new(mocks.MockSessionRepository)
+			svc := services.NewAuthService(mockUserSvc, mockSessionRepo, nil, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+			userID := bson.NewObjectID()
+			session := &models.Session{ID: bson.NewObjectID(), UserID: userID, ExpiresTime: time.Now().Add(time.Hour)}
+			mockSessionRepo.On("Rotate", mock.Anything, helpers.HashToken("old-refresh"), mock.Anything, mock.Anything).Return(session, nil)
+			mockUserSvc.On("GetUserByID", mock.Anything, userID.Hex()).Return(tt.user, tt.userErr)
+			mockSessionRepo.On("Revoke", mock.Anything, session.ID.Hex(), "user_inactive").Return(nil)
+
+			tokens, err := svc.Refresh(context.Background(), "old-refresh")
+			assert.ErrorIs(t, err, services.ErrInvalidRefreshToken)
+			assert.Nil(t, tokens)
+			mockSessionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthService_Refresh_UnknownToken(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	svc := services.NewAuthService(mockUserSvc, mockSessionRepo, nil, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	hash := helpers.HashToken("unknown")
+	mockSessionRepo.On("Rotate", mock.Anything, hash, mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
+	mockSessionRepo.On("GetByPreviousHash", mock.Anything, hash).Return(nil, errors.New("not found"))
+
+	tokens, err := svc.Refresh(context.Background(), "unknown")
+	assert.ErrorIs(t, err, services.ErrInvalidRefreshToken)
+	assert.Nil(t, tokens)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestAuthService_Refresh_ReuseRevokesSession(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	svc := services.NewAuthService(mockUserSvc, mockSessionRepo, nil, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	hash := helpers.HashToken("rotated")
+	session := &models.Session{ID: bson.NewObjectID()}
+	mockSessionRepo.On("Rotate", mock.Anything, hash, mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
+	mockSessionRepo.On("GetByPreviousHash", mock.Anything, hash).Return(session, nil)
+	mockSessionRepo.On("Revoke", mock.Anything, session.ID.Hex(), "refresh_token_reuse").Return(nil)
+
+	tokens, err := svc.Refresh(context.Background(), "rotated")
+	assert.ErrorIs(t, err, services.ErrRefreshTokenReused)
+	assert.Nil(t, tokens)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestAuthService_Refresh_EmptyToken(t *testing.T) {
+	svc := services.NewAuthService(new(mocks.MockUserService), new(mocks.MockSessionRepository), nil, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	_, err := svc.Refresh(context.Background(), "")
+	assert.ErrorIs(t, err, services.ErrInvalidRefreshToken)
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	svc := services.NewAuthService(new(mocks.MockUserService), mockSessionRepo, nil, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	sessionID := bson.NewObjectID().Hex()
+	mockSessionRepo.On("Revoke", mock.Anything, sessionID, "logout").Return(nil)
+
+	err := svc.Logout(context.Background(), sessionID)
+	assert.NoError(t, err)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestAuthService_LogoutAll(t *testing.T) {
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	svc := services.NewAuthService(new(mocks.MockUserService), mockSessionRepo, nil, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	userID := bson.NewObjectID().Hex()
+	mockSessionRepo.On("RevokeAllForUser", mock.Anything, userID, "logout_all").Return(nil)
+
+	err := svc.LogoutAll(context.Background(), userID)
+	assert.NoError(t, err)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestAuthService_IsSessionActive(t *testing.T) {
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	svc := services.NewAuthService(new(mocks.MockUserService), mockSessionRepo, nil, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	revokedAt := time.Now()
+	active := &models.Session{ID: bson.NewObjectID(), ExpiresTime: time.Now().Add(time.Hour)}
+	revoked := &models.Session{ID: bson.NewObjectID(), ExpiresTime: time.Now().Add(time.Hour), RevokedTime: &revokedAt}
+	expired := &models.Session{ID: bson.NewObjectID(), ExpiresTime: time.Now().Add(-time.Minute)}
+	mockSessionRepo.On("GetByID", mock.Anything, active.ID.Hex()).Return(active, nil)
+	mockSessionRepo.On("GetByID", mock.Anything, revoked.ID.Hex()).Return(revoked, nil)
+	mockSessionRepo.On("GetByID", mock.Anything, expired.ID.Hex()).Return(expired, nil)
+
+	ok, err := svc.IsSessionActive(context.Background(), active.ID.Hex())
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, _ = svc.IsSessionActive(context.Background(), revoked.ID.Hex())
+	assert.False(t, ok)
+
+	ok, _ = svc.IsSessionActive(context.Background(), expired.ID.Hex())
+	assert.False(t, ok)
+}
+
+func TestAuthService_ForgotPassword_SendsResetLink(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	mockNotifier := new(mocks.MockNotifier)
+	svc := services.NewAuthService(mockUserSvc, nil, mockTokenRepo, nil, mockNotifier, services.AuthConfig{JWTSecret: "test-secret", AppBaseURL: "https://jobs.example.com/"})
+
+	user := &models.User{ID: bson.NewObjectID(), FirstName: "Alice", Email: "alice@example.com"}
+	mockUserSvc.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(user, nil)
+	mockTokenRepo.On("DeleteByUser", mock.Anything, user.ID.Hex(), models.TokenPurposePasswordReset).Return(nil)
+	mockTokenRepo.On("Create", mock.Anything, mock.MatchedBy(func(token *models.UserToken) bool {
+		return token.UserID == user.ID && token.Purpose == models.TokenPurposePasswordReset && token.TokenHash != "" && token.ExpiresTime.After(time.Now())
+	})).Return(nil)
+	mockNotifier.On("Send", mock.Anything, "alice@example.com", "Reset your password", mock.MatchedBy(func(body string) bool {
+		return strings.Contains(body, "https://jobs.example.com/reset-password?token=")
+	})).Return(nil)
+
+	err := svc.ForgotPassword(context.Background(), "alice@example.com")
+	assert.NoError(t, err)
+	mockTokenRepo.AssertExpectations(t)
+	mockNotifier.AssertExpectations(t)
+}
+
+func TestAuthService_ForgotPassword_UnknownEmail(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	mockNotifier := new(mocks.MockNotifier)
+	svc := services.NewAuthService(mockUserSvc, nil, mockTokenRepo, nil, mockNotifier, services.AuthConfig{JWTSecret: "test-secret"})
+
+	mockUserSvc.On("GetUserByEmail", mock.Anything, "nope@example.com").Return(nil, errors.New("not found"))
+
+	err := svc.ForgotPassword(context.Background(), "nope@example.com")
+	assert.NoError(t, err)
+	mockTokenRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockNotifier.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_ResetPassword_Success(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	svc := services.NewAuthService(mockUserSvc, mockSessionRepo, mockTokenRepo, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	userID := bson.NewObjectID()
+	mockTokenRepo.On("Consume", mock.Anything, helpers.HashToken("reset-token"), models.TokenPurposePasswordReset).Return(&models.UserToken{UserID: userID}, nil)
+	mockUserSvc.On("UpdatePassword", actedBy(userID.Hex()), userID.Hex(), "newpassword123").Return(nil)
+	mockSessionRepo.On("RevokeAllForUser", mock.Anything, userID.Hex(), "password_reset").Return(nil)
+
+	err := svc.ResetPassword(context.Background(), "reset-token", "newpassword123")
+	assert.NoError(t, err)
+	mockTokenRepo.AssertExpectations(t)
+	mockUserSvc.AssertExpectations(t)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestAuthService_ResetPassword_InvalidToken(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	svc := services.NewAuthService(mockUserSvc, nil, mockTokenRepo, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	mockTokenRepo.On("Consume", mock.Anything, helpers.HashToken("used-token"), models.TokenPurposePasswordReset).Return(nil, errors.New("not found"))
+
+	err := svc.ResetPassword(context.Background(), "used-token", "newpassword123")
+	assert.ErrorIs(t, err, services.ErrInvalidResetToken)
+	mockUserSvc.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_VerifyEmail_Success(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	svc := services.NewAuthService(mockUserSvc, nil, mockTokenRepo, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	userID := bson.NewObjectID()
+	mockTokenRepo.On("Consume", mock.Anything, helpers.HashToken("verify-token"), models.TokenPurposeEmailVerification).Return(&models.UserToken{UserID: userID}, nil)
+	mockUserSvc.On("MarkVerified", actedBy(userID.Hex()), userID.Hex()).Return(nil)
+
+	err := svc.VerifyEmail(context.Background(), "verify-token")
+	assert.NoError(t, err)
+	mockTokenRepo.AssertExpectations(t)
+	mockUserSvc.AssertExpectations(t)
+}
+
+func TestAuthService_VerifyEmail_InvalidToken(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	svc := services.NewAuthService(mockUserSvc, nil, mockTokenRepo, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	mockTokenRepo.On("Consume", mock.Anything, helpers.HashToken("expired"), models.TokenPurposeEmailVerification).Return(nil, errors.New("not found"))
+
+	err := svc.VerifyEmail(context.Background(), "expired")
+	assert.ErrorIs(t, err, services.ErrInvalidVerifyToken)
+	mockUserSvc.AssertNotCalled(t, "MarkVerified", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_ResendVerification_Unverified(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	mockNotifier := new(mocks.MockNotifier)
+	svc := services.NewAuthService(mockUserSvc, nil, mockTokenRepo, nil, mockNotifier, services.AuthConfig{JWTSecret: "test-secret"})
+
+	user := &models.User{ID: bson.NewObjectID(), Email: "alice@example.com"}
+	mockUserSvc.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(user, nil)
+	mockTokenRepo.On("DeleteByUser", mock.Anything, user.ID.Hex(), models.TokenPurposeEmailVerification).Return(nil)
+	mockTokenRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.UserToken")).Return(nil)
+	mockNotifier.On("Send", mock.Anything, "alice@example.com", "Verify your email address", mock.Anything).Return(nil)
+
+	err := svc.ResendVerification(context.Background(), "alice@example.com")
+	assert.NoError(t, err)
+	mockNotifier.AssertExpectations(t)
+}
+
+func TestAuthService_ResendVerification_AlreadyVerified(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockNotifier := new(mocks.MockNotifier)
+	svc := services.NewAuthService(mockUserSvc, nil, nil, nil, mockNotifier, services.AuthConfig{JWTSecret: "test-secret"})
+
+	user := &models.User{ID: bson.NewObjectID(), Email: "alice@example.com", Verified: true}
+	mockUserSvc.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(user, nil)
+
+	err := svc.ResendVerification(context.Background(), "alice@example.com")
+	assert.NoError(t, err)
+	mockNotifier.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Register_RejectsAdminRole(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	svc := services.NewAuthService(mockUserSvc, nil, nil, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	user := &models.User{Email: "mallory@example.com", Password: "password123", Role: "admin"}
+
+	err := svc.Register(context.Background(), user)
+	assert.ErrorIs(t, err, services.ErrRoleNotAllowed)
+	mockUserSvc.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Register_EmailTaken(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	svc := services.NewAuthService(mockUserSvc, nil, nil, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	mockUserSvc.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.User")).Return(services.ErrEmailTaken)
+
+	user := &models.User{Email: "alice@example.com", Password: "password123", Role: "candidate"}
+
+	err := svc.Register(context.Background(), user)
+	assert.ErrorIs(t, err, services.ErrEmailTaken)
+}
+
+func TestAuthService_Register_RecordsTermsAcceptance(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	mockNotifier := new(mocks.MockNotifier)
+	svc := services.NewAuthService(mockUserSvc, nil, mockTokenRepo, nil, mockNotifier, services.AuthConfig{JWTSecret: "test-secret"})
+
+	mockUserSvc.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
+	mockTokenRepo.On("DeleteByUser", mock.Anything, mock.Anything, models.TokenPurposeEmailVerification).Return(nil)
+	mockTokenRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.UserToken")).Return(nil)
+	mockNotifier.On("Send", mock.Anything, "bob@acme.com", mock.Anything, mock.Anything).Return(nil)
+
+	user := &models.User{Email: "bob@acme.com", Password: "password123", Role: "recruiter", CompanyName: "Acme", TermsAccepted: true}
+
+	err := svc.Register(context.Background(), user)
+	assert.NoError(t, err)
+	assert.NotNil(t, user.LastTermsAccepted)
+}
+
+func TestAuthService_Invite_Success(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	mockNotifier := new(mocks.MockNotifier)
+	svc := services.NewAuthService(mockUserSvc, nil, mockTokenRepo, nil, mockNotifier, services.AuthConfig{JWTSecret: "test-secret", AppBaseURL: "https://jobs.example.com"})
+
+	mockUserSvc.On("GetUserByEmail", mock.Anything, "new.admin@example.com").Return(nil, errors.New("not found"))
+	mockTokenRepo.On("DeleteByEmail", mock.Anything, "new.admin@example.com", models.TokenPurposeInvitation).Return(nil)
+	mockTokenRepo.On("Create", mock.Anything, mock.MatchedBy(func(tok *models.UserToken) bool {
+		return tok.Purpose == models.TokenPurposeInvitation &&
+			tok.Email == "new.admin@example.com" &&
+			tok.Role == models.RoleAdmin &&
+			tok.CreatedBy == "admin-id" &&
+			tok.UserID.IsZero()
+	})).Return(nil)
+	mockNotifier.On("Send", mock.Anything, "new.admin@example.com", "You have been invited", mock.MatchedBy(func(body string) bool {
+		return strings.Contains(body, "https://jobs.example.com/accept-invitation?token=")
+	})).Return(nil)
+
+	ctx := context.WithValue(context.Background(), middleware.ClaimsKey, &middleware.Claims{UserID: "admin-id", Role: "admin"})
+	err := svc.Invite(ctx, "new.admin@example.com")
+	assert.NoError(t, err)
+	mockTokenRepo.AssertExpectations(t)
+	mockNotifier.AssertExpectations(t)
+}
+
+func TestAuthService_Invite_EmailTaken(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	svc := services.NewAuthService(mockUserSvc, nil, mockTokenRepo, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	mockUserSvc.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(&models.User{Email: "alice@example.com"}, nil)
+
+	err := svc.Invite(context.Background(), "alice@example.com")
+	assert.ErrorIs(t, err, services.ErrEmailTaken)
+	mockTokenRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_AcceptInvitation_Success(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	svc := services.NewAuthService(mockUserSvc, nil, mockTokenRepo, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	invitation := &models.UserToken{Purpose: models.TokenPurposeInvitation, Email: "new.admin@example.com", Role: models.RoleAdmin, CreatedBy: "admin-id"}
+	mockTokenRepo.On("Consume", mock.Anything, helpers.HashToken("invite-token"), models.TokenPurposeInvitation).Return(invitation, nil)
+	mockUserSvc.On("CreateUser", mock.MatchedBy(func(ctx context.Context) bool {
+		return middleware.ActorID(ctx) == "admin-id"
+	}), mock.AnythingOfType("*models.User")).Return(nil)
+
+	user := &models.User{FirstName: "Nina", LastName: "Admin", Password: "password123", Role: "candidate"}
+
+	err := svc.AcceptInvitation(context.Background(), "invite-token", user)
+	assert.NoError(t, err)
+	assert.Equal(t, "new.admin@example.com", user.Email)
+	assert.Equal(t, models.RoleAdmin, user.Role)
+	assert.True(t, user.Verified)
+	mockUserSvc.AssertExpectations(t)
+}
+
+func TestAuthService_AcceptInvitation_InvalidToken(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	svc := services.NewAuthService(mockUserSvc, nil, mockTokenRepo, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	mockTokenRepo.On("Consume", mock.Anything, helpers.HashToken("bad"), models.TokenPurposeInvitation).Return(nil, errors.New("not found"))
+
+	err := svc.AcceptInvitation(context.Background(), "bad", &models.User{})
+	assert.ErrorIs(t, err, services.ErrInvalidInvitation)
+	mockUserSvc.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_UnlockAccount(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockAttemptRepo := new(mocks.MockLoginAttemptRepository)
+	svc := services.NewAuthService(mockUserSvc, nil, nil, mockAttemptRepo, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	userID := bson.NewObjectID()
+	mockUserSvc.On("GetUserByID", mock.Anything, userID.Hex()).Return(&models.User{ID: userID, Email: "Alice@Example.com"}, nil)
+	mockAttemptRepo.On("Reset", mock.Anything, models.LoginScopeAccount, "alice@example.com").Return(nil)
+
+	err := svc.UnlockAccount(context.Background(), userID.Hex())
+	assert.NoError(t, err)
+	mockAttemptRepo.AssertExpectations(t)
+}
+
+func TestAuthService_UnlockAccount_UserNotFound(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockAttemptRepo := new(mocks.MockLoginAttemptRepository)
+	svc := services.NewAuthService(mockUserSvc, nil, nil, mockAttemptRepo, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	mockUserSvc.On("GetUserByID", mock.Anything, "missing").Return(nil, errors.New("not found"))
+
+	err := svc.UnlockAccount(context.Background(), "missing")
+	assert.Error(t, err)
+	mockAttemptRepo.AssertNotCalled(t, "Reset", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func mfaUser(t *testing.T) (*models.User, string) {
+	t.Helper()
+	secret, err := helpers.GenerateTOTPSecret()
+	assert.NoError(t, err)
+	return &models.User{
+		ID:         bson.NewObjectID(),
+		Email:      "admin@example.com",
+		Password:   makeHashedPassword("password123"),
+		Active:     true,
+		Role:       models.RoleAdmin,
+		MFAEnabled: true,
+		MFASecret:  secret,
+	}, secret
+}
+
+// currentTOTP returns the code for the current time step together with the step
+func currentTOTP(t *testing.T, secret string) (string, int64) {
+	t.Helper()
+	step := helpers.TOTPStep(time.Now())
+	code, err := helpers.TOTPCode(secret, step)
+	assert.NoError(t, err)
+	return code, step
+}
+
+func TestAuthService_Login_MFAEnabledReturnsChallenge(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	mockAttemptRepo := new(mocks.MockLoginAttemptRepository)
+	svc := services.NewAuthService(mockUserSvc, nil, mockTokenRepo, mockAttemptRepo, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	user, _ := mfaUser(t)
+	mockAttemptRepo.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
+	mockUserSvc.On("GetUserByEmail", mock.Anything, "admin@example.com").Return(user, nil)
+	mockTokenRepo.On("DeleteByUser", mock.Anything, user.ID.Hex(), models.TokenPurposeMFAChallenge).Return(nil)
+	mockTokenRepo.On("Create", mock.Anything, mock.MatchedBy(func(token *models.UserToken) bool {
+		return token.Purpose == models.TokenPurposeMFAChallenge && token.UserID == user.ID
+	})).Return(nil)
+
+	tokens, err := svc.Login(context.Background(), "admin@example.com", "password123", "203.0.113.7")
+	assert.NoError(t, err)
+	assert.True(t, tokens.MFARequired)
+	assert.NotEmpty(t, tokens.MFAToken)
+	assert.Empty(t, tokens.AccessToken)
+	assert.Empty(t, tokens.RefreshToken)
+	assert.Equal(t, int64(300), tokens.ExpiresIn)
+	mockTokenRepo.AssertExpectations(t)
+	// The failed attempt counter is only cleared after the second factor
+	mockAttemptRepo.AssertNotCalled(t, "Reset", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_VerifyMFALogin_Success(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	mockAttemptRepo := new(mocks.MockLoginAttemptRepository)
+	svc := services.NewAuthService(mockUserSvc, mockSessionRepo, mockTokenRepo, mockAttemptRepo, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	user, secret := mfaUser(t)
+	code, step := currentTOTP(t, secret)
+	mockTokenRepo.On("Consume", mock.Anything, helpers.HashToken("challenge"), models.TokenPurposeMFAChallenge).Return(&models.UserToken{UserID: user.ID}, nil)
+	mockUserSvc.On("GetUserByID", mock.Anything, user.ID.Hex()).Return(user, nil)
+	mockAttemptRepo.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
+	mockUserSvc.On("UseMFAStep", mock.Anything, user.ID.Hex(), step).Return(nil)
+	mockAttemptRepo.On("Reset", mock.Anything, models.LoginScopeAccount, "admin@example.com").Return(nil)
+	mockUserSvc.On("RecordLogin", mock.Anything, user.ID.Hex()).Return(nil)
+	mockSessionRepo.On("Create", mock.Anything, mock.MatchedBy(func(session *models.Session) bool {
+		return session.MFA
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Session).ID = bson.NewObjectID()
+	}).Return(nil)
+
+	tokens, err := svc.VerifyMFALogin(context.Background(), "challenge", code, "203.0.113.7")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+
+	claims := &middleware.Claims{}
+	_, err = jwt.ParseWithClaims(tokens.AccessToken, claims, func(*jwt.Token) (interface{}, error) {
+		return []byte("test-secret"), nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, claims.MFA)
+	mockUserSvc.AssertExpectations(t)
+	mockSessionRepo.AssertExpectations(t)
+	mockAttemptRepo.AssertExpectations(t)
+}
+
+func TestAuthService_VerifyMFALogin_RecoveryCode(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	mockAttemptRepo := new(mocks.MockLoginAttemptRepository)
+	svc := services.NewAuthService(mockUserSvc, mockSessionRepo, mockTokenRepo, mockAttemptRepo, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	user, _ := mfaUser(t)
+	mockTokenRepo.On("Consume", mock.Anything, helpers.HashToken("challenge"), models.TokenPurposeMFAChallenge).Return(&models.UserToken{UserID: user.ID}, nil)
+	mockUserSvc.On("GetUserByID", mock.Anything, user.ID.Hex()).Return(user, nil)
+	mockAttemptRepo.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
+	mockUserSvc.On("UseRecoveryCode", mock.Anything, user.ID.Hex(), helpers.HashToken("0a1b2c3d4e")).Return(nil)
+	mockAttemptRepo.On("Reset", mock.Anything, models.LoginScopeAccount, "admin@example.com").Return(nil)
+	mockUserSvc.On("RecordLogin", mock.Anything, user.ID.Hex()).Return(nil)
+	mockSessionRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Session")).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Session).ID = bson.NewObjectID()
+	}).Return(nil)
+
+	tokens, err := svc.VerifyMFALogin(context.Background(), "challenge", "0A1B2-C3D4E", "")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	mockUserSvc.AssertExpectations(t)
+}
+
+func TestAuthService_VerifyMFALogin_WrongCodeCountsAsFailure(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	mockAttemptRepo := new(mocks.MockLoginAttemptRepository)
+	svc := services.NewAuthService(mockUserSvc, nil, mockTokenRepo, mockAttemptRepo, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	user, _ := mfaUser(t)
+	mockTokenRepo.On("Consume", mock.Anything, helpers.HashToken("challenge"), models.TokenPurposeMFAChallenge).Return(&models.UserToken{UserID: user.ID}, nil)
+	mockUserSvc.On("GetUserByID", mock.Anything, user.ID.Hex()).Return(user, nil)
+	mockAttemptRepo.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
+	mockAttemptRepo.On("RecordFailure", mock.Anything, models.LoginScopeAccount, "admin@example.com", mock.Anything).Return(&models.LoginAttempt{Failures: 1}, nil)
+	mockAttemptRepo.On("RecordFailure", mock.Anything, models.LoginScopeIP, "203.0.113.7", mock.Anything).Return(&models.LoginAttempt{Failures: 1}, nil)
+
+	tokens, err := svc.VerifyMFALogin(context.Background(), "challenge", "not-a-code", "203.0.113.7")
+	assert.ErrorIs(t, err, services.ErrInvalidMFACode)
+	assert.Nil(t, tokens)
+	mockAttemptRepo.AssertExpectations(t)
+}
+
+func TestAuthService_VerifyMFALogin_ReplayedCode(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	mockAttemptRepo := new(mocks.MockLoginAttemptRepository)
+	svc := services.NewAuthService(mockUserSvc, nil, mockTokenRepo, mockAttemptRepo, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	user, secret := mfaUser(t)
+	mockTokenRepo.On("Consume", mock.Anything, mock.Anything, models.TokenPurposeMFAChallenge).Return(&models.UserToken{UserID: user.ID}, nil)
+	mockUserSvc.On("GetUserByID", mock.Anything, user.ID.Hex()).Return(user, nil)
+	mockAttemptRepo.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
+	mockUserSvc.On("UseMFAStep", mock.Anything, user.ID.Hex(), mock.Anything).Return(errors.New("step already used"))
+	mockAttemptRepo.On("RecordFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.LoginAttempt{Failures: 1}, nil)
+
+	code, _ := currentTOTP(t, secret)
+	_, err := svc.VerifyMFALogin(context.Background(), "challenge", code, "203.0.113.7")
+	assert.ErrorIs(t, err, services.ErrInvalidMFACode)
+}
+
+func TestAuthService_VerifyMFALogin_InvalidChallenge(t *testing.T) {
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	svc := services.NewAuthService(new(mocks.MockUserService), nil, mockTokenRepo, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	mockTokenRepo.On("Consume", mock.Anything, mock.Anything, models.TokenPurposeMFAChallenge).Return(nil, errors.New("not found"))
+
+	_, err := svc.VerifyMFALogin(context.Background(), "expired", "123456", "")
+	assert.ErrorIs(t, err, services.ErrInvalidMFAChallenge)
+}
+
+func TestAuthService_VerifyMFALogin_InactiveUser(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	mockTokenRepo := new(mocks.MockUserTokenRepository)
+	svc := services.NewAuthService(mockUserSvc, mockSessionRepo, mockTokenRepo, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	user, secret := mfaUser(t)
+	user.Active = false
+	code, _ := currentTOTP(t, secret)
+	mockTokenRepo.On("Consume", mock.Anything, helpers.HashToken("challenge"), models.TokenPurposeMFAChallenge).Return(&models.UserToken{UserID: user.ID}, nil)
+	mockUserSvc.On("GetUserByID", mock.Anything, user.ID.Hex()).Return(user, nil)
+
+	tokens, err := svc.VerifyMFALogin(context.Background(), "challenge", code, "")
+	assert.ErrorIs(t, err, services.ErrInvalidMFAChallenge)
+	assert.Nil(t, tokens)
+	mockSessionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_EnrollMFA_Success(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	svc := services.NewAuthService(mockUserSvc, nil, nil, nil, nil, services.AuthConfig{JWTSecret: "test-secret", MFAIssuer: "Acme Jobs"})
+
+	user := &models.User{ID: bson.NewObjectID(), Email: "admin@example.com", Role: models.RoleAdmin}
+	mockUserSvc.On("GetUserByID", mock.Anything, user.ID.Hex()).Return(user, nil)
+	mockUserSvc.On("SetMFASecret", mock.Anything, user.ID.Hex(), mock.AnythingOfType("string")).Return(nil)
+
+	enrollment, err := svc.EnrollMFA(context.Background(), user.ID.Hex())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, enrollment.Secret)
+	assert.True(t, strings.HasPrefix(enrollment.URI, "otpauth://totp/Acme%20Jobs:admin@example.com?"))
+	assert.Contains(t, enrollment.URI, "secret="+enrollment.Secret)
+	mockUserSvc.AssertExpectations(t)
+}
+
+func TestAuthService_EnrollMFA_AlreadyEnabled(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	svc := services.NewAuthService(mockUserSvc, nil, nil, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	user, _ := mfaUser(t)
+	mockUserSvc.On("GetUserByID", mock.Anything, user.ID.Hex()).Return(user, nil)
+
+	_, err := svc.EnrollMFA(context.Background(), user.ID.Hex())
+	assert.ErrorIs(t, err, services.ErrMFAAlreadyEnabled)
+	mockUserSvc.AssertNotCalled(t, "SetMFASecret", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_ConfirmMFA_Success(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	svc := services.NewAuthService(mockUserSvc, nil, nil, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	user, secret := mfaUser(t)
+	user.MFAEnabled = false
+	code, step := currentTOTP(t, secret)
+	mockUserSvc.On("GetUserByID", mock.Anything, user.ID.Hex()).Return(user, nil)
+
+	var storedHashes []string
+	mockUserSvc.On("EnableMFA", mock.Anything, user.ID.Hex(), mock.Anything, step).Run(func(args mock.Arguments) {
+		storedHashes = args.Get(2).([]string)
+	}).Return(nil)
+
+	codes, err := svc.ConfirmMFA(context.Background(), user.ID.Hex(), code)
+	assert.NoError(t, err)
+	assert.Len(t, codes, 10)
+	assert.Len(t, storedHashes, 10)
+	assert.Equal(t, helpers.HashToken(strings.ReplaceAll(codes[0], "-", "")), storedHashes[0])
+	assert.NotContains(t, storedHashes, codes[0])
+	mockUserSvc.AssertExpectations(t)
+}
+
+func TestAuthService_ConfirmMFA_InvalidCode(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	svc := services.NewAuthService(mockUserSvc, nil, nil, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	user, _ := mfaUser(t)
+	user.MFAEnabled = false
+	mockUserSvc.On("GetUserByID", mock.Anything, user.ID.Hex()).Return(user, nil)
+
+	_, err := svc.ConfirmMFA(context.Background(), user.ID.Hex(), "000000x")
+	assert.ErrorIs(t, err, services.ErrInvalidMFACode)
+	mockUserSvc.AssertNotCalled(t, "EnableMFA", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_ConfirmMFA_NotEnrolled(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	svc := services.NewAuthService(mockUserSvc, nil, nil, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	user := &models.User{ID: bson.NewObjectID(), Email: "admin@example.com"}
+	mockUserSvc.On("GetUserByID", mock.Anything, user.ID.Hex()).Return(user, nil)
+
+	_, err := svc.ConfirmMFA(context.Background(), user.ID.Hex(), "123456")
+	assert.ErrorIs(t, err, services.ErrInvalidMFACode)
+}
+
+func TestAuthService_DisableMFA_Success(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	svc := services.NewAuthService(mockUserSvc, nil, nil, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	user, secret := mfaUser(t)
+	mockUserSvc.On("GetUserByID", mock.Anything, user.ID.Hex()).Return(user, nil)
+	mockUserSvc.On("UseMFAStep", mock.Anything, user.ID.Hex(), mock.Anything).Return(nil)
+	mockUserSvc.On("DisableMFA", mock.Anything, user.ID.Hex()).Return(nil)
+
+	code, _ := currentTOTP(t, secret)
+	err := svc.DisableMFA(context.Background(), user.ID.Hex(), code)
+	assert.NoError(t, err)
+	mockUserSvc.AssertExpectations(t)
+}
+
+func TestAuthService_DisableMFA_NotEnabled(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	svc := services.NewAuthService(mockUserSvc, nil, nil, nil, nil, services.AuthConfig{JWTSecret: "test-secret"})
+
+	user := &models.User{ID: bson.NewObjectID()}
+	mockUserSvc.On("GetUserByID", mock.Anything, user.ID.Hex()).Return(user, nil)
+
+	err := svc.DisableMFA(context.Background(), user.ID.Hex(), "123456")
+	assert.ErrorIs(t, err, services.ErrMFANotEnabled)
+}
+
+func TestAuthService_Login_SignsWithKeySet(t *testing.T) {
+	mockUserSvc := new(mocks.MockUserService)
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	mockAttemptRepo := new(mocks.MockLoginAttemptRepository)
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	key, err := middleware.NewSigningKey("ed-1", privateKey)
+	assert.NoError(t, err)
+	keys, err := middleware.NewKeySet("", []middleware.SigningKey{key}, "")
+	assert.NoError(t, err)
+	svc := services.NewAuthService(mockUserSvc, mockSessionRepo, nil, mockAttemptRepo, nil, services.AuthConfig{Keys: keys})
+
+	user := &models.User{
+		ID:       bson.NewObjectID(),
+		Email:    "alice@example.com",
+		Password: makeHashedPassword("password123"),
+		Active:   true,
+		Role:     "candidate",
+	}
+	mockAttemptRepo.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
+	mockUserSvc.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(user, nil)
+	mockAttemptRepo.On("Reset", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockUserSvc.On("RecordLogin", mock.Anything, user.ID.Hex()).Return(nil)
+	mockSessionRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Session")).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Session).ID = bson.NewObjectID()
+	}).Return(nil)
+
+	tokens, err := svc.Login(context.Background(), "alice@example.com", "password123", "")
+	assert.NoError(t, err)
+
+	claims := &middleware.Claims{}
+	token, err := jwt.ParseWithClaims(tokens.AccessToken, claims, keys.Keyfunc)
+	assert.NoError(t, err)
+	assert.Equal(t, "EdDSA", token.Method.Alg())
+	assert.Equal(t, "ed-1", token.Header["kid"])
+	assert.Equal(t, user.ID.Hex(), claims.UserID)
+}
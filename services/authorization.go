@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go-mongodb-api/middleware"
+	"go-mongodb-api/models"
+)
+
+// ErrForbidden is returned when the caller is not allowed to act on a resource
+var ErrForbidden = errors.New("forbidden")
+
+// isAdmin reports whether the caller in ctx is an admin
+func isAdmin(ctx context.Context) bool {
+	claims, ok := middleware.GetClaims(ctx)
+	return ok && claims.Role == models.RoleAdmin
+}
+
+// authorizeUser allows admins and the user identified by userID.
+// Requests without claims are rejected.
+func authorizeUser(ctx context.Context, userID string) error {
+	claims, ok := middleware.GetClaims(ctx)
+	if !ok {
+		return ErrForbidden
+	}
+	if claims.Role == models.RoleAdmin || claims.UserID == userID {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// isCaller reports whether the request was made by the user identified by userID
+func isCaller(ctx context.Context, userID string) bool {
+	claims, ok := middleware.GetClaims(ctx)
+	return ok && claims.UserID == userID
+}
+
+// authorizeJob allows admins and the recruiter who posted the job
+func authorizeJob(ctx context.Context, job *models.Job) error {
+	return authorizeUser(ctx, job.UserID.Hex())
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"go-mongodb-api/interfaces"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/models"
+	"time"
 )
 
 type CandidateSkillService struct {
@@ -27,18 +29,37 @@ func (s *CandidateSkillService) GetAllCandidateSkills(ctx context.Context, page,
 	return s.repo.GetAll(ctx, page, limit, filters, sort, order)
 }
 
-// GetCandidateSkillByID retrieves a candidate skill by ID
+// GetCandidateSkillByID retrieves one of the caller's candidate skills by ID
 func (s *CandidateSkillService) GetCandidateSkillByID(ctx context.Context, id string) (*models.CandidateSkill, error) {
-	return s.repo.GetByID(ctx, id)
+	candidateSkill, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeUser(ctx, candidateSkill.UserID.Hex()); err != nil {
+		return nil, err
+	}
+
+	return candidateSkill, nil
 }
 
-// GetCandidateSkillsByUserID retrieves all skills for a specific user
+// GetCandidateSkillsByUserID retrieves all skills for a specific user.
+// Recruiters may view any candidate's skills; candidates only their own.
 func (s *CandidateSkillService) GetCandidateSkillsByUserID(ctx context.Context, userID string) ([]models.CandidateSkill, error) {
+	if claims, ok := middleware.GetClaims(ctx); !ok || claims.Role != models.RoleRecruiter {
+		if err := authorizeUser(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
 	return s.repo.GetByUserID(ctx, userID)
 }
 
-// CreateCandidateSkill creates a new candidate skill
+// CreateCandidateSkill adds a skill to the caller's profile
 func (s *CandidateSkillService) CreateCandidateSkill(ctx context.Context, candidateSkill *models.CandidateSkill) error {
+	if err := authorizeUser(ctx, candidateSkill.UserID.Hex()); err != nil {
+		return err
+	}
+
 	if _, err := s.userRepo.GetByID(ctx, candidateSkill.UserID.Hex()); err != nil {
 		return fmt.Errorf("user not found")
 	}
@@ -47,15 +68,36 @@ func (s *CandidateSkillService) CreateCandidateSkill(ctx context.Context, candid
 		return fmt.Errorf("skill not found")
 	}
 
+	stampCreated(ctx, candidateSkill, time.Now())
 	return s.repo.Create(ctx, candidateSkill)
 }
 
-// UpdateCandidateSkillProficiencyLevel updates the proficiency level of a candidate skill
+// UpdateCandidateSkillProficiencyLevel updates the proficiency level of one of the caller's candidate skills
 func (s *CandidateSkillService) UpdateCandidateSkillProficiencyLevel(ctx context.Context, id string, proficiencyLevel string) error {
-	return s.repo.UpdateProficiencyLevel(ctx, id, proficiencyLevel)
+	if err := s.authorizeOwner(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.UpdateProficiencyLevel(ctx, id, proficiencyLevel, updatedAudit(ctx, time.Now()))
 }
 
-// DeleteCandidateSkill deletes a candidate skill by ID
+// DeleteCandidateSkill deletes one of the caller's candidate skills by ID
 func (s *CandidateSkillService) DeleteCandidateSkill(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	if err := s.authorizeOwner(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id, middleware.ActorID(ctx))
+}
+
+// RestoreCandidateSkill restores a soft-deleted candidate skill by ID
+func (s *CandidateSkillService) RestoreCandidateSkill(ctx context.Context, id string) error {
+	return s.repo.Restore(ctx, id, updatedAudit(ctx, time.Now()))
+}
+
+// authorizeOwner allows admins and the candidate the skill belongs to
+func (s *CandidateSkillService) authorizeOwner(ctx context.Context, id string) error {
+	candidateSkill, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return authorizeUser(ctx, candidateSkill.UserID.Hex())
 }
@@ -33,10 +33,11 @@ func TestCandidateSkillService_GetCandidateSkillByID(t *testing.T) {
 	svc := services.NewCandidateSkillService(mockRepo, nil, nil)
 
 	id := bson.NewObjectID()
-	expected := &models.CandidateSkill{ID: id, ProficiencyLevel: "intermediate"}
+	userID := bson.NewObjectID()
+	expected := &models.CandidateSkill{ID: id, UserID: userID, ProficiencyLevel: "intermediate"}
 	mockRepo.On("GetByID", mock.Anything, id.Hex()).Return(expected, nil)
 
-	cs, err := svc.GetCandidateSkillByID(context.Background(), id.Hex())
+	cs, err := svc.GetCandidateSkillByID(claimsContext("candidate", userID.Hex()), id.Hex())
 	assert.NoError(t, err)
 	assert.Equal(t, "intermediate", cs.ProficiencyLevel)
 	mockRepo.AssertExpectations(t)
@@ -50,12 +51,22 @@ func TestCandidateSkillService_GetByUserID(t *testing.T) {
 	expected := []models.CandidateSkill{{ProficiencyLevel: "beginner"}}
 	mockRepo.On("GetByUserID", mock.Anything, userID.Hex()).Return(expected, nil)
 
-	skills, err := svc.GetCandidateSkillsByUserID(context.Background(), userID.Hex())
+	skills, err := svc.GetCandidateSkillsByUserID(claimsContext("recruiter", bson.NewObjectID().Hex()), userID.Hex())
 	assert.NoError(t, err)
 	assert.Len(t, skills, 1)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestCandidateSkillService_GetByUserID_OtherCandidate(t *testing.T) {
+	mockRepo := new(mocks.MockCandidateSkillRepository)
+	svc := services.NewCandidateSkillService(mockRepo, nil, nil)
+
+	skills, err := svc.GetCandidateSkillsByUserID(claimsContext("candidate", bson.NewObjectID().Hex()), bson.NewObjectID().Hex())
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	assert.Nil(t, skills)
+	mockRepo.AssertNotCalled(t, "GetByUserID", mock.Anything, mock.Anything)
+}
+
 func TestCandidateSkillService_CreateCandidateSkill_Success(t *testing.T) {
 	mockRepo := new(mocks.MockCandidateSkillRepository)
 	mockUserRepo := new(mocks.MockUserRepository)
@@ -69,7 +80,7 @@ func TestCandidateSkillService_CreateCandidateSkill_Success(t *testing.T) {
 	mockSkillRepo.On("GetByID", mock.Anything, skillID.Hex()).Return(&models.Skill{}, nil)
 	mockRepo.On("Create", mock.Anything, cs).Return(nil)
 
-	err := svc.CreateCandidateSkill(context.Background(), cs)
+	err := svc.CreateCandidateSkill(claimsContext("candidate", cs.UserID.Hex()), cs)
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 	mockUserRepo.AssertExpectations(t)
@@ -86,7 +97,7 @@ func TestCandidateSkillService_CreateCandidateSkill_UserNotFound(t *testing.T) {
 	cs := &models.CandidateSkill{UserID: userID}
 	mockUserRepo.On("GetByID", mock.Anything, userID.Hex()).Return(nil, errors.New("not found"))
 
-	err := svc.CreateCandidateSkill(context.Background(), cs)
+	err := svc.CreateCandidateSkill(claimsContext("candidate", cs.UserID.Hex()), cs)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "user not found")
 }
@@ -103,18 +114,31 @@ func TestCandidateSkillService_CreateCandidateSkill_SkillNotFound(t *testing.T)
 	mockUserRepo.On("GetByID", mock.Anything, userID.Hex()).Return(&models.User{}, nil)
 	mockSkillRepo.On("GetByID", mock.Anything, skillID.Hex()).Return(nil, errors.New("not found"))
 
-	err := svc.CreateCandidateSkill(context.Background(), cs)
+	err := svc.CreateCandidateSkill(claimsContext("candidate", cs.UserID.Hex()), cs)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "skill not found")
 }
 
+func TestCandidateSkillService_CreateCandidateSkill_ForAnotherUser(t *testing.T) {
+	mockRepo := new(mocks.MockCandidateSkillRepository)
+	svc := services.NewCandidateSkillService(mockRepo, nil, nil)
+
+	cs := &models.CandidateSkill{UserID: bson.NewObjectID(), SkillID: bson.NewObjectID()}
+
+	err := svc.CreateCandidateSkill(claimsContext("candidate", bson.NewObjectID().Hex()), cs)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
 func TestCandidateSkillService_UpdateProficiencyLevel(t *testing.T) {
 	mockRepo := new(mocks.MockCandidateSkillRepository)
 	svc := services.NewCandidateSkillService(mockRepo, nil, nil)
 
-	mockRepo.On("UpdateProficiencyLevel", mock.Anything, "cs-id", "expert").Return(nil)
+	userID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "cs-id").Return(&models.CandidateSkill{UserID: userID}, nil)
+	mockRepo.On("UpdateProficiencyLevel", mock.Anything, "cs-id", "expert", updatedBy(userID.Hex())).Return(nil)
 
-	err := svc.UpdateCandidateSkillProficiencyLevel(context.Background(), "cs-id", "expert")
+	err := svc.UpdateCandidateSkillProficiencyLevel(claimsContext("candidate", userID.Hex()), "cs-id", "expert")
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
@@ -123,9 +147,22 @@ func TestCandidateSkillService_DeleteCandidateSkill(t *testing.T) {
 	mockRepo := new(mocks.MockCandidateSkillRepository)
 	svc := services.NewCandidateSkillService(mockRepo, nil, nil)
 
-	mockRepo.On("Delete", mock.Anything, "cs-id").Return(nil)
+	userID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "cs-id").Return(&models.CandidateSkill{UserID: userID}, nil)
+	mockRepo.On("Delete", mock.Anything, "cs-id", mock.Anything).Return(nil)
 
-	err := svc.DeleteCandidateSkill(context.Background(), "cs-id")
+	err := svc.DeleteCandidateSkill(claimsContext("candidate", userID.Hex()), "cs-id")
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestCandidateSkillService_DeleteCandidateSkill_OtherCandidate(t *testing.T) {
+	mockRepo := new(mocks.MockCandidateSkillRepository)
+	svc := services.NewCandidateSkillService(mockRepo, nil, nil)
+
+	mockRepo.On("GetByID", mock.Anything, "cs-id").Return(&models.CandidateSkill{UserID: bson.NewObjectID()}, nil)
+
+	err := svc.DeleteCandidateSkill(claimsContext("candidate", bson.NewObjectID().Hex()), "cs-id")
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"go-mongodb-api/interfaces"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/models"
+	"time"
 )
 
 type CountryService struct {
@@ -23,13 +25,19 @@ func (s *CountryService) GetCountryByID(ctx context.Context, id string) (*models
 }
 
 func (s *CountryService) CreateCountry(ctx context.Context, country *models.Country) error {
+	stampCreated(ctx, country, time.Now())
 	return s.repo.Create(ctx, country)
 }
 
 func (s *CountryService) UpdateCountry(ctx context.Context, id string, country *models.Country) (*models.Country, error) {
+	stampUpdated(ctx, country, time.Now())
 	return s.repo.Update(ctx, id, country)
 }
 
 func (s *CountryService) DeleteCountry(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	return s.repo.Delete(ctx, id, middleware.ActorID(ctx))
+}
+
+func (s *CountryService) RestoreCountry(ctx context.Context, id string) error {
+	return s.repo.Restore(ctx, id, updatedAudit(ctx, time.Now()))
 }
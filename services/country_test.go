@@ -2,6 +2,7 @@ package services_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 
@@ -66,11 +67,27 @@ func TestCountryService_CreateCountry(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestCountryService_CreateCountry_IgnoresDeletedFields(t *testing.T) {
+	mockRepo := new(mocks.MockCountryRepository)
+	svc := services.NewCountryService(mockRepo)
+
+	var country models.Country
+	body := `{"name": "Germany", "deleted_time": "2024-01-01T00:00:00Z", "deleted_by": "someone"}`
+	assert.NoError(t, json.Unmarshal([]byte(body), &country))
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(c *models.Country) bool {
+		return c.DeletedTime == nil && c.DeletedBy == ""
+	})).Return(nil)
+
+	err := svc.CreateCountry(context.Background(), &country)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestCountryService_DeleteCountry(t *testing.T) {
 	mockRepo := new(mocks.MockCountryRepository)
 	svc := services.NewCountryService(mockRepo)
 
-	mockRepo.On("Delete", mock.Anything, "country-id").Return(nil)
+	mockRepo.On("Delete", mock.Anything, "country-id", mock.Anything).Return(nil)
 
 	err := svc.DeleteCountry(context.Background(), "country-id")
 	assert.NoError(t, err)
@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"go-mongodb-api/interfaces"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/models"
+	"time"
 )
 
 type EducationLevelService struct {
@@ -23,13 +25,19 @@ func (s *EducationLevelService) GetEducationLevelByID(ctx context.Context, id st
 }
 
 func (s *EducationLevelService) CreateEducationLevel(ctx context.Context, educationLevel *models.EducationLevel) error {
+	stampCreated(ctx, educationLevel, time.Now())
 	return s.repo.Create(ctx, educationLevel)
 }
 
 func (s *EducationLevelService) UpdateEducationLevel(ctx context.Context, id string, educationLevel *models.EducationLevel) (*models.EducationLevel, error) {
+	stampUpdated(ctx, educationLevel, time.Now())
 	return s.repo.Update(ctx, id, educationLevel)
 }
 
 func (s *EducationLevelService) DeleteEducationLevel(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	return s.repo.Delete(ctx, id, middleware.ActorID(ctx))
+}
+
+func (s *EducationLevelService) RestoreEducationLevel(ctx context.Context, id string) error {
+	return s.repo.Restore(ctx, id, updatedAudit(ctx, time.Now()))
 }
@@ -70,7 +70,7 @@ func TestEducationLevelService_DeleteEducationLevel(t *testing.T) {
 	mockRepo := new(mocks.MockEducationLevelRepository)
 	svc := services.NewEducationLevelService(mockRepo)
 
-	mockRepo.On("Delete", mock.Anything, "level-id").Return(nil)
+	mockRepo.On("Delete", mock.Anything, "level-id", mock.Anything).Return(nil)
 
 	err := svc.DeleteEducationLevel(context.Background(), "level-id")
 	assert.NoError(t, err)
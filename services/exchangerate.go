@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go-mongodb-api/interfaces"
+	"go-mongodb-api/models"
+)
+
+// ErrBaseCurrencyRate is returned when setting or deleting the rate of the base currency, which is always 1
+var ErrBaseCurrencyRate = errors.New("the base currency rate is fixed at 1")
+
+type ExchangeRateService struct {
+	repo         interfaces.ExchangeRateRepository
+	jobRepo      interfaces.JobRepository
+	baseCurrency string
+}
+
+func NewExchangeRateService(repo interfaces.ExchangeRateRepository, jobRepo interfaces.JobRepository, baseCurrency string) *ExchangeRateService {
+	return &ExchangeRateService{repo: repo, jobRepo: jobRepo, baseCurrency: baseCurrency}
+}
+
+// GetExchangeRates lists the exchange rates together with the base currency they are against
+func (s *ExchangeRateService) GetExchangeRates(ctx context.Context) (*models.ExchangeRates, error) {
+	rates, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ExchangeRates{BaseCurrency: s.baseCurrency, Rates: rates}, nil
+}
+
+// SetExchangeRate creates or updates the rate of a currency and renormalizes the salaries of the
+// jobs paid in it
+func (s *ExchangeRateService) SetExchangeRate(ctx context.Context, currency string, rate float64) (*models.ExchangeRate, error) {
+	if currency == s.baseCurrency {
+		return nil, ErrBaseCurrencyRate
+	}
+
+	exchangeRate := &models.ExchangeRate{Currency: currency, Rate: rate}
+	stampUpdated(ctx, exchangeRate, time.Now())
+	stored, err := s.repo.Upsert(ctx, exchangeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.jobRepo.NormalizeSalaries(ctx, currency, rate)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("exchange rate %s set to %g, renormalized %d job(s)", currency, rate, updated)
+	return stored, nil
+}
+
+// DeleteExchangeRate removes the rate of a currency. Jobs paid in it lose their normalized salaries.
+func (s *ExchangeRateService) DeleteExchangeRate(ctx context.Context, currency string) error {
+	if currency == s.baseCurrency {
+		return ErrBaseCurrencyRate
+	}
+
+	if err := s.repo.Delete(ctx, currency); err != nil {
+		return err
+	}
+
+	_, err := s.jobRepo.ClearNormalizedSalaries(ctx, currency)
+	return err
+}
@@ -0,0 +1,96 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-mongodb-api/mocks"
+	"go-mongodb-api/models"
+	"go-mongodb-api/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestExchangeRateService_GetExchangeRates(t *testing.T) {
+	mockRepo := new(mocks.MockExchangeRateRepository)
+	svc := services.NewExchangeRateService(mockRepo, nil, "USD")
+
+	rates := []models.ExchangeRate{{Currency: "EUR", Rate: 0.92}}
+	mockRepo.On("GetAll", mock.Anything).Return(rates, nil)
+
+	result, err := svc.GetExchangeRates(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", result.BaseCurrency)
+	assert.Equal(t, rates, result.Rates)
+}
+
+func TestExchangeRateService_SetExchangeRate_RenormalizesJobs(t *testing.T) {
+	mockRepo := new(mocks.MockExchangeRateRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewExchangeRateService(mockRepo, mockJobRepo, "USD")
+
+	adminID := bson.NewObjectID().Hex()
+	stored := &models.ExchangeRate{Currency: "EUR", Rate: 0.92}
+	mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *models.ExchangeRate) bool {
+		return r.Currency == "EUR" && r.Rate == 0.92 && r.UpdatedBy == adminID
+	})).Return(stored, nil)
+	mockJobRepo.On("NormalizeSalaries", mock.Anything, "EUR", 0.92).Return(int64(4), nil)
+
+	result, err := svc.SetExchangeRate(claimsContext("admin", adminID), "EUR", 0.92)
+	assert.NoError(t, err)
+	assert.Equal(t, stored, result)
+	mockRepo.AssertExpectations(t)
+	mockJobRepo.AssertExpectations(t)
+}
+
+func TestExchangeRateService_SetExchangeRate_BaseCurrency(t *testing.T) {
+	mockRepo := new(mocks.MockExchangeRateRepository)
+	svc := services.NewExchangeRateService(mockRepo, nil, "USD")
+
+	result, err := svc.SetExchangeRate(context.Background(), "USD", 2)
+	assert.ErrorIs(t, err, services.ErrBaseCurrencyRate)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestExchangeRateService_SetExchangeRate_RepoError(t *testing.T) {
+	mockRepo := new(mocks.MockExchangeRateRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewExchangeRateService(mockRepo, mockJobRepo, "USD")
+
+	mockRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+
+	_, err := svc.SetExchangeRate(context.Background(), "EUR", 0.92)
+	assert.Error(t, err)
+	mockJobRepo.AssertNotCalled(t, "NormalizeSalaries", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExchangeRateService_DeleteExchangeRate_ClearsJobs(t *testing.T) {
+	mockRepo := new(mocks.MockExchangeRateRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewExchangeRateService(mockRepo, mockJobRepo, "USD")
+
+	mockRepo.On("Delete", mock.Anything, "EUR").Return(nil)
+	mockJobRepo.On("ClearNormalizedSalaries", mock.Anything, "EUR").Return(int64(4), nil)
+
+	err := svc.DeleteExchangeRate(context.Background(), "EUR")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockJobRepo.AssertExpectations(t)
+}
+
+func TestExchangeRateService_DeleteExchangeRate_NotFound(t *testing.T) {
+	mockRepo := new(mocks.MockExchangeRateRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewExchangeRateService(mockRepo, mockJobRepo, "USD")
+
+	mockRepo.On("Delete", mock.Anything, "EUR").Return(mongo.ErrNoDocuments)
+
+	err := svc.DeleteExchangeRate(context.Background(), "EUR")
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	mockJobRepo.AssertNotCalled(t, "ClearNormalizedSalaries", mock.Anything, mock.Anything)
+}
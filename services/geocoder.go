@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go-mongodb-api/interfaces"
+	"go-mongodb-api/models"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var ErrPlaceNotFound = errors.New("place not found")
+
+// Geocoder resolves place names to coordinates from the imported cities, offline
+type Geocoder struct {
+	cityRepo    interfaces.CityRepository
+	countryRepo interfaces.CountryRepository
+}
+
+// NewGeocoder creates a new geocoder
+func NewGeocoder(cityRepo interfaces.CityRepository, countryRepo interfaces.CountryRepository) *Geocoder {
+	return &Geocoder{
+		cityRepo:    cityRepo,
+		countryRepo: countryRepo,
+	}
+}
+
+// Geocode finds the city a place names. A place is a city name, optionally followed by a comma and
+// its country ("Skopje, North Macedonia"); without a country the most populous city of that name is
+// chosen. Parts between the city and the country, such as a region, are ignored. It returns
+// ErrPlaceNotFound when no imported city matches.
+func (g *Geocoder) Geocode(ctx context.Context, place string) (*models.City, error) {
+	parts := strings.Split(place, ",")
+	name := strings.TrimSpace(parts[0])
+	if name == "" {
+		return nil, ErrPlaceNotFound
+	}
+
+	countryID := ""
+	if len(parts) > 1 {
+		country, err := g.countryRepo.GetByName(ctx, strings.TrimSpace(parts[len(parts)-1]))
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrPlaceNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		countryID = country.ID.Hex()
+	}
+
+	city, err := g.cityRepo.FindByName(ctx, name, countryID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrPlaceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return city, nil
+}
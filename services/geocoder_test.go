@@ -0,0 +1,75 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"go-mongodb-api/mocks"
+	"go-mongodb-api/models"
+	"go-mongodb-api/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestGeocoder_Geocode_CityOnly(t *testing.T) {
+	mockCityRepo := new(mocks.MockCityRepository)
+	mockCountryRepo := new(mocks.MockCountryRepository)
+	geocoder := services.NewGeocoder(mockCityRepo, mockCountryRepo)
+
+	city := &models.City{Name: "Skopje"}
+	mockCityRepo.On("FindByName", mock.Anything, "Skopje", "").Return(city, nil)
+
+	result, err := geocoder.Geocode(context.Background(), " Skopje ")
+	assert.NoError(t, err)
+	assert.Equal(t, city, result)
+	mockCountryRepo.AssertNotCalled(t, "GetByName", mock.Anything, mock.Anything)
+}
+
+func TestGeocoder_Geocode_WithCountry(t *testing.T) {
+	mockCityRepo := new(mocks.MockCityRepository)
+	mockCountryRepo := new(mocks.MockCountryRepository)
+	geocoder := services.NewGeocoder(mockCityRepo, mockCountryRepo)
+
+	countryID := bson.NewObjectID()
+	city := &models.City{Name: "Portland", CountryID: countryID}
+	mockCountryRepo.On("GetByName", mock.Anything, "United States").Return(&models.Country{ID: countryID}, nil)
+	mockCityRepo.On("FindByName", mock.Anything, "Portland", countryID.Hex()).Return(city, nil)
+
+	result, err := geocoder.Geocode(context.Background(), "Portland, Oregon, United States")
+	assert.NoError(t, err)
+	assert.Equal(t, city, result)
+	mockCityRepo.AssertExpectations(t)
+}
+
+func TestGeocoder_Geocode_UnknownCountry(t *testing.T) {
+	mockCityRepo := new(mocks.MockCityRepository)
+	mockCountryRepo := new(mocks.MockCountryRepository)
+	geocoder := services.NewGeocoder(mockCityRepo, mockCountryRepo)
+
+	mockCountryRepo.On("GetByName", mock.Anything, "Atlantis").Return(nil, mongo.ErrNoDocuments)
+
+	_, err := geocoder.Geocode(context.Background(), "Poseidonia, Atlantis")
+	assert.ErrorIs(t, err, services.ErrPlaceNotFound)
+	mockCityRepo.AssertNotCalled(t, "FindByName", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGeocoder_Geocode_UnknownCity(t *testing.T) {
+	mockCityRepo := new(mocks.MockCityRepository)
+	mockCountryRepo := new(mocks.MockCountryRepository)
+	geocoder := services.NewGeocoder(mockCityRepo, mockCountryRepo)
+
+	mockCityRepo.On("FindByName", mock.Anything, "Remote", "").Return(nil, mongo.ErrNoDocuments)
+
+	_, err := geocoder.Geocode(context.Background(), "Remote")
+	assert.ErrorIs(t, err, services.ErrPlaceNotFound)
+}
+
+func TestGeocoder_Geocode_Empty(t *testing.T) {
+	geocoder := services.NewGeocoder(new(mocks.MockCityRepository), new(mocks.MockCountryRepository))
+
+	_, err := geocoder.Geocode(context.Background(), " , Germany")
+	assert.ErrorIs(t, err, services.ErrPlaceNotFound)
+}
@@ -2,55 +2,545 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go-mongodb-api/interfaces"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/models"
+	"log"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var (
+	ErrCategoryNotFound             = errors.New("job category not found")
+	ErrJobTypeNotFound              = errors.New("job type not found")
+	ErrCountryNotFound              = errors.New("country not found")
+	ErrEducationLevelNotFound       = errors.New("education level not found")
+	ErrLocationAvailabilityNotFound = errors.New("location availability not found")
+	ErrInvalidJobTransition         = errors.New("invalid job status transition")
+	ErrInvalidClosesAt              = errors.New("closes_at must be in the future")
 )
 
+// jobTransitions lists the statuses a job can move to from each status.
+// Reopening moves a closed job back to active; archived jobs are final.
+var jobTransitions = map[string][]string{
+	models.JobStatusDraft:  {models.JobStatusActive, models.JobStatusArchived},
+	models.JobStatusActive: {models.JobStatusClosed},
+	models.JobStatusClosed: {models.JobStatusActive, models.JobStatusArchived},
+}
+
+// DefaultSalaryBands are the lower bounds of the salary facet bands used unless configured
+var DefaultSalaryBands = []int{0, 30000, 50000, 75000, 100000, 150000}
+
 type JobService struct {
 	repo         interfaces.JobRepository
 	userRepo     interfaces.UserRepository
 	categoryRepo interfaces.JobCategoryRepository
+	salaryBands  []int
+	rateRepo     interfaces.ExchangeRateRepository
+	baseCurrency string
+	geocoder     interfaces.Geocoder
+
+	jobTypeRepo              interfaces.JobTypeRepository
+	countryRepo              interfaces.CountryRepository
+	educationLevelRepo       interfaces.EducationLevelRepository
+	locationAvailabilityRepo interfaces.LocationAvailabilityRepository
+
+	templateRepo interfaces.PipelineTemplateRepository
+}
+
+// JobServiceOption configures optional JobService behaviour
+type JobServiceOption func(*JobService)
+
+// WithSalaryBands sets the ascending lower bounds of the salary facet bands
+func WithSalaryBands(bands []int) JobServiceOption {
+	return func(s *JobService) {
+		if len(bands) > 0 {
+			s.salaryBands = bands
+		}
+	}
+}
+
+// WithSalaryNormalization stores each job's salary range per year in baseCurrency next to the salary as
+// posted, converted with the exchange rates in rateRepo
+func WithSalaryNormalization(rateRepo interfaces.ExchangeRateRepository, baseCurrency string) JobServiceOption {
+	return func(s *JobService) {
+		s.rateRepo = rateRepo
+		s.baseCurrency = baseCurrency
+	}
+}
+
+// WithGeocoder locates jobs posted without coordinates from their location, and resolves the place
+// named by the "near" list filter
+func WithGeocoder(geocoder interfaces.Geocoder) JobServiceOption {
+	return func(s *JobService) {
+		s.geocoder = geocoder
+	}
+}
+
+// WithReferenceData checks that the job type, country, education level and location availability a job
+// references exist whenever it is saved
+func WithReferenceData(jobTypeRepo interfaces.JobTypeRepository, countryRepo interfaces.CountryRepository, educationLevelRepo interfaces.EducationLevelRepository, locationAvailabilityRepo interfaces.LocationAvailabilityRepository) JobServiceOption {
+	return func(s *JobService) {
+		s.jobTypeRepo = jobTypeRepo
+		s.countryRepo = countryRepo
+		s.educationLevelRepo = educationLevelRepo
+		s.locationAvailabilityRepo = locationAvailabilityRepo
+	}
+}
+
+// WithPipelineTemplates lets a job's pipeline stages be copied from a pipeline template
+func WithPipelineTemplates(templateRepo interfaces.PipelineTemplateRepository) JobServiceOption {
+	return func(s *JobService) {
+		s.templateRepo = templateRepo
+	}
 }
 
 // NewJobService creates a new job service
-func NewJobService(repo interfaces.JobRepository, userRepo interfaces.UserRepository, categoryRepo interfaces.JobCategoryRepository) *JobService {
-	return &JobService{
+func NewJobService(repo interfaces.JobRepository, userRepo interfaces.UserRepository, categoryRepo interfaces.JobCategoryRepository, opts ...JobServiceOption) *JobService {
+	s := &JobService{
 		repo:         repo,
 		userRepo:     userRepo,
 		categoryRepo: categoryRepo,
+		salaryBands:  DefaultSalaryBands,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // GetAllJobs retrieves all jobs with pagination, filtering, and sorting
 func (s *JobService) GetAllJobs(ctx context.Context, page, limit int, filters map[string]string, sort, order string) ([]models.Job, int64, error) {
-	return s.repo.GetAll(ctx, page, limit, filters, sort, order)
+	filters, err := s.resolveNear(ctx, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+	filters = visibleJobs(ctx, filters)
+	jobs, total, err := s.repo.GetAll(ctx, page, limit, filters, sort, order)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range jobs {
+		hideKnockouts(ctx, &jobs[i])
+	}
+	return jobs, total, nil
+}
+
+// GetJobFacets counts the jobs matching filters by category, job type, location, status and salary band
+func (s *JobService) GetJobFacets(ctx context.Context, filters map[string]string) (*models.JobFacets, error) {
+	filters, err := s.resolveNear(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Facets(ctx, visibleJobs(ctx, filters), s.salaryBands)
+}
+
+// visibleJobs limits filters to the jobs the caller may see: every job for admins, and otherwise
+// the public ones and those the caller posted
+func visibleJobs(ctx context.Context, filters map[string]string) map[string]string {
+	visible := maps.Clone(filters)
+	if visible == nil {
+		visible = make(map[string]string)
+	}
+	if isAdmin(ctx) {
+		delete(visible, models.FilterVisibleTo)
+		return visible
+	}
+	visible[models.FilterVisibleTo] = ""
+	if claims, ok := middleware.GetClaims(ctx); ok {
+		visible[models.FilterVisibleTo] = claims.UserID
+	}
+	return visible
 }
 
-// GetJobByID retrieves a job by ID
+// canSeeJob reports whether the caller may see job: public jobs are shown to anyone, drafts and
+// archived jobs only to admins and the job's recruiter
+func canSeeJob(ctx context.Context, job *models.Job) bool {
+	return job.IsPublic() || isAdmin(ctx) || isCaller(ctx, job.UserID.Hex())
+}
+
+// resolveNear replaces the place named by the "near" filter with its coordinates, returning the
+// filters unchanged when there is none. It returns ErrPlaceNotFound when the place is unknown.
+func (s *JobService) resolveNear(ctx context.Context, filters map[string]string) (map[string]string, error) {
+	place := filters[models.FilterNear]
+	if place == "" {
+		return filters, nil
+	}
+	if s.geocoder == nil {
+		return nil, ErrPlaceNotFound
+	}
+	city, err := s.geocoder.Geocode(ctx, place)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := maps.Clone(filters)
+	delete(resolved, models.FilterNear)
+	resolved[models.FilterLongitude] = strconv.FormatFloat(city.Location.Coordinates[0], 'f', -1, 64)
+	resolved[models.FilterLatitude] = strconv.FormatFloat(city.Location.Coordinates[1], 'f', -1, 64)
+	return resolved, nil
+}
+
+// GetJobByID retrieves a job by ID. Drafts and archived jobs are not found unless the caller is an
+// admin or posted them.
 func (s *JobService) GetJobByID(ctx context.Context, id string) (*models.Job, error) {
-	return s.repo.GetByID(ctx, id)
+	job, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canSeeJob(ctx, job) {
+		return nil, mongo.ErrNoDocuments
+	}
+	hideKnockouts(ctx, job)
+	return job, nil
 }
 
-// GetJobsByUser retrieves jobs by user (recruiter) ID
+// GetJobsByUser retrieves jobs by user (recruiter) ID. Drafts and archived jobs are left out unless
+// the caller is an admin or the recruiter.
 func (s *JobService) GetJobsByUser(ctx context.Context, userID string) ([]models.Job, error) {
-	return s.repo.GetByUserID(ctx, userID)
+	jobs, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	visible := jobs[:0]
+	for i := range jobs {
+		if !canSeeJob(ctx, &jobs[i]) {
+			continue
+		}
+		hideKnockouts(ctx, &jobs[i])
+		visible = append(visible, jobs[i])
+	}
+	return visible, nil
+}
+
+// hideKnockouts removes the knockout rules from the screening questions of a job unless the caller is
+// an admin or posted it, so that candidates cannot tailor their answers to them
+func hideKnockouts(ctx context.Context, job *models.Job) {
+	if job == nil || isAdmin(ctx) || isCaller(ctx, job.UserID.Hex()) {
+		return
+	}
+	for i := range job.ScreeningQuestions {
+		job.ScreeningQuestions[i].Knockout = nil
+	}
+}
+
+// ExpandJobs embeds the references named in expand into jobs
+func (s *JobService) ExpandJobs(ctx context.Context, jobs []models.Job, expand []string) error {
+	return s.repo.Expand(ctx, jobs, expand)
 }
 
-// CreateJob creates a new job
+// CreateJob creates a new job posted by the caller. Jobs are created as drafts unless they are
+// published right away with status active.
 func (s *JobService) CreateJob(ctx context.Context, job *models.Job) error {
+	if err := authorizeJob(ctx, job); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	switch job.Status {
+	case "", models.JobStatusDraft:
+		job.Status = models.JobStatusDraft
+		job.PublishedTime = nil
+	case models.JobStatusActive:
+		job.PublishedTime = &now
+	default:
+		return ErrInvalidJobTransition
+	}
+	if job.ClosesAt != nil && !job.ClosesAt.After(now) {
+		return ErrInvalidClosesAt
+	}
+	job.Active = job.Status == models.JobStatusActive
+	job.ClosedTime = nil
+
 	if _, err := s.userRepo.GetByID(ctx, job.UserID.Hex()); err != nil {
 		return fmt.Errorf("user not found")
 	}
 
-	if _, err := s.categoryRepo.GetByID(ctx, job.CategoryID.Hex()); err != nil {
-		return fmt.Errorf("job category not found")
+	if err := s.checkReferences(ctx, job); err != nil {
+		return err
+	}
+
+	if err := s.normalizeSalary(ctx, job); err != nil {
+		return err
+	}
+
+	if err := s.geocode(ctx, job); err != nil {
+		return err
 	}
 
+	stampCreated(ctx, job, now)
 	return s.repo.Create(ctx, job)
 }
 
-// DeleteJob deletes a job by ID
+// UpdateJob replaces the editable fields of a job owned by the caller and returns the updated job
+func (s *JobService) UpdateJob(ctx context.Context, id string, job *models.Job) (*models.Job, error) {
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeJob(ctx, existing); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkReferences(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// a new deadline must lie ahead; an unchanged one may already have passed
+	deadlineChanged := job.ClosesAt != nil && (existing.ClosesAt == nil || !job.ClosesAt.Equal(*existing.ClosesAt))
+	if deadlineChanged && !job.ClosesAt.After(time.Now()) {
+		return nil, ErrInvalidClosesAt
+	}
+
+	if err := s.normalizeSalary(ctx, job); err != nil {
+		return nil, err
+	}
+
+	if err := s.geocode(ctx, job); err != nil {
+		return nil, err
+	}
+
+	stampUpdated(ctx, job, time.Now())
+	return s.repo.Update(ctx, id, job)
+}
+
+// checkReferences verifies that the category and reference data a job points at exist. The optional
+// references are only checked when set.
+func (s *JobService) checkReferences(ctx context.Context, job *models.Job) error {
+	if _, err := s.categoryRepo.GetByID(ctx, job.CategoryID.Hex()); err != nil {
+		return ErrCategoryNotFound
+	}
+	if s.jobTypeRepo == nil {
+		return nil
+	}
+
+	if _, err := s.jobTypeRepo.GetByID(ctx, job.JobTypeID.Hex()); err != nil {
+		return ErrJobTypeNotFound
+	}
+	if job.CountryID != nil {
+		if _, err := s.countryRepo.GetByID(ctx, job.CountryID.Hex()); err != nil {
+			return ErrCountryNotFound
+		}
+	}
+	if job.EducationLevelID != nil {
+		if _, err := s.educationLevelRepo.GetByID(ctx, job.EducationLevelID.Hex()); err != nil {
+			return ErrEducationLevelNotFound
+		}
+	}
+	if job.LocationAvailabilityID != nil {
+		if _, err := s.locationAvailabilityRepo.GetByID(ctx, job.LocationAvailabilityID.Hex()); err != nil {
+			return ErrLocationAvailabilityNotFound
+		}
+	}
+	return nil
+}
+
+// normalizeSalary sets the annual base-currency salary range of a job, or clears it when salaries are
+// not normalized or the job's currency has no exchange rate
+func (s *JobService) normalizeSalary(ctx context.Context, job *models.Job) error {
+	job.SalaryAnnualMin, job.SalaryAnnualMax = nil, nil
+	if s.rateRepo == nil {
+		return nil
+	}
+
+	rate := 1.0
+	if job.SalaryCurrency != s.baseCurrency {
+		exchangeRate, err := s.rateRepo.GetByCurrency(ctx, job.SalaryCurrency)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		rate = exchangeRate.Rate
+	}
+
+	periods, ok := models.PeriodsPerYear[job.SalaryPeriod]
+	if !ok {
+		periods = 1
+	}
+	annualMin := int(math.RoundToEven(float64(job.SalaryMin*periods) / rate))
+	annualMax := int(math.RoundToEven(float64(job.SalaryMax*periods) / rate))
+	job.SalaryAnnualMin, job.SalaryAnnualMax = &annualMin, &annualMax
+	return nil
+}
+
+// geocode sets the coordinates of a job given without them to those of the city its location names.
+// A location no city matches, such as "Remote", leaves the job without coordinates.
+func (s *JobService) geocode(ctx context.Context, job *models.Job) error {
+	if job.Coordinates != nil || s.geocoder == nil {
+		return nil
+	}
+	city, err := s.geocoder.Geocode(ctx, job.Location)
+	if errors.Is(err, ErrPlaceNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	location := city.Location
+	job.Coordinates = &location
+	return nil
+}
+
+// PublishJob makes a draft job active, optionally with an application deadline
+func (s *JobService) PublishJob(ctx context.Context, id string, closesAt *time.Time) (*models.Job, error) {
+	return s.transitionJob(ctx, id, models.JobStatusDraft, models.JobStatusActive, closesAt)
+}
+
+// CloseJob stops an active job from accepting applications
+func (s *JobService) CloseJob(ctx context.Context, id string) (*models.Job, error) {
+	return s.transitionJob(ctx, id, models.JobStatusActive, models.JobStatusClosed, nil)
+}
+
+// ReopenJob makes a closed job active again. A deadline that has already passed is cleared
+// unless a new one is given.
+func (s *JobService) ReopenJob(ctx context.Context, id string, closesAt *time.Time) (*models.Job, error) {
+	return s.transitionJob(ctx, id, models.JobStatusClosed, models.JobStatusActive, closesAt)
+}
+
+// ArchiveJob retires a draft or closed job for good
+func (s *JobService) ArchiveJob(ctx context.Context, id string) (*models.Job, error) {
+	return s.transitionJob(ctx, id, "", models.JobStatusArchived, nil)
+}
+
+// CloseExpiredJobs closes every active job whose closes_at deadline has passed
+func (s *JobService) CloseExpiredJobs(ctx context.Context, now time.Time) (int64, error) {
+	return s.repo.CloseExpired(ctx, now, middleware.SystemActor)
+}
+
+// RunExpirySweeper closes expired jobs right away and then on every interval until ctx is cancelled
+func (s *JobService) RunExpirySweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		closed, err := s.CloseExpiredJobs(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			log.Printf("error closing expired jobs: %v", err)
+		} else if closed > 0 {
+			log.Printf("closed %d expired job(s)", closed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// transitionJob moves a job owned by the caller to status to. from restricts the current status
+// the action applies to; when empty, any status with a transition to the target is accepted.
+func (s *JobService) transitionJob(ctx context.Context, id, from, to string, closesAt *time.Time) (*models.Job, error) {
+	job, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	current := job.Status
+	if (from != "" && current != from) || !canTransitionJob(current, to) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidJobTransition, current, to)
+	}
+
+	now := time.Now()
+	if to == models.JobStatusActive {
+		switch {
+		case closesAt != nil && !closesAt.After(now):
+			return nil, ErrInvalidClosesAt
+		case closesAt != nil:
+			job.ClosesAt = closesAt
+		case job.ClosesAt != nil && !job.ClosesAt.After(now):
+			job.ClosesAt = nil
+		}
+		if job.PublishedTime == nil {
+			job.PublishedTime = &now
+		}
+		job.ClosedTime = nil
+	}
+	if to == models.JobStatusClosed {
+		job.ClosedTime = &now
+	}
+
+	job.Status = to
+	job.Active = to == models.JobStatusActive
+	stampUpdated(ctx, job, now)
+
+	updated, err := s.repo.UpdateStatus(ctx, id, current, job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// the status changed since the job was read
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidJobTransition, current, to)
+	}
+	return updated, err
+}
+
+// canTransitionJob reports whether a job may move from one status to another
+func canTransitionJob(from, to string) bool {
+	return slices.Contains(jobTransitions[from], to)
+}
+
+// SetJobStages replaces the pipeline stages of a job owned by the caller with stages, or with a copy
+// of those of the pipeline template templateID of the caller's company when it is given. Applications in a stage the job no
+// longer has keep it until they are moved.
+func (s *JobService) SetJobStages(ctx context.Context, id string, stages []models.PipelineStage, templateID string) (*models.Job, error) {
+	job, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	if templateID != "" {
+		if s.templateRepo == nil {
+			return nil, ErrPipelineTemplateNotFound
+		}
+		template, err := s.templateRepo.GetByID(ctx, templateID)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrPipelineTemplateNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := authorizeTemplate(ctx, s.userRepo, template); err != nil {
+			return nil, err
+		}
+		stages = template.Stages
+	}
+
+	job.Stages = stages
+	stampUpdated(ctx, job, time.Now())
+	return s.repo.UpdateStages(ctx, id, job)
+}
+
+// DeleteJob deletes a job owned by the caller by ID
 func (s *JobService) DeleteJob(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	job, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeJob(ctx, job); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, id, middleware.ActorID(ctx))
+}
+
+// RestoreJob restores a soft-deleted job and the records deleted along with it
+func (s *JobService) RestoreJob(ctx context.Context, id string) error {
+	return s.repo.Restore(ctx, id, updatedAudit(ctx, time.Now()))
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"go-mongodb-api/mocks"
 	"go-mongodb-api/models"
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 func TestJobService_GetAllJobs(t *testing.T) {
@@ -33,7 +35,7 @@ func TestJobService_GetJobByID_Success(t *testing.T) {
 	svc := services.NewJobService(mockRepo, nil, nil)
 
 	id := bson.NewObjectID()
-	expected := &models.Job{ID: id, Title: "Dev"}
+	expected := &models.Job{ID: id, Title: "Dev", Status: models.JobStatusActive}
 	mockRepo.On("GetByID", mock.Anything, id.Hex()).Return(expected, nil)
 
 	job, err := svc.GetJobByID(context.Background(), id.Hex())
@@ -42,12 +44,152 @@ func TestJobService_GetJobByID_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestJobService_GetJobByID_HidesKnockouts(t *testing.T) {
+	recruiterID := bson.NewObjectID()
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		hidden bool
+	}{
+		{"anonymous", context.Background(), true},
+		{"candidate", claimsContext("candidate", bson.NewObjectID().Hex()), true},
+		{"other recruiter", claimsContext("recruiter", bson.NewObjectID().Hex()), true},
+		{"job recruiter", claimsContext("recruiter", recruiterID.Hex()), false},
+		{"admin", claimsContext("admin", bson.NewObjectID().Hex()), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockJobRepository)
+			svc := services.NewJobService(mockRepo, nil, nil)
+
+			minYears := 3.0
+			mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: recruiterID, Status: models.JobStatusActive, ScreeningQuestions: []models.ScreeningQuestion{
+				{Key: "years_go", Question: "Years of Go?", Type: models.QuestionTypeNumber, Knockout: &models.KnockoutRule{Min: &minYears}},
+			}}, nil)
+
+			job, err := svc.GetJobByID(tt.ctx, "job-id")
+			assert.NoError(t, err)
+			assert.Equal(t, "Years of Go?", job.ScreeningQuestions[0].Question)
+			assert.Equal(t, tt.hidden, job.ScreeningQuestions[0].Knockout == nil)
+		})
+	}
+}
+
+func TestJobService_GetAllJobs_Visibility(t *testing.T) {
+	recruiterID := bson.NewObjectID().Hex()
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		visible string
+		all     bool
+	}{
+		{"anonymous", context.Background(), "", false},
+		{"candidate", claimsContext("candidate", "candidate-id"), "candidate-id", false},
+		{"recruiter", claimsContext("recruiter", recruiterID), recruiterID, false},
+		{"admin", claimsContext("admin", "admin-id"), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockJobRepository)
+			svc := services.NewJobService(mockRepo, nil, nil)
+
+			expected := map[string]string{"status": "draft"}
+			if !tt.all {
+				expected[models.FilterVisibleTo] = tt.visible
+			}
+			mockRepo.On("GetAll", mock.Anything, 1, 10, expected, "", "").Return([]models.Job{}, int64(0), nil)
+
+			// a visibility filter sent by the caller is replaced
+			filters := map[string]string{"status": "draft", models.FilterVisibleTo: "someone-else"}
+			_, _, err := svc.GetAllJobs(tt.ctx, 1, 10, filters, "", "")
+			assert.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestJobService_GetJobByID_Unpublished(t *testing.T) {
+	recruiterID := bson.NewObjectID()
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		found bool
+	}{
+		{"anonymous", context.Background(), false},
+		{"candidate", claimsContext("candidate", bson.NewObjectID().Hex()), false},
+		{"other recruiter", claimsContext("recruiter", bson.NewObjectID().Hex()), false},
+		{"job recruiter", claimsContext("recruiter", recruiterID.Hex()), true},
+		{"admin", claimsContext("admin", bson.NewObjectID().Hex()), true},
+	}
+
+	for _, status := range []string{models.JobStatusDraft, models.JobStatusArchived} {
+		for _, tt := range tests {
+			t.Run(status+" "+tt.name, func(t *testing.T) {
+				mockRepo := new(mocks.MockJobRepository)
+				svc := services.NewJobService(mockRepo, nil, nil)
+
+				mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: recruiterID, Status: status}, nil)
+
+				job, err := svc.GetJobByID(tt.ctx, "job-id")
+				if tt.found {
+					assert.NoError(t, err)
+					assert.Equal(t, status, job.Status)
+				} else {
+					assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+					assert.Nil(t, job)
+				}
+			})
+		}
+	}
+}
+
+func TestJobService_GetJobsByUser_HidesUnpublished(t *testing.T) {
+	recruiterID := bson.NewObjectID()
+	stored := func() []models.Job {
+		return []models.Job{
+			{Title: "Draft", UserID: recruiterID, Status: models.JobStatusDraft},
+			{Title: "Active", UserID: recruiterID, Status: models.JobStatusActive},
+			{Title: "Closed", UserID: recruiterID, Status: models.JobStatusClosed},
+			{Title: "Archived", UserID: recruiterID, Status: models.JobStatusArchived},
+		}
+	}
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		titles []string
+	}{
+		{"anonymous", context.Background(), []string{"Active", "Closed"}},
+		{"other recruiter", claimsContext("recruiter", bson.NewObjectID().Hex()), []string{"Active", "Closed"}},
+		{"job recruiter", claimsContext("recruiter", recruiterID.Hex()), []string{"Draft", "Active", "Closed", "Archived"}},
+		{"admin", claimsContext("admin", bson.NewObjectID().Hex()), []string{"Draft", "Active", "Closed", "Archived"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockJobRepository)
+			svc := services.NewJobService(mockRepo, nil, nil)
+
+			mockRepo.On("GetByUserID", mock.Anything, recruiterID.Hex()).Return(stored(), nil)
+
+			jobs, err := svc.GetJobsByUser(tt.ctx, recruiterID.Hex())
+			assert.NoError(t, err)
+			var titles []string
+			for _, job := range jobs {
+				titles = append(titles, job.Title)
+			}
+			assert.Equal(t, tt.titles, titles)
+		})
+	}
+}
+
 func TestJobService_GetJobsByUser(t *testing.T) {
 	mockRepo := new(mocks.MockJobRepository)
 	svc := services.NewJobService(mockRepo, nil, nil)
 
 	userID := bson.NewObjectID()
-	expected := []models.Job{{Title: "SWE"}}
+	expected := []models.Job{{Title: "SWE", Status: models.JobStatusActive}}
 	mockRepo.On("GetByUserID", mock.Anything, userID.Hex()).Return(expected, nil)
 
 	jobs, err := svc.GetJobsByUser(context.Background(), userID.Hex())
@@ -56,6 +198,19 @@ func TestJobService_GetJobsByUser(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestJobService_ExpandJobs(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	jobs := []models.Job{{ID: bson.NewObjectID()}}
+	expand := []string{models.ExpandCategory, models.ExpandSkills}
+	mockRepo.On("Expand", mock.Anything, jobs, expand).Return(nil)
+
+	err := svc.ExpandJobs(context.Background(), jobs, expand)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestJobService_CreateJob_Success(t *testing.T) {
 	mockRepo := new(mocks.MockJobRepository)
 	mockUserRepo := new(mocks.MockUserRepository)
@@ -69,13 +224,208 @@ func TestJobService_CreateJob_Success(t *testing.T) {
 	mockCategoryRepo.On("GetByID", mock.Anything, categoryID.Hex()).Return(&models.JobCategory{}, nil)
 	mockRepo.On("Create", mock.Anything, job).Return(nil)
 
-	err := svc.CreateJob(context.Background(), job)
+	err := svc.CreateJob(claimsContext("recruiter", job.UserID.Hex()), job)
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 	mockUserRepo.AssertExpectations(t)
 	mockCategoryRepo.AssertExpectations(t)
 }
 
+func TestJobService_CreateJob_NormalizesSalary(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockCategoryRepo := new(mocks.MockJobCategoryRepository)
+	mockRateRepo := new(mocks.MockExchangeRateRepository)
+	svc := services.NewJobService(mockRepo, mockUserRepo, mockCategoryRepo, services.WithSalaryNormalization(mockRateRepo, "USD"))
+
+	userID := bson.NewObjectID()
+	job := &models.Job{UserID: userID, SalaryMin: 4000, SalaryMax: 5000, SalaryCurrency: "EUR", SalaryPeriod: models.SalaryPeriodMonthly}
+	mockUserRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.User{}, nil)
+	mockCategoryRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.JobCategory{}, nil)
+	mockRateRepo.On("GetByCurrency", mock.Anything, "EUR").Return(&models.ExchangeRate{Currency: "EUR", Rate: 0.8}, nil)
+	mockRepo.On("Create", mock.Anything, job).Return(nil)
+
+	err := svc.CreateJob(claimsContext("recruiter", userID.Hex()), job)
+	assert.NoError(t, err)
+	assert.Equal(t, 60000, *job.SalaryAnnualMin)
+	assert.Equal(t, 75000, *job.SalaryAnnualMax)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestJobService_CreateJob_BaseCurrencySkipsRateLookup(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockCategoryRepo := new(mocks.MockJobCategoryRepository)
+	mockRateRepo := new(mocks.MockExchangeRateRepository)
+	svc := services.NewJobService(mockRepo, mockUserRepo, mockCategoryRepo, services.WithSalaryNormalization(mockRateRepo, "USD"))
+
+	userID := bson.NewObjectID()
+	job := &models.Job{UserID: userID, SalaryMin: 40, SalaryMax: 50, SalaryCurrency: "USD", SalaryPeriod: models.SalaryPeriodHourly}
+	mockUserRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.User{}, nil)
+	mockCategoryRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.JobCategory{}, nil)
+	mockRepo.On("Create", mock.Anything, job).Return(nil)
+
+	err := svc.CreateJob(claimsContext("recruiter", userID.Hex()), job)
+	assert.NoError(t, err)
+	assert.Equal(t, 83200, *job.SalaryAnnualMin)
+	assert.Equal(t, 104000, *job.SalaryAnnualMax)
+	mockRateRepo.AssertNotCalled(t, "GetByCurrency", mock.Anything, mock.Anything)
+}
+
+func TestJobService_CreateJob_CurrencyWithoutRate(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockCategoryRepo := new(mocks.MockJobCategoryRepository)
+	mockRateRepo := new(mocks.MockExchangeRateRepository)
+	svc := services.NewJobService(mockRepo, mockUserRepo, mockCategoryRepo, services.WithSalaryNormalization(mockRateRepo, "USD"))
+
+	userID := bson.NewObjectID()
+	annual := 1
+	job := &models.Job{UserID: userID, SalaryMin: 40000, SalaryMax: 50000, SalaryCurrency: "CHF", SalaryAnnualMin: &annual, SalaryAnnualMax: &annual}
+	mockUserRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.User{}, nil)
+	mockCategoryRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.JobCategory{}, nil)
+	mockRateRepo.On("GetByCurrency", mock.Anything, "CHF").Return(nil, mongo.ErrNoDocuments)
+	mockRepo.On("Create", mock.Anything, job).Return(nil)
+
+	err := svc.CreateJob(claimsContext("recruiter", userID.Hex()), job)
+	assert.NoError(t, err)
+	assert.Nil(t, job.SalaryAnnualMin)
+	assert.Nil(t, job.SalaryAnnualMax)
+}
+
+func TestJobService_CreateJob_GeocodesLocation(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockCategoryRepo := new(mocks.MockJobCategoryRepository)
+	mockGeocoder := new(mocks.MockGeocoder)
+	svc := services.NewJobService(mockRepo, mockUserRepo, mockCategoryRepo, services.WithGeocoder(mockGeocoder))
+
+	userID := bson.NewObjectID()
+	job := &models.Job{UserID: userID, Location: "Skopje, North Macedonia"}
+	city := &models.City{Name: "Skopje", Location: *models.NewGeoPoint(41.9981, 21.4254)}
+	mockUserRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.User{}, nil)
+	mockCategoryRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.JobCategory{}, nil)
+	mockGeocoder.On("Geocode", mock.Anything, "Skopje, North Macedonia").Return(city, nil)
+	mockRepo.On("Create", mock.Anything, job).Return(nil)
+
+	err := svc.CreateJob(claimsContext("recruiter", userID.Hex()), job)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{21.4254, 41.9981}, job.Coordinates.Coordinates)
+	mockGeocoder.AssertExpectations(t)
+}
+
+func TestJobService_CreateJob_UnknownLocation(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockCategoryRepo := new(mocks.MockJobCategoryRepository)
+	mockGeocoder := new(mocks.MockGeocoder)
+	svc := services.NewJobService(mockRepo, mockUserRepo, mockCategoryRepo, services.WithGeocoder(mockGeocoder))
+
+	userID := bson.NewObjectID()
+	job := &models.Job{UserID: userID, Location: "Remote"}
+	mockUserRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.User{}, nil)
+	mockCategoryRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.JobCategory{}, nil)
+	mockGeocoder.On("Geocode", mock.Anything, "Remote").Return(nil, services.ErrPlaceNotFound)
+	mockRepo.On("Create", mock.Anything, job).Return(nil)
+
+	err := svc.CreateJob(claimsContext("recruiter", userID.Hex()), job)
+	assert.NoError(t, err)
+	assert.Nil(t, job.Coordinates)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestJobService_CreateJob_KeepsGivenCoordinates(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockCategoryRepo := new(mocks.MockJobCategoryRepository)
+	mockGeocoder := new(mocks.MockGeocoder)
+	svc := services.NewJobService(mockRepo, mockUserRepo, mockCategoryRepo, services.WithGeocoder(mockGeocoder))
+
+	userID := bson.NewObjectID()
+	coordinates := models.NewGeoPoint(42.0, 21.4)
+	job := &models.Job{UserID: userID, Location: "Skopje", Coordinates: coordinates}
+	mockUserRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.User{}, nil)
+	mockCategoryRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.JobCategory{}, nil)
+	mockRepo.On("Create", mock.Anything, job).Return(nil)
+
+	err := svc.CreateJob(claimsContext("recruiter", userID.Hex()), job)
+	assert.NoError(t, err)
+	assert.Equal(t, coordinates, job.Coordinates)
+	mockGeocoder.AssertNotCalled(t, "Geocode", mock.Anything, mock.Anything)
+}
+
+func TestJobService_CreateJob_ChecksReferences(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockCategoryRepo := new(mocks.MockJobCategoryRepository)
+	mockJobTypeRepo := new(mocks.MockJobTypeRepository)
+	mockCountryRepo := new(mocks.MockCountryRepository)
+	mockEducationLevelRepo := new(mocks.MockEducationLevelRepository)
+	mockAvailabilityRepo := new(mocks.MockLocationAvailabilityRepository)
+	svc := services.NewJobService(mockRepo, mockUserRepo, mockCategoryRepo,
+		services.WithReferenceData(mockJobTypeRepo, mockCountryRepo, mockEducationLevelRepo, mockAvailabilityRepo))
+
+	userID := bson.NewObjectID()
+	countryID := bson.NewObjectID()
+	availabilityID := bson.NewObjectID()
+	job := &models.Job{UserID: userID, JobTypeID: bson.NewObjectID(), CountryID: &countryID, LocationAvailabilityID: &availabilityID}
+	mockUserRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.User{}, nil)
+	mockCategoryRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.JobCategory{}, nil)
+	mockJobTypeRepo.On("GetByID", mock.Anything, job.JobTypeID.Hex()).Return(&models.JobType{}, nil)
+	mockCountryRepo.On("GetByID", mock.Anything, countryID.Hex()).Return(&models.Country{}, nil)
+	mockAvailabilityRepo.On("GetByID", mock.Anything, availabilityID.Hex()).Return(&models.LocationAvailability{}, nil)
+	mockRepo.On("Create", mock.Anything, job).Return(nil)
+
+	err := svc.CreateJob(claimsContext("recruiter", userID.Hex()), job)
+	assert.NoError(t, err)
+	mockJobTypeRepo.AssertExpectations(t)
+	mockCountryRepo.AssertExpectations(t)
+	mockAvailabilityRepo.AssertExpectations(t)
+	// no education level is required, so none is looked up
+	mockEducationLevelRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestJobService_CreateJob_JobTypeNotFound(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockCategoryRepo := new(mocks.MockJobCategoryRepository)
+	mockJobTypeRepo := new(mocks.MockJobTypeRepository)
+	svc := services.NewJobService(mockRepo, mockUserRepo, mockCategoryRepo,
+		services.WithReferenceData(mockJobTypeRepo, nil, nil, nil))
+
+	userID := bson.NewObjectID()
+	job := &models.Job{UserID: userID, JobTypeID: bson.NewObjectID()}
+	mockUserRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.User{}, nil)
+	mockCategoryRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.JobCategory{}, nil)
+	mockJobTypeRepo.On("GetByID", mock.Anything, job.JobTypeID.Hex()).Return(nil, mongo.ErrNoDocuments)
+
+	err := svc.CreateJob(claimsContext("recruiter", userID.Hex()), job)
+	assert.ErrorIs(t, err, services.ErrJobTypeNotFound)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestJobService_CreateJob_CountryNotFound(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockCategoryRepo := new(mocks.MockJobCategoryRepository)
+	mockJobTypeRepo := new(mocks.MockJobTypeRepository)
+	mockCountryRepo := new(mocks.MockCountryRepository)
+	svc := services.NewJobService(mockRepo, mockUserRepo, mockCategoryRepo,
+		services.WithReferenceData(mockJobTypeRepo, mockCountryRepo, nil, nil))
+
+	userID := bson.NewObjectID()
+	countryID := bson.NewObjectID()
+	job := &models.Job{UserID: userID, JobTypeID: bson.NewObjectID(), CountryID: &countryID}
+	mockUserRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.User{}, nil)
+	mockCategoryRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.JobCategory{}, nil)
+	mockJobTypeRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.JobType{}, nil)
+	mockCountryRepo.On("GetByID", mock.Anything, countryID.Hex()).Return(nil, mongo.ErrNoDocuments)
+
+	err := svc.CreateJob(claimsContext("recruiter", userID.Hex()), job)
+	assert.ErrorIs(t, err, services.ErrCountryNotFound)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
 func TestJobService_CreateJob_UserNotFound(t *testing.T) {
 	mockRepo := new(mocks.MockJobRepository)
 	mockUserRepo := new(mocks.MockUserRepository)
@@ -86,7 +436,7 @@ func TestJobService_CreateJob_UserNotFound(t *testing.T) {
 	job := &models.Job{UserID: userID}
 	mockUserRepo.On("GetByID", mock.Anything, userID.Hex()).Return(nil, errors.New("not found"))
 
-	err := svc.CreateJob(context.Background(), job)
+	err := svc.CreateJob(claimsContext("recruiter", job.UserID.Hex()), job)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "user not found")
 }
@@ -103,18 +453,527 @@ func TestJobService_CreateJob_CategoryNotFound(t *testing.T) {
 	mockUserRepo.On("GetByID", mock.Anything, userID.Hex()).Return(&models.User{}, nil)
 	mockCategoryRepo.On("GetByID", mock.Anything, categoryID.Hex()).Return(nil, errors.New("not found"))
 
-	err := svc.CreateJob(context.Background(), job)
-	assert.Error(t, err)
+	err := svc.CreateJob(claimsContext("recruiter", job.UserID.Hex()), job)
+	assert.ErrorIs(t, err, services.ErrCategoryNotFound)
 	assert.Contains(t, err.Error(), "job category not found")
 }
 
+func TestJobService_CreateJob_ForAnotherUser(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	job := &models.Job{UserID: bson.NewObjectID(), CategoryID: bson.NewObjectID()}
+
+	err := svc.CreateJob(claimsContext("recruiter", bson.NewObjectID().Hex()), job)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestJobService_GetAllJobs_ResolvesNear(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockGeocoder := new(mocks.MockGeocoder)
+	svc := services.NewJobService(mockRepo, nil, nil, services.WithGeocoder(mockGeocoder))
+
+	city := &models.City{Name: "Skopje", Location: *models.NewGeoPoint(41.9981, 21.4254)}
+	mockGeocoder.On("Geocode", mock.Anything, "Skopje").Return(city, nil)
+	resolved := map[string]string{"lat": "41.9981", "lng": "21.4254", "radius_km": "30", models.FilterVisibleTo: ""}
+	mockRepo.On("GetAll", mock.Anything, 1, 10, resolved, "", "").Return([]models.Job{}, int64(0), nil)
+
+	filters := map[string]string{"near": "Skopje", "radius_km": "30"}
+	_, _, err := svc.GetAllJobs(context.Background(), 1, 10, filters, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "Skopje", filters["near"])
+	mockRepo.AssertExpectations(t)
+}
+
+func TestJobService_GetAllJobs_NearUnknownPlace(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockGeocoder := new(mocks.MockGeocoder)
+	svc := services.NewJobService(mockRepo, nil, nil, services.WithGeocoder(mockGeocoder))
+
+	mockGeocoder.On("Geocode", mock.Anything, "Atlantis").Return(nil, services.ErrPlaceNotFound)
+
+	_, _, err := svc.GetAllJobs(context.Background(), 1, 10, map[string]string{"near": "Atlantis"}, "", "")
+	assert.ErrorIs(t, err, services.ErrPlaceNotFound)
+	mockRepo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestJobService_GetJobFacets_DefaultBands(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	filters := map[string]string{"location": "Berlin"}
+	facets := &models.JobFacets{}
+	visible := map[string]string{"location": "Berlin", models.FilterVisibleTo: ""}
+	mockRepo.On("Facets", mock.Anything, visible, services.DefaultSalaryBands).Return(facets, nil)
+
+	result, err := svc.GetJobFacets(context.Background(), filters)
+	assert.NoError(t, err)
+	assert.Equal(t, facets, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestJobService_GetJobFacets_ConfiguredBands(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil, services.WithSalaryBands([]int{0, 40000, 80000}))
+
+	mockRepo.On("Facets", mock.Anything, mock.Anything, []int{0, 40000, 80000}).Return(&models.JobFacets{}, nil)
+
+	_, err := svc.GetJobFacets(context.Background(), map[string]string{})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestJobService_DeleteJob(t *testing.T) {
 	mockRepo := new(mocks.MockJobRepository)
 	svc := services.NewJobService(mockRepo, nil, nil)
 
-	mockRepo.On("Delete", mock.Anything, "job-id").Return(nil)
+	recruiterID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: recruiterID}, nil)
+	mockRepo.On("Delete", mock.Anything, "job-id", recruiterID.Hex()).Return(nil)
+
+	err := svc.DeleteJob(claimsContext("recruiter", recruiterID.Hex()), "job-id")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestJobService_RestoreJob_ParentDeleted(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	adminID := bson.NewObjectID().Hex()
+	mockRepo.On("Restore", mock.Anything, "job-id", updatedBy(adminID)).Return(models.ErrParentDeleted)
+
+	err := svc.RestoreJob(claimsContext("admin", adminID), "job-id")
+	assert.ErrorIs(t, err, models.ErrParentDeleted)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestJobService_DeleteJob_NotOwner(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: bson.NewObjectID()}, nil)
+
+	err := svc.DeleteJob(claimsContext("recruiter", bson.NewObjectID().Hex()), "job-id")
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestJobService_UpdateJob(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockCategoryRepo := new(mocks.MockJobCategoryRepository)
+	svc := services.NewJobService(mockRepo, nil, mockCategoryRepo)
+
+	recruiterID := bson.NewObjectID()
+	categoryID := bson.NewObjectID()
+	job := &models.Job{UserID: recruiterID, CategoryID: categoryID, Title: "Senior Go Developer"}
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: recruiterID}, nil)
+	mockCategoryRepo.On("GetByID", mock.Anything, categoryID.Hex()).Return(&models.JobCategory{}, nil)
+	mockRepo.On("Update", mock.Anything, "job-id", job).Return(job, nil)
 
-	err := svc.DeleteJob(context.Background(), "job-id")
+	updated, err := svc.UpdateJob(claimsContext("recruiter", recruiterID.Hex()), "job-id", job)
 	assert.NoError(t, err)
+	assert.Equal(t, "Senior Go Developer", updated.Title)
 	mockRepo.AssertExpectations(t)
+	mockCategoryRepo.AssertExpectations(t)
+}
+
+func TestJobService_UpdateJob_NotFound(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(nil, errors.New("not found"))
+
+	updated, err := svc.UpdateJob(claimsContext("admin", bson.NewObjectID().Hex()), "job-id", &models.Job{})
+	assert.Error(t, err)
+	assert.Nil(t, updated)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestJobService_UpdateJob_NotOwner(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: bson.NewObjectID()}, nil)
+
+	updated, err := svc.UpdateJob(claimsContext("recruiter", bson.NewObjectID().Hex()), "job-id", &models.Job{})
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	assert.Nil(t, updated)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestJobService_UpdateJob_CategoryNotFound(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockCategoryRepo := new(mocks.MockJobCategoryRepository)
+	svc := services.NewJobService(mockRepo, nil, mockCategoryRepo)
+
+	recruiterID := bson.NewObjectID()
+	categoryID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: recruiterID}, nil)
+	mockCategoryRepo.On("GetByID", mock.Anything, categoryID.Hex()).Return(nil, errors.New("not found"))
+
+	updated, err := svc.UpdateJob(claimsContext("recruiter", recruiterID.Hex()), "job-id", &models.Job{CategoryID: categoryID})
+	assert.ErrorIs(t, err, services.ErrCategoryNotFound)
+	assert.Nil(t, updated)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestJobService_UpdateJob_EducationLevelNotFound(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockCategoryRepo := new(mocks.MockJobCategoryRepository)
+	mockJobTypeRepo := new(mocks.MockJobTypeRepository)
+	mockEducationLevelRepo := new(mocks.MockEducationLevelRepository)
+	svc := services.NewJobService(mockRepo, nil, mockCategoryRepo,
+		services.WithReferenceData(mockJobTypeRepo, nil, mockEducationLevelRepo, nil))
+
+	userID := bson.NewObjectID()
+	levelID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: userID}, nil)
+	mockCategoryRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.JobCategory{}, nil)
+	mockJobTypeRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.JobType{}, nil)
+	mockEducationLevelRepo.On("GetByID", mock.Anything, levelID.Hex()).Return(nil, mongo.ErrNoDocuments)
+
+	_, err := svc.UpdateJob(claimsContext("recruiter", userID.Hex()), "job-id", &models.Job{EducationLevelID: &levelID})
+	assert.ErrorIs(t, err, services.ErrEducationLevelNotFound)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestJobService_CreateJob_DefaultsToDraft(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockCategoryRepo := new(mocks.MockJobCategoryRepository)
+	svc := services.NewJobService(mockRepo, mockUserRepo, mockCategoryRepo)
+
+	job := &models.Job{UserID: bson.NewObjectID(), CategoryID: bson.NewObjectID(), Active: true}
+	mockUserRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.User{}, nil)
+	mockCategoryRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.JobCategory{}, nil)
+	mockRepo.On("Create", mock.Anything, job).Return(nil)
+
+	err := svc.CreateJob(claimsContext("recruiter", job.UserID.Hex()), job)
+	assert.NoError(t, err)
+	assert.Equal(t, models.JobStatusDraft, job.Status)
+	assert.False(t, job.Active)
+	assert.Nil(t, job.PublishedTime)
+}
+
+func TestJobService_CreateJob_Published(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockCategoryRepo := new(mocks.MockJobCategoryRepository)
+	svc := services.NewJobService(mockRepo, mockUserRepo, mockCategoryRepo)
+
+	job := &models.Job{UserID: bson.NewObjectID(), CategoryID: bson.NewObjectID(), Status: models.JobStatusActive}
+	mockUserRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.User{}, nil)
+	mockCategoryRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.JobCategory{}, nil)
+	mockRepo.On("Create", mock.Anything, job).Return(nil)
+
+	err := svc.CreateJob(claimsContext("recruiter", job.UserID.Hex()), job)
+	assert.NoError(t, err)
+	assert.True(t, job.Active)
+	assert.NotNil(t, job.PublishedTime)
+}
+
+func TestJobService_CreateJob_InvalidInitialStatus(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	job := &models.Job{UserID: bson.NewObjectID(), Status: models.JobStatusClosed}
+
+	err := svc.CreateJob(claimsContext("recruiter", job.UserID.Hex()), job)
+	assert.ErrorIs(t, err, services.ErrInvalidJobTransition)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestJobService_CreateJob_PastDeadline(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	past := time.Now().Add(-time.Hour)
+	job := &models.Job{UserID: bson.NewObjectID(), Status: models.JobStatusActive, ClosesAt: &past}
+
+	err := svc.CreateJob(claimsContext("recruiter", job.UserID.Hex()), job)
+	assert.ErrorIs(t, err, services.ErrInvalidClosesAt)
+}
+
+func TestJobService_UpdateJob_PastDeadline(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockCategoryRepo := new(mocks.MockJobCategoryRepository)
+	svc := services.NewJobService(mockRepo, nil, mockCategoryRepo)
+
+	recruiterID := bson.NewObjectID()
+	past := time.Now().Add(-time.Hour)
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: recruiterID}, nil)
+	mockCategoryRepo.On("GetByID", mock.Anything, mock.Anything).Return(&models.JobCategory{}, nil)
+
+	updated, err := svc.UpdateJob(claimsContext("recruiter", recruiterID.Hex()), "job-id", &models.Job{ClosesAt: &past})
+	assert.ErrorIs(t, err, services.ErrInvalidClosesAt)
+	assert.Nil(t, updated)
+}
+
+func TestJobService_PublishJob(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	recruiterID := bson.NewObjectID()
+	closesAt := time.Now().Add(30 * 24 * time.Hour)
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: recruiterID, Status: models.JobStatusDraft}, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, "job-id", models.JobStatusDraft, mock.MatchedBy(func(j *models.Job) bool {
+		return j.Status == models.JobStatusActive && j.Active && j.PublishedTime != nil &&
+			j.ClosesAt.Equal(closesAt) && j.UpdatedBy == recruiterID.Hex()
+	})).Return(&models.Job{Status: models.JobStatusActive}, nil)
+
+	job, err := svc.PublishJob(claimsContext("recruiter", recruiterID.Hex()), "job-id", &closesAt)
+	assert.NoError(t, err)
+	assert.Equal(t, models.JobStatusActive, job.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestJobService_PublishJob_PastDeadline(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	recruiterID := bson.NewObjectID()
+	past := time.Now().Add(-time.Hour)
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: recruiterID, Status: models.JobStatusDraft}, nil)
+
+	job, err := svc.PublishJob(claimsContext("recruiter", recruiterID.Hex()), "job-id", &past)
+	assert.ErrorIs(t, err, services.ErrInvalidClosesAt)
+	assert.Nil(t, job)
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestJobService_PublishJob_NotDraft(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	recruiterID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: recruiterID, Status: models.JobStatusClosed}, nil)
+
+	job, err := svc.PublishJob(claimsContext("recruiter", recruiterID.Hex()), "job-id", nil)
+	assert.ErrorIs(t, err, services.ErrInvalidJobTransition)
+	assert.Nil(t, job)
+}
+
+func TestJobService_CloseJob(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	recruiterID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: recruiterID, Status: models.JobStatusActive, Active: true}, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, "job-id", models.JobStatusActive, mock.MatchedBy(func(j *models.Job) bool {
+		return j.Status == models.JobStatusClosed && !j.Active && j.ClosedTime != nil
+	})).Return(&models.Job{Status: models.JobStatusClosed}, nil)
+
+	_, err := svc.CloseJob(claimsContext("recruiter", recruiterID.Hex()), "job-id")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestJobService_CloseJob_NotOwner(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: bson.NewObjectID(), Status: models.JobStatusActive}, nil)
+
+	_, err := svc.CloseJob(claimsContext("recruiter", bson.NewObjectID().Hex()), "job-id")
+	assert.ErrorIs(t, err, services.ErrForbidden)
+}
+
+func TestJobService_CloseJob_ConcurrentChange(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	recruiterID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: recruiterID, Status: models.JobStatusActive}, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, "job-id", models.JobStatusActive, mock.Anything).Return(nil, mongo.ErrNoDocuments)
+
+	_, err := svc.CloseJob(claimsContext("recruiter", recruiterID.Hex()), "job-id")
+	assert.ErrorIs(t, err, services.ErrInvalidJobTransition)
+}
+
+func TestJobService_ReopenJob_ClearsPassedDeadline(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	recruiterID := bson.NewObjectID()
+	published := time.Now().Add(-60 * 24 * time.Hour)
+	closed := time.Now().Add(-time.Hour)
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{
+		UserID: recruiterID, Status: models.JobStatusClosed, PublishedTime: &published, ClosesAt: &closed, ClosedTime: &closed,
+	}, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, "job-id", models.JobStatusClosed, mock.MatchedBy(func(j *models.Job) bool {
+		return j.Status == models.JobStatusActive && j.ClosesAt == nil && j.ClosedTime == nil && j.PublishedTime.Equal(published)
+	})).Return(&models.Job{Status: models.JobStatusActive}, nil)
+
+	_, err := svc.ReopenJob(claimsContext("recruiter", recruiterID.Hex()), "job-id", nil)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestJobService_ArchiveJob(t *testing.T) {
+	for _, status := range []string{models.JobStatusDraft, models.JobStatusClosed} {
+		t.Run(status, func(t *testing.T) {
+			mockRepo := new(mocks.MockJobRepository)
+			svc := services.NewJobService(mockRepo, nil, nil)
+
+			recruiterID := bson.NewObjectID()
+			mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: recruiterID, Status: status}, nil)
+			mockRepo.On("UpdateStatus", mock.Anything, "job-id", status, mock.Anything).Return(&models.Job{Status: models.JobStatusArchived}, nil)
+
+			_, err := svc.ArchiveJob(claimsContext("recruiter", recruiterID.Hex()), "job-id")
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestJobService_ArchiveJob_Active(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	recruiterID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: recruiterID, Status: models.JobStatusActive}, nil)
+
+	_, err := svc.ArchiveJob(claimsContext("recruiter", recruiterID.Hex()), "job-id")
+	assert.ErrorIs(t, err, services.ErrInvalidJobTransition)
+}
+
+func TestJobService_CloseExpiredJobs(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	now := time.Now()
+	mockRepo.On("CloseExpired", mock.Anything, now, "system").Return(int64(3), nil)
+
+	closed, err := svc.CloseExpiredJobs(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), closed)
+}
+
+func TestJobService_RunExpirySweeper_StopsOnCancel(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockRepo.On("CloseExpired", mock.Anything, mock.Anything, "system").Return(int64(0), nil).Run(func(mock.Arguments) { cancel() })
+
+	done := make(chan struct{})
+	go func() {
+		svc.RunExpirySweeper(ctx, time.Hour)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("sweeper did not stop after cancel")
+	}
+	mockRepo.AssertNumberOfCalls(t, "CloseExpired", 1)
+}
+
+func TestJobService_SetJobStages(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	recruiterID := bson.NewObjectID()
+	stages := []models.PipelineStage{{Key: "screen", Name: "Screening"}, {Key: "hired", Name: "Hired", Outcome: models.ApplicationStatusAccepted}}
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: recruiterID}, nil)
+	mockRepo.On("UpdateStages", mock.Anything, "job-id", mock.MatchedBy(func(j *models.Job) bool {
+		return len(j.Stages) == 2 && j.Stages[0].Key == "screen" && j.UpdatedBy == recruiterID.Hex()
+	})).Return(&models.Job{Stages: stages}, nil)
+
+	job, err := svc.SetJobStages(claimsContext("recruiter", recruiterID.Hex()), "job-id", stages, "")
+	assert.NoError(t, err)
+	assert.Equal(t, stages, job.Stages)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestJobService_SetJobStages_NotOwner(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobService(mockRepo, nil, nil)
+
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: bson.NewObjectID()}, nil)
+
+	_, err := svc.SetJobStages(claimsContext("recruiter", bson.NewObjectID().Hex()), "job-id", nil, "")
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "UpdateStages", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestJobService_SetJobStages_FromTemplate(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockTemplateRepo := new(mocks.MockPipelineTemplateRepository)
+	svc := services.NewJobService(mockRepo, nil, nil, services.WithPipelineTemplates(mockTemplateRepo))
+
+	recruiterID := bson.NewObjectID()
+	stages := []models.PipelineStage{{Key: "call", Name: "Intro call"}}
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: recruiterID}, nil)
+	mockTemplateRepo.On("GetByID", mock.Anything, "template-id").Return(&models.PipelineTemplate{UserID: recruiterID, Stages: stages}, nil)
+	mockRepo.On("UpdateStages", mock.Anything, "job-id", mock.MatchedBy(func(j *models.Job) bool {
+		return len(j.Stages) == 1 && j.Stages[0].Key == "call"
+	})).Return(&models.Job{Stages: stages}, nil)
+
+	_, err := svc.SetJobStages(claimsContext("recruiter", recruiterID.Hex()), "job-id", nil, "template-id")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestJobService_SetJobStages_TemplateNotFound(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockTemplateRepo := new(mocks.MockPipelineTemplateRepository)
+	svc := services.NewJobService(mockRepo, nil, nil, services.WithPipelineTemplates(mockTemplateRepo))
+
+	recruiterID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: recruiterID}, nil)
+	mockTemplateRepo.On("GetByID", mock.Anything, "template-id").Return(nil, mongo.ErrNoDocuments)
+
+	_, err := svc.SetJobStages(claimsContext("recruiter", recruiterID.Hex()), "job-id", nil, "template-id")
+	assert.ErrorIs(t, err, services.ErrPipelineTemplateNotFound)
+	mockRepo.AssertNotCalled(t, "UpdateStages", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestJobService_SetJobStages_CompanyTemplate(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockTemplateRepo := new(mocks.MockPipelineTemplateRepository)
+	svc := services.NewJobService(mockRepo, mockUserRepo, nil, services.WithPipelineTemplates(mockTemplateRepo))
+
+	recruiterID := bson.NewObjectID()
+	stages := []models.PipelineStage{{Key: "call", Name: "Intro call"}}
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: recruiterID}, nil)
+	mockTemplateRepo.On("GetByID", mock.Anything, "template-id").Return(&models.PipelineTemplate{UserID: bson.NewObjectID(), CompanyName: "Acme", Stages: stages}, nil)
+	mockUserRepo.On("GetByID", mock.Anything, recruiterID.Hex()).Return(&models.User{CompanyName: "Acme"}, nil)
+	mockRepo.On("UpdateStages", mock.Anything, "job-id", mock.Anything).Return(&models.Job{Stages: stages}, nil)
+
+	_, err := svc.SetJobStages(claimsContext("recruiter", recruiterID.Hex()), "job-id", nil, "template-id")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestJobService_SetJobStages_OtherCompanysTemplate(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockTemplateRepo := new(mocks.MockPipelineTemplateRepository)
+	svc := services.NewJobService(mockRepo, mockUserRepo, nil, services.WithPipelineTemplates(mockTemplateRepo))
+
+	recruiterID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: recruiterID}, nil)
+	mockTemplateRepo.On("GetByID", mock.Anything, "template-id").Return(&models.PipelineTemplate{UserID: bson.NewObjectID(), CompanyName: "Globex"}, nil)
+	mockUserRepo.On("GetByID", mock.Anything, recruiterID.Hex()).Return(&models.User{CompanyName: "Acme"}, nil)
+
+	_, err := svc.SetJobStages(claimsContext("recruiter", recruiterID.Hex()), "job-id", nil, "template-id")
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "UpdateStages", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestJobService_SetJobStages_OtherRecruitersTemplate(t *testing.T) {
+	mockRepo := new(mocks.MockJobRepository)
+	mockTemplateRepo := new(mocks.MockPipelineTemplateRepository)
+	svc := services.NewJobService(mockRepo, nil, nil, services.WithPipelineTemplates(mockTemplateRepo))
+
+	recruiterID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "job-id").Return(&models.Job{UserID: recruiterID}, nil)
+	mockTemplateRepo.On("GetByID", mock.Anything, "template-id").Return(&models.PipelineTemplate{UserID: bson.NewObjectID()}, nil)
+
+	_, err := svc.SetJobStages(claimsContext("recruiter", recruiterID.Hex()), "job-id", nil, "template-id")
+	assert.ErrorIs(t, err, services.ErrForbidden)
 }
@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"go-mongodb-api/interfaces"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/models"
+	"time"
 )
 
 type JobCategoryService struct {
@@ -27,15 +29,22 @@ func (s *JobCategoryService) GetJobCategoryByID(ctx context.Context, id string)
 
 // CreateJobCategory creates a new job category
 func (s *JobCategoryService) CreateJobCategory(ctx context.Context, jobCategory *models.JobCategory) error {
+	stampCreated(ctx, jobCategory, time.Now())
 	return s.repo.Create(ctx, jobCategory)
 }
 
 // UpdateJobCategory updates a job category's allowed fields
 func (s *JobCategoryService) UpdateJobCategory(ctx context.Context, id string, jobCategory *models.JobCategory) (*models.JobCategory, error) {
+	stampUpdated(ctx, jobCategory, time.Now())
 	return s.repo.Update(ctx, id, jobCategory)
 }
 
 // DeleteJobCategory deletes a job category by ID (only if no jobs use it)
 func (s *JobCategoryService) DeleteJobCategory(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	return s.repo.Delete(ctx, id, middleware.ActorID(ctx))
+}
+
+// RestoreJobCategory restores a soft-deleted job category by ID
+func (s *JobCategoryService) RestoreJobCategory(ctx context.Context, id string) error {
+	return s.repo.Restore(ctx, id, updatedAudit(ctx, time.Now()))
 }
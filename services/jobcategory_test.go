@@ -70,7 +70,7 @@ func TestJobCategoryService_DeleteJobCategory(t *testing.T) {
 	mockRepo := new(mocks.MockJobCategoryRepository)
 	svc := services.NewJobCategoryService(mockRepo)
 
-	mockRepo.On("Delete", mock.Anything, "cat-id").Return(nil)
+	mockRepo.On("Delete", mock.Anything, "cat-id", mock.Anything).Return(nil)
 
 	err := svc.DeleteJobCategory(context.Background(), "cat-id")
 	assert.NoError(t, err)
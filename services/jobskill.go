@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"go-mongodb-api/interfaces"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/models"
+	"time"
 )
 
 type JobSkillService struct {
@@ -37,25 +39,61 @@ func (s *JobSkillService) GetJobSkillsByJobID(ctx context.Context, jobID string)
 	return s.repo.GetByJobID(ctx, jobID)
 }
 
-// CreateJobSkill creates a new job skill
+// CreateJobSkill adds a skill requirement to a job owned by the caller
 func (s *JobSkillService) CreateJobSkill(ctx context.Context, jobSkill *models.JobSkill) error {
-	if _, err := s.jobRepo.GetByID(ctx, jobSkill.JobID.Hex()); err != nil {
+	job, err := s.jobRepo.GetByID(ctx, jobSkill.JobID.Hex())
+	if err != nil {
 		return fmt.Errorf("job not found")
 	}
 
+	if err := authorizeJob(ctx, job); err != nil {
+		return err
+	}
+
 	if _, err := s.skillRepo.GetByID(ctx, jobSkill.SkillID.Hex()); err != nil {
 		return fmt.Errorf("skill not found")
 	}
 
+	stampCreated(ctx, jobSkill, time.Now())
 	return s.repo.Create(ctx, jobSkill)
 }
 
-// UpdateJobSkillProficiencyLevel updates the proficiency level required for a job skill
+// UpdateJobSkillProficiencyLevel updates the proficiency level required for a job skill on a job owned by the caller
 func (s *JobSkillService) UpdateJobSkillProficiencyLevel(ctx context.Context, id string, proficiencyLevel string) error {
-	return s.repo.UpdateProficiencyLevel(ctx, id, proficiencyLevel)
+	if err := s.authorizeJobOwner(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.UpdateProficiencyLevel(ctx, id, proficiencyLevel, updatedAudit(ctx, time.Now()))
 }
 
-// DeleteJobSkill deletes a job skill by ID
+// DeleteJobSkill deletes a job skill by ID from a job owned by the caller
 func (s *JobSkillService) DeleteJobSkill(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	if err := s.authorizeJobOwner(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id, middleware.ActorID(ctx))
+}
+
+// RestoreJobSkill restores a soft-deleted job skill by ID
+func (s *JobSkillService) RestoreJobSkill(ctx context.Context, id string) error {
+	return s.repo.Restore(ctx, id, updatedAudit(ctx, time.Now()))
+}
+
+// authorizeJobOwner allows admins and the recruiter who posted the job the skill belongs to
+func (s *JobSkillService) authorizeJobOwner(ctx context.Context, id string) error {
+	if isAdmin(ctx) {
+		return nil
+	}
+
+	jobSkill, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	job, err := s.jobRepo.GetByID(ctx, jobSkill.JobID.Hex())
+	if err != nil {
+		return err
+	}
+
+	return authorizeJob(ctx, job)
 }
@@ -63,13 +63,14 @@ func TestJobSkillService_CreateJobSkill_Success(t *testing.T) {
 	svc := services.NewJobSkillService(mockRepo, mockJobRepo, mockSkillRepo)
 
 	jobID := bson.NewObjectID()
+	recruiterID := bson.NewObjectID()
 	skillID := bson.NewObjectID()
 	js := &models.JobSkill{JobID: jobID, SkillID: skillID, ProficiencyLevelRequired: "intermediate"}
-	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{ID: jobID, UserID: recruiterID}, nil)
 	mockSkillRepo.On("GetByID", mock.Anything, skillID.Hex()).Return(&models.Skill{}, nil)
 	mockRepo.On("Create", mock.Anything, js).Return(nil)
 
-	err := svc.CreateJobSkill(context.Background(), js)
+	err := svc.CreateJobSkill(claimsContext("recruiter", recruiterID.Hex()), js)
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 	mockJobRepo.AssertExpectations(t)
@@ -86,7 +87,7 @@ func TestJobSkillService_CreateJobSkill_JobNotFound(t *testing.T) {
 	js := &models.JobSkill{JobID: jobID}
 	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(nil, errors.New("not found"))
 
-	err := svc.CreateJobSkill(context.Background(), js)
+	err := svc.CreateJobSkill(claimsContext("recruiter", bson.NewObjectID().Hex()), js)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "job not found")
 }
@@ -98,23 +99,43 @@ func TestJobSkillService_CreateJobSkill_SkillNotFound(t *testing.T) {
 	svc := services.NewJobSkillService(mockRepo, mockJobRepo, mockSkillRepo)
 
 	jobID := bson.NewObjectID()
+	recruiterID := bson.NewObjectID()
 	skillID := bson.NewObjectID()
 	js := &models.JobSkill{JobID: jobID, SkillID: skillID}
-	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{ID: jobID, UserID: recruiterID}, nil)
 	mockSkillRepo.On("GetByID", mock.Anything, skillID.Hex()).Return(nil, errors.New("not found"))
 
-	err := svc.CreateJobSkill(context.Background(), js)
+	err := svc.CreateJobSkill(claimsContext("recruiter", recruiterID.Hex()), js)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "skill not found")
 }
 
+func TestJobSkillService_CreateJobSkill_NotJobOwner(t *testing.T) {
+	mockRepo := new(mocks.MockJobSkillRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobSkillService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	js := &models.JobSkill{JobID: jobID, SkillID: bson.NewObjectID()}
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{ID: jobID, UserID: bson.NewObjectID()}, nil)
+
+	err := svc.CreateJobSkill(claimsContext("recruiter", bson.NewObjectID().Hex()), js)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
 func TestJobSkillService_UpdateProficiencyLevel(t *testing.T) {
 	mockRepo := new(mocks.MockJobSkillRepository)
-	svc := services.NewJobSkillService(mockRepo, nil, nil)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobSkillService(mockRepo, mockJobRepo, nil)
 
-	mockRepo.On("UpdateProficiencyLevel", mock.Anything, "js-id", "expert").Return(nil)
+	jobID := bson.NewObjectID()
+	recruiterID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "js-id").Return(&models.JobSkill{JobID: jobID}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{ID: jobID, UserID: recruiterID}, nil)
+	mockRepo.On("UpdateProficiencyLevel", mock.Anything, "js-id", "expert", updatedBy(recruiterID.Hex())).Return(nil)
 
-	err := svc.UpdateJobSkillProficiencyLevel(context.Background(), "js-id", "expert")
+	err := svc.UpdateJobSkillProficiencyLevel(claimsContext("recruiter", recruiterID.Hex()), "js-id", "expert")
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
@@ -123,9 +144,23 @@ func TestJobSkillService_DeleteJobSkill(t *testing.T) {
 	mockRepo := new(mocks.MockJobSkillRepository)
 	svc := services.NewJobSkillService(mockRepo, nil, nil)
 
-	mockRepo.On("Delete", mock.Anything, "js-id").Return(nil)
+	mockRepo.On("Delete", mock.Anything, "js-id", mock.Anything).Return(nil)
 
-	err := svc.DeleteJobSkill(context.Background(), "js-id")
+	err := svc.DeleteJobSkill(claimsContext("admin", bson.NewObjectID().Hex()), "js-id")
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestJobSkillService_DeleteJobSkill_NotJobOwner(t *testing.T) {
+	mockRepo := new(mocks.MockJobSkillRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	svc := services.NewJobSkillService(mockRepo, mockJobRepo, nil)
+
+	jobID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "js-id").Return(&models.JobSkill{JobID: jobID}, nil)
+	mockJobRepo.On("GetByID", mock.Anything, jobID.Hex()).Return(&models.Job{ID: jobID, UserID: bson.NewObjectID()}, nil)
+
+	err := svc.DeleteJobSkill(claimsContext("recruiter", bson.NewObjectID().Hex()), "js-id")
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"go-mongodb-api/interfaces"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/models"
+	"time"
 )
 
 type JobTypeService struct {
@@ -23,13 +25,19 @@ func (s *JobTypeService) GetJobTypeByID(ctx context.Context, id string) (*models
 }
 
 func (s *JobTypeService) CreateJobType(ctx context.Context, jobType *models.JobType) error {
+	stampCreated(ctx, jobType, time.Now())
 	return s.repo.Create(ctx, jobType)
 }
 
 func (s *JobTypeService) UpdateJobType(ctx context.Context, id string, jobType *models.JobType) (*models.JobType, error) {
+	stampUpdated(ctx, jobType, time.Now())
 	return s.repo.Update(ctx, id, jobType)
 }
 
 func (s *JobTypeService) DeleteJobType(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	return s.repo.Delete(ctx, id, middleware.ActorID(ctx))
+}
+
+func (s *JobTypeService) RestoreJobType(ctx context.Context, id string) error {
+	return s.repo.Restore(ctx, id, updatedAudit(ctx, time.Now()))
 }
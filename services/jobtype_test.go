@@ -70,7 +70,7 @@ func TestJobTypeService_DeleteJobType(t *testing.T) {
 	mockRepo := new(mocks.MockJobTypeRepository)
 	svc := services.NewJobTypeService(mockRepo)
 
-	mockRepo.On("Delete", mock.Anything, "jobtype-id").Return(nil)
+	mockRepo.On("Delete", mock.Anything, "jobtype-id", mock.Anything).Return(nil)
 
 	err := svc.DeleteJobType(context.Background(), "jobtype-id")
 	assert.NoError(t, err)
@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"go-mongodb-api/interfaces"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/models"
+	"time"
 )
 
 type KnowledgeLevelService struct {
@@ -23,13 +25,19 @@ func (s *KnowledgeLevelService) GetKnowledgeLevelByID(ctx context.Context, id st
 }
 
 func (s *KnowledgeLevelService) CreateKnowledgeLevel(ctx context.Context, knowledgeLevel *models.KnowledgeLevel) error {
+	stampCreated(ctx, knowledgeLevel, time.Now())
 	return s.repo.Create(ctx, knowledgeLevel)
 }
 
 func (s *KnowledgeLevelService) UpdateKnowledgeLevel(ctx context.Context, id string, knowledgeLevel *models.KnowledgeLevel) (*models.KnowledgeLevel, error) {
+	stampUpdated(ctx, knowledgeLevel, time.Now())
 	return s.repo.Update(ctx, id, knowledgeLevel)
 }
 
 func (s *KnowledgeLevelService) DeleteKnowledgeLevel(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	return s.repo.Delete(ctx, id, middleware.ActorID(ctx))
+}
+
+func (s *KnowledgeLevelService) RestoreKnowledgeLevel(ctx context.Context, id string) error {
+	return s.repo.Restore(ctx, id, updatedAudit(ctx, time.Now()))
 }
@@ -70,7 +70,7 @@ func TestKnowledgeLevelService_DeleteKnowledgeLevel(t *testing.T) {
 	mockRepo := new(mocks.MockKnowledgeLevelRepository)
 	svc := services.NewKnowledgeLevelService(mockRepo)
 
-	mockRepo.On("Delete", mock.Anything, "level-id").Return(nil)
+	mockRepo.On("Delete", mock.Anything, "level-id", mock.Anything).Return(nil)
 
 	err := svc.DeleteKnowledgeLevel(context.Background(), "level-id")
 	assert.NoError(t, err)
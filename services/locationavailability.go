@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"go-mongodb-api/interfaces"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/models"
+	"time"
 )
 
 type LocationAvailabilityService struct {
@@ -23,13 +25,19 @@ func (s *LocationAvailabilityService) GetLocationAvailabilityByID(ctx context.Co
 }
 
 func (s *LocationAvailabilityService) CreateLocationAvailability(ctx context.Context, locationAvailability *models.LocationAvailability) error {
+	stampCreated(ctx, locationAvailability, time.Now())
 	return s.repo.Create(ctx, locationAvailability)
 }
 
 func (s *LocationAvailabilityService) UpdateLocationAvailability(ctx context.Context, id string, locationAvailability *models.LocationAvailability) (*models.LocationAvailability, error) {
+	stampUpdated(ctx, locationAvailability, time.Now())
 	return s.repo.Update(ctx, id, locationAvailability)
 }
 
 func (s *LocationAvailabilityService) DeleteLocationAvailability(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	return s.repo.Delete(ctx, id, middleware.ActorID(ctx))
+}
+
+func (s *LocationAvailabilityService) RestoreLocationAvailability(ctx context.Context, id string) error {
+	return s.repo.Restore(ctx, id, updatedAudit(ctx, time.Now()))
 }
@@ -70,7 +70,7 @@ func TestLocationAvailabilityService_DeleteLocationAvailability(t *testing.T) {
 	mockRepo := new(mocks.MockLocationAvailabilityRepository)
 	svc := services.NewLocationAvailabilityService(mockRepo)
 
-	mockRepo.On("Delete", mock.Anything, "item-id").Return(nil)
+	mockRepo.On("Delete", mock.Anything, "item-id", mock.Anything).Return(nil)
 
 	err := svc.DeleteLocationAvailability(context.Background(), "item-id")
 	assert.NoError(t, err)
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-mongodb-api/interfaces"
+	"go-mongodb-api/middleware"
+	"go-mongodb-api/models"
+)
+
+// ErrPipelineTemplateNotFound is returned when a job's stages are set from a template that does not exist
+var ErrPipelineTemplateNotFound = errors.New("pipeline template not found")
+
+type PipelineTemplateService struct {
+	repo     interfaces.PipelineTemplateRepository
+	userRepo interfaces.UserRepository
+}
+
+// NewPipelineTemplateService creates a new pipeline template service
+func NewPipelineTemplateService(repo interfaces.PipelineTemplateRepository, userRepo interfaces.UserRepository) *PipelineTemplateService {
+	return &PipelineTemplateService{repo: repo, userRepo: userRepo}
+}
+
+// GetPipelineTemplates lists the pipeline templates of the caller's company and the caller's own, or
+// every template for admins
+func (s *PipelineTemplateService) GetPipelineTemplates(ctx context.Context) ([]models.PipelineTemplate, error) {
+	if isAdmin(ctx) {
+		return s.repo.GetAll(ctx, "", "")
+	}
+	claims, ok := middleware.GetClaims(ctx)
+	if !ok {
+		return nil, ErrForbidden
+	}
+	company, err := callerCompany(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetAll(ctx, claims.UserID, company)
+}
+
+// GetPipelineTemplateByID retrieves a pipeline template of the caller's company by ID
+func (s *PipelineTemplateService) GetPipelineTemplateByID(ctx context.Context, id string) (*models.PipelineTemplate, error) {
+	template, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeTemplate(ctx, s.userRepo, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// CreatePipelineTemplate creates a pipeline template owned by the caller and shared with the other
+// recruiters of their company
+func (s *PipelineTemplateService) CreatePipelineTemplate(ctx context.Context, template *models.PipelineTemplate) error {
+	if err := authorizeUser(ctx, template.UserID.Hex()); err != nil {
+		return err
+	}
+
+	owner, err := s.userRepo.GetByID(ctx, template.UserID.Hex())
+	if err != nil {
+		return fmt.Errorf("user not found")
+	}
+	template.CompanyName = owner.CompanyName
+
+	stampCreated(ctx, template, time.Now())
+	return s.repo.Create(ctx, template)
+}
+
+// UpdatePipelineTemplate replaces the name and stages of a pipeline template of the caller's company.
+// Jobs whose stages were copied from it keep their stages.
+func (s *PipelineTemplateService) UpdatePipelineTemplate(ctx context.Context, id string, template *models.PipelineTemplate) (*models.PipelineTemplate, error) {
+	if _, err := s.GetPipelineTemplateByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	stampUpdated(ctx, template, time.Now())
+	return s.repo.Update(ctx, id, template)
+}
+
+// DeletePipelineTemplate deletes a pipeline template of the caller's company
+func (s *PipelineTemplateService) DeletePipelineTemplate(ctx context.Context, id string) error {
+	if _, err := s.GetPipelineTemplateByID(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// authorizeTemplate allows admins, the recruiter who created the template and the other recruiters of
+// the company it belongs to. Templates created without a company name are only their creator's.
+func authorizeTemplate(ctx context.Context, users interfaces.UserRepository, template *models.PipelineTemplate) error {
+	if isAdmin(ctx) || isCaller(ctx, template.UserID.Hex()) {
+		return nil
+	}
+	if template.CompanyName == "" {
+		return ErrForbidden
+	}
+	company, err := callerCompany(ctx, users)
+	if err != nil {
+		return err
+	}
+	if company != template.CompanyName {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// callerCompany returns the company name of the caller, which is empty for recruiters without one
+func callerCompany(ctx context.Context, users interfaces.UserRepository) (string, error) {
+	claims, ok := middleware.GetClaims(ctx)
+	if !ok || users == nil {
+		return "", ErrForbidden
+	}
+	user, err := users.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return "", ErrForbidden
+	}
+	return user.CompanyName, nil
+}
@@ -0,0 +1,144 @@
+package services_test
+
+import (
+	"testing"
+
+	"go-mongodb-api/mocks"
+	"go-mongodb-api/models"
+	"go-mongodb-api/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestPipelineTemplateService_GetPipelineTemplates_Company(t *testing.T) {
+	mockRepo := new(mocks.MockPipelineTemplateRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	svc := services.NewPipelineTemplateService(mockRepo, mockUserRepo)
+
+	recruiterID := bson.NewObjectID().Hex()
+	templates := []models.PipelineTemplate{{Name: "Engineering"}}
+	mockUserRepo.On("GetByID", mock.Anything, recruiterID).Return(&models.User{CompanyName: "Acme"}, nil)
+	mockRepo.On("GetAll", mock.Anything, recruiterID, "Acme").Return(templates, nil)
+
+	result, err := svc.GetPipelineTemplates(claimsContext("recruiter", recruiterID))
+	assert.NoError(t, err)
+	assert.Equal(t, templates, result)
+}
+
+func TestPipelineTemplateService_GetPipelineTemplates_Admin(t *testing.T) {
+	mockRepo := new(mocks.MockPipelineTemplateRepository)
+	svc := services.NewPipelineTemplateService(mockRepo, nil)
+
+	mockRepo.On("GetAll", mock.Anything, "", "").Return([]models.PipelineTemplate{}, nil)
+
+	_, err := svc.GetPipelineTemplates(claimsContext("admin", bson.NewObjectID().Hex()))
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPipelineTemplateService_GetPipelineTemplateByID_Authorization(t *testing.T) {
+	creatorID := bson.NewObjectID()
+	tests := []struct {
+		name     string
+		company  string
+		caller   string
+		callerCo string
+		allowed  bool
+	}{
+		{"creator", "Acme", creatorID.Hex(), "Acme", true},
+		{"same company", "Acme", bson.NewObjectID().Hex(), "Acme", true},
+		{"other company", "Acme", bson.NewObjectID().Hex(), "Globex", false},
+		{"no company", "", bson.NewObjectID().Hex(), "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockPipelineTemplateRepository)
+			mockUserRepo := new(mocks.MockUserRepository)
+			svc := services.NewPipelineTemplateService(mockRepo, mockUserRepo)
+
+			mockRepo.On("GetByID", mock.Anything, "template-id").Return(&models.PipelineTemplate{UserID: creatorID, CompanyName: tt.company}, nil)
+			mockUserRepo.On("GetByID", mock.Anything, tt.caller).Return(&models.User{CompanyName: tt.callerCo}, nil)
+
+			template, err := svc.GetPipelineTemplateByID(claimsContext("recruiter", tt.caller), "template-id")
+			if tt.allowed {
+				assert.NoError(t, err)
+				assert.NotNil(t, template)
+			} else {
+				assert.ErrorIs(t, err, services.ErrForbidden)
+			}
+		})
+	}
+}
+
+func TestPipelineTemplateService_CreatePipelineTemplate(t *testing.T) {
+	mockRepo := new(mocks.MockPipelineTemplateRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	svc := services.NewPipelineTemplateService(mockRepo, mockUserRepo)
+
+	recruiterID := bson.NewObjectID()
+	template := &models.PipelineTemplate{UserID: recruiterID, Name: "Engineering", Stages: []models.PipelineStage{{Key: "screen", Name: "Screening"}}}
+	mockUserRepo.On("GetByID", mock.Anything, recruiterID.Hex()).Return(&models.User{CompanyName: "Acme"}, nil)
+	mockRepo.On("Create", mock.Anything, template).Return(nil)
+
+	err := svc.CreatePipelineTemplate(claimsContext("recruiter", recruiterID.Hex()), template)
+	assert.NoError(t, err)
+	assert.Equal(t, "Acme", template.CompanyName)
+	assert.Equal(t, recruiterID.Hex(), template.CreatedBy)
+	assert.False(t, template.CreatedTime.IsZero())
+}
+
+func TestPipelineTemplateService_CreatePipelineTemplate_ForAnotherUser(t *testing.T) {
+	mockRepo := new(mocks.MockPipelineTemplateRepository)
+	svc := services.NewPipelineTemplateService(mockRepo, nil)
+
+	template := &models.PipelineTemplate{UserID: bson.NewObjectID(), Name: "Engineering"}
+
+	err := svc.CreatePipelineTemplate(claimsContext("recruiter", bson.NewObjectID().Hex()), template)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestPipelineTemplateService_UpdatePipelineTemplate(t *testing.T) {
+	mockRepo := new(mocks.MockPipelineTemplateRepository)
+	svc := services.NewPipelineTemplateService(mockRepo, nil)
+
+	recruiterID := bson.NewObjectID()
+	template := &models.PipelineTemplate{Name: "Sales", Stages: []models.PipelineStage{{Key: "call", Name: "Intro call"}}}
+	mockRepo.On("GetByID", mock.Anything, "template-id").Return(&models.PipelineTemplate{UserID: recruiterID}, nil)
+	mockRepo.On("Update", mock.Anything, "template-id", mock.MatchedBy(func(t *models.PipelineTemplate) bool {
+		return t.Name == "Sales" && t.UpdatedBy == recruiterID.Hex()
+	})).Return(&models.PipelineTemplate{Name: "Sales"}, nil)
+
+	result, err := svc.UpdatePipelineTemplate(claimsContext("recruiter", recruiterID.Hex()), "template-id", template)
+	assert.NoError(t, err)
+	assert.Equal(t, "Sales", result.Name)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPipelineTemplateService_DeletePipelineTemplate(t *testing.T) {
+	mockRepo := new(mocks.MockPipelineTemplateRepository)
+	svc := services.NewPipelineTemplateService(mockRepo, nil)
+
+	recruiterID := bson.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, "template-id").Return(&models.PipelineTemplate{UserID: recruiterID}, nil)
+	mockRepo.On("Delete", mock.Anything, "template-id").Return(nil)
+
+	err := svc.DeletePipelineTemplate(claimsContext("recruiter", recruiterID.Hex()), "template-id")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPipelineTemplateService_DeletePipelineTemplate_NotFound(t *testing.T) {
+	mockRepo := new(mocks.MockPipelineTemplateRepository)
+	svc := services.NewPipelineTemplateService(mockRepo, nil)
+
+	mockRepo.On("GetByID", mock.Anything, "template-id").Return(nil, mongo.ErrNoDocuments)
+
+	err := svc.DeletePipelineTemplate(claimsContext("recruiter", bson.NewObjectID().Hex()), "template-id")
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"go-mongodb-api/interfaces"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/models"
+	"time"
 )
 
 type SkillService struct {
@@ -27,15 +29,22 @@ func (s *SkillService) GetSkillByID(ctx context.Context, id string) (*models.Ski
 
 // CreateSkill creates a new skill
 func (s *SkillService) CreateSkill(ctx context.Context, skill *models.Skill) error {
+	stampCreated(ctx, skill, time.Now())
 	return s.repo.Create(ctx, skill)
 }
 
 // UpdateSkill updates a skill's allowed fields
 func (s *SkillService) UpdateSkill(ctx context.Context, id string, skill *models.Skill) (*models.Skill, error) {
+	stampUpdated(ctx, skill, time.Now())
 	return s.repo.Update(ctx, id, skill)
 }
 
 // DeleteSkill deletes a skill by ID
 func (s *SkillService) DeleteSkill(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	return s.repo.Delete(ctx, id, middleware.ActorID(ctx))
+}
+
+// RestoreSkill restores a soft-deleted skill by ID
+func (s *SkillService) RestoreSkill(ctx context.Context, id string) error {
+	return s.repo.Restore(ctx, id, updatedAudit(ctx, time.Now()))
 }
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"testing"
 
+	"go-mongodb-api/interfaces"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/mocks"
 	"go-mongodb-api/models"
 	"go-mongodb-api/services"
@@ -66,11 +68,59 @@ func TestSkillService_CreateSkill(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestSkillService_UpdateSkill_StampsAudit(t *testing.T) {
+	mockRepo := new(mocks.MockSkillRepository)
+	svc := services.NewSkillService(mockRepo)
+
+	skill := &models.Skill{Name: "Go"}
+	mockRepo.On("Update", mock.Anything, "skill-id", skill).Return(skill, nil)
+
+	_, err := svc.UpdateSkill(context.Background(), "skill-id", skill)
+	assert.NoError(t, err)
+	assert.Equal(t, middleware.SystemActor, skill.UpdatedBy)
+	assert.False(t, skill.UpdatedTime.IsZero())
+	assert.Empty(t, skill.CreatedBy)
+}
+
+func TestSkillService_DeleteThenCreateSameName(t *testing.T) {
+	mockRepo := new(mocks.MockSkillRepository)
+	svc := services.NewSkillService(mockRepo)
+
+	mockRepo.On("Delete", mock.Anything, "skill-id", mock.Anything).Return(nil)
+	skill := &models.Skill{Name: "Python"}
+	mockRepo.On("Create", mock.Anything, skill).Return(nil)
+
+	assert.NoError(t, svc.DeleteSkill(context.Background(), "skill-id"))
+	assert.NoError(t, svc.CreateSkill(context.Background(), skill))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSkillService_CreateSkill_LiveDuplicate(t *testing.T) {
+	mockRepo := new(mocks.MockSkillRepository)
+	svc := services.NewSkillService(mockRepo)
+
+	skill := &models.Skill{Name: "Python"}
+	mockRepo.On("Create", mock.Anything, skill).Return(interfaces.ErrDuplicateKey)
+
+	err := svc.CreateSkill(context.Background(), skill)
+	assert.ErrorIs(t, err, interfaces.ErrDuplicateKey)
+}
+
+func TestSkillService_RestoreSkill_LiveDuplicate(t *testing.T) {
+	mockRepo := new(mocks.MockSkillRepository)
+	svc := services.NewSkillService(mockRepo)
+
+	mockRepo.On("Restore", mock.Anything, "skill-id", mock.Anything).Return(interfaces.ErrDuplicateKey)
+
+	err := svc.RestoreSkill(context.Background(), "skill-id")
+	assert.ErrorIs(t, err, interfaces.ErrDuplicateKey)
+}
+
 func TestSkillService_DeleteSkill(t *testing.T) {
 	mockRepo := new(mocks.MockSkillRepository)
 	svc := services.NewSkillService(mockRepo)
 
-	mockRepo.On("Delete", mock.Anything, "skill-id").Return(nil)
+	mockRepo.On("Delete", mock.Anything, "skill-id", mock.Anything).Return(nil)
 
 	err := svc.DeleteSkill(context.Background(), "skill-id")
 	assert.NoError(t, err)
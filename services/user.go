@@ -2,13 +2,19 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go-mongodb-api/interfaces"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/models"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+// ErrEmailTaken is returned when another account already uses the email address
+var ErrEmailTaken = errors.New("email address already registered")
+
 type UserService struct {
 	repo interfaces.UserRepository
 }
@@ -40,7 +46,15 @@ func (s *UserService) CreateUser(ctx context.Context, user *models.User) error {
 		return fmt.Errorf("failed to hash password: %w", err)
 	}
 	user.Password = string(hashed)
-	return s.repo.Create(ctx, user)
+
+	stampCreated(ctx, user, time.Now())
+	if err := s.repo.Create(ctx, user); err != nil {
+		if errors.Is(err, interfaces.ErrDuplicateKey) {
+			return ErrEmailTaken
+		}
+		return err
+	}
+	return nil
 }
 
 // UpdateUser updates a user's allowed fields
@@ -52,10 +66,60 @@ func (s *UserService) UpdateUser(ctx context.Context, id string, user *models.Us
 		}
 		user.Password = string(hashed)
 	}
+	stampUpdated(ctx, user, time.Now())
 	return s.repo.Update(ctx, id, user)
 }
 
+// UpdatePassword hashes and stores a new password for a user
+func (s *UserService) UpdatePassword(ctx context.Context, id string, password string) error {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	return s.repo.UpdatePassword(ctx, id, string(hashed), updatedAudit(ctx, time.Now()))
+}
+
+// MarkVerified flags a user's email address as verified
+func (s *UserService) MarkVerified(ctx context.Context, id string) error {
+	return s.repo.MarkVerified(ctx, id, updatedAudit(ctx, time.Now()))
+}
+
+// RecordLogin stores the current time as the user's last login
+func (s *UserService) RecordLogin(ctx context.Context, id string) error {
+	return s.repo.UpdateLastLogin(ctx, id, time.Now())
+}
+
+// SetMFASecret stores a pending TOTP secret for the user
+func (s *UserService) SetMFASecret(ctx context.Context, id string, secret string) error {
+	return s.repo.SetMFASecret(ctx, id, secret, updatedAudit(ctx, time.Now()))
+}
+
+// EnableMFA turns on two-factor authentication for the user
+func (s *UserService) EnableMFA(ctx context.Context, id string, recoveryCodeHashes []string, step int64) error {
+	return s.repo.EnableMFA(ctx, id, recoveryCodeHashes, step, updatedAudit(ctx, time.Now()))
+}
+
+// DisableMFA turns off two-factor authentication for the user
+func (s *UserService) DisableMFA(ctx context.Context, id string) error {
+	return s.repo.DisableMFA(ctx, id, updatedAudit(ctx, time.Now()))
+}
+
+// UseMFAStep marks a TOTP time step as used so its code cannot be replayed
+func (s *UserService) UseMFAStep(ctx context.Context, id string, step int64) error {
+	return s.repo.UseMFAStep(ctx, id, step)
+}
+
+// UseRecoveryCode consumes one of the user's recovery codes
+func (s *UserService) UseRecoveryCode(ctx context.Context, id string, codeHash string) error {
+	return s.repo.UseRecoveryCode(ctx, id, codeHash)
+}
+
 // DeleteUser deletes a user by ID
 func (s *UserService) DeleteUser(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	return s.repo.Delete(ctx, id, middleware.ActorID(ctx))
+}
+
+// RestoreUser restores a soft-deleted user and the records deleted along with it
+func (s *UserService) RestoreUser(ctx context.Context, id string) error {
+	return s.repo.Restore(ctx, id, updatedAudit(ctx, time.Now()))
 }
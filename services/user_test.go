@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"go-mongodb-api/interfaces"
+	"go-mongodb-api/middleware"
 	"go-mongodb-api/mocks"
 	"go-mongodb-api/models"
 	"go-mongodb-api/services"
@@ -12,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 func TestUserService_GetAllUsers(t *testing.T) {
@@ -96,11 +100,22 @@ func TestUserService_CreateUser_RepoError(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUserService_CreateUser_DuplicateEmail(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	svc := services.NewUserService(mockRepo)
+
+	user := &models.User{Email: "alice@example.com", Password: "password123"}
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).Return(interfaces.ErrDuplicateKey)
+
+	err := svc.CreateUser(context.Background(), user)
+	assert.ErrorIs(t, err, services.ErrEmailTaken)
+}
+
 func TestUserService_DeleteUser_Success(t *testing.T) {
 	mockRepo := new(mocks.MockUserRepository)
 	svc := services.NewUserService(mockRepo)
 
-	mockRepo.On("Delete", mock.Anything, "some-id").Return(nil)
+	mockRepo.On("Delete", mock.Anything, "some-id", mock.Anything).Return(nil)
 
 	err := svc.DeleteUser(context.Background(), "some-id")
 	assert.NoError(t, err)
@@ -111,13 +126,71 @@ func TestUserService_DeleteUser_Error(t *testing.T) {
 	mockRepo := new(mocks.MockUserRepository)
 	svc := services.NewUserService(mockRepo)
 
-	mockRepo.On("Delete", mock.Anything, "bad-id").Return(errors.New("not found"))
+	mockRepo.On("Delete", mock.Anything, "bad-id", mock.Anything).Return(errors.New("not found"))
 
 	err := svc.DeleteUser(context.Background(), "bad-id")
 	assert.Error(t, err)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUserService_DeleteUser_RecordsActor(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	svc := services.NewUserService(mockRepo)
+
+	adminID := bson.NewObjectID().Hex()
+	mockRepo.On("Delete", mock.Anything, "some-id", adminID).Return(nil)
+
+	err := svc.DeleteUser(claimsContext("admin", adminID), "some-id")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_RestoreUser_Success(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	svc := services.NewUserService(mockRepo)
+
+	adminID := bson.NewObjectID().Hex()
+	mockRepo.On("Restore", mock.Anything, "some-id", updatedBy(adminID)).Return(nil)
+
+	err := svc.RestoreUser(claimsContext("admin", adminID), "some-id")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_DeleteThenCreateSameEmail(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	svc := services.NewUserService(mockRepo)
+
+	mockRepo.On("Delete", mock.Anything, "user-id", mock.Anything).Return(nil)
+	user := &models.User{Email: "ana@example.com", Password: "password123"}
+	mockRepo.On("Create", mock.Anything, user).Return(nil)
+
+	assert.NoError(t, svc.DeleteUser(claimsContext("admin", "admin-id"), "user-id"))
+	assert.NoError(t, svc.CreateUser(context.Background(), user))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_RestoreUser_LiveDuplicate(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	svc := services.NewUserService(mockRepo)
+
+	mockRepo.On("Restore", mock.Anything, "user-id", mock.Anything).Return(interfaces.ErrDuplicateKey)
+
+	err := svc.RestoreUser(context.Background(), "user-id")
+	assert.ErrorIs(t, err, interfaces.ErrDuplicateKey)
+}
+
+func TestUserService_RestoreUser_Error(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	svc := services.NewUserService(mockRepo)
+
+	mockRepo.On("Restore", mock.Anything, "bad-id", mock.Anything).Return(mongo.ErrNoDocuments)
+
+	err := svc.RestoreUser(context.Background(), "bad-id")
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestUserService_UpdateUser_Success(t *testing.T) {
 	mockRepo := new(mocks.MockUserRepository)
 	svc := services.NewUserService(mockRepo)
@@ -161,3 +234,31 @@ func TestUserService_UpdateUser_WithPassword(t *testing.T) {
 	assert.NotEqual(t, "newpassword123", input.Password)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestUserService_UpdatePassword_HashesPassword(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	svc := services.NewUserService(mockRepo)
+
+	id := bson.NewObjectID().Hex()
+	mockRepo.On("UpdatePassword", mock.Anything, id, mock.MatchedBy(func(hash string) bool {
+		return hash != "newpassword123" && len(hash) > 0
+	}), updatedBy(middleware.SystemActor)).Return(nil)
+
+	err := svc.UpdatePassword(context.Background(), id, "newpassword123")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_RecordLogin(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	svc := services.NewUserService(mockRepo)
+
+	id := bson.NewObjectID().Hex()
+	mockRepo.On("UpdateLastLogin", mock.Anything, id, mock.MatchedBy(func(loginTime time.Time) bool {
+		return time.Since(loginTime) < time.Minute
+	})).Return(nil)
+
+	err := svc.RecordLogin(context.Background(), id)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}